@@ -0,0 +1,41 @@
+package agentrunner
+
+import "sync"
+
+// dispatcher delivers events to an OnEvent handler in emission order without ever
+// blocking the agent: events are buffered in an unbounded queue and drained by at most
+// one goroutine at a time, which exits whenever the queue runs dry.
+type dispatcher struct {
+	mu      sync.Mutex
+	pending []Event
+	running bool
+}
+
+func (d *dispatcher) push(handler func(Event), ev Event) {
+	d.mu.Lock()
+	d.pending = append(d.pending, ev)
+	if d.running {
+		d.mu.Unlock()
+		return
+	}
+	d.running = true
+	d.mu.Unlock()
+	go d.drain(handler)
+}
+
+func (d *dispatcher) drain(handler func(Event)) {
+	for {
+		d.mu.Lock()
+		batch := d.pending
+		d.pending = nil
+		if len(batch) == 0 {
+			d.running = false
+			d.mu.Unlock()
+			return
+		}
+		d.mu.Unlock()
+		for _, ev := range batch {
+			handler(ev)
+		}
+	}
+}
@@ -0,0 +1,53 @@
+package agentrunner
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+var (
+	registryMu sync.RWMutex
+	registry   = map[string]func() CLI{
+		"claude":  ClaudeCLI,
+		"codex":   CodexCLI,
+		"copilot": CopilotCLI,
+		"gemini":  GeminiCLI,
+	}
+)
+
+// Register makes a CLI available under name (case-insensitive) for NewCLIByName.
+// Registering an existing name replaces it, which lets embedders customise the built-ins.
+func Register(name string, factory func() CLI) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if name == "" || factory == nil {
+		panic("agentrunner: Register requires a name and a factory")
+	}
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	registry[name] = factory
+}
+
+// NewCLIByName builds a fresh CLI adapter for a registered name.
+func NewCLIByName(name string) (CLI, error) {
+	registryMu.RLock()
+	factory, ok := registry[strings.ToLower(strings.TrimSpace(name))]
+	registryMu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("unknown agent %q (registered: %s)", name, strings.Join(Names(), ", "))
+	}
+	return factory(), nil
+}
+
+// Names lists the registered CLI names in sorted order.
+func Names() []string {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	names := make([]string, 0, len(registry))
+	for name := range registry {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
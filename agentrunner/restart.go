@@ -0,0 +1,52 @@
+package agentrunner
+
+import "time"
+
+// RestartCondition selects which process exits trigger an automatic restart.
+type RestartCondition int
+
+const (
+	// RestartOnFailure restarts only after a non-zero exit.
+	RestartOnFailure RestartCondition = iota
+	// RestartAlways restarts after any exit, including a clean one.
+	RestartAlways
+)
+
+// RestartPolicy configures automatic recovery of agent processes that exit on their own.
+// The zero value disables restarts. Exits caused by Stop or by cancelling the Start
+// context never trigger a restart.
+type RestartPolicy struct {
+	// MaxRestarts bounds how many times the agent is relaunched.
+	MaxRestarts int
+	// Backoff is the delay before the first restart; it doubles after each attempt.
+	Backoff time.Duration
+	// MaxBackoff caps the delay between restarts (0 = uncapped).
+	MaxBackoff time.Duration
+	// On selects which exits restart the agent.
+	On RestartCondition
+	// Prompt builds the prompt for a restart from the new restart count.
+	// When nil the original prompt is reused.
+	Prompt func(restartCount int) string
+}
+
+func (p RestartPolicy) shouldRestart(restarts int, exitCode int) bool {
+	if restarts >= p.MaxRestarts {
+		return false
+	}
+	return exitCode != 0 || p.On == RestartAlways
+}
+
+// delay returns the backoff before restart number n (1-based).
+func (p RestartPolicy) delay(n int) time.Duration {
+	d := p.Backoff
+	for i := 1; i < n && d > 0; i++ {
+		d *= 2
+		if p.MaxBackoff > 0 && d >= p.MaxBackoff {
+			break
+		}
+	}
+	if p.MaxBackoff > 0 && d > p.MaxBackoff {
+		d = p.MaxBackoff
+	}
+	return d
+}
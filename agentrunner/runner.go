@@ -11,7 +11,9 @@ import (
 	"regexp"
 	"strings"
 	"sync"
+	"syscall"
 	"time"
+	"unicode"
 )
 
 // Event is emitted for agent lifecycle and output.
@@ -24,11 +26,14 @@ type AgentAdded struct {
 	Model    string
 	LogPath  string
 	Worktree string
+	Restarts int
 }
 
 type AgentStopped struct {
 	ID       string
 	ExitCode int
+	// Restarting is set when the restart policy will relaunch the agent.
+	Restarting bool
 }
 
 type AgentLine struct {
@@ -77,18 +82,33 @@ type Agent struct {
 	Display string
 	CLI     CLI
 	Events  chan<- Event
+	// OnEvent, when set, receives every event in order on a dedicated goroutine. It can be
+	// used instead of (or alongside) Events; slow handlers never block the agent because
+	// events are buffered until the handler catches up.
+	OnEvent func(Event)
 	// DisablePreamble omits the startup/prompt preamble from the log.
 	DisablePreamble bool
 	// InlineParse parses stdout/stderr directly instead of tailing the log file.
 	InlineParse bool
+	// Restart configures automatic recovery when the process exits on its own.
+	Restart RestartPolicy
+	// StopGrace is how long Stop waits after SIGTERM before killing the process.
+	// Zero means DefaultStopGrace.
+	StopGrace time.Duration
 
 	cmd        *exec.Cmd
+	pid        int
 	logFile    *os.File
 	mu         sync.Mutex
 	tailCancel context.CancelFunc
 	tailWG     sync.WaitGroup
 	done       chan struct{}
 	lastExit   int
+	restarts   int
+	stopped    bool
+	stopCh     chan struct{}
+	state      State
+	dispatch   dispatcher
 }
 
 // Start launches the agent and begins streaming output/events.
@@ -101,7 +121,25 @@ func (a *Agent) Start(ctx context.Context) error {
 	}
 
 	a.done = make(chan struct{})
+	a.stopCh = make(chan struct{})
+	a.stopped = false
+	a.restarts = 0
+	if err := a.launch(ctx); err != nil {
+		a.emit(a.setStateLocked(State{Kind: StateFailed, Err: err}))
+		return err
+	}
+	return nil
+}
+
+// RestartCount reports how many times the restart policy has relaunched the agent.
+func (a *Agent) RestartCount() int {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.restarts
+}
 
+// launch starts one process for the agent. Callers must hold a.mu.
+func (a *Agent) launch(ctx context.Context) error {
 	if err := os.MkdirAll(filepath.Dir(a.LogPath), 0o755); err != nil {
 		return err
 	}
@@ -123,6 +161,10 @@ func (a *Agent) Start(ctx context.Context) error {
 	args := a.CLI.BuildArgs(a.Prompt, a.Model)
 	cmd := exec.CommandContext(ctx, a.CLI.Command(), args...)
 	cmd.Dir = a.Workdir
+	setProcessGroup(cmd)
+	// Context cancellation gets the same SIGTERM-then-SIGKILL treatment as Stop.
+	cmd.Cancel = func() error { return signalGroup(cmd.Process.Pid, syscall.SIGTERM) }
+	cmd.WaitDelay = a.stopGrace()
 
 	if !a.DisablePreamble {
 		_, _ = fmt.Fprintf(a.logFile, "[%s] command: %s %s\n\n", time.Now().Format(time.RFC3339), a.CLI.Command(), strings.Join(args, " "))
@@ -153,6 +195,7 @@ func (a *Agent) Start(ctx context.Context) error {
 	}
 
 	a.cmd = cmd
+	a.pid = cmd.Process.Pid
 	display := a.Display
 	if display == "" {
 		display = a.Model
@@ -164,8 +207,10 @@ func (a *Agent) Start(ctx context.Context) error {
 		Model:    display,
 		LogPath:  a.LogPath,
 		Worktree: a.Workdir,
+		Restarts: a.restarts,
 	}
 	a.emit(added)
+	a.emit(a.setStateLocked(State{Kind: StateRunning}))
 
 	tailCtx, cancel := context.WithCancel(context.Background())
 	a.tailCancel = cancel
@@ -183,27 +228,72 @@ func (a *Agent) Start(ctx context.Context) error {
 	return nil
 }
 
-// Stop terminates the process.
+// DefaultStopGrace is used when an agent has no StopGrace configured.
+const DefaultStopGrace = 10 * time.Second
+
+// Stop cancels any pending restart and asks the CLI to exit with SIGTERM so it can flush
+// partial output and transcripts, killing it only once the grace period has elapsed.
 func (a *Agent) Stop() {
 	a.mu.Lock()
-	defer a.mu.Unlock()
+	if !a.stopped && a.stopCh != nil {
+		close(a.stopCh)
+	}
+	a.stopped = true
+	cmd := a.cmd
+	pid := a.pid
+	done := a.done
+	var changed *AgentStateChanged
+	if cmd != nil && a.state.Kind == StateRunning {
+		ev := a.setStateLocked(State{Kind: StateStopping})
+		changed = &ev
+	}
+	a.mu.Unlock()
 
-	if a.cmd == nil || a.cmd.Process == nil {
+	if cmd == nil {
 		return
 	}
-	_ = a.cmd.Process.Kill()
+	if changed != nil {
+		a.emit(*changed)
+	}
+	terminate(pid, done, a.stopGrace())
 
-	if a.tailCancel != nil {
-		a.tailCancel()
+	a.mu.Lock()
+	cancel := a.tailCancel
+	a.mu.Unlock()
+	if cancel != nil {
+		cancel()
 	}
 	a.tailWG.Wait()
 }
 
+func (a *Agent) stopGrace() time.Duration {
+	if a.StopGrace > 0 {
+		return a.StopGrace
+	}
+	return DefaultStopGrace
+}
+
+// terminate sends SIGTERM to the agent's process group and waits up to grace for it to
+// exit, then kills whatever is left of the group so spawned builds and watchers don't
+// outlive the agent. Platforms without SIGTERM support are killed immediately.
+func terminate(pid int, done <-chan struct{}, grace time.Duration) {
+	if err := signalGroup(pid, syscall.SIGTERM); err != nil {
+		killGroup(pid)
+		return
+	}
+	select {
+	case <-done:
+	case <-time.After(grace):
+	}
+	killGroup(pid)
+}
+
 func (a *Agent) stream(r io.Reader) {
 	scanner := bufio.NewScanner(r)
 	for scanner.Scan() {
 		line := scanner.Text()
 		_, _ = a.logFile.WriteString(line + "\n")
+		a.emitUsage(line)
 	}
 }
 
@@ -219,6 +309,7 @@ func (a *Agent) parseStream(ctx context.Context, r io.Reader) {
 		if a.logFile != nil {
 			_, _ = a.logFile.WriteString(line + "\n")
 		}
+		a.emitUsage(line)
 		clean := cleanLine(strings.TrimRight(line, "\r\n"))
 		if strings.TrimSpace(clean) == "" {
 			continue
@@ -245,22 +336,28 @@ func (a *Agent) parseStream(ctx context.Context, r io.Reader) {
 func (a *Agent) wait(ctx context.Context) {
 	err := a.cmd.Wait()
 	exit := 0
+	final := State{Kind: StateExited}
 	if err != nil {
 		if exitErr, ok := err.(*exec.ExitError); ok {
 			exit = exitErr.ExitCode()
+			final.ExitCode = exit
 		} else {
 			exit = 1
+			final = State{Kind: StateFailed, ExitCode: exit, Err: err}
 		}
 	}
 	a.mu.Lock()
 	a.lastExit = exit
 	done := a.done
+	restart := ctx.Err() == nil && !a.stopped && a.Restart.shouldRestart(a.restarts, exit)
+	changed := a.setStateLocked(final)
 	a.mu.Unlock()
 
 	select {
 	case <-ctx.Done():
 	default:
-		a.emit(AgentStopped{ID: a.ID, ExitCode: exit})
+		a.emit(changed)
+		a.emit(AgentStopped{ID: a.ID, ExitCode: exit, Restarting: restart})
 	}
 
 	a.mu.Lock()
@@ -282,12 +379,50 @@ func (a *Agent) wait(ctx context.Context) {
 	}
 	a.tailWG.Wait()
 
+	if restart && a.relaunch(ctx) {
+		return
+	}
 	if done != nil {
 		close(done)
 	}
 }
 
+// relaunch waits out the backoff and starts the next process, reporting whether it is running.
+func (a *Agent) relaunch(ctx context.Context) bool {
+	a.mu.Lock()
+	n := a.restarts + 1
+	stop := a.stopCh
+	a.mu.Unlock()
+
+	select {
+	case <-ctx.Done():
+		return false
+	case <-stop:
+		return false
+	case <-time.After(a.Restart.delay(n)):
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.stopped {
+		return false
+	}
+	a.restarts = n
+	if a.Restart.Prompt != nil {
+		a.Prompt = a.Restart.Prompt(n)
+	}
+	if err := a.launch(ctx); err != nil {
+		a.emit(AgentLine{ID: a.ID, Kind: MessageSay, Line: fmt.Sprintf("restart %d failed: %v", n, err)})
+		a.emit(a.setStateLocked(State{Kind: StateFailed, Err: err}))
+		return false
+	}
+	return true
+}
+
 func (a *Agent) emit(ev Event) {
+	if a.OnEvent != nil {
+		a.dispatch.push(a.OnEvent, ev)
+	}
 	if a.Events == nil {
 		return
 	}
@@ -367,7 +502,9 @@ func (a *Agent) tailFile(ctx context.Context) {
 	}
 }
 
-var ansiRegexp = regexp.MustCompile(`\x1b\[[0-9;?]*[ -/]*[@-~]`)
+// ansiRegexp matches CSI sequences (colours, cursor movement) and OSC sequences (window
+// titles, hyperlinks) ended by BEL or ST.
+var ansiRegexp = regexp.MustCompile(`\x1b\[[0-9;?]*[ -/]*[@-~]|\x1b\][^\x07\x1b]*(?:\x07|\x1b\\)`)
 
 func stripANSI(s string) string {
 	if strings.IndexByte(s, '\x1b') == -1 {
@@ -384,17 +521,18 @@ func trimLines(s string) string {
 	return strings.Join(lines, "\n")
 }
 
+// cleanLine drops escape sequences, control characters and invalid UTF-8, and expands
+// tabs. Zero-width joiners and combining marks are kept so emoji and accented text survive.
 func cleanLine(input string) string {
-	stripped := ansiRegexp.ReplaceAllString(input, "")
+	stripped := strings.ToValidUTF8(stripANSI(input), "")
 	var b strings.Builder
 	for _, r := range stripped {
-		switch r {
-		case '\t':
+		switch {
+		case r == '\t':
 			b.WriteString("    ")
+		case unicode.IsControl(r):
 		default:
-			if r >= 32 {
-				b.WriteRune(r)
-			}
+			b.WriteRune(r)
 		}
 	}
 	return b.String()
@@ -0,0 +1,76 @@
+package agentrunner
+
+import "fmt"
+
+// StateKind enumerates the lifecycle phases of an Agent.
+type StateKind int
+
+const (
+	// StatePending is an agent that has not been started yet, or is waiting to be restarted.
+	StatePending StateKind = iota
+	// StateRunning is an agent whose process is alive.
+	StateRunning
+	// StateStopping is an agent that has been asked to stop but has not exited yet.
+	StateStopping
+	// StateExited is an agent whose process exited; State.ExitCode holds the code.
+	StateExited
+	// StateFailed is an agent whose process could not be started or waited on; State.Err holds the cause.
+	StateFailed
+)
+
+func (k StateKind) String() string {
+	switch k {
+	case StatePending:
+		return "pending"
+	case StateRunning:
+		return "running"
+	case StateStopping:
+		return "stopping"
+	case StateExited:
+		return "exited"
+	case StateFailed:
+		return "failed"
+	default:
+		return fmt.Sprintf("StateKind(%d)", int(k))
+	}
+}
+
+// State is a snapshot of an agent's lifecycle.
+type State struct {
+	Kind     StateKind
+	ExitCode int
+	Err      error
+}
+
+func (s State) String() string {
+	switch s.Kind {
+	case StateExited:
+		return fmt.Sprintf("exited(%d)", s.ExitCode)
+	case StateFailed:
+		return fmt.Sprintf("failed(%v)", s.Err)
+	default:
+		return s.Kind.String()
+	}
+}
+
+// AgentStateChanged is emitted on every lifecycle transition.
+type AgentStateChanged struct {
+	ID    string
+	State State
+}
+
+func (AgentStateChanged) isEvent() {}
+
+// State returns the agent's current lifecycle state.
+func (a *Agent) State() State {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.state
+}
+
+// setStateLocked records a transition and returns the event describing it. Callers must
+// hold a.mu and emit the event themselves (after unlocking where possible).
+func (a *Agent) setStateLocked(s State) AgentStateChanged {
+	a.state = s
+	return AgentStateChanged{ID: a.ID, State: s}
+}
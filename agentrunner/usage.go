@@ -0,0 +1,94 @@
+package agentrunner
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+// Usage is the token accounting a CLI reports at the end of a turn.
+type Usage struct {
+	InputTokens      int
+	OutputTokens     int
+	CacheReadTokens  int
+	CacheWriteTokens int
+	// CostUSD is the CLI's own cost estimate, when it reports one.
+	CostUSD float64
+}
+
+// AgentUsage is emitted each time an agent's CLI reports token usage for a finished turn.
+type AgentUsage struct {
+	ID    string
+	Usage Usage
+}
+
+func (AgentUsage) isEvent() {}
+
+// UsageParser is implemented by CLIs whose output carries token usage.
+type UsageParser interface {
+	ParseUsage(line string) (Usage, bool)
+}
+
+// ParseClaudeUsage extracts usage from a Claude stream-json "result" record.
+func ParseClaudeUsage(line string) (Usage, bool) {
+	if !strings.Contains(line, `"usage"`) {
+		return Usage{}, false
+	}
+	var root struct {
+		Type  string `json:"type"`
+		Usage *struct {
+			InputTokens              int `json:"input_tokens"`
+			OutputTokens             int `json:"output_tokens"`
+			CacheReadInputTokens     int `json:"cache_read_input_tokens"`
+			CacheCreationInputTokens int `json:"cache_creation_input_tokens"`
+		} `json:"usage"`
+		TotalCostUSD float64 `json:"total_cost_usd"`
+	}
+	if err := json.Unmarshal([]byte(line), &root); err != nil || root.Type != "result" || root.Usage == nil {
+		return Usage{}, false
+	}
+	return Usage{
+		InputTokens:      root.Usage.InputTokens,
+		OutputTokens:     root.Usage.OutputTokens,
+		CacheReadTokens:  root.Usage.CacheReadInputTokens,
+		CacheWriteTokens: root.Usage.CacheCreationInputTokens,
+		CostUSD:          root.TotalCostUSD,
+	}, true
+}
+
+// ParseGeminiUsage extracts usage from a Gemini stream-json "result" record.
+func ParseGeminiUsage(line string) (Usage, bool) {
+	if !strings.Contains(line, `"stats"`) {
+		return Usage{}, false
+	}
+	var root struct {
+		Type  string `json:"type"`
+		Stats *struct {
+			InputTokens  int `json:"input_tokens"`
+			OutputTokens int `json:"output_tokens"`
+			Cached       int `json:"cached"`
+		} `json:"stats"`
+	}
+	if err := json.Unmarshal([]byte(line), &root); err != nil || root.Type != "result" || root.Stats == nil {
+		return Usage{}, false
+	}
+	return Usage{
+		InputTokens:     root.Stats.InputTokens,
+		OutputTokens:    root.Stats.OutputTokens,
+		CacheReadTokens: root.Stats.Cached,
+	}, true
+}
+
+func (claudeCLI) ParseUsage(line string) (Usage, bool) { return ParseClaudeUsage(line) }
+func (geminiCLI) ParseUsage(line string) (Usage, bool) { return ParseGeminiUsage(line) }
+
+// emitUsage reports usage found in a raw output line. It only runs on live process
+// output so replayed log history is never counted twice.
+func (a *Agent) emitUsage(line string) {
+	up, ok := a.CLI.(UsageParser)
+	if !ok {
+		return
+	}
+	if u, ok := up.ParseUsage(strings.TrimSpace(line)); ok {
+		a.emit(AgentUsage{ID: a.ID, Usage: u})
+	}
+}
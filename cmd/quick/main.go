@@ -7,12 +7,13 @@ import (
 	"os"
 	"os/exec"
 	"os/signal"
+	"path/filepath"
 	"regexp"
 	"strings"
-	"syscall"
 	"time"
 
 	"github.com/asynkron/Asynkron.SwarmGo/agentrunner"
+	"github.com/asynkron/Asynkron.SwarmGo/internal/termtext"
 	"github.com/charmbracelet/glamour"
 )
 
@@ -23,14 +24,14 @@ func main() {
 		model     string
 	)
 	flag.StringVar(&repo, "repo", "", "working directory (defaults to git root or current dir)")
-	flag.StringVar(&agentType, "agent", "claude", "agent to use (codex|claude|copilot|gemini)")
+	flag.StringVar(&agentType, "agent", "claude", "agent to use ("+strings.Join(agentrunner.Names(), "|")+")")
 	flag.StringVar(&model, "model", "", "model override (optional)")
 	yolo := flag.Bool("yolo", false, "allow non-read-only actions (drops guardrails in prompt)")
 	flag.Parse()
 
 	userInput := strings.TrimSpace(strings.Join(flag.Args(), " "))
 	if userInput == "" {
-		fmt.Println("usage: quick [--repo PATH] [--agent codex|claude|copilot|gemini] [--model NAME] <request>")
+		fmt.Printf("usage: quick [--repo PATH] [--agent %s] [--model NAME] <request>\n", strings.Join(agentrunner.Names(), "|"))
 		os.Exit(1)
 	}
 
@@ -44,7 +45,7 @@ func main() {
 		}
 	}
 
-	cli, err := chooseCLI(agentType)
+	cli, err := agentrunner.NewCLIByName(agentType)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "invalid agent: %v\n", err)
 		os.Exit(1)
@@ -59,7 +60,7 @@ func main() {
 	logPath, cleanup := tempLogPath()
 	defer cleanup()
 	events := make(chan agentrunner.Event, 256)
-	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	ctx, cancel := signal.NotifyContext(context.Background(), shutdownSignals...)
 	defer cancel()
 
 	agent := &agentrunner.Agent{
@@ -113,11 +114,11 @@ done:
 					spinIdx++
 					spin := spinnerFrame(spinIdx)
 					status := spin
-					visible := len(stripANSI(status))
+					visible := termtext.Width(termtext.StripANSI(status))
 					if visible > statusWidth {
 						statusWidth = visible
 					}
-					fmt.Printf("\r%-*s\r", statusWidth, status)
+					fmt.Printf("\r%s%s\r", status, strings.Repeat(" ", statusWidth-visible))
 					spinActive = true
 				case agentrunner.MessageSee:
 					// hide see output
@@ -156,11 +157,11 @@ done:
 				spinIdx++
 				spin := spinnerFrame(spinIdx)
 				status := spin
-				visible := len(stripANSI(status))
+				visible := termtext.Width(termtext.StripANSI(status))
 				if visible > statusWidth {
 					statusWidth = visible
 				}
-				fmt.Printf("\r%-*s\r", statusWidth, status)
+				fmt.Printf("\r%s%s\r", status, strings.Repeat(" ", statusWidth-visible))
 			}
 		case <-stopTimer:
 			if stopping {
@@ -178,21 +179,6 @@ func newRenderer() *glamour.TermRenderer {
 	return r
 }
 
-func chooseCLI(name string) (agentrunner.CLI, error) {
-	switch strings.ToLower(name) {
-	case "codex":
-		return agentrunner.CodexCLI(), nil
-	case "claude":
-		return agentrunner.ClaudeCLI(), nil
-	case "copilot":
-		return agentrunner.CopilotCLI(), nil
-	case "gemini":
-		return agentrunner.GeminiCLI(), nil
-	default:
-		return nil, fmt.Errorf("unknown agent %q", name)
-	}
-}
-
 func buildPrompt(repo string, userInput string, yolo bool) string {
 	if yolo {
 		return fmt.Sprintf(`You are Quick. Interpret the user's request and perform the minimum commands needed to answer it.
@@ -231,7 +217,7 @@ func gitRoot() (string, error) {
 	if err != nil {
 		return "", err
 	}
-	return strings.TrimSpace(out), nil
+	return filepath.FromSlash(strings.TrimSpace(out)), nil
 }
 
 func runCmd(cmd string, args ...string) (string, error) {
@@ -245,7 +231,7 @@ func runCmd(cmd string, args ...string) (string, error) {
 
 func tempLogPath() (string, func()) {
 	dir, _ := os.MkdirTemp("", "swarm-quick-*")
-	path := dir + "/agent.log"
+	path := filepath.Join(dir, "agent.log")
 	cleanup := func() {
 		_ = os.RemoveAll(dir)
 	}
@@ -419,9 +405,3 @@ func spinnerFrame(idx int) string {
 	b.WriteString("\x1b[0m")
 	return b.String()
 }
-
-var ansiPattern = regexp.MustCompile(`\x1b\[[0-9;]*m`)
-
-func stripANSI(s string) string {
-	return ansiPattern.ReplaceAllString(s, "")
-}
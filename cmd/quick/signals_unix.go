@@ -0,0 +1,11 @@
+//go:build !windows
+
+package main
+
+import (
+	"os"
+	"syscall"
+)
+
+// shutdownSignals cancel the run and stop agents gracefully.
+var shutdownSignals = []os.Signal{os.Interrupt, syscall.SIGTERM}
@@ -0,0 +1,9 @@
+//go:build windows
+
+package main
+
+import "os"
+
+// shutdownSignals cancel the run and stop agents gracefully. Windows only delivers
+// Ctrl+C/Ctrl+Break, which Go reports as os.Interrupt.
+var shutdownSignals = []os.Signal{os.Interrupt}
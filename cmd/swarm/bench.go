@@ -0,0 +1,205 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/asynkron/Asynkron.SwarmGo/internal/bench"
+	"github.com/asynkron/Asynkron.SwarmGo/internal/config"
+	"github.com/asynkron/Asynkron.SwarmGo/internal/control"
+	"github.com/asynkron/Asynkron.SwarmGo/internal/events"
+	"github.com/asynkron/Asynkron.SwarmGo/internal/orchestrator"
+	"github.com/asynkron/Asynkron.SwarmGo/internal/pricing"
+	"github.com/asynkron/Asynkron.SwarmGo/internal/session"
+	"github.com/asynkron/Asynkron.SwarmGo/internal/worktree"
+)
+
+// runBench implements `swarm bench`: it runs the same todo in --runs isolated rounds per
+// agent type, one agent type at a time, and prints a table comparing tests fixed, cost and
+// wall time.
+func runBench(args []string) {
+	fs := flag.NewFlagSet("bench", flag.ExitOnError)
+	repo := fs.String("repo", "", "path to git repository (defaults to current repo)")
+	todo := fs.String("todo", "todo.md", "path to todo file relative to repo")
+	types := fs.String("types", "claude,codex", "comma-separated agent types to compare (claude|codex|copilot|gemini|mock)")
+	runs := fs.Int("runs", 3, "isolated rounds per agent type")
+	minutes := fs.Int("minutes", 15, "time limit of each round in minutes")
+	evalCmd := fs.String("eval", "", "shell command run in each worktree before and after the round to count passing tests, e.g. 'go test ./...'")
+	out := fs.String("out", "", "also write the comparison table to this Markdown file")
+	keep := fs.Bool("keep-worktrees", false, "keep each round's worktree instead of removing it")
+	configPath := fs.String("config", "", "JSON config file whose pricing overrides cost estimates (default: <user config dir>/swarmgo/config.json)")
+	sessionDir := fs.String("session-dir", "", "directory sessions are kept in (default: <temp>/swarmgo)")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "usage: swarm bench [--types claude,codex] [--runs N] [--minutes N] [--eval CMD] [--out FILE] [--repo DIR] [--todo FILE]")
+		fs.PrintDefaults()
+	}
+	_ = fs.Parse(args)
+	if fs.NArg() > 0 || *runs < 1 || *minutes < 1 {
+		fs.Usage()
+		os.Exit(2)
+	}
+	if err := applyEnv(fs); err != nil {
+		fmt.Fprintf(os.Stderr, "invalid environment: %v\n", err)
+		os.Exit(2)
+	}
+	if err := setSessionRoot(*sessionDir); err != nil {
+		fmt.Fprintf(os.Stderr, "invalid --session-dir: %v\n", err)
+		os.Exit(2)
+	}
+
+	var agentTypes []config.AgentType
+	for _, name := range strings.Split(*types, ",") {
+		t, err := config.ParseAgentType(strings.TrimSpace(name))
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "invalid --types value: %v\n", err)
+			os.Exit(2)
+		}
+		agentTypes = append(agentTypes, t)
+	}
+
+	path := *configPath
+	if path == "" {
+		path = config.DefaultFilePath()
+	}
+	file, err := config.LoadFile(path, *configPath != "")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		os.Exit(1)
+	}
+	prices := pricing.Default.With(file.Pricing)
+
+	// Validate once against the source repository so path and todo mistakes fail up front.
+	base := config.Options{AgentMode: true, AgentType: agentTypes[0], Repo: *repo, Todo: *todo, Minutes: *minutes, MaxRounds: 1}
+	if err := base.Validate(); err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		os.Exit(1)
+	}
+
+	ctx, cancel := signal.NotifyContext(context.Background(), shutdownSignals...)
+	defer cancel()
+
+	dir := filepath.Join(session.Root, "bench-"+time.Now().Format("20060102-150405"))
+	var results []bench.Run
+	for _, t := range agentTypes {
+		for i := 1; i <= *runs && ctx.Err() == nil; i++ {
+			fmt.Printf("%s run %d/%d...\n", t, i, *runs)
+			r := benchRound(ctx, base, t, i, filepath.Join(dir, fmt.Sprintf("%s-%d", t, i)), *evalCmd, prices, *keep)
+			if r.Err != "" {
+				fmt.Printf("  failed: %s\n", r.Err)
+			} else {
+				fmt.Printf("  %d/%d tasks, %d tests fixed, %s\n", r.TasksDone, r.Tasks, r.Fixed(), r.Wall.Round(time.Second))
+			}
+			results = append(results, r)
+		}
+	}
+	if len(results) == 0 {
+		os.Exit(1)
+	}
+
+	table := bench.Table(results)
+	fmt.Println()
+	fmt.Print(table)
+	if *out != "" {
+		if err := os.WriteFile(*out, []byte(table), 0o644); err != nil {
+			fmt.Fprintf(os.Stderr, "write %s: %v\n", *out, err)
+			os.Exit(1)
+		}
+	}
+}
+
+// benchRound runs one agent-mode round of agent type t in a fresh detached worktree of the
+// source repository and scores it.
+func benchRound(ctx context.Context, base config.Options, t config.AgentType, index int, wt, evalCmd string, prices pricing.Table, keep bool) bench.Run {
+	r := bench.Run{Agent: string(t), Index: index}
+	fail := func(err error) bench.Run {
+		r.Err = err.Error()
+		return r
+	}
+
+	if err := worktree.Create(ctx, base.Repo, []string{wt}); err != nil {
+		return fail(err)
+	}
+	if !keep {
+		defer func() { _ = worktree.Remove(context.Background(), base.Repo, wt) }()
+	}
+	// The todo may be uncommitted in the source repository; every round starts from it.
+	if data, err := os.ReadFile(filepath.Join(base.Repo, base.Todo)); err == nil {
+		if err := os.MkdirAll(filepath.Dir(filepath.Join(wt, base.Todo)), 0o755); err != nil {
+			return fail(err)
+		}
+		if err := os.WriteFile(filepath.Join(wt, base.Todo), data, 0o644); err != nil {
+			return fail(err)
+		}
+	}
+
+	if evalCmd != "" {
+		score, err := bench.Eval(ctx, wt, evalCmd)
+		if err != nil {
+			return fail(err)
+		}
+		r.Before, r.Evaluated = score, true
+	}
+
+	opts := base
+	opts.Repo, opts.AgentType, opts.Supervisor, opts.PrepAgent = wt, t, t, t
+	if err := opts.Validate(); err != nil {
+		return fail(err)
+	}
+	sess, err := session.New(opts)
+	if err != nil {
+		return fail(err)
+	}
+	r.SessionID = sess.ID
+
+	eventCh := make(chan events.Event, 512)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for ev := range eventCh {
+			switch e := ev.(type) {
+			case events.AgentUsage:
+				usage := pricing.Usage{
+					InputTokens:      e.InputTokens,
+					OutputTokens:     e.OutputTokens,
+					CacheReadTokens:  e.CacheReadTokens,
+					CacheWriteTokens: e.CacheWriteTokens,
+					CostUSD:          e.CostUSD,
+				}
+				if cost, ok := prices.Estimate(usage, e.Model, string(t)); ok {
+					r.Cost += cost
+					r.CostKnown = true
+				}
+			case events.TodoProgress:
+				r.TasksDone, r.Tasks = e.Done, e.Total
+			}
+		}
+	}()
+
+	start := time.Now()
+	orch := orchestrator.New(sess, opts, false, eventCh, make(chan control.Command))
+	runErr := orch.Run(ctx)
+	r.Wall = time.Since(start)
+	close(eventCh)
+	<-done
+	if runErr != nil && ctx.Err() == nil {
+		return fail(runErr)
+	}
+	if ctx.Err() != nil {
+		return fail(ctx.Err())
+	}
+
+	if evalCmd != "" {
+		score, err := bench.Eval(ctx, wt, evalCmd)
+		if err != nil {
+			return fail(err)
+		}
+		r.After = score
+	}
+	return r
+}
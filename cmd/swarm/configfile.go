@@ -0,0 +1,66 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"sort"
+
+	"github.com/asynkron/Asynkron.SwarmGo/internal/config"
+)
+
+// applyConfigFile fills the flags that neither the command line nor the environment set,
+// first from the selected profile and then from the config file's defaults. path is the
+// --config value; when empty the default location is used if it exists. The loaded file is
+// returned for its settings that are not flags.
+func applyConfigFile(fs *flag.FlagSet, path, profile string) (config.File, error) {
+	required := path != ""
+	if path == "" {
+		path = config.DefaultFilePath()
+	}
+	file, err := config.LoadFile(path, required)
+	if err != nil {
+		return file, err
+	}
+	if v := file.Defaults["profile"]; profile == "" && len(v) > 0 {
+		profile = v[len(v)-1]
+	}
+
+	type layer struct {
+		source string
+		values map[string]config.Value
+	}
+	var layers []layer
+	if profile != "" {
+		values, err := file.Profile(profile)
+		if err != nil {
+			return file, err
+		}
+		layers = append(layers, layer{source: "profile " + profile, values: values})
+	}
+	layers = append(layers, layer{source: "config file " + path, values: file.Defaults})
+
+	given := map[string]bool{"profile": true, "config": true}
+	fs.Visit(func(f *flag.Flag) { given[f.Name] = true })
+	for _, l := range layers {
+		names := make([]string, 0, len(l.values))
+		for name := range l.values {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			if fs.Lookup(name) == nil {
+				return file, fmt.Errorf("%s: unknown option %q", l.source, name)
+			}
+			if given[name] {
+				continue
+			}
+			for _, v := range l.values[name] {
+				if err := fs.Set(name, v); err != nil {
+					return file, fmt.Errorf("%s: %s=%q: %w", l.source, name, v, err)
+				}
+			}
+			given[name] = true
+		}
+	}
+	return file, nil
+}
@@ -0,0 +1,92 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+
+	"github.com/asynkron/Asynkron.SwarmGo/internal/forge"
+)
+
+// runForge implements `swarm forge <create-pr|list-prs|comment-issue>`, a forge-agnostic
+// front end agents use where no official CLI exists (Bitbucket Cloud). It acts on the
+// repository in the current directory.
+func runForge(args []string) {
+	usage := func() {
+		fmt.Fprintln(os.Stderr, "usage: swarm forge create-pr --title T --body B [--branch NAME] [--base NAME]")
+		fmt.Fprintln(os.Stderr, "       swarm forge list-prs")
+		fmt.Fprintln(os.Stderr, "       swarm forge comment-issue --issue N --body B   (--body - reads stdin)")
+		os.Exit(2)
+	}
+	if len(args) == 0 {
+		usage()
+	}
+	dir, err := os.Getwd()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	f, err := forge.Open(dir)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "forge: %v\n", err)
+		os.Exit(1)
+	}
+	ctx, cancel := signal.NotifyContext(context.Background(), shutdownSignals...)
+	defer cancel()
+
+	fs := flag.NewFlagSet("forge "+args[0], flag.ExitOnError)
+	switch args[0] {
+	case "create-pr":
+		var pr forge.NewPR
+		fs.StringVar(&pr.Title, "title", "", "pull request title")
+		fs.StringVar(&pr.Body, "body", "", "pull request description")
+		fs.StringVar(&pr.Branch, "branch", "", "source branch (default: the current branch)")
+		fs.StringVar(&pr.Base, "base", "", "target branch (default: the repository's default branch)")
+		_ = fs.Parse(args[1:])
+		if pr.Title == "" {
+			usage()
+		}
+		created, err := f.CreatePR(ctx, dir, pr)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "create pull request: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Println(created.URL)
+	case "list-prs":
+		_ = fs.Parse(args[1:])
+		prs, err := f.ListPRs(ctx, dir)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "list pull requests: %v\n", err)
+			os.Exit(1)
+		}
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		_ = enc.Encode(prs)
+	case "comment-issue":
+		issue := fs.Int("issue", 0, "issue number")
+		body := fs.String("body", "", "comment text, or - to read it from stdin")
+		_ = fs.Parse(args[1:])
+		if *issue <= 0 || *body == "" {
+			usage()
+		}
+		text := *body
+		if text == "-" {
+			data, err := io.ReadAll(os.Stdin)
+			if err != nil {
+				fmt.Fprintln(os.Stderr, err)
+				os.Exit(1)
+			}
+			text = string(data)
+		}
+		if err := f.CommentIssue(ctx, dir, *issue, text); err != nil {
+			fmt.Fprintf(os.Stderr, "comment on issue: %v\n", err)
+			os.Exit(1)
+		}
+	default:
+		usage()
+	}
+}
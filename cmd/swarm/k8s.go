@@ -0,0 +1,56 @@
+package main
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+
+	"github.com/asynkron/Asynkron.SwarmGo/internal/kube"
+)
+
+// runK8s implements the hidden `swarm k8s-run` subcommand that workers run under
+// --isolation kubernetes: it runs the agent command after `--` as a Kubernetes Job,
+// streams its output and exits with its exit code.
+func runK8s(args []string) {
+	fs := flag.NewFlagSet("k8s-run", flag.ExitOnError)
+	var job kube.Job
+	fs.StringVar(&job.Namespace, "namespace", "default", "namespace to create the job in")
+	fs.StringVar(&job.Image, "image", "", "image with git and the agent CLI installed")
+	fs.StringVar(&job.Secret, "secret", "", "secret whose keys become the agent's environment")
+	fs.StringVar(&job.Repo, "repo", "", "repository URL the pod clones")
+	fs.StringVar(&job.Ref, "ref", "", "branch or commit the agent's branch is created from")
+	fs.StringVar(&job.Branch, "branch", "", "branch the agent works on")
+	fs.StringVar(&job.Workdir, "workdir", "/work", "path the repository is cloned to")
+	fs.BoolVar(&job.Stdin, "stdin", false, "attach stdin to the agent")
+	label := fs.String("label", "", "KEY=VALUE label put on the job and its pod")
+	_ = fs.Parse(args)
+
+	job.Command = fs.Args()
+	if len(job.Command) == 0 || job.Image == "" || job.Repo == "" || job.Ref == "" {
+		fmt.Fprintln(os.Stderr, "usage: swarm k8s-run --image IMAGE --repo URL --ref REF [flags] -- COMMAND [ARGS...]")
+		os.Exit(2)
+	}
+	if job.Branch == "" {
+		job.Branch = job.Ref
+	}
+	var suffix [3]byte
+	_, _ = rand.Read(suffix[:])
+	job.Name = "swarm-" + hex.EncodeToString(suffix[:])
+	if key, value, ok := strings.Cut(*label, "="); ok {
+		job.Labels = map[string]string{key: value}
+		job.Name = "swarm-" + value + "-" + hex.EncodeToString(suffix[:])
+	}
+
+	ctx, cancel := signal.NotifyContext(context.Background(), shutdownSignals...)
+	code, err := kube.Run(ctx, job, os.Stdin, os.Stdout, os.Stderr)
+	if err != nil && ctx.Err() == nil {
+		fmt.Fprintln(os.Stderr, err)
+	}
+	cancel()
+	os.Exit(code)
+}
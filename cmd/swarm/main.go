@@ -2,29 +2,67 @@ package main
 
 import (
 	"context"
+	"encoding/json"
 	"flag"
 	"fmt"
+	"math/rand/v2"
 	"os"
 	"os/signal"
+	"path/filepath"
 	"strconv"
 	"strings"
 	"sync"
-	"syscall"
+	"time"
 
 	"github.com/asynkron/Asynkron.SwarmGo/internal/config"
 	"github.com/asynkron/Asynkron.SwarmGo/internal/control"
+	"github.com/asynkron/Asynkron.SwarmGo/internal/denylist"
 	"github.com/asynkron/Asynkron.SwarmGo/internal/detector"
+	"github.com/asynkron/Asynkron.SwarmGo/internal/disk"
 	"github.com/asynkron/Asynkron.SwarmGo/internal/events"
 	"github.com/asynkron/Asynkron.SwarmGo/internal/orchestrator"
+	"github.com/asynkron/Asynkron.SwarmGo/internal/preflight"
+	"github.com/asynkron/Asynkron.SwarmGo/internal/redact"
 	"github.com/asynkron/Asynkron.SwarmGo/internal/session"
+	"github.com/asynkron/Asynkron.SwarmGo/internal/sink"
 	"github.com/asynkron/Asynkron.SwarmGo/internal/ui"
 	tea "github.com/charmbracelet/bubbletea"
 )
 
 func main() {
-	opts, supervisorFlag, prepAgentFlag, minutesOverride, minutesSet := parseFlags()
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "replay":
+			runReplay(os.Args[2:])
+			return
+		case "forge":
+			runForge(os.Args[2:])
+			return
+		case "sessions":
+			runSessions(os.Args[2:])
+			return
+		case "bench":
+			runBench(os.Args[2:])
+			return
+		case "mock-agent":
+			runMockAgent(os.Args[2:])
+			return
+		case "k8s-run":
+			runK8s(os.Args[2:])
+			return
+		}
+	}
+
+	opts, supervisorFlag, prepAgentFlag, limit := parseFlags()
 
 	if opts.Detect {
+		if opts.DetectJSON {
+			if err := runDetectJSON(); err != nil {
+				fmt.Fprintf(os.Stderr, "detect: %v\n", err)
+				os.Exit(1)
+			}
+			return
+		}
 		runDetect()
 		return
 	}
@@ -41,21 +79,32 @@ func main() {
 			fmt.Fprintf(os.Stderr, "load session: %v\n", err)
 			os.Exit(1)
 		}
+		until, prices, accessible := opts.Until, opts.Pricing, opts.Accessible
 		opts = sess.Options
-		// Allow overriding minutes on resume to extend/shorten the run.
-		if minutesSet {
-			opts.Minutes = minutesOverride
+		// Allow overriding the time limit on resume to extend/shorten the run.
+		limit.apply(&opts)
+		opts.Until = until
+		// A crashed round continues with the time it had left, unless a new limit is given.
+		if cp, ok, err := sess.LoadCheckpoint(); err == nil && ok && !limit.given() && until == "" {
+			if left := time.Until(cp.Deadline); left > 0 {
+				opts.RunLength = left.Round(time.Second)
+				fmt.Printf("Resuming with %s left (checkpoint of %s)\n", opts.RunLength, cp.Saved.Format("15:04:05"))
+			}
 		}
+		// Prices change; the current config file wins over the session's copy.
+		opts.Pricing = prices
+		// So is how the run is shown.
+		opts.Accessible = accessible
 		resume = true
 	} else {
-		supervisorType, err := parseAgentType(supervisorFlag)
+		supervisorType, err := config.ParseAgentType(supervisorFlag)
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "invalid --supervisor value: %v\n", err)
 			os.Exit(1)
 		}
 		opts.Supervisor = supervisorType
 
-		prepAgent, err := parseAgentType(prepAgentFlag)
+		prepAgent, err := config.ParseAgentType(prepAgentFlag)
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "invalid --prep-agent value: %v\n", err)
 			os.Exit(1)
@@ -63,19 +112,43 @@ func main() {
 		opts.PrepAgent = prepAgent
 	}
 
+	if limit.minutes.set && opts.Until != "" {
+		fmt.Fprintln(os.Stderr, "error: --minutes and --until cannot be combined")
+		os.Exit(1)
+	}
+	if limit.duration.set && opts.Until != "" {
+		fmt.Fprintln(os.Stderr, "error: --duration and --until cannot be combined")
+		os.Exit(1)
+	}
+	if limit.minutes.set && limit.duration.set {
+		fmt.Fprintln(os.Stderr, "error: --minutes and --duration cannot be combined")
+		os.Exit(1)
+	}
+
 	if err := opts.Validate(); err != nil {
 		fmt.Fprintf(os.Stderr, "error: %v\n", err)
 		os.Exit(1)
 	}
+	if _, err := redact.New(opts.RedactPatterns); err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		os.Exit(1)
+	}
+	if _, err := denylist.New(opts.DeniedCommands); err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		os.Exit(1)
+	}
 
-	if !opts.SkipDetect {
-		if err := ensureAgentsInstalled(opts); err != nil {
-			fmt.Fprintln(os.Stderr, err)
-			os.Exit(1)
-		}
+	results := preflight.Run(context.Background(), opts, resume)
+	preflight.Print(os.Stdout, results)
+	if preflight.Failed(results) {
+		fmt.Fprintln(os.Stderr, "preflight failed; fix the problems above and run again")
+		os.Exit(1)
 	}
 
 	if sess == nil {
+		if opts.JitterModels {
+			opts.ModelOffset = rand.IntN(1 << 16)
+		}
 		sess, err = session.New(opts)
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "create session: %v\n", err)
@@ -84,27 +157,71 @@ func main() {
 	}
 
 	eventCh := make(chan events.Event, 512)
+	// Keep eventCh drained whatever the UI is doing; the queue holds events by priority.
+	queued := make(chan events.Event, 512)
+	go events.Queue(eventCh, queued)
+	uiCh := queued
+	recorder, err := events.NewRecorder(sess.EventLogPath())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "warning: event log disabled: %v\n", err)
+		if len(opts.LogSinks) > 0 {
+			fmt.Fprintln(os.Stderr, "warning: log sinks disabled with it")
+		}
+	} else {
+		defer recorder.Close()
+		for _, spec := range opts.LogSinks {
+			parsed, _ := sink.Parse(spec)
+			s, err := sink.Open(parsed, sess.ID)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "warning: %v\n", err)
+				continue
+			}
+			recorder.AddSink(s)
+			defer func() {
+				if err := s.Close(); err != nil {
+					fmt.Fprintf(os.Stderr, "warning: %v\n", err)
+				}
+			}()
+		}
+		recorded := make(chan events.Event, 512)
+		go recorder.Tee(queued, recorded)
+		uiCh = recorded
+	}
+	coalesced := make(chan events.Event, 512)
+	go events.Coalesce(uiCh, coalesced)
+	uiCh = coalesced
 	ctrlCh := make(chan control.Command, 16)
-	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	ctx, cancel := signal.NotifyContext(context.Background(), shutdownSignals...)
 	defer cancel()
 
 	var wg sync.WaitGroup
 	wg.Add(1)
 	go func() {
 		defer wg.Done()
-		orch := orchestrator.New(sess, opts, resume, eventCh, ctrlCh)
-		if err := orch.Run(ctx); err != nil && ctx.Err() == nil {
+		var err error
+		if opts.Arena {
+			err = orchestrator.RunArena(ctx, sess, opts, resume, eventCh, ctrlCh)
+		} else {
+			err = orchestrator.New(sess, opts, resume, eventCh, ctrlCh).Run(ctx)
+		}
+		if err != nil && ctx.Err() == nil {
 			eventCh <- events.StatusMessage{Message: fmt.Sprintf("orchestrator error: %v", err)}
 		}
 		close(eventCh)
 	}()
 
-	program := tea.NewProgram(
-		ui.New(sess, opts, eventCh, ctrlCh),
-		tea.WithAltScreen(),
-		tea.WithMouseCellMotion(),
-		tea.WithContext(ctx),
-	)
+	var program *tea.Program
+	if opts.Accessible {
+		// Printed lines instead of a redrawn screen, so a screen reader can follow along.
+		program = tea.NewProgram(ui.NewAccessible(sess, uiCh, ctrlCh), tea.WithContext(ctx))
+	} else {
+		program = tea.NewProgram(
+			ui.New(sess, opts, uiCh, ctrlCh),
+			tea.WithAltScreen(),
+			tea.WithMouseCellMotion(),
+			tea.WithContext(ctx),
+		)
+	}
 	if _, err := program.Run(); err != nil && ctx.Err() == nil {
 		fmt.Fprintf(os.Stderr, "ui error: %v\n", err)
 	}
@@ -113,45 +230,192 @@ func main() {
 	wg.Wait()
 
 	fmt.Printf("\nSession complete. To resume use: swarm --resume %s\n", sess.ID)
+	if _, err := os.Stat(sess.ReportPath()); err == nil {
+		fmt.Printf("Round report: %s\n", sess.ReportPath())
+	}
+	if _, err := os.Stat(sess.HTMLReportPath()); err == nil {
+		fmt.Printf("HTML report: %s\n", sess.HTMLReportPath())
+	}
+	for _, a := range sess.Artifacts {
+		if a.Kind == session.ArtifactBundle {
+			fmt.Printf("Artifacts: %s\n", a.URL)
+		}
+	}
 }
 
-func parseFlags() (config.Options, string, string, int, bool) {
+func parseFlags() (config.Options, string, string, runLimit) {
 	var opts config.Options
 	var supervisor string
 	var prepAgent string
 	var agentType string
+	var observeAgent string
+	var repos []string
+	var sessionDir string
+	var configPath string
+	var profile string
 	minutesFlag := &intFlag{value: 15}
+	lengthFlag := &durationFlag{}
 
 	flag.IntVar(&opts.ClaudeWorkers, "claude", 0, "number of Claude worker agents")
 	flag.IntVar(&opts.CodexWorkers, "codex", 0, "number of Codex worker agents")
 	flag.IntVar(&opts.CopilotWorkers, "copilot", 0, "number of Copilot worker agents")
 	flag.IntVar(&opts.GeminiWorkers, "gemini", 0, "number of Gemini worker agents")
-	flag.StringVar(&opts.Repo, "repo", "", "path to git repository (defaults to current repo)")
+	flag.IntVar(&opts.MockWorkers, "mock", 0, "number of mock worker agents that replay a script (no CLI or tokens needed)")
+	flag.StringVar(&opts.MockScript, "mock-script", "", "output script mock agents replay (default: built-in demo)")
+	flag.DurationVar(&opts.MockDelay, "mock-delay", 0, "pause after each line a mock agent prints (default 300ms)")
+	flag.Var((*stringsFlag)(&repos), "repo", "path to git repository (defaults to current repo; repeat to spread workers across repositories)")
+	flag.Var((*stringsFlag)(&opts.WorkerNames), "worker-name", "name the next worker, e.g. backend, instead of Worker N; used in the UI, prompts, branch names and reports (repeatable, in worker order)")
 	flag.StringVar(&opts.Todo, "todo", "todo.md", "path to todo file relative to repo")
 	flag.Var(minutesFlag, "minutes", "minutes to run before stopping workers")
-	flag.BoolVar(&opts.Arena, "arena", false, "arena mode (multiple timed rounds)")
+	flag.Var(lengthFlag, "duration", "time to run before stopping workers, e.g. 1h30m (instead of --minutes)")
+	flag.BoolVar(&opts.ExtendOnSleep, "extend-on-sleep", false, "when the machine was suspended during the round, extend the deadline by the time slept instead of offering to")
+	flag.StringVar(&opts.Until, "until", "", "local time to stop workers, e.g. 18:30 (instead of --minutes)")
+	flag.BoolVar(&opts.Arena, "arena", false, "arena mode: timed rounds, each starting from the previous round's merged winner")
 	flag.BoolVar(&opts.Autopilot, "autopilot", true, "autopilot mode (workers create PR branches)")
 	flag.IntVar(&opts.MaxRounds, "max-rounds", 10, "maximum number of rounds in arena mode")
+	flag.IntVar(&opts.MaxStaleRounds, "max-stale-rounds", 0, "end the arena after this many rounds in a row without more tests passing (0 = never)")
+	flag.BoolVar(&opts.StopWhenPassing, "stop-when-passing", false, "end the arena after a round in which a worker's tests all passed")
+	flag.StringVar(&configPath, "config", "", "JSON config file with defaults and profiles (default: <user config dir>/swarmgo/config.json)")
+	flag.StringVar(&profile, "profile", "", "run preset: quick, standard, overnight or one from the config file")
+	flag.StringVar(&sessionDir, "session-dir", "", "directory to keep sessions in (default: <temp>/swarmgo)")
 	flag.StringVar(&opts.Resume, "resume", "", "resume a previous session by its ID")
 	flag.BoolVar(&opts.Detect, "detect", false, "detect installed CLI agents and exit")
+	flag.BoolVar(&opts.DetectJSON, "json", false, "with --detect, print detection results as JSON")
 	flag.BoolVar(&opts.SkipDetect, "skip-detect", false, "skip agent detection")
 	flag.StringVar(&supervisor, "supervisor", "claude", "supervisor agent type (claude|codex|copilot|gemini)")
 	flag.StringVar(&prepAgent, "prep-agent", "claude", "agent type for prep (claude|codex|copilot|gemini)")
 	flag.BoolVar(&opts.AgentMode, "agent", false, "run a single agent directly in the repo (no prep/supervisor)")
 	flag.StringVar(&agentType, "agent-type", "codex", "agent type for --agent mode (claude|codex|copilot|gemini)")
+	flag.Var((*stringsFlag)(&opts.Observe), "observe", "watch a worktree of an agent started by other tooling, as WORKTREE or WORKTREE=LOG, instead of launching workers (repeatable)")
+	flag.StringVar(&observeAgent, "observe-agent", "claude", "CLI whose output format the --observe logs are in (claude|codex|copilot|gemini)")
+	flag.StringVar(&opts.ClaudeModel, "claude-model", "", "model override for Claude agents")
+	flag.StringVar(&opts.CodexModel, "codex-model", "", "model override for Codex agents")
+	flag.StringVar(&opts.CopilotModel, "copilot-model", "", "model override for Copilot agents")
+	flag.StringVar(&opts.GeminiModel, "gemini-model", "", "model override for Gemini agents")
+	flag.Var((*stringsFlag)(&opts.RedactPatterns), "redact", "extra regular expression to mask in agent logs (repeatable)")
+	flag.DurationVar(&opts.StartStagger, "start-stagger", 0, "delay between worker launches, plus up to 50% random jitter (0 starts all at once)")
+	flag.BoolVar(&opts.JitterModels, "jitter-models", false, "randomise which model of each CLI's rotation a worker gets")
+	flag.StringVar(&opts.BaseRef, "base-ref", "", "branch or commit to create worker worktrees from (default: current HEAD)")
+	flag.Var((*stringsFlag)(&opts.SparsePaths), "sparse", "only check out this directory in worktrees, plus top-level files (repeatable; for large repos)")
+	flag.BoolVar(&opts.Submodules, "submodules", false, "run git submodule update --init --recursive in every new worktree")
+	flag.IntVar(&opts.WorktreeJobs, "worktree-jobs", 4, "how many worker worktrees are created and checked out at once")
+	flag.BoolVar(&opts.NoGit, "no-git", false, "run against a plain directory: copy it into per-worker sandboxes and skip git metrics")
+	flag.BoolVar(&opts.KeepWorktrees, "keep-worktrees", false, "keep worker worktrees after a round instead of removing them")
+	flag.Var((*sizeFlag)(&opts.DiskWarn), "disk-warn", "warn when the session directory (worktrees and logs) grows past this size, e.g. 20G (0 disables)")
+	flag.Var((*sizeFlag)(&opts.DiskLimit), "disk-limit", "refuse to create more worktrees once the session directory is this large, e.g. 50G (0 disables)")
+	flag.BoolVar(&opts.Safe, "safe", false, "run agents in their default sandboxes instead of passing permission bypass flags (slower, no unrestricted shell)")
+	flag.BoolVar(&opts.Plan, "plan", false, "planning mode: the prep agent splits the todo into tasks per worker and each worker is told which tasks it owns and in what order")
+	flag.StringVar((*string)(&opts.Reviewer), "reviewer", "", "agent type that reviews each worker branch after the round and writes structured review comments (claude|codex|copilot|gemini; default: no review)")
+	flag.StringVar((*string)(&opts.Tester), "tester", "", "agent type that keeps running the tests against each worker's latest commit and reports regressions to it (claude|codex|copilot|gemini; default: no tester)")
+	flag.StringVar((*string)(&opts.Docs), "docs", "", "agent type that drafts changelog entries and doc updates for the worker branches after the round (claude|codex|copilot|gemini; default: none)")
+	flag.Var((*stringsFlag)(&opts.Roles), "role", "run this role from the config file's \"roles\" section before, during or after the round (repeatable)")
+	flag.StringVar(&opts.DocsBranch, "docs-branch", "", "with --docs, also commit the changelog and doc updates on this branch (created from the workers' starting commit if missing)")
+	flag.BoolVar(&opts.ApprovePRs, "approve-prs", false, "supervised autopilot: workers push their branch and swarm opens each PR only after you approve it in the TUI")
+	flag.BoolVar(&opts.AutoMerge, "auto-merge", false, "after the round, enable squash auto-merge on autopilot PRs with passing checks that touch no files another swarm PR touches")
+	flag.IntVar(&opts.TrackingIssue, "tracking-issue", 0, "GitHub issue number to post periodic progress comments and the final report to (0 disables)")
+	flag.DurationVar(&opts.TrackingInterval, "tracking-interval", 15*time.Minute, "how often to comment on --tracking-issue")
+	flag.DurationVar(&opts.StopGrace, "stop-grace", 10*time.Second, "time a stopped agent gets to exit after SIGTERM before it is killed")
+	flag.IntVar(&opts.StartRetries, "start-retries", 2, "times a failed agent launch is retried before the round is aborted")
+	flag.IntVar(&opts.MaxRestarts, "max-restarts", 3, "times a worker that crashes mid-round is restarted automatically (0 disables)")
+	flag.DurationVar(&opts.StallTimeout, "stall-timeout", 10*time.Minute, "warn when a worker produces no output for this long (0 disables)")
+	flag.StringVar((*string)(&opts.StallAction), "stall-action", string(config.StallWarn), "what to do with a stalled worker: warn, nudge or restart")
+	flag.StringVar((*string)(&opts.TimeboxAction), "timebox-action", string(config.TimeboxNudge), "what to do with a worker over its task's time box: nudge or reassign")
+	flag.StringVar((*string)(&opts.DuplicateAction), "duplicate-action", string(config.DuplicateWarn), "what to do when two workers appear to work on the same todo task: warn or switch")
+	flag.Var((*stringsFlag)(&opts.DeniedCommands), "deny-command", "extra regular expression for shell commands that raise an alert, e.g. 'npm publish' (repeatable)")
+	flag.StringVar((*string)(&opts.DenyAction), "deny-action", string(config.DenyAlert), "what to do with an agent that runs a denied command: alert or stop")
+	flag.StringVar((*string)(&opts.Isolation), "isolation", string(config.IsolationNone), "where workers run: none (on the host), docker (each in its own container that sees only its worktree), devcontainer (each in its worktree's devcontainer) or kubernetes (each agent run as a Kubernetes Job; experimental)")
+	flag.StringVar(&opts.DockerImage, "docker-image", "", "image worker containers are created from under --isolation docker; it must have the agent CLIs installed")
+	flag.StringVar(&opts.KubeImage, "k8s-image", "", "image worker jobs run under --isolation kubernetes; it must have git and the agent CLIs installed")
+	flag.StringVar(&opts.KubeNamespace, "k8s-namespace", "default", "namespace worker jobs are created in under --isolation kubernetes")
+	flag.StringVar(&opts.KubeSecret, "k8s-secret", "", "secret whose keys become the environment of worker jobs, for agent and forge credentials")
+	flag.StringVar(&opts.UploadArtifacts, "upload-artifacts", "", "after the round, upload a tar.gz of logs, snapshots, reports and diffs to s3://BUCKET/PREFIX, gs://BUCKET/PREFIX or az://CONTAINER/PREFIX")
+	flag.Var((*stringsFlag)(&opts.LogSinks), "log-sink", "also stream events and agent output as JSON lines to file:PATH, unix:PATH or an http(s) URL (repeatable)")
+	flag.StringVar((*string)(&opts.Report), "report", string(config.ReportMarkdown), "end-of-round report format: markdown, or html to also write a self-contained report.html")
+	flag.BoolVar(&opts.Accessible, "accessible", false, "screen-reader-friendly output: print each update as a labeled line instead of drawing the TUI (no colours, spinners or boxes)")
+	flag.StringVar(&opts.OpenCommand, "open-command", "", "command the o key runs to open the selected worktree, e.g. 'code -n {worktree}' (default: $VISUAL, $EDITOR or code)")
+	flag.BoolVar(&opts.TimelineInRepo, "timeline-in-repo", false, "also save the end-of-round timeline report to .swarm/reports/<session>.md in the repository")
+	flag.StringVar(&opts.ReportsBranch, "reports-branch", "", "commit the timeline report as .swarm/reports/<session>.md on this branch (created from HEAD if missing)")
 
 	flag.Parse()
+	if err := applyEnv(flag.CommandLine); err != nil {
+		fmt.Fprintf(os.Stderr, "invalid environment: %v\n", err)
+		os.Exit(2)
+	}
+	// Only the command line and environment override the time limit of a resumed session.
+	minutesGiven, durationGiven := minutesFlag.set, lengthFlag.set
+	file, err := applyConfigFile(flag.CommandLine, configPath, profile)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		os.Exit(2)
+	}
+	minutesFlag.set, lengthFlag.set = minutesGiven, durationGiven
+	opts.Pricing = file.Pricing
+	opts.RoleDefs = file.Roles
 
+	if err := setSessionRoot(sessionDir); err != nil {
+		fmt.Fprintf(os.Stderr, "invalid --session-dir: %v\n", err)
+		os.Exit(2)
+	}
 	opts.Minutes = minutesFlag.value
+	opts.RunLength = lengthFlag.value
+	if len(repos) > 0 {
+		opts.Repo = repos[0]
+	}
+	if len(repos) > 1 {
+		opts.Repos = repos
+	}
 	if opts.AgentMode {
-		at, err := parseAgentType(agentType)
+		at, err := config.ParseAgentType(agentType)
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "invalid --agent-type value: %v\n", err)
 			os.Exit(1)
 		}
 		opts.AgentType = at
 	}
-	return opts, supervisor, prepAgent, minutesFlag.value, minutesFlag.set
+	if len(opts.Observe) > 0 {
+		at, err := config.ParseAgentType(observeAgent)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "invalid --observe-agent value: %v\n", err)
+			os.Exit(1)
+		}
+		opts.ObserveAgent = at
+	}
+	return opts, supervisor, prepAgent, runLimit{minutes: *minutesFlag, duration: *lengthFlag}
+}
+
+// runLimit is the time limit given as --minutes or --duration, which may replace the one
+// of a resumed session.
+type runLimit struct {
+	minutes  intFlag
+	duration durationFlag
+}
+
+// given reports whether a time limit was given on the command line or in the environment.
+func (l runLimit) given() bool {
+	return l.minutes.set || l.duration.set
+}
+
+// apply replaces the time limit in opts with the given one, if any.
+func (l runLimit) apply(opts *config.Options) {
+	switch {
+	case l.duration.set:
+		opts.RunLength = l.duration.value
+	case l.minutes.set:
+		opts.Minutes, opts.RunLength = l.minutes.value, 0
+	}
+}
+
+// setSessionRoot points the session store at dir; empty keeps the default.
+func setSessionRoot(dir string) error {
+	if dir == "" {
+		return nil
+	}
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return err
+	}
+	session.Root = abs
+	return nil
 }
 
 // intFlag tracks whether the flag was explicitly set.
@@ -174,19 +438,61 @@ func (f *intFlag) Set(s string) error {
 	return nil
 }
 
-func parseAgentType(value string) (config.AgentType, error) {
-	switch strings.ToLower(value) {
-	case "claude":
-		return config.AgentClaude, nil
-	case "codex":
-		return config.AgentCodex, nil
-	case "copilot":
-		return config.AgentCopilot, nil
-	case "gemini":
-		return config.AgentGemini, nil
-	default:
-		return "", fmt.Errorf("unknown agent %q", value)
+// durationFlag is a time.Duration flag that records whether it was set.
+type durationFlag struct {
+	value time.Duration
+	set   bool
+}
+
+func (f *durationFlag) String() string {
+	if f.value == 0 {
+		return ""
+	}
+	return f.value.String()
+}
+
+func (f *durationFlag) Set(s string) error {
+	v, err := time.ParseDuration(s)
+	if err != nil {
+		return err
+	}
+	if v <= 0 {
+		return fmt.Errorf("duration must be positive")
+	}
+	f.value = v
+	f.set = true
+	return nil
+}
+
+// sizeFlag parses a byte size such as 500M or 20G.
+type sizeFlag int64
+
+func (f *sizeFlag) String() string {
+	if *f == 0 {
+		return "0"
 	}
+	return disk.FormatSize(int64(*f))
+}
+
+func (f *sizeFlag) Set(s string) error {
+	n, err := disk.ParseSize(s)
+	if err != nil {
+		return err
+	}
+	*f = sizeFlag(n)
+	return nil
+}
+
+// stringsFlag collects a repeatable string flag.
+type stringsFlag []string
+
+func (f *stringsFlag) String() string {
+	return strings.Join(*f, ",")
+}
+
+func (f *stringsFlag) Set(s string) error {
+	*f = append(*f, s)
+	return nil
 }
 
 func runDetect() {
@@ -201,7 +507,14 @@ func runDetect() {
 		if version == "" {
 			version = "-"
 		}
-		fmt.Printf("%-8s installed=%-3s version=%s", title(string(s.Type)), installed, version)
+		auth := string(s.Auth)
+		if auth == "" {
+			auth = "-"
+		}
+		fmt.Printf("%-8s installed=%-3s auth=%-13s version=%s", title(string(s.Type)), installed, auth, version)
+		if s.Outdated {
+			fmt.Printf(" (outdated: requires >= %s)", detector.MinimumVersion(s.Type))
+		}
 		if s.Error != "" {
 			fmt.Printf(" (%s)", s.Error)
 		}
@@ -209,39 +522,12 @@ func runDetect() {
 	}
 }
 
-func ensureAgentsInstalled(opts config.Options) error {
+// runDetectJSON prints detection results as a JSON array so provisioning scripts can check readiness.
+func runDetectJSON() error {
 	statuses := detector.DetectAll()
-	required := map[config.AgentType]bool{}
-	if opts.AgentMode {
-		required[opts.AgentType] = true
-	} else {
-		if opts.ClaudeWorkers > 0 {
-			required[config.AgentClaude] = true
-		}
-		if opts.CodexWorkers > 0 {
-			required[config.AgentCodex] = true
-		}
-		if opts.CopilotWorkers > 0 {
-			required[config.AgentCopilot] = true
-		}
-		if opts.GeminiWorkers > 0 {
-			required[config.AgentGemini] = true
-		}
-		required[opts.Supervisor] = true
-		required[opts.PrepAgent] = true
-	}
-
-	missing := []string{}
-	for _, st := range statuses {
-		if required[st.Type] && !st.Installed {
-			missing = append(missing, string(st.Type))
-		}
-	}
-
-	if len(missing) > 0 {
-		return fmt.Errorf("required agents not installed: %s", strings.Join(missing, ", "))
-	}
-	return nil
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	return enc.Encode(statuses)
 }
 
 func title(s string) string {
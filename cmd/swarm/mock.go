@@ -0,0 +1,35 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+
+	"github.com/asynkron/Asynkron.SwarmGo/internal/mock"
+)
+
+// runMockAgent implements the hidden `swarm mock-agent` subcommand that mock agents run:
+// it replays a script to stdout and exits with the code the script asks for.
+func runMockAgent(args []string) {
+	fs := flag.NewFlagSet("mock-agent", flag.ExitOnError)
+	script := fs.String("script", "", "output script to replay (default: built-in demo)")
+	delay := fs.Duration("delay", mock.DefaultDelay, "pause after each printed line")
+	_ = fs.Parse(args)
+
+	r, err := mock.Open(*script)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	ctx, cancel := signal.NotifyContext(context.Background(), shutdownSignals...)
+	code, err := mock.Play(ctx, r, os.Stdout, *delay)
+	if err != nil && ctx.Err() == nil {
+		fmt.Fprintln(os.Stderr, err)
+	}
+	cancel()
+	_ = r.Close()
+	os.Exit(code)
+}
@@ -0,0 +1,115 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"time"
+
+	"github.com/asynkron/Asynkron.SwarmGo/internal/events"
+	"github.com/asynkron/Asynkron.SwarmGo/internal/session"
+	"github.com/asynkron/Asynkron.SwarmGo/internal/ui"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// runReplay implements `swarm replay <session-id>`: it feeds a session's persisted
+// event log back into the TUI, preserving the original pacing scaled by --speed.
+func runReplay(args []string) {
+	fs := flag.NewFlagSet("replay", flag.ExitOnError)
+	speed := fs.Float64("speed", 1, "playback speed multiplier (e.g. 10 = ten times faster)")
+	maxGap := fs.Duration("max-gap", 0, "cap idle gaps between events after scaling (0 = no cap)")
+	sessionDir := fs.String("session-dir", "", "directory sessions are kept in (default: <temp>/swarmgo)")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "usage: swarm replay [--speed N] [--max-gap D] [--session-dir DIR] <session-id>")
+		fs.PrintDefaults()
+	}
+	// Allow flags after the session id as well as before it.
+	var id string
+	for len(args) > 0 {
+		_ = fs.Parse(args)
+		args = fs.Args()
+		if len(args) == 0 {
+			break
+		}
+		if id != "" {
+			fs.Usage()
+			os.Exit(2)
+		}
+		id, args = args[0], args[1:]
+	}
+	if err := applyEnv(fs); err != nil {
+		fmt.Fprintf(os.Stderr, "invalid environment: %v\n", err)
+		os.Exit(2)
+	}
+	if id == "" || *speed <= 0 {
+		fs.Usage()
+		os.Exit(2)
+	}
+	if err := setSessionRoot(*sessionDir); err != nil {
+		fmt.Fprintf(os.Stderr, "invalid --session-dir: %v\n", err)
+		os.Exit(2)
+	}
+
+	sess, err := session.Load(id)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "load session: %v\n", err)
+		os.Exit(1)
+	}
+	records, err := events.ReadLog(sess.EventLogPath())
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	if len(records) == 0 {
+		fmt.Fprintf(os.Stderr, "session %s has no recorded events\n", id)
+		os.Exit(1)
+	}
+
+	ctx, cancel := signal.NotifyContext(context.Background(), shutdownSignals...)
+	defer cancel()
+
+	eventCh := make(chan events.Event, 512)
+	go playback(ctx, records, *speed, *maxGap, eventCh)
+	uiCh := make(chan events.Event, 512)
+	go events.Coalesce(eventCh, uiCh)
+
+	program := tea.NewProgram(
+		ui.NewReplay(sess, sess.Options, uiCh),
+		tea.WithAltScreen(),
+		tea.WithMouseCellMotion(),
+		tea.WithContext(ctx),
+	)
+	if _, err := program.Run(); err != nil && ctx.Err() == nil {
+		fmt.Fprintf(os.Stderr, "ui error: %v\n", err)
+	}
+}
+
+func playback(ctx context.Context, records []events.Record, speed float64, maxGap time.Duration, out chan<- events.Event) {
+	defer close(out)
+	prev := records[0].Time
+	for _, rec := range records {
+		gap := time.Duration(float64(rec.Time.Sub(prev)) / speed)
+		if maxGap > 0 && gap > maxGap {
+			gap = maxGap
+		}
+		prev = rec.Time
+		if gap > 0 {
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(gap):
+			}
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case out <- rec.Event:
+		}
+	}
+	select {
+	case <-ctx.Done():
+	case out <- events.StatusMessage{Message: fmt.Sprintf("Replay complete (%d events)", len(records))}:
+	}
+}
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/asynkron/Asynkron.SwarmGo/internal/session"
+)
+
+// runSessions implements `swarm sessions`: it lists stored sessions with the pull requests
+// and issues recorded for each, optionally only those linked to a given artifact.
+func runSessions(args []string) {
+	fs := flag.NewFlagSet("sessions", flag.ExitOnError)
+	sessionDir := fs.String("session-dir", "", "directory sessions are kept in (default: <temp>/swarmgo)")
+	artifact := fs.String("artifact", "", "only list sessions whose pull requests or issues contain this text (e.g. a PR URL or \"pull/42\")")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "usage: swarm sessions [--session-dir DIR] [--artifact TEXT]")
+		fs.PrintDefaults()
+	}
+	_ = fs.Parse(args)
+	if fs.NArg() > 0 {
+		fs.Usage()
+		os.Exit(2)
+	}
+	if err := applyEnv(fs); err != nil {
+		fmt.Fprintf(os.Stderr, "invalid environment: %v\n", err)
+		os.Exit(2)
+	}
+	if err := setSessionRoot(*sessionDir); err != nil {
+		fmt.Fprintf(os.Stderr, "invalid --session-dir: %v\n", err)
+		os.Exit(2)
+	}
+
+	list, err := session.List()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	shown := 0
+	for _, sess := range list {
+		artifacts := sess.Artifacts
+		if *artifact != "" {
+			artifacts = matchArtifacts(artifacts, *artifact)
+			if len(artifacts) == 0 {
+				continue
+			}
+		}
+		shown++
+		fmt.Printf("%s  %s  %s\n", sess.ID, sess.Created.Local().Format(time.DateTime), strings.Join(sess.Options.Repositories(), ", "))
+		for _, a := range artifacts {
+			fmt.Printf("    %-6s %s", a.Kind, a.URL)
+			if a.Agent != "" {
+				fmt.Printf("  (%s)", a.Agent)
+			}
+			fmt.Println()
+		}
+	}
+	if shown == 0 {
+		fmt.Fprintf(os.Stderr, "no sessions found in %s\n", session.Root)
+	}
+}
+
+func matchArtifacts(list []session.Artifact, text string) []session.Artifact {
+	var out []session.Artifact
+	for _, a := range list {
+		if strings.Contains(a.URL, text) {
+			out = append(out, a)
+		}
+	}
+	return out
+}
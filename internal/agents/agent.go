@@ -8,37 +8,79 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
-	"regexp"
 	"strings"
 	"sync"
+	"syscall"
 	"time"
 
+	"github.com/asynkron/Asynkron.SwarmGo/internal/denylist"
 	"github.com/asynkron/Asynkron.SwarmGo/internal/events"
+	"github.com/asynkron/Asynkron.SwarmGo/internal/redact"
+	"github.com/asynkron/Asynkron.SwarmGo/internal/termtext"
 )
 
 // Agent represents a running CLI process and streams its output to the UI.
 type Agent struct {
-	ID       string
-	Name     string
-	Prompt   string
-	Workdir  string
-	LogPath  string
-	Model    string
-	Display  string
-	CLI      CLI
+	ID      string
+	Name    string
+	Prompt  string
+	Workdir string
+	LogPath string
+	Model   string
+	Display string
+	CLI     CLI
+	// StopGrace is how long Stop waits after SIGTERM before killing the process.
+	// Zero means DefaultStopGrace.
+	StopGrace time.Duration
+	// Redactor masks secrets in output before it reaches the log file and the UI.
+	Redactor *redact.Redactor
+	// Commands receives the shell commands the agent runs; nil disables the audit.
+	Commands *CommandLog
+	// Denylist flags dangerous commands; each match is sent to Denials without blocking.
+	Denylist *denylist.List
+	Denials  chan<- Denial
+	// TestReports receives the tester agent's result lines; nil for every other agent.
+	TestReports chan<- TestReport
+	// Tells receives the messages the agent addresses to another agent with
+	// `tell <agent> "..."`; nil disables them.
+	Tells    chan<- Tell
 	events   chan<- events.Event
 	done     chan struct{}
 	lastExit int
 	restarts int
+	stopped  bool
+	// lastOutput is when the process last wrote to stdout or stderr.
+	lastOutput time.Time
+	// exited is set once Wait has returned for the current process.
+	exited bool
+	// rateLimited is set while the output shows a provider rate limit; rateLimitedUntil is
+	// the end of the cooldown it advertised, if any.
+	rateLimited      bool
+	rateLimitedUntil time.Time
+	// quotaSeen holds the usage cap notices already reported.
+	quotaSeen map[string]bool
+	// claim is the task the worker last announced and claimedAt when.
+	claim     string
+	claimedAt time.Time
+	// lastTell is the last addressed message detected, to skip its echo.
+	lastTell string
+	// liveFrom is the log size when this run started; the tail treats earlier lines as
+	// history and keeps them out of the command audit.
+	liveFrom int64
 
 	cmd             *exec.Cmd
+	pid             int
 	logFile         *os.File
 	mu              sync.Mutex
 	tailCancel      context.CancelFunc
 	tailWG          sync.WaitGroup
+	streamWG        sync.WaitGroup
+	input           io.WriteCloser
+	inputMu         sync.Mutex
 	isSupervisor    bool
 	workerWorktrees []string
 	workerLogPaths  []string
+	workerNames     []string
 }
 
 // Start launches the agent process and begins streaming output.
@@ -60,6 +102,15 @@ func (a *Agent) Start(ctx context.Context) error {
 		return fmt.Errorf("create log: %w", err)
 	}
 	a.logFile = logFile
+	started := false
+	defer func() {
+		// A failed start releases the log so Start can be retried.
+		if !started {
+			_ = logFile.Close()
+			a.logFile = nil
+			a.done = nil
+		}
+	}()
 
 	if info, err := logFile.Stat(); err == nil && info.Size() > 0 {
 		_, _ = fmt.Fprintln(a.logFile)
@@ -71,18 +122,34 @@ func (a *Agent) Start(ctx context.Context) error {
 	args := a.CLI.BuildArgs(a.Prompt, a.Model)
 	cmd := exec.CommandContext(ctx, a.CLI.Command(), args...)
 	cmd.Dir = a.Workdir
+	setProcessGroup(cmd)
+	// Context cancellation gets the same SIGTERM-then-SIGKILL treatment as Stop.
+	cmd.Cancel = func() error {
+		if box, ok := capability[signaler](a.CLI); ok {
+			_ = box.signal("TERM")
+		}
+		return signalGroup(cmd.Process.Pid, syscall.SIGTERM)
+	}
+	cmd.WaitDelay = a.stopGrace()
 
-	_, _ = fmt.Fprintf(a.logFile, "[%s] command: %s %s\n\n", time.Now().Format(time.RFC3339), a.CLI.Command(), strings.Join(args, " "))
+	_, _ = fmt.Fprintf(a.logFile, "[%s] command: %s %s\n\n", time.Now().Format(time.RFC3339), a.CLI.Command(), a.Redactor.Line(strings.Join(args, " ")))
+	if info, err := a.logFile.Stat(); err == nil {
+		a.liveFrom = info.Size()
+	}
 
 	if a.CLI.UseStdin() {
 		stdin, err := cmd.StdinPipe()
 		if err != nil {
 			return fmt.Errorf("stdin pipe: %w", err)
 		}
-		go func() {
-			_, _ = io.WriteString(stdin, a.Prompt)
-			_ = stdin.Close()
-		}()
+		if conv, ok := asConversational(a.CLI); ok {
+			a.openInput(stdin, conv)
+		} else {
+			go func() {
+				_, _ = io.WriteString(stdin, a.Prompt)
+				_ = stdin.Close()
+			}()
+		}
 	}
 
 	stdout, err := cmd.StdoutPipe()
@@ -95,10 +162,15 @@ func (a *Agent) Start(ctx context.Context) error {
 	}
 
 	if err := cmd.Start(); err != nil {
+		_, _ = fmt.Fprintf(a.logFile, "[%s] start failed: %v\n", time.Now().Format(time.RFC3339), err)
 		return fmt.Errorf("start agent: %w", err)
 	}
 
+	started = true
 	a.cmd = cmd
+	a.pid = cmd.Process.Pid
+	a.exited = false
+	a.lastOutput = time.Now()
 	display := a.Display
 	if display == "" {
 		display = a.Model
@@ -111,6 +183,7 @@ func (a *Agent) Start(ctx context.Context) error {
 		LogPath:  a.LogPath,
 		Worktree: a.Workdir,
 		Running:  true,
+		Restarts: a.restarts,
 	}
 	a.emit(added)
 	// Log the add to help debug missing agents in the UI.
@@ -123,6 +196,7 @@ func (a *Agent) Start(ctx context.Context) error {
 	a.tailWG.Add(1)
 	go a.tailFile(tailCtx)
 
+	a.streamWG.Add(2)
 	go a.stream(stdout)
 	go a.stream(stderr)
 	go a.wait(ctx)
@@ -130,32 +204,90 @@ func (a *Agent) Start(ctx context.Context) error {
 	return nil
 }
 
-// Stop terminates the process.
+// DefaultStopGrace is used when an agent has no StopGrace configured.
+const DefaultStopGrace = 10 * time.Second
+
+// Stop asks the CLI to exit with SIGTERM so it can flush partial output and transcripts,
+// and only kills it if it is still running once the grace period has elapsed.
 func (a *Agent) Stop() {
 	a.mu.Lock()
-	defer a.mu.Unlock()
+	cmd := a.cmd
+	pid := a.pid
+	done := a.done
+	a.stopped = true
+	a.mu.Unlock()
 
-	if a.cmd == nil || a.cmd.Process == nil {
+	if cmd == nil {
 		return
 	}
-	_ = a.cmd.Process.Kill()
+	if box, ok := capability[signaler](a.CLI); ok {
+		// The docker exec client doesn't forward signals; stop the agent inside the container.
+		_ = box.signal("TERM")
+		select {
+		case <-done:
+		case <-time.After(a.stopGrace()):
+			_ = box.signal("KILL")
+		}
+	}
+	terminate(pid, done, a.stopGrace())
 
-	if a.tailCancel != nil {
-		a.tailCancel()
+	a.mu.Lock()
+	cancel := a.tailCancel
+	a.mu.Unlock()
+	if cancel != nil {
+		cancel()
 	}
 	a.tailWG.Wait()
 }
 
+func (a *Agent) stopGrace() time.Duration {
+	if a.StopGrace > 0 {
+		return a.StopGrace
+	}
+	return DefaultStopGrace
+}
+
+// terminate sends SIGTERM to the agent's process group and waits up to grace for it to
+// exit, then kills whatever is left of the group so spawned builds and watchers don't
+// outlive the agent. Platforms without SIGTERM support are killed immediately.
+func terminate(pid int, done <-chan struct{}, grace time.Duration) {
+	if err := signalGroup(pid, syscall.SIGTERM); err != nil {
+		killGroup(pid)
+		return
+	}
+	select {
+	case <-done:
+	case <-time.After(grace):
+	}
+	killGroup(pid)
+}
+
 func (a *Agent) stream(r io.Reader) {
+	defer a.streamWG.Done()
+	conv, _ := asConversational(a.CLI)
 	scanner := bufio.NewScanner(r)
 	for scanner.Scan() {
 		line := scanner.Text()
-		_, _ = a.logFile.WriteString(line + "\n")
+		a.mu.Lock()
+		a.lastOutput = time.Now()
+		a.mu.Unlock()
+		_, _ = a.logFile.WriteString(a.Redactor.Line(line) + "\n")
+		a.emitUsage(line)
+		a.detectQuota(line)
+		if conv != nil && conv.IsTurnEnd(line) {
+			a.closeInput()
+		}
 	}
 }
 
 func (a *Agent) wait(ctx context.Context) {
 	err := a.cmd.Wait()
+	a.mu.Lock()
+	a.exited = true
+	a.mu.Unlock()
+	// Wait closes the pipes; let the readers finish logging what they already got.
+	a.streamWG.Wait()
+	a.closeInput()
 	exit := 0
 	if err != nil {
 		if exitErr, ok := err.(*exec.ExitError); ok {
@@ -205,20 +337,21 @@ func (a *Agent) emit(ev events.Event) {
 		return
 	}
 	defer func() { _ = recover() }()
-	switch ev.(type) {
-	case events.AgentAdded:
-		// Agent presence is critical; block rather than drop.
+	if !events.Droppable(ev) {
+		// Stops, status and the like must reach the UI; block rather than drop.
 		a.events <- ev
+		return
+	}
+	select {
+	case a.events <- ev:
 	default:
-		select {
-		case a.events <- ev:
-		default:
-			// Drop if channel is full to keep agents flowing.
-		}
+		// Drop output if the channel is full to keep agents flowing.
 	}
 }
 
 // tailFile streams appended log content to the UI, similar to the original C# message stream.
+// Once ctx is cancelled it reads on to the end of the log, so the agent's last lines are
+// handled before Done closes.
 func (a *Agent) tailFile(ctx context.Context) {
 	defer a.tailWG.Done()
 
@@ -238,24 +371,29 @@ func (a *Agent) tailFile(ctx context.Context) {
 		}
 
 		reader := bufio.NewReader(f)
+		var pos int64
 		if info, _ := f.Stat(); info != nil && info.Size() > tailBytes {
-			_, _ = f.Seek(-tailBytes, io.SeekEnd)
+			pos, _ = f.Seek(-tailBytes, io.SeekEnd)
 			reader = bufio.NewReader(f)
-			_, _ = reader.ReadString('\n') // drop partial line
+			partial, _ := reader.ReadString('\n') // drop partial line
+			pos += int64(len(partial))
 		}
+		var commands commandTracker
+		draining := false
 
 		for {
 			select {
 			case <-ctx.Done():
-				_ = f.Close()
-				return
+				draining = true
 			default:
 			}
 
 			line, err := reader.ReadString('\n')
+			live := pos >= a.liveFrom
+			pos += int64(len(line))
 			if line != "" {
 				trimmed := strings.TrimRight(line, "\r\n")
-				clean := cleanLine(trimmed)
+				clean := termtext.Clean(trimmed)
 				if strings.TrimSpace(clean) == "" {
 					continue
 				}
@@ -264,6 +402,17 @@ func (a *Agent) tailFile(ctx context.Context) {
 					continue
 				}
 				for _, msg := range msgs {
+					if cmd, ok := commands.command(a.CLI, msg); ok && live {
+						a.Commands.Record(time.Now(), a.ID, a.Workdir, cmd)
+						a.checkDenied(cmd)
+						a.detectTells(cmd)
+					}
+					if live && msg.Kind == events.MessageSay {
+						a.detectRateLimit(msg.Text)
+						a.detectClaim(msg.Text)
+						a.detectTestReports(msg.Text)
+						a.detectTells(msg.Text)
+					}
 					if a.isSupervisor {
 						// Skip See/Do noise; summarize activity instead.
 						if msg.Kind == events.MessageSee {
@@ -294,6 +443,10 @@ func (a *Agent) tailFile(ctx context.Context) {
 				continue
 			}
 			if err == io.EOF {
+				if draining {
+					_ = f.Close()
+					return
+				}
 				// Wait for more data in same file.
 				time.Sleep(50 * time.Millisecond)
 				continue
@@ -306,29 +459,20 @@ func (a *Agent) tailFile(ctx context.Context) {
 	}
 }
 
-var ansiRegexp = regexp.MustCompile(`\x1b\[[0-9;?]*[ -/]*[@-~]`)
-
-func cleanLine(input string) string {
-	stripped := ansiRegexp.ReplaceAllString(input, "")
-	var b strings.Builder
-	for _, r := range stripped {
-		switch r {
-		case '\t':
-			b.WriteString("    ")
-		default:
-			if r >= 32 {
-				b.WriteRune(r)
-			}
-		}
+// workerName returns the --worker-name of worker index i for the supervisor's activity
+// lines, else "Worker N".
+func (a *Agent) workerName(i int) string {
+	if i < len(a.workerNames) {
+		return a.workerNames[i]
 	}
-	return b.String()
+	return fmt.Sprintf("Worker %d", i+1)
 }
 
 func (a *Agent) supervisorSummary(text string) string {
 	lower := strings.ToLower(text)
 	for i, path := range a.workerLogPaths {
 		if strings.Contains(text, path) {
-			return fmt.Sprintf("📜 Reading logs for Worker %d", i+1)
+			return fmt.Sprintf("📜 Reading logs for %s", a.workerName(i))
 		}
 	}
 
@@ -336,28 +480,29 @@ func (a *Agent) supervisorSummary(text string) string {
 		if !strings.Contains(text, wt) {
 			continue
 		}
+		name := a.workerName(i)
 
 		switch {
 		case strings.Contains(lower, "git status"):
-			return fmt.Sprintf("🔍 Checking git status for Worker %d", i+1)
+			return fmt.Sprintf("🔍 Checking git status for %s", name)
 		case strings.Contains(lower, "git diff"):
-			return fmt.Sprintf("📄 Checking git diff for Worker %d", i+1)
+			return fmt.Sprintf("📄 Checking git diff for %s", name)
 		case strings.Contains(lower, "git log"):
-			return fmt.Sprintf("🧭 Checking git log for Worker %d", i+1)
+			return fmt.Sprintf("🧭 Checking git log for %s", name)
 		case strings.Contains(lower, "git cherry-pick"):
-			return fmt.Sprintf("🍒 Cherry-picking commits for Worker %d", i+1)
+			return fmt.Sprintf("🍒 Cherry-picking commits for %s", name)
 		case strings.Contains(lower, "git merge"):
-			return fmt.Sprintf("🧵 Merging changes for Worker %d", i+1)
+			return fmt.Sprintf("🧵 Merging changes for %s", name)
 		case strings.Contains(lower, "glob"):
-			return fmt.Sprintf("🔎 Searching files for Worker %d", i+1)
+			return fmt.Sprintf("🔎 Searching files for %s", name)
 		case strings.Contains(lower, "grep"):
-			return fmt.Sprintf("🔎 Searching code for Worker %d", i+1)
+			return fmt.Sprintf("🔎 Searching code for %s", name)
 		case strings.Contains(lower, "test"):
-			return fmt.Sprintf("🧪 Running tests for Worker %d", i+1)
+			return fmt.Sprintf("🧪 Running tests for %s", name)
 		case strings.Contains(lower, "read"):
-			return fmt.Sprintf("📖 Reading file for Worker %d", i+1)
+			return fmt.Sprintf("📖 Reading file for %s", name)
 		default:
-			return fmt.Sprintf("👀 Inspecting for Worker %d", i+1)
+			return fmt.Sprintf("👀 Inspecting for %s", name)
 		}
 	}
 
@@ -371,6 +516,30 @@ func (a *Agent) Done() <-chan struct{} {
 	return a.done
 }
 
+// LastOutput returns when the running process last produced output (its start time if it
+// has been silent so far).
+func (a *Agent) LastOutput() time.Time {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.lastOutput
+}
+
+// Alive reports whether the agent's process is running: it was started and Wait has not
+// returned for it yet. Unlike probing the pid, this is false for an exited process that
+// was not reaped yet, and never mistakes a reused pid for the agent.
+func (a *Agent) Alive() bool {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.cmd != nil && !a.exited
+}
+
+// Stopped reports whether Stop was called, as opposed to the process exiting on its own.
+func (a *Agent) Stopped() bool {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.stopped
+}
+
 // ExitCode returns the last recorded exit code after the agent stops.
 func (a *Agent) ExitCode() int {
 	a.mu.Lock()
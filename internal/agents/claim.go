@@ -0,0 +1,34 @@
+package agents
+
+import (
+	"regexp"
+	"strings"
+	"time"
+)
+
+// claimLine matches the line workers are asked to print when they start a task, e.g.
+// "TASK: Fix the login redirect", allowing markdown emphasis around it.
+var claimLine = regexp.MustCompile(`(?m)^[\s*_>-]*TASK:[*_]*\s*(.+?)[\s*_]*$`)
+
+// detectClaim records the task a worker announced in a live message.
+func (a *Agent) detectClaim(text string) {
+	matches := claimLine.FindAllStringSubmatch(text, -1)
+	if matches == nil {
+		return
+	}
+	task := strings.TrimSpace(matches[len(matches)-1][1])
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if task == a.claim {
+		return
+	}
+	a.claim = task
+	a.claimedAt = time.Now()
+}
+
+// Claim returns the task the worker last announced and when, or "" if it announced none.
+func (a *Agent) Claim() (task string, since time.Time) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.claim, a.claimedAt
+}
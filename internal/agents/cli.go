@@ -2,11 +2,14 @@ package agents
 
 import (
 	"encoding/json"
-	"fmt"
+	"math"
+	"slices"
 	"strings"
 
+	"github.com/asynkron/Asynkron.SwarmGo/agentrunner"
 	"github.com/asynkron/Asynkron.SwarmGo/internal/config"
 	"github.com/asynkron/Asynkron.SwarmGo/internal/events"
+	"github.com/asynkron/Asynkron.SwarmGo/internal/termtext"
 )
 
 // CLI abstracts how to invoke each agent executable.
@@ -31,319 +34,204 @@ type SupervisorModeler interface {
 	SupervisorModel() (apiModel string, display string)
 }
 
-// NewCLI returns an implementation for the given agent type.
+// SupervisorModel returns the model cli uses for the supervisor and its helper agents,
+// looking through wrappers such as WithSafeMode. CLIs without a supervisor model fall
+// back to Model(index).
+func SupervisorModel(cli CLI, index int) (apiModel string, display string) {
+	if sm, ok := capability[SupervisorModeler](cli); ok {
+		return sm.SupervisorModel()
+	}
+	return cli.Model(index)
+}
+
+// NewCLI returns an implementation for the given agent type. Every type but mock is
+// built from the agentrunner registry, so a registered adapter replaces a built-in one;
+// the built-in names then get swarm's extra capabilities on top. Unknown types fall back
+// to Codex.
 func NewCLI(agent config.AgentType) CLI {
+	if agent == config.AgentMock {
+		return MockCLI("", 0)
+	}
+	base, err := agentrunner.NewCLIByName(string(agent))
+	if err != nil {
+		agent, base = config.AgentCodex, agentrunner.CodexCLI()
+	}
+	cli := runnerCLI{CLI: base}
 	switch agent {
 	case config.AgentClaude:
-		return claudeCLI{}
+		return claudeCLI{cli}
 	case config.AgentCodex:
-		return &codexCLI{}
+		return codexCLI{cli}
 	case config.AgentCopilot:
-		return copilotCLI{}
+		return copilotCLI{cli}
 	case config.AgentGemini:
-		return geminiCLI{}
+		return geminiCLI{cli}
 	default:
-		return &codexCLI{}
+		return cli
 	}
 }
 
-type codexCLI struct {
-	doMode bool
+// WithModelOffset shifts the model rotation of cli by offset, so workers get a different
+// model than their index alone would pick. A zero offset returns cli unchanged. The
+// supervisor model is not rotated; SupervisorModel finds it through the wrapper.
+func WithModelOffset(cli CLI, offset int) CLI {
+	if offset == 0 {
+		return cli
+	}
+	return modelOffset{CLI: cli, offset: offset}
 }
 
-func (*codexCLI) Name() string    { return "Codex" }
-func (*codexCLI) Command() string { return "codex" }
-func (*codexCLI) UseStdin() bool  { return false }
-func (*codexCLI) SupervisorModel() (string, string) {
-	return "gpt-5.1-codex-mini", "5.1-mini"
+type modelOffset struct {
+	CLI
+	offset int
 }
-func (*codexCLI) Model(i int) (string, string) {
-	models := []string{"gpt-5.2-codex", "gpt-5.1-codex-max", "gpt-5.2"}
-	short := []string{"5.2-cdx", "5.1-max", "5.2"}
-	idx := i % len(models)
-	return models[idx], short[idx]
+
+func (m modelOffset) Model(i int) (string, string) {
+	// Keep the index non-negative for CLIs that use it modulo their model list.
+	return m.CLI.Model((i + m.offset) & math.MaxInt)
 }
-func (*codexCLI) BuildArgs(prompt string, model string) []string {
-	args := []string{"exec", prompt, "--skip-git-repo-check", "--dangerously-bypass-approvals-and-sandbox"}
-	if model != "" {
-		args = append(args, "--model", model)
+
+// Unwrap exposes the wrapped CLI so optional capabilities can still be detected.
+func (m modelOffset) Unwrap() CLI { return m.CLI }
+
+// WithModel overrides the model a CLI picks for every agent. An empty model returns cli unchanged.
+func WithModel(cli CLI, model string) CLI {
+	if model == "" {
+		return cli
 	}
-	return args
+	return modelOverride{CLI: cli, model: model}
 }
-func (c *codexCLI) Parse(line string) []ParsedMessage {
-	if strings.TrimSpace(line) == "" {
-		return nil
-	}
-	clean := stripANSI(line)
-	trim := strings.TrimSpace(clean)
-	switch trim {
-	case "thinking":
-		c.doMode = false
-		return []ParsedMessage{{Kind: events.MessageSay, Text: "[thinking]"}}
-	case "exec":
-		c.doMode = true
-		return []ParsedMessage{{Kind: events.MessageDo, Text: "[exec]"}}
-	default:
-		kind := codexClassify(trim)
-		if c.doMode {
-			kind = events.MessageDo
-		}
-		return []ParsedMessage{{Kind: kind, Text: clean}}
-	}
+
+type modelOverride struct {
+	CLI
+	model string
 }
 
-func stripANSI(s string) string {
-	if strings.IndexByte(s, '\x1b') == -1 {
-		return s
-	}
-	return ansiRegexp.ReplaceAllString(s, "")
+func (m modelOverride) Model(int) (string, string) { return m.model, m.model }
+
+// SupervisorModel keeps an explicit model ahead of the wrapped CLI's supervisor model.
+func (m modelOverride) SupervisorModel() (string, string) { return m.model, m.model }
+
+// Unwrap exposes the wrapped CLI so optional capabilities can still be detected.
+func (m modelOverride) Unwrap() CLI { return m.CLI }
+
+// safeArgs maps each CLI's permission bypass flag to its sandboxed equivalent: file edits
+// in the working directory are allowed, anything else follows the CLI's default policy.
+var safeArgs = map[string][]string{
+	"--dangerously-skip-permissions":             {"--permission-mode", "acceptEdits"},
+	"--dangerously-bypass-approvals-and-sandbox": {"--full-auto"},
+	"--yolo": {"--approval-mode", "auto_edit"},
 }
 
-func codexClassify(trim string) events.AgentMessageKind {
-	lower := strings.ToLower(trim)
-	switch {
-	case strings.HasPrefix(trim, "$ "):
-		return events.MessageDo
-	case strings.HasPrefix(lower, "stdout:"):
-		return events.MessageSee
-	case strings.HasPrefix(lower, "stderr:"):
-		return events.MessageSee
-	case strings.HasPrefix(lower, "exit code"):
-		return events.MessageSee
-	case strings.HasPrefix(lower, "result:"):
-		return events.MessageSee
-	case strings.HasPrefix(lower, "output:"):
-		return events.MessageSee
-	default:
-		return events.MessageSay
-	}
+// WithSafeMode replaces the permission bypass flags in cli's arguments with the CLI's
+// sandboxed defaults. Agents make slower progress but no longer get unrestricted shell.
+func WithSafeMode(cli CLI) CLI {
+	return safeMode{CLI: cli}
 }
 
-type claudeCLI struct{}
+type safeMode struct {
+	CLI
+}
 
-func (claudeCLI) Name() string               { return "Claude" }
-func (claudeCLI) Command() string            { return "claude" }
-func (claudeCLI) UseStdin() bool             { return true }
-func (claudeCLI) Model(int) (string, string) { return "opus", "opus" }
-func (claudeCLI) BuildArgs(prompt string, model string) []string {
-	args := []string{"-p", "--dangerously-skip-permissions", "--tools", "default", "--output-format", "stream-json", "--verbose"}
-	if model != "" {
-		args = append(args, "--model", model)
+func (s safeMode) BuildArgs(prompt string, model string) []string {
+	var args []string
+	for _, arg := range s.CLI.BuildArgs(prompt, model) {
+		if safe, ok := safeArgs[arg]; ok {
+			args = append(args, safe...)
+			continue
+		}
+		args = append(args, arg)
 	}
-	// Bubble Tea provides its own prompt injection; Claude reads from stdin.
 	return args
 }
-func (claudeCLI) Parse(line string) []ParsedMessage {
-	if strings.TrimSpace(line) == "" {
-		return nil
-	}
 
-	var root map[string]any
-	if err := json.Unmarshal([]byte(line), &root); err != nil {
-		return []ParsedMessage{{Kind: events.MessageSay, Text: line}}
-	}
+// Unwrap exposes the wrapped CLI so optional capabilities can still be detected.
+func (s safeMode) Unwrap() CLI { return s.CLI }
 
-	typ, _ := root["type"].(string)
-	switch typ {
-	case "assistant":
-		return parseClaudeAssistant(root)
-	case "user":
-		return parseClaudeToolResult(root)
-	case "result":
-		if result, ok := root["result"].(string); ok && strings.TrimSpace(result) != "" {
-			return []ParsedMessage{{Kind: events.MessageSay, Text: result}}
+// capability finds an optional interface on cli, looking through wrappers such as WithModel.
+func capability[T any](cli CLI) (T, bool) {
+	for {
+		if c, ok := cli.(T); ok {
+			return c, true
+		}
+		w, ok := cli.(interface{ Unwrap() CLI })
+		if !ok {
+			var zero T
+			return zero, false
 		}
+		cli = w.Unwrap()
 	}
-	return nil
 }
 
-type copilotCLI struct{}
-
-func (copilotCLI) Name() string               { return "Copilot" }
-func (copilotCLI) Command() string            { return "copilot" }
-func (copilotCLI) UseStdin() bool             { return false }
-func (copilotCLI) Model(int) (string, string) { return "gpt-5", "gpt-5" }
-func (copilotCLI) BuildArgs(prompt string, model string) []string {
-	if model == "" {
-		model = "gpt-5"
-	}
-	return []string{"-p", prompt, "--allow-all-tools", "--allow-all-paths", "--stream", "on", "--model", model}
-}
-func (copilotCLI) Parse(line string) []ParsedMessage {
-	if strings.TrimSpace(line) == "" {
-		return nil
-	}
-	return []ParsedMessage{{Kind: events.MessageSay, Text: line}}
+// codexCLI adds the supervisor model to the registered Codex adapter.
+type codexCLI struct {
+	runnerCLI
 }
 
-type geminiCLI struct{}
-
-func (geminiCLI) Name() string               { return "Gemini" }
-func (geminiCLI) Command() string            { return "gemini" }
-func (geminiCLI) UseStdin() bool             { return false }
-func (geminiCLI) Model(int) (string, string) { return "", "" }
-func (geminiCLI) BuildArgs(prompt string, model string) []string {
-	args := []string{prompt, "--yolo", "--output-format", "stream-json"}
-	if model != "" {
-		args = append(args, "--model", model)
-	}
-	return args
+func (codexCLI) SupervisorModel() (string, string) {
+	return "gpt-5.1-codex-mini", "5.1-mini"
 }
-func (geminiCLI) Parse(line string) []ParsedMessage {
-	trim := strings.TrimSpace(line)
-	if trim == "" {
-		return nil
-	}
-	if !strings.HasPrefix(trim, "{") {
-		return []ParsedMessage{{Kind: events.MessageSay, Text: line}}
-	}
-	var root map[string]any
-	if err := json.Unmarshal([]byte(line), &root); err != nil {
-		return []ParsedMessage{{Kind: events.MessageSay, Text: line}}
-	}
-	typ, _ := root["type"].(string)
-	switch typ {
-	case "message":
-		if content, ok := root["content"].(string); ok && strings.TrimSpace(content) != "" {
-			return []ParsedMessage{{Kind: events.MessageSay, Text: trimLines(content)}}
-		}
-	case "tool_use":
-		name, _ := root["tool_name"].(string)
-		params, _ := root["parameters"].(map[string]any)
-		return []ParsedMessage{{Kind: events.MessageDo, Text: summarizeGeminiTool(name, params)}}
-	case "tool_result":
-		if out, ok := root["output"].(string); ok && strings.TrimSpace(out) != "" {
-			return []ParsedMessage{{Kind: events.MessageSee, Text: strings.TrimSpace(out)}}
-		}
-		status, _ := root["status"].(string)
-		toolID, _ := root["tool_id"].(string)
-		if errObj, ok := root["error"].(map[string]any); ok {
-			if msg, ok := errObj["message"].(string); ok {
-				return []ParsedMessage{{Kind: events.MessageSee, Text: msg}}
-			}
-		}
-		summary := "tool_result"
-		if toolID != "" && status != "" {
-			summary = fmt.Sprintf("tool_result %s (%s)", toolID, status)
-		} else if toolID != "" {
-			summary = fmt.Sprintf("tool_result %s", toolID)
-		} else if status != "" {
-			summary = fmt.Sprintf("tool_result (%s)", status)
-		}
-		return []ParsedMessage{{Kind: events.MessageSee, Text: summary}}
-	case "result":
-		if status, _ := root["status"].(string); status == "error" {
-			if errObj, ok := root["error"].(map[string]any); ok {
-				if msg, ok := errObj["message"].(string); ok {
-					return []ParsedMessage{{Kind: events.MessageSay, Text: msg}}
-				}
+
+// Parse labels Codex's thinking marker, which the registered adapter leaves blank.
+func (c codexCLI) Parse(line string) []ParsedMessage {
+	msgs := c.runnerCLI.Parse(line)
+	if strings.TrimSpace(termtext.StripANSI(line)) == "thinking" {
+		for i := range msgs {
+			if msgs[i].Text == "" {
+				msgs[i].Text = "[thinking]"
 			}
 		}
 	}
-	return []ParsedMessage{{Kind: events.MessageSay, Text: line}}
+	return msgs
 }
 
-func summarizeClaudeTool(name string, input map[string]any) string {
-	if name == "" {
-		return "Unknown tool"
-	}
-	switch name {
-	case "Bash":
-		if cmd, ok := input["command"].(string); ok {
-			return "$ " + cmd
-		}
-	case "Read", "Write", "Edit":
-		if path, ok := input["file_path"].(string); ok {
-			return strings.ToLower(name) + ": " + path
-		}
-	case "Glob", "Grep":
-		if pattern, ok := input["pattern"].(string); ok {
-			return strings.ToLower(name) + ": " + pattern
-		}
-	}
-	return name
+// claudeCLI adds stream-json input and turn detection to the registered Claude adapter,
+// so swarm can keep talking to an agent after its first turn.
+type claudeCLI struct {
+	runnerCLI
 }
 
-func summarizeGeminiTool(name string, params map[string]any) string {
-	if name == "" {
-		return "tool"
+func (c claudeCLI) BuildArgs(prompt string, model string) []string {
+	base := c.runnerCLI.BuildArgs(prompt, model)
+	if slices.Contains(base, "--input-format") {
+		return base
 	}
-	switch name {
-	case "run_shell_command", "shell":
-		if cmd, ok := params["command"].(string); ok {
-			return "$ " + cmd
-		}
-	case "read_file", "write_file", "edit_file", "replace":
-		if path, ok := params["file_path"].(string); ok {
-			return strings.ReplaceAll(name, "_", " ") + ": " + path
-		}
-	case "glob", "grep":
-		if pattern, ok := params["pattern"].(string); ok {
-			return name + ": " + pattern
+	var args []string
+	for _, arg := range base {
+		if arg == "--output-format" {
+			args = append(args, "--input-format", "stream-json")
 		}
+		args = append(args, arg)
 	}
-	return name
+	// Bubble Tea provides its own prompt injection; Claude reads from stdin.
+	return args
 }
 
-func trimLines(s string) string {
-	lines := strings.Split(s, "\n")
-	for i := range lines {
-		lines[i] = strings.TrimSpace(lines[i])
+// EncodeInput wraps text as a stream-json user message.
+func (claudeCLI) EncodeInput(text string) string {
+	msg := map[string]any{
+		"type":    "user",
+		"message": map[string]any{"role": "user", "content": text},
 	}
-	return strings.Join(lines, "\n")
+	data, _ := json.Marshal(msg)
+	return string(data) + "\n"
 }
 
-func parseClaudeAssistant(root map[string]any) []ParsedMessage {
-	msg, ok := root["message"].(map[string]any)
-	if !ok {
-		return nil
+// IsTurnEnd reports the final "result" record Claude prints when its turn is over.
+func (claudeCLI) IsTurnEnd(line string) bool {
+	if !strings.Contains(line, `"result"`) {
+		return false
 	}
-	content, ok := msg["content"].([]any)
-	if !ok {
-		return nil
+	var root struct {
+		Type string `json:"type"`
 	}
-	var out []ParsedMessage
-	for _, item := range content {
-		obj, ok := item.(map[string]any)
-		if !ok {
-			continue
-		}
-		switch obj["type"] {
-		case "text":
-			if text, ok := obj["text"].(string); ok {
-				trimmed := trimTrailingWhitespacePerLine(text)
-				if strings.TrimSpace(trimmed) != "" {
-					out = append(out, ParsedMessage{Kind: events.MessageSay, Text: trimmed})
-				}
-			}
-		case "tool_use":
-			name, _ := obj["name"].(string)
-			input, _ := obj["input"].(map[string]any)
-			out = append(out, ParsedMessage{Kind: events.MessageDo, Text: summarizeClaudeTool(name, input)})
-		}
-	}
-	return out
+	return json.Unmarshal([]byte(line), &root) == nil && root.Type == "result"
 }
 
-func parseClaudeToolResult(root map[string]any) []ParsedMessage {
-	result, ok := root["tool_use_result"].(map[string]any)
-	if !ok {
-		return nil
-	}
-	var out []ParsedMessage
-	if stdout, ok := result["stdout"].(string); ok && strings.TrimSpace(stdout) != "" {
-		out = append(out, ParsedMessage{Kind: events.MessageSee, Text: strings.TrimSpace(stdout)})
-	}
-	if stderr, ok := result["stderr"].(string); ok && strings.TrimSpace(stderr) != "" {
-		out = append(out, ParsedMessage{Kind: events.MessageSee, Text: strings.TrimSpace(stderr)})
-	}
-	return out
+type copilotCLI struct {
+	runnerCLI
 }
 
-func trimTrailingWhitespacePerLine(content string) string {
-	lines := strings.Split(content, "\n")
-	for i := range lines {
-		lines[i] = strings.TrimRight(lines[i], " \t")
-	}
-	return strings.Join(lines, "\n")
+type geminiCLI struct {
+	runnerCLI
 }
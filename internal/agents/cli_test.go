@@ -0,0 +1,26 @@
+package agents
+
+import (
+	"testing"
+
+	"github.com/asynkron/Asynkron.SwarmGo/internal/config"
+)
+
+func TestSupervisorModelThroughWrappers(t *testing.T) {
+	cases := []struct {
+		name string
+		cli  CLI
+		want string
+	}{
+		{"plain", NewCLI(config.AgentCodex), "gpt-5.1-codex-mini"},
+		{"model offset", WithModelOffset(NewCLI(config.AgentCodex), 1), "gpt-5.1-codex-mini"},
+		{"safe mode", WithSafeMode(WithModelOffset(NewCLI(config.AgentCodex), 2)), "gpt-5.1-codex-mini"},
+		{"model override", WithModel(WithSafeMode(NewCLI(config.AgentCodex)), "o3"), "o3"},
+		{"no supervisor model", WithModelOffset(NewCLI(config.AgentClaude), 1), "opus"},
+	}
+	for _, tc := range cases {
+		if got, _ := SupervisorModel(tc.cli, 0); got != tc.want {
+			t.Errorf("%s: SupervisorModel = %q, want %q", tc.name, got, tc.want)
+		}
+	}
+}
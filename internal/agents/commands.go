@@ -0,0 +1,118 @@
+package agents
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/asynkron/Asynkron.SwarmGo/internal/events"
+)
+
+// CommandLog is the audit trail of shell commands run by every agent of a session: one
+// tab-separated line per command with the time, agent ID, working directory and command.
+type CommandLog struct {
+	mu   sync.Mutex
+	file *os.File
+}
+
+// OpenCommandLog opens the audit file at path for appending.
+func OpenCommandLog(path string) (*CommandLog, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, err
+	}
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return nil, err
+	}
+	return &CommandLog{file: f}, nil
+}
+
+// Record appends one command. Newlines and tabs in it are escaped so every command stays
+// on one line.
+func (l *CommandLog) Record(at time.Time, agentID, workdir, command string) {
+	if l == nil {
+		return
+	}
+	command = strings.NewReplacer("\\", `\\`, "\n", `\n`, "\t", `\t`).Replace(command)
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.file == nil {
+		return
+	}
+	_, _ = fmt.Fprintf(l.file, "%s\t%s\t%s\t%s\n", at.Format(time.RFC3339), agentID, workdir, command)
+}
+
+// Close closes the audit file; later records are dropped.
+func (l *CommandLog) Close() error {
+	if l == nil {
+		return nil
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.file == nil {
+		return nil
+	}
+	err := l.file.Close()
+	l.file = nil
+	return err
+}
+
+// ShellCommander is implemented by CLIs whose actions are all shell commands, so every
+// Do message is one.
+type ShellCommander interface {
+	ShellCommand(text string) (string, bool)
+}
+
+func (mockCLI) ShellCommand(text string) (string, bool) { return text, true }
+
+// commandTracker picks the shell commands out of an agent's Do messages. Claude and Gemini
+// print shell tool calls as "$ <command>"; Codex prints its "exec" marker and then the
+// command on the next line.
+type commandTracker struct {
+	afterExec bool
+}
+
+func (t *commandTracker) command(cli CLI, msg ParsedMessage) (string, bool) {
+	if msg.Kind != events.MessageDo {
+		t.afterExec = false
+		return "", false
+	}
+	if sc, ok := capability[ShellCommander](cli); ok {
+		return sc.ShellCommand(msg.Text)
+	}
+	if msg.Text == "[exec]" {
+		t.afterExec = true
+		return "", false
+	}
+	if t.afterExec {
+		t.afterExec = false
+		return strings.TrimSpace(msg.Text), true
+	}
+	if cmd, ok := strings.CutPrefix(msg.Text, "$ "); ok {
+		return cmd, true
+	}
+	return "", false
+}
+
+// Denial is a command of an agent that matched the denylist.
+type Denial struct {
+	Agent   *Agent
+	Command string
+	Pattern string
+}
+
+// checkDenied reports cmd to Denials when it matches the denylist. A full channel drops the
+// report rather than stalling the tail.
+func (a *Agent) checkDenied(cmd string) {
+	pattern, ok := a.Denylist.Match(cmd)
+	if !ok || a.Denials == nil {
+		return
+	}
+	select {
+	case a.Denials <- Denial{Agent: a, Command: cmd, Pattern: pattern}:
+	default:
+	}
+}
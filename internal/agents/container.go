@@ -0,0 +1,146 @@
+package agents
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// passEnv are the credentials the agent CLIs and forge tools read from the environment.
+// Those set on the host are passed into containers; everything else stays outside.
+var passEnv = []string{
+	"ANTHROPIC_API_KEY", "CLAUDE_CODE_OAUTH_TOKEN",
+	"OPENAI_API_KEY", "CODEX_API_KEY",
+	"GEMINI_API_KEY", "GOOGLE_API_KEY",
+	"GH_TOKEN", "GITHUB_TOKEN", "COPILOT_GITHUB_TOKEN",
+	"GITLAB_TOKEN", "BITBUCKET_TOKEN", "BITBUCKET_USERNAME", "BITBUCKET_APP_PASSWORD",
+	"AZURE_DEVOPS_EXT_PAT",
+}
+
+// Sandbox is an isolated environment worker agents run in under --isolation.
+type Sandbox interface {
+	// Start creates the environment, replacing one left over from an earlier run.
+	Start(ctx context.Context) error
+	// Remove tears it down along with everything still running in it.
+	Remove() error
+	// exec returns the host command and arguments that run command with args inside it.
+	exec(command string, args []string, stdin bool) (string, []string)
+	// signal sends sig to every agent process inside it.
+	signal(sig string) error
+}
+
+// dockerTimeout bounds the docker commands that manage a container, as opposed to the
+// agent runs inside it.
+const dockerTimeout = 2 * time.Minute
+
+// Container is a long-lived Docker container an agent runs in under --isolation docker.
+// Only the mounted paths are visible inside it; each is mounted at the same path as on
+// the host so the prompts' paths and git's worktree links stay valid. It runs as the
+// host user, so files agents create in the mounts stay the user's.
+type Container struct {
+	Name    string
+	Image   string
+	Workdir string
+	// Mounts are host paths bind-mounted read-write at the same path.
+	Mounts []string
+	// ReadOnly are host paths bind-mounted read-only at the same path. Mounts nested
+	// inside one stay writable.
+	ReadOnly []string
+}
+
+// Start (re)creates the container, idling until agents are run in it with docker exec.
+// A container left over from an earlier run of the same session is replaced.
+func (c *Container) Start(ctx context.Context) error {
+	_ = c.Remove()
+	ctx, cancel := context.WithTimeout(ctx, dockerTimeout)
+	defer cancel()
+	// The idle sleep is the container's init, which `kill -1` leaves alone, so stopping an
+	// agent never takes the container down with it.
+	args := []string{"run", "--detach", "--rm", "--name", c.Name, "--workdir", c.Workdir}
+	if uid, gid := os.Getuid(), os.Getgid(); uid >= 0 {
+		// The host user rarely exists in the image, so HOME points somewhere writable for
+		// the CLIs' own state.
+		args = append(args, "--user", fmt.Sprintf("%d:%d", uid, gid), "--env", "HOME=/tmp")
+	}
+	// Docker mounts parents before the paths nested inside them, whatever the order here.
+	for _, m := range c.ReadOnly {
+		args = append(args, "--volume", m+":"+m+":ro")
+	}
+	for _, m := range c.Mounts {
+		args = append(args, "--volume", m+":"+m)
+	}
+	args = append(args, "--entrypoint", "sleep", c.Image, "infinity")
+	if out, err := exec.CommandContext(ctx, "docker", args...).CombinedOutput(); err != nil {
+		return fmt.Errorf("start container %s: %v: %s", c.Name, err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+// Remove force-removes the container and everything still running in it.
+func (c *Container) Remove() error {
+	ctx, cancel := context.WithTimeout(context.Background(), dockerTimeout)
+	defer cancel()
+	out, err := exec.CommandContext(ctx, "docker", "rm", "--force", c.Name).CombinedOutput()
+	if err != nil && !strings.Contains(string(out), "No such container") {
+		return fmt.Errorf("remove container %s: %v: %s", c.Name, err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+// signal sends sig to every process in the container but its init, the idle sleep.
+// Signals sent to the local docker exec client are not forwarded, so stopping an agent
+// goes through here.
+func (c *Container) signal(sig string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	return exec.CommandContext(ctx, "docker", "exec", c.Name, "sh", "-c", "kill -"+sig+" -1").Run()
+}
+
+func (c *Container) exec(command string, args []string, stdin bool) (string, []string) {
+	execArgs := []string{"exec", "--workdir", c.Workdir}
+	if stdin {
+		execArgs = append(execArgs, "--interactive")
+	}
+	for _, name := range passEnv {
+		if _, ok := os.LookupEnv(name); ok {
+			// Without a value, docker copies the variable from its own environment.
+			execArgs = append(execArgs, "--env", name)
+		}
+	}
+	execArgs = append(execArgs, c.Name, command)
+	return "docker", append(execArgs, args...)
+}
+
+// WithSandbox runs cli inside box instead of on the host.
+func WithSandbox(cli CLI, box Sandbox) CLI {
+	return sandboxed{CLI: cli, box: box}
+}
+
+type sandboxed struct {
+	CLI
+	box Sandbox
+}
+
+func (s sandboxed) Command() string {
+	command, _ := s.box.exec(s.CLI.Command(), nil, s.CLI.UseStdin())
+	return command
+}
+
+func (s sandboxed) BuildArgs(prompt string, model string) []string {
+	_, args := s.box.exec(s.CLI.Command(), s.CLI.BuildArgs(prompt, model), s.CLI.UseStdin())
+	return args
+}
+
+// Unwrap exposes the wrapped CLI so optional capabilities can still be detected.
+func (s sandboxed) Unwrap() CLI { return s.CLI }
+
+// signaler is implemented by CLIs whose agents run in a sandbox, where signals sent to
+// the local client process don't reach them.
+type signaler interface {
+	signal(sig string) error
+}
+
+func (s sandboxed) signal(sig string) error { return s.box.signal(sig) }
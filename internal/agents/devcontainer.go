@@ -0,0 +1,101 @@
+package agents
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// devcontainerTimeout bounds `devcontainer up`, which builds the image the first time.
+const devcontainerTimeout = 30 * time.Minute
+
+// Devcontainer is a worktree's own development container under --isolation
+// devcontainer, built and started from the repository's devcontainer.json with the
+// devcontainer CLI. The worktree is mounted where the configuration says; Mounts are
+// extra host paths bind-mounted at the same path.
+type Devcontainer struct {
+	Workspace string
+	Mounts    []string
+}
+
+// HasDevcontainer reports whether dir holds a devcontainer configuration.
+func HasDevcontainer(dir string) bool {
+	for _, p := range []string{filepath.Join(".devcontainer", "devcontainer.json"), ".devcontainer.json"} {
+		if _, err := os.Stat(filepath.Join(dir, p)); err == nil {
+			return true
+		}
+	}
+	return false
+}
+
+// Start builds and starts the worktree's devcontainer, replacing one left over from an
+// earlier run of the same session. Credentials only reach it through the configuration,
+// e.g. "remoteEnv": {"ANTHROPIC_API_KEY": "${localEnv:ANTHROPIC_API_KEY}"}.
+func (d *Devcontainer) Start(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, devcontainerTimeout)
+	defer cancel()
+	args := []string{"up", "--workspace-folder", d.Workspace, "--remove-existing-container"}
+	for _, m := range d.Mounts {
+		args = append(args, "--mount", fmt.Sprintf("type=bind,source=%s,target=%s", m, m))
+	}
+	if out, err := exec.CommandContext(ctx, "devcontainer", args...).CombinedOutput(); err != nil {
+		return fmt.Errorf("start devcontainer for %s: %v: %s", d.Workspace, err, lastLines(string(out), 5))
+	}
+	return nil
+}
+
+// Remove force-removes the containers the devcontainer CLI created for the worktree. The
+// CLI has no command for it, so they are found by the label it puts on them.
+func (d *Devcontainer) Remove() error {
+	ctx, cancel := context.WithTimeout(context.Background(), dockerTimeout)
+	defer cancel()
+	out, err := exec.CommandContext(ctx, "docker", "ps", "--all", "--quiet", "--filter", "label=devcontainer.local_folder="+d.Workspace).Output()
+	if err != nil {
+		return fmt.Errorf("find devcontainer for %s: %w", d.Workspace, err)
+	}
+	ids := strings.Fields(string(out))
+	if len(ids) == 0 {
+		return nil
+	}
+	if out, err := exec.CommandContext(ctx, "docker", append([]string{"rm", "--force"}, ids...)...).CombinedOutput(); err != nil {
+		return fmt.Errorf("remove devcontainer for %s: %v: %s", d.Workspace, err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+// agentPIDFile is where the agent started in a devcontainer records its PID for signal.
+const agentPIDFile = "/tmp/swarm-agent.pid"
+
+func (d *Devcontainer) exec(command string, args []string, stdin bool) (string, []string) {
+	// devcontainer exec passes stdin through and runs in the container's workspace folder.
+	// The shell records the agent's PID, then becomes the agent.
+	execArgs := []string{"exec", "--workspace-folder", d.Workspace, "sh", "-c", `echo $$ > ` + agentPIDFile + ` && exec "$@"`, "sh", command}
+	return "devcontainer", append(execArgs, args...)
+}
+
+// signal sends sig to the agent's process group, or to the agent alone when it doesn't
+// lead one. Unlike Container, the devcontainer's own processes are not swarm's to signal.
+func (d *Devcontainer) signal(sig string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	script := fmt.Sprintf(`p=$(cat %s) || exit 1; kill -%s -"$p" 2>/dev/null || kill -%s "$p"`, agentPIDFile, sig, sig)
+	return exec.CommandContext(ctx, "devcontainer", "exec", "--workspace-folder", d.Workspace, "sh", "-c", script).Run()
+}
+
+// lastLines returns the last n non-empty lines of s, trimmed.
+func lastLines(s string, n int) string {
+	var lines []string
+	for _, l := range strings.Split(s, "\n") {
+		if l = strings.TrimSpace(l); l != "" {
+			lines = append(lines, l)
+		}
+	}
+	if len(lines) > n {
+		lines = lines[len(lines)-n:]
+	}
+	return strings.Join(lines, "\n")
+}
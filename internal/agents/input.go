@@ -0,0 +1,64 @@
+package agents
+
+import (
+	"fmt"
+	"io"
+)
+
+// Conversational is implemented by CLIs that accept follow-up user messages on stdin
+// while their turn is still running. Such agents keep stdin open until the CLI reports
+// the end of its turn, at which point stdin is closed so the process can exit normally.
+type Conversational interface {
+	// EncodeInput formats one user message for the CLI's stdin.
+	EncodeInput(text string) string
+	// IsTurnEnd reports whether an output line marks the end of the agent's turn.
+	IsTurnEnd(line string) bool
+}
+
+func asConversational(cli CLI) (Conversational, bool) {
+	return capability[Conversational](cli)
+}
+
+// AcceptsInput reports whether the agent's CLI can take follow-up messages while running.
+func (a *Agent) AcceptsInput() bool {
+	_, ok := asConversational(a.CLI)
+	return ok
+}
+
+// Send writes a follow-up message to the running agent without restarting it.
+func (a *Agent) Send(text string) error {
+	conv, ok := asConversational(a.CLI)
+	if !ok {
+		return fmt.Errorf("%s does not accept input while running; restart it with a note instead", a.CLI.Name())
+	}
+	a.inputMu.Lock()
+	defer a.inputMu.Unlock()
+	if a.input == nil {
+		return fmt.Errorf("%s is not accepting input (not running or its turn has ended)", a.ID)
+	}
+	if _, err := io.WriteString(a.input, conv.EncodeInput(text)); err != nil {
+		return fmt.Errorf("send to %s: %w", a.ID, err)
+	}
+	return nil
+}
+
+// openInput keeps stdin open for follow-up messages and writes the initial prompt.
+func (a *Agent) openInput(stdin io.WriteCloser, conv Conversational) {
+	// The lock is handed to the writer goroutine so no follow-up can overtake the prompt.
+	a.inputMu.Lock()
+	a.input = stdin
+	go func() {
+		defer a.inputMu.Unlock()
+		_, _ = io.WriteString(stdin, conv.EncodeInput(a.Prompt))
+	}()
+}
+
+// closeInput closes stdin so the CLI finishes once it has consumed any queued messages.
+func (a *Agent) closeInput() {
+	a.inputMu.Lock()
+	defer a.inputMu.Unlock()
+	if a.input != nil {
+		_ = a.input.Close()
+		a.input = nil
+	}
+}
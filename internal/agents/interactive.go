@@ -0,0 +1,23 @@
+package agents
+
+// Interactive is implemented by CLIs with an interactive mode that can pick up the
+// conversation the agent last had in its working directory, so a human can take it over.
+type Interactive interface {
+	// InteractiveArgs returns the arguments that open the last conversation interactively.
+	InteractiveArgs() []string
+}
+
+func (claudeCLI) InteractiveArgs() []string  { return []string{"--continue"} }
+func (codexCLI) InteractiveArgs() []string   { return []string{"resume", "--last"} }
+func (copilotCLI) InteractiveArgs() []string { return []string{"--continue"} }
+func (geminiCLI) InteractiveArgs() []string  { return []string{"--resume", "latest"} }
+
+// InteractiveCommand returns the command and arguments that reopen cli's last conversation
+// interactively. ok is false for CLIs without an interactive mode, such as the mock agent.
+func InteractiveCommand(cli CLI) (command string, args []string, ok bool) {
+	i, ok := capability[Interactive](cli)
+	if !ok {
+		return "", nil, false
+	}
+	return cli.Command(), i.InteractiveArgs(), true
+}
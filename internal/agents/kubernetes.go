@@ -0,0 +1,57 @@
+package agents
+
+import (
+	"context"
+	"os"
+
+	"github.com/asynkron/Asynkron.SwarmGo/internal/kube"
+)
+
+// KubeLabel is the label that ties a worker's Kubernetes jobs to it.
+const KubeLabel = "swarm-worker"
+
+// KubeJob runs a worker's agents as Kubernetes Jobs under --isolation kubernetes. Each
+// run is its own job, started by the hidden `swarm k8s-run` subcommand, whose pod clones
+// Repo into Workdir, creates Branch from Ref and streams the agent's output back. Worker
+// labels the jobs so they can be stopped and removed together.
+type KubeJob struct {
+	Worker    string
+	Namespace string
+	Image     string
+	Secret    string
+	Repo      string
+	Ref       string
+	Branch    string
+	Workdir   string
+}
+
+// Start does nothing: every agent run creates its own job.
+func (k *KubeJob) Start(context.Context) error { return nil }
+
+// Remove deletes the worker's jobs and their pods.
+func (k *KubeJob) Remove() error {
+	return kube.Delete(context.Background(), k.Namespace, KubeLabel+"="+k.Worker)
+}
+
+// signal deletes the worker's jobs, which sends their pods SIGTERM and, after the pod's
+// grace period, SIGKILL; there is no way to deliver a chosen signal to a pod.
+func (k *KubeJob) signal(string) error {
+	return k.Remove()
+}
+
+func (k *KubeJob) exec(command string, args []string, stdin bool) (string, []string) {
+	exe, err := os.Executable()
+	if err != nil {
+		exe = "swarm"
+	}
+	runArgs := []string{"k8s-run",
+		"--namespace", k.Namespace, "--image", k.Image, "--secret", k.Secret,
+		"--label", KubeLabel + "=" + k.Worker,
+		"--repo", k.Repo, "--ref", k.Ref, "--branch", k.Branch, "--workdir", k.Workdir,
+	}
+	if stdin {
+		runArgs = append(runArgs, "--stdin")
+	}
+	runArgs = append(runArgs, "--", command)
+	return exe, append(runArgs, args...)
+}
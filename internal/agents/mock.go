@@ -0,0 +1,56 @@
+package agents
+
+import (
+	"os"
+	"strings"
+	"time"
+
+	"github.com/asynkron/Asynkron.SwarmGo/internal/events"
+)
+
+// MockCLI runs the swarm binary's hidden mock-agent subcommand, which replays a scripted
+// output file instead of calling a model. An empty script plays the built-in demo script and
+// a zero delay uses the player's default.
+func MockCLI(script string, delay time.Duration) CLI {
+	return mockCLI{script: script, delay: delay}
+}
+
+type mockCLI struct {
+	script string
+	delay  time.Duration
+}
+
+func (mockCLI) Name() string { return "Mock" }
+func (mockCLI) Command() string {
+	if exe, err := os.Executable(); err == nil {
+		return exe
+	}
+	return "swarm"
+}
+func (mockCLI) UseStdin() bool             { return false }
+func (mockCLI) Model(int) (string, string) { return "mock", "mock" }
+func (m mockCLI) BuildArgs(string, string) []string {
+	args := []string{"mock-agent"}
+	if m.delay > 0 {
+		args = append(args, "--delay", m.delay.String())
+	}
+	if m.script != "" {
+		args = append(args, "--script", m.script)
+	}
+	return args
+}
+
+// Parse maps the script conventions onto message kinds: "$ " lines are actions and "> "
+// lines are tool output.
+func (mockCLI) Parse(line string) []ParsedMessage {
+	if strings.TrimSpace(line) == "" {
+		return nil
+	}
+	if text, ok := strings.CutPrefix(line, "$ "); ok {
+		return []ParsedMessage{{Kind: events.MessageDo, Text: text}}
+	}
+	if text, ok := strings.CutPrefix(line, "> "); ok {
+		return []ParsedMessage{{Kind: events.MessageSee, Text: text}}
+	}
+	return []ParsedMessage{{Kind: events.MessageSay, Text: line}}
+}
@@ -0,0 +1,32 @@
+package agents
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/asynkron/Asynkron.SwarmGo/internal/events"
+)
+
+// NewObserved builds a view of an agent started by other tooling, shown as name. Observe
+// follows its log for the UI; no process is launched.
+func NewObserved(index int, name string, worktree string, cli CLI, logPath string, events chan<- events.Event) *Agent {
+	return &Agent{
+		ID:      fmt.Sprintf("worker-%d", index+1),
+		Name:    name,
+		Workdir: worktree,
+		LogPath: logPath,
+		CLI:     cli,
+		events:  events,
+	}
+}
+
+// Observe streams the agent's log to the UI until ctx is canceled, replaying the recent
+// history first. Only lines written after Observe starts reach the command audit.
+func (a *Agent) Observe(ctx context.Context) {
+	if info, err := os.Stat(a.LogPath); err == nil {
+		a.liveFrom = info.Size()
+	}
+	a.tailWG.Add(1)
+	a.tailFile(ctx)
+}
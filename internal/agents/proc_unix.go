@@ -0,0 +1,30 @@
+//go:build !windows
+
+package agents
+
+import (
+	"os/exec"
+	"syscall"
+)
+
+// setProcessGroup starts the command in its own process group so everything it spawns
+// (builds, test runners, watchers) can be signalled together.
+func setProcessGroup(cmd *exec.Cmd) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+}
+
+// signalGroup sends sig to the process group led by pid.
+func signalGroup(pid int, sig syscall.Signal) error {
+	if pid <= 1 {
+		return syscall.ESRCH
+	}
+	return syscall.Kill(-pid, sig)
+}
+
+// killGroup force-kills the process group led by pid, including orphaned children.
+func killGroup(pid int) {
+	if pid <= 1 {
+		return
+	}
+	_ = syscall.Kill(-pid, syscall.SIGKILL)
+}
@@ -0,0 +1,63 @@
+//go:build !windows
+
+package agents
+
+import (
+	"bufio"
+	"os/exec"
+	"strconv"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestTerminateProcessGroup(t *testing.T) {
+	// The shell stands in for an agent CLI; the background sleep for a build it spawned.
+	cmd := exec.Command("sh", "-c", "sleep 30 & echo $!; wait")
+	setProcessGroup(cmd)
+	out, err := cmd.StdoutPipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := cmd.Start(); err != nil {
+		t.Fatal(err)
+	}
+	line, err := bufio.NewReader(out).ReadString('\n')
+	if err != nil {
+		t.Fatal(err)
+	}
+	child, err := strconv.Atoi(strings.TrimSpace(line))
+	if err != nil {
+		t.Fatal(err)
+	}
+	done := make(chan struct{})
+	go func() {
+		_ = cmd.Wait()
+		close(done)
+	}()
+
+	if !exists(cmd.Process.Pid) || !exists(child) {
+		t.Fatal("processes not running before terminate")
+	}
+	terminate(cmd.Process.Pid, done, 5*time.Second)
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("process still running after terminate")
+	}
+	// The orphaned child is reaped by init, which may take a moment.
+	deadline := time.Now().Add(5 * time.Second)
+	for exists(child) {
+		if time.Now().After(deadline) {
+			t.Fatal("child of the process group survived terminate")
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+}
+
+// exists reports whether a process with the given pid exists, zombies included.
+func exists(pid int) bool {
+	err := syscall.Kill(pid, 0)
+	return err == nil || err == syscall.EPERM
+}
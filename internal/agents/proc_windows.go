@@ -0,0 +1,36 @@
+//go:build windows
+
+package agents
+
+import (
+	"os/exec"
+	"strconv"
+	"syscall"
+)
+
+// Windows has no POSIX signals. Agents get their own console process group so a
+// CTRL_BREAK can be delivered to them alone, and taskkill /T tears down the whole tree.
+
+const ctrlBreakEvent = 1
+
+var generateConsoleCtrlEvent = syscall.NewLazyDLL("kernel32.dll").NewProc("GenerateConsoleCtrlEvent")
+
+func setProcessGroup(cmd *exec.Cmd) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{CreationFlags: syscall.CREATE_NEW_PROCESS_GROUP}
+}
+
+// signalGroup asks the process group to exit with CTRL_BREAK; sig is ignored.
+func signalGroup(pid int, sig syscall.Signal) error {
+	if r, _, err := generateConsoleCtrlEvent.Call(ctrlBreakEvent, uintptr(pid)); r == 0 {
+		return err
+	}
+	return nil
+}
+
+// killGroup force-kills pid and every process it spawned.
+func killGroup(pid int) {
+	if pid <= 0 {
+		return
+	}
+	_ = exec.Command("taskkill", "/T", "/F", "/PID", strconv.Itoa(pid)).Run()
+}
@@ -0,0 +1,29 @@
+//go:build windows
+
+package agents
+
+import (
+	"os/exec"
+	"testing"
+	"time"
+)
+
+func TestTerminateProcessTree(t *testing.T) {
+	cmd := exec.Command("cmd", "/c", "ping -n 30 127.0.0.1 >NUL")
+	setProcessGroup(cmd)
+	if err := cmd.Start(); err != nil {
+		t.Fatal(err)
+	}
+	done := make(chan struct{})
+	go func() {
+		_ = cmd.Wait()
+		close(done)
+	}()
+
+	terminate(cmd.Process.Pid, done, 5*time.Second)
+	select {
+	case <-done:
+	case <-time.After(10 * time.Second):
+		t.Fatal("process still running after terminate")
+	}
+}
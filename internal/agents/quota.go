@@ -0,0 +1,47 @@
+package agents
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/asynkron/Asynkron.SwarmGo/internal/events"
+	"github.com/asynkron/Asynkron.SwarmGo/internal/termtext"
+)
+
+// quotaWarning matches the usage cap notices CLIs print, e.g. "5-hour limit reached",
+// "You've used 90% of your weekly limit" or Gemini's RESOURCE_EXHAUSTED.
+var quotaWarning = regexp.MustCompile(`(?i)\b\d+[- ]?(?:hour|hr|day)\b[^.\n]{0,20}\blimit\b|\b(?:weekly|daily|monthly|session|usage|opus|premium)\s+(?:usage\s+)?(?:limit|cap|quota)\b|\bquota\b[^.\n]{0,40}\b(?:exceeded|reached|remaining|used|exhausted)\b|\d+%\s+of\s+your\b[^.\n]{0,40}\b(?:limit|quota|usage|requests)\b|\bapproaching\b[^.\n]{0,30}\blimit\b|resource_exhausted`)
+
+// maxQuotaLine skips long output lines, which are file contents or tool results rather
+// than a notice from the CLI.
+const maxQuotaLine = 2000
+
+// detectQuota reports usage cap notices in a live output line. Each distinct notice is
+// reported once per run.
+func (a *Agent) detectQuota(line string) {
+	if len(line) > maxQuotaLine || !quotaWarning.MatchString(line) {
+		return
+	}
+	msg := ""
+	for _, m := range a.CLI.Parse(line) {
+		if quotaWarning.MatchString(m.Text) {
+			msg = m.Text
+			break
+		}
+	}
+	if msg == "" {
+		msg = line
+	}
+	msg = strings.Join(strings.Fields(a.Redactor.Line(msg)), " ")
+	msg = termtext.Truncate(msg, maxRateLimitMessage, "...")
+	a.mu.Lock()
+	if a.quotaSeen == nil {
+		a.quotaSeen = map[string]bool{}
+	}
+	seen := a.quotaSeen[msg]
+	a.quotaSeen[msg] = true
+	a.mu.Unlock()
+	if !seen {
+		a.emit(events.QuotaWarning{ID: a.ID, Message: msg})
+	}
+}
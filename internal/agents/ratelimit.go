@@ -0,0 +1,156 @@
+package agents
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/asynkron/Asynkron.SwarmGo/internal/events"
+	"github.com/asynkron/Asynkron.SwarmGo/internal/termtext"
+)
+
+// RateLimitDetector is implemented by CLIs that recognise their provider's rate limit and
+// usage cap messages.
+type RateLimitDetector interface {
+	// DetectRateLimit reports whether line says the agent is rate limited, and until when
+	// if the message advertises it (zero otherwise). now anchors relative cooldowns.
+	DetectRateLimit(line string, now time.Time) (until time.Time, ok bool)
+}
+
+var (
+	claudeRateLimit  = regexp.MustCompile(`(?i)usage limit reached|limit reached\W+resets|rate_limit_error|429 too many requests`)
+	codexRateLimit   = regexp.MustCompile(`(?i)you've hit your usage limit|usage limit reached|rate limit reached|429 too many requests`)
+	copilotRateLimit = regexp.MustCompile(`(?i)rate limit(ed| exceeded)|quota exceeded|429 too many requests`)
+	geminiRateLimit  = regexp.MustCompile(`(?i)resource_exhausted|quota exceeded|rate limit exceeded|429 too many requests`)
+
+	// Claude's "usage limit reached|<unix seconds>" carries the reset time directly.
+	epochReset = regexp.MustCompile(`limit reached\|(\d{10})\b`)
+	// "try again in 1h 30m", "retry in 27.5s", "resets in 2 hours", "Retry-After: 60".
+	relativeReset = regexp.MustCompile(`(?i)(?:try again|retry|resets?) (?:in|after) ((?:\d+(?:\.\d+)?\s*(?:hours?|hrs?|h|minutes?|mins?|m|seconds?|secs?|s)\b,?\s*(?:and\s+)?)+)`)
+	retryAfter    = regexp.MustCompile(`(?i)retry-after:?\s*(\d+)`)
+	durationPart  = regexp.MustCompile(`(?i)(\d+(?:\.\d+)?)\s*(hours?|hrs?|h|minutes?|mins?|m|seconds?|secs?|s)\b`)
+	// "resets 3pm", "resets at 15:30", "resets 4:30 am".
+	clockReset = regexp.MustCompile(`(?i)resets (?:at )?(\d{1,2})(?::(\d{2}))?\s*(am|pm)?\b`)
+)
+
+func (claudeCLI) DetectRateLimit(line string, now time.Time) (time.Time, bool) {
+	return detectRateLimit(claudeRateLimit, line, now)
+}
+
+func (codexCLI) DetectRateLimit(line string, now time.Time) (time.Time, bool) {
+	return detectRateLimit(codexRateLimit, line, now)
+}
+
+func (copilotCLI) DetectRateLimit(line string, now time.Time) (time.Time, bool) {
+	return detectRateLimit(copilotRateLimit, line, now)
+}
+
+func (geminiCLI) DetectRateLimit(line string, now time.Time) (time.Time, bool) {
+	return detectRateLimit(geminiRateLimit, line, now)
+}
+
+func detectRateLimit(pattern *regexp.Regexp, line string, now time.Time) (time.Time, bool) {
+	if !pattern.MatchString(line) {
+		return time.Time{}, false
+	}
+	return resetTime(line, now), true
+}
+
+// resetTime reads the cooldown a rate limit message advertises, or returns zero.
+func resetTime(line string, now time.Time) time.Time {
+	if m := epochReset.FindStringSubmatch(line); m != nil {
+		if secs, err := strconv.ParseInt(m[1], 10, 64); err == nil {
+			return time.Unix(secs, 0)
+		}
+	}
+	if m := relativeReset.FindStringSubmatch(line); m != nil {
+		var total time.Duration
+		for _, part := range durationPart.FindAllStringSubmatch(m[1], -1) {
+			n, _ := strconv.ParseFloat(part[1], 64)
+			unit := time.Second
+			switch strings.ToLower(part[2])[0] {
+			case 'h':
+				unit = time.Hour
+			case 'm':
+				unit = time.Minute
+			}
+			total += time.Duration(n * float64(unit))
+		}
+		if total > 0 {
+			return now.Add(total)
+		}
+	}
+	if m := retryAfter.FindStringSubmatch(line); m != nil {
+		if secs, err := strconv.Atoi(m[1]); err == nil && secs > 0 {
+			return now.Add(time.Duration(secs) * time.Second)
+		}
+	}
+	if m := clockReset.FindStringSubmatch(line); m != nil {
+		hour, _ := strconv.Atoi(m[1])
+		minute, _ := strconv.Atoi(m[2])
+		switch strings.ToLower(m[3]) {
+		case "pm":
+			if hour < 12 {
+				hour += 12
+			}
+		case "am":
+			if hour == 12 {
+				hour = 0
+			}
+		}
+		if hour > 23 || minute > 59 {
+			return time.Time{}
+		}
+		at := time.Date(now.Year(), now.Month(), now.Day(), hour, minute, 0, 0, now.Location())
+		if !at.After(now) {
+			at = at.AddDate(0, 0, 1)
+		}
+		return at
+	}
+	return time.Time{}
+}
+
+// maxRateLimitMessage caps the output line carried by a RateLimited event.
+const maxRateLimitMessage = 200
+
+// detectRateLimit checks a live message the CLI itself printed, such as an error or result
+// event, for a rate limit. Tool calls and their output are never passed in, so a file that
+// merely mentions a rate limit doesn't count. A limit is reported once per advertised
+// cooldown, so a CLI repeating the message doesn't flood the UI, and any other message
+// clears it: the agent is working again.
+func (a *Agent) detectRateLimit(text string) {
+	d, ok := capability[RateLimitDetector](a.CLI)
+	if !ok {
+		return
+	}
+	until, ok := d.DetectRateLimit(text, time.Now())
+	if !ok {
+		a.mu.Lock()
+		a.rateLimited = false
+		a.rateLimitedUntil = time.Time{}
+		a.mu.Unlock()
+		return
+	}
+	a.mu.Lock()
+	repeated := a.rateLimited && !until.After(a.rateLimitedUntil)
+	a.rateLimited = true
+	if until.After(a.rateLimitedUntil) {
+		a.rateLimitedUntil = until
+	}
+	a.mu.Unlock()
+	if repeated {
+		return
+	}
+	msg := strings.TrimSpace(a.Redactor.Line(text))
+	msg = termtext.Truncate(msg, maxRateLimitMessage, "...")
+	a.emit(events.RateLimited{ID: a.ID, Until: until, Message: msg})
+}
+
+// RateLimited reports whether the agent's latest message was a rate limit, and the end of
+// the cooldown it advertised (zero when none).
+func (a *Agent) RateLimited() (until time.Time, ok bool) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.rateLimitedUntil, a.rateLimited
+}
@@ -0,0 +1,19 @@
+package agents
+
+import (
+	"testing"
+
+	"github.com/asynkron/Asynkron.SwarmGo/internal/config"
+)
+
+func TestRateLimitClearsWhenOutputResumes(t *testing.T) {
+	a := &Agent{ID: "worker-1", CLI: NewCLI(config.AgentClaude)}
+	a.detectRateLimit("Claude AI usage limit reached|1893456000")
+	if until, ok := a.RateLimited(); !ok || until.Unix() != 1893456000 {
+		t.Fatalf("RateLimited = %v, %v; want limited until 1893456000", until, ok)
+	}
+	a.detectRateLimit("Picking up where I left off.")
+	if _, ok := a.RateLimited(); ok {
+		t.Fatal("rate limit not cleared by normal output")
+	}
+}
@@ -0,0 +1,43 @@
+package agents
+
+import (
+	"github.com/asynkron/Asynkron.SwarmGo/agentrunner"
+	"github.com/asynkron/Asynkron.SwarmGo/internal/events"
+)
+
+// runnerCLI adapts a CLI registered with agentrunner.Register to swarm's CLI interface,
+// so built-in and custom agents plug into the orchestrator without a second adapter.
+type runnerCLI struct {
+	agentrunner.CLI
+}
+
+func (r runnerCLI) Parse(line string) []ParsedMessage {
+	msgs := r.CLI.Parse(line)
+	if msgs == nil {
+		return nil
+	}
+	out := make([]ParsedMessage, 0, len(msgs))
+	for _, msg := range msgs {
+		out = append(out, ParsedMessage{Kind: messageKind(msg.Kind), Text: msg.Text})
+	}
+	return out
+}
+
+// ParseUsage forwards to the registered CLI when it reports usage.
+func (r runnerCLI) ParseUsage(line string) (agentrunner.Usage, bool) {
+	if up, ok := r.CLI.(agentrunner.UsageParser); ok {
+		return up.ParseUsage(line)
+	}
+	return agentrunner.Usage{}, false
+}
+
+func messageKind(kind agentrunner.MessageKind) events.AgentMessageKind {
+	switch kind {
+	case agentrunner.MessageDo:
+		return events.MessageDo
+	case agentrunner.MessageSee:
+		return events.MessageSee
+	default:
+		return events.MessageSay
+	}
+}
@@ -0,0 +1,47 @@
+package agents
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Tell is a message an agent addressed to one other agent, e.g.
+// `tell worker-2 "Worker 1: the login test needs the fixture from testdata/users.json"`.
+type Tell struct {
+	From string
+	To   string
+	Text string
+}
+
+// tellLine matches an addressed tell, whether run as a command or printed, allowing
+// markdown emphasis and a shell prompt in front. The address is anything shaped like an
+// agent ID; the orchestrator drops those naming no agent of the round. Plain `tell "..."`
+// lines have no address and are left to the logs the supervisor reads.
+var tellLine = regexp.MustCompile(`(?m)^[\s*_>$-]*tell\s+([a-z0-9][a-z0-9-]*)\s+(?:"(.+)"|'(.+)')\s*$`)
+
+// detectTells sends the addressed messages in text to Tells. A message repeated right
+// after itself, as when a command is echoed in the agent's reply, is sent once; a full
+// channel drops it rather than stalling the tail.
+func (a *Agent) detectTells(text string) {
+	if a.Tells == nil {
+		return
+	}
+	for _, m := range tellLine.FindAllStringSubmatch(text, -1) {
+		t := Tell{From: a.ID, To: m[1], Text: strings.TrimSpace(m[2] + m[3])}
+		if t.Text == "" || t.To == a.ID {
+			continue
+		}
+		key := t.To + "\x00" + t.Text
+		a.mu.Lock()
+		repeated := key == a.lastTell
+		a.lastTell = key
+		a.mu.Unlock()
+		if repeated {
+			continue
+		}
+		select {
+		case a.Tells <- t:
+		default:
+		}
+	}
+}
@@ -0,0 +1,31 @@
+package agents
+
+import "testing"
+
+func TestDetectTellsAddressesAnyAgent(t *testing.T) {
+	tells := make(chan Tell, 8)
+	a := &Agent{ID: "worker-1", Tells: tells}
+	a.detectTells(`tell worker-2 "the fixture moved"
+$ tell reviewer 'look at the retry loop first'
+- tell security-audit "the token is logged in auth.go"
+tell worker-1 "a note to self"
+tell "for the supervisor's eyes"`)
+	close(tells)
+	var got []Tell
+	for t := range tells {
+		got = append(got, t)
+	}
+	want := []Tell{
+		{From: "worker-1", To: "worker-2", Text: "the fixture moved"},
+		{From: "worker-1", To: "reviewer", Text: "look at the retry loop first"},
+		{From: "worker-1", To: "security-audit", Text: "the token is logged in auth.go"},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("got %d tells %v, want %v", len(got), got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("tell %d = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+}
@@ -0,0 +1,38 @@
+package agents
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// TestReport is one result line of the tester agent, e.g.
+// "TEST: worker-2 REGRESSION TestLogin fails since a1b2c3d".
+type TestReport struct {
+	Worker int
+	// Status is PASS, FAIL (failing, but no worse than before) or REGRESSION.
+	Status string
+	Detail string
+}
+
+// testReportLine matches the line the tester prints after each run, allowing markdown
+// emphasis around it.
+var testReportLine = regexp.MustCompile(`(?m)^[\s*_>-]*TEST:[*_]*\s*worker-(\d+)\s+(PASS|FAIL|REGRESSION)\b[\s:,-]*(.*?)[\s*_]*$`)
+
+// detectTestReports sends the results the tester announced in a live message to
+// TestReports. A full channel drops them rather than stalling the tail.
+func (a *Agent) detectTestReports(text string) {
+	if a.TestReports == nil {
+		return
+	}
+	for _, m := range testReportLine.FindAllStringSubmatch(text, -1) {
+		worker, err := strconv.Atoi(m[1])
+		if err != nil {
+			continue
+		}
+		select {
+		case a.TestReports <- TestReport{Worker: worker, Status: m[2], Detail: strings.TrimSpace(m[3])}:
+		default:
+		}
+	}
+}
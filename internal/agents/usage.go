@@ -0,0 +1,40 @@
+package agents
+
+import (
+	"strings"
+
+	"github.com/asynkron/Asynkron.SwarmGo/agentrunner"
+	"github.com/asynkron/Asynkron.SwarmGo/internal/events"
+)
+
+// UsageParser is implemented by CLIs that report token usage in their output.
+type UsageParser interface {
+	ParseUsage(line string) (agentrunner.Usage, bool)
+}
+
+// ParseUsage extracts token usage from a raw output line of cli, for CLIs that report it.
+func ParseUsage(cli CLI, line string) (agentrunner.Usage, bool) {
+	up, ok := capability[UsageParser](cli)
+	if !ok {
+		return agentrunner.Usage{}, false
+	}
+	return up.ParseUsage(strings.TrimSpace(line))
+}
+
+// emitUsage reports usage found in a live output line; replayed log history is never
+// parsed here, so restarts don't double count.
+func (a *Agent) emitUsage(line string) {
+	u, ok := ParseUsage(a.CLI, line)
+	if !ok {
+		return
+	}
+	a.emit(events.AgentUsage{
+		ID:               a.ID,
+		Model:            a.Model,
+		InputTokens:      u.InputTokens,
+		OutputTokens:     u.OutputTokens,
+		CacheReadTokens:  u.CacheReadTokens,
+		CacheWriteTokens: u.CacheWriteTokens,
+		CostUSD:          u.CostUSD,
+	})
+}
@@ -5,35 +5,34 @@ import (
 	"time"
 
 	"github.com/asynkron/Asynkron.SwarmGo/internal/events"
+	"github.com/asynkron/Asynkron.SwarmGo/internal/forge"
 	"github.com/asynkron/Asynkron.SwarmGo/internal/prompts"
 )
 
-// NewWorker builds a configured Agent representing a worker.
-func NewWorker(index int, worktree string, todoFile string, cli CLI, logPath string, autopilot bool, branchName string, restartCount int, ghAvailable bool, isGitHubRepo bool, events chan<- events.Event) *Agent {
+// NewWorker builds a configured Agent representing a worker; name is its display name.
+func NewWorker(index int, name string, worktree string, todoFile string, cli CLI, logPath string, autopilot bool, branchName string, restartCount int, repoForge forge.Kind, forgeCLI bool, events chan<- events.Event) *Agent {
 	// Pick display model based on worker index for a bit of variety.
 	apiModel, displayModel := cli.Model(index)
-	prompt := prompts.WorkerPrompt(todoFile, fmt.Sprintf("Worker %d", index+1), autopilot, branchName, logPath, restartCount, ghAvailable, isGitHubRepo)
+	prompt := prompts.WorkerPrompt(todoFile, name, autopilot, branchName, logPath, restartCount, repoForge, forgeCLI)
 
 	return &Agent{
-		ID:      fmt.Sprintf("worker-%d", index+1),
-		Name:    fmt.Sprintf("Worker %d", index+1),
-		Prompt:  prompt,
-		Workdir: worktree,
-		LogPath: logPath,
-		Model:   apiModel,
-		CLI:     cli,
-		Display: displayModel,
-		events:  events,
+		ID:       fmt.Sprintf("worker-%d", index+1),
+		Name:     name,
+		Prompt:   prompt,
+		Workdir:  worktree,
+		LogPath:  logPath,
+		Model:    apiModel,
+		CLI:      cli,
+		Display:  displayModel,
+		events:   events,
+		restarts: restartCount,
 	}
 }
 
 // NewSupervisor builds the supervisor agent.
-func NewSupervisor(worktrees []string, workerLogs []string, repoPath string, codedPath string, cli CLI, logPath string, autopilot bool, restartCount int, ghAvailable bool, isGitHubRepo bool, events chan<- events.Event) *Agent {
-	prompt := prompts.SupervisorPrompt(worktrees, workerLogs, repoPath, codedPath, autopilot, restartCount, ghAvailable, isGitHubRepo)
-	apiModel, displayModel := cli.Model(int(time.Now().UnixNano()))
-	if sm, ok := cli.(SupervisorModeler); ok {
-		apiModel, displayModel = sm.SupervisorModel()
-	}
+func NewSupervisor(worktrees []string, workerLogs []string, workerRepos []string, workerNames []string, repoPath string, codedPath string, cli CLI, logPath string, autopilot bool, restartCount int, repoForge forge.Kind, forgeCLI bool, events chan<- events.Event) *Agent {
+	prompt := prompts.SupervisorPrompt(worktrees, workerLogs, workerRepos, workerNames, repoPath, codedPath, autopilot, restartCount, repoForge, forgeCLI)
+	apiModel, displayModel := SupervisorModel(cli, int(time.Now().UnixNano()))
 	return &Agent{
 		ID:              "supervisor",
 		Name:            "Supervisor",
@@ -47,20 +46,36 @@ func NewSupervisor(worktrees []string, workerLogs []string, repoPath string, cod
 		isSupervisor:    true,
 		workerWorktrees: worktrees,
 		workerLogPaths:  workerLogs,
+		workerNames:     workerNames,
+		restarts:        restartCount,
 	}
 }
 
 // NewUserCommand builds the one-off user-controlled agent.
-func NewUserCommand(worktrees []string, repoPath string, cli CLI, logPath string, message string, events chan<- events.Event) *Agent {
-	prompt := prompts.UserCommandPrompt(worktrees, repoPath, message)
-	apiModel, displayModel := cli.Model(len(worktrees) + 99)
-	if sm, ok := cli.(SupervisorModeler); ok {
-		_, displayModel = sm.SupervisorModel()
-	}
+func NewUserCommand(worktrees []string, repoPaths []string, cli CLI, logPath string, message string, events chan<- events.Event) *Agent {
+	prompt := prompts.UserCommandPrompt(worktrees, repoPaths, message)
+	apiModel, _ := cli.Model(len(worktrees) + 99)
+	_, displayModel := SupervisorModel(cli, len(worktrees)+99)
 	return &Agent{
 		ID:      "user-command",
 		Name:    "User Command",
 		Prompt:  prompt,
+		Workdir: repoPaths[0],
+		LogPath: logPath,
+		Model:   apiModel,
+		Display: displayModel,
+		CLI:     cli,
+		events:  events,
+	}
+}
+
+// NewReviewer builds the agent that reviews the workers' branches after the round.
+func NewReviewer(repoPath string, todoFile string, targets []prompts.ReviewTarget, cli CLI, logPath string, repoForge forge.Kind, forgeCLI bool, events chan<- events.Event) *Agent {
+	apiModel, displayModel := SupervisorModel(cli, len(targets))
+	return &Agent{
+		ID:      "reviewer",
+		Name:    "Reviewer",
+		Prompt:  prompts.ReviewerPrompt(todoFile, targets, repoForge, forgeCLI),
 		Workdir: repoPath,
 		LogPath: logPath,
 		Model:   apiModel,
@@ -70,6 +85,60 @@ func NewUserCommand(worktrees []string, repoPath string, cli CLI, logPath string
 	}
 }
 
+// NewDocs builds the agent that drafts changelog entries and doc updates for the workers'
+// branches at the end of the round. branch is the docs branch checked out in workdir, or
+// empty to only write the draft.
+func NewDocs(workdir string, sources []prompts.DocsSource, draftPath, branch string, cli CLI, logPath string, events chan<- events.Event) *Agent {
+	apiModel, displayModel := cli.Model(len(sources) + 4)
+	return &Agent{
+		ID:      "docs",
+		Name:    "Docs",
+		Prompt:  prompts.DocsPrompt(sources, draftPath, branch),
+		Workdir: workdir,
+		LogPath: logPath,
+		Model:   apiModel,
+		Display: displayModel,
+		CLI:     cli,
+		events:  events,
+	}
+}
+
+// NewTester builds the agent that keeps testing the workers' latest commits in its own
+// worktree. worktrees maps worker numbers to their worktrees.
+func NewTester(worktree string, worktrees map[int]string, base string, cli CLI, logPath string, restartCount int, events chan<- events.Event) *Agent {
+	apiModel, displayModel := cli.Model(len(worktrees) + 3)
+	return &Agent{
+		ID:       "tester",
+		Name:     "Tester",
+		Prompt:   prompts.TesterPrompt(worktree, worktrees, base, restartCount),
+		Workdir:  worktree,
+		LogPath:  logPath,
+		Model:    apiModel,
+		Display:  displayModel,
+		CLI:      cli,
+		events:   events,
+		restarts: restartCount,
+	}
+}
+
+// NewRole builds the agent of a role defined in the config file. prompt is its rendered
+// template.
+func NewRole(name string, workdir string, prompt string, cli CLI, logPath string, restartCount int, events chan<- events.Event) *Agent {
+	apiModel, displayModel := cli.Model(0)
+	return &Agent{
+		ID:       "role-" + name,
+		Name:     "Role " + name,
+		Prompt:   prompt,
+		Workdir:  workdir,
+		LogPath:  logPath,
+		Model:    apiModel,
+		Display:  displayModel,
+		CLI:      cli,
+		events:   events,
+		restarts: restartCount,
+	}
+}
+
 // NewPrep builds the prep agent that generates test.sh before workers start.
 func NewPrep(worktree string, todoPath string, cli CLI, logPath string, events chan<- events.Event) *Agent {
 	apiModel, displayModel := cli.Model(0)
@@ -0,0 +1,161 @@
+package artifact
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io"
+	"io/fs"
+	"maps"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// Destination is a parsed --upload-artifacts target.
+type Destination struct {
+	// Scheme is "s3", "gs" or "az".
+	Scheme string
+	// Bucket is the S3 or GCS bucket, or the Azure blob container.
+	Bucket string
+	// Prefix is the key prefix inside the bucket, without slashes at either end.
+	Prefix string
+}
+
+func (d Destination) String() string {
+	return d.Scheme + "://" + path.Join(d.Bucket, d.Prefix)
+}
+
+// CLI is the command line tool the upload runs.
+func (d Destination) CLI() string {
+	switch d.Scheme {
+	case "s3":
+		return "aws"
+	case "gs":
+		return "gcloud"
+	}
+	return "az"
+}
+
+// ParseDestination reads an upload target: s3://BUCKET/PREFIX, gs://BUCKET/PREFIX or
+// az://CONTAINER/PREFIX. The prefix is optional.
+func ParseDestination(s string) (Destination, error) {
+	scheme, rest, ok := strings.Cut(strings.TrimSpace(s), "://")
+	if !ok || (scheme != "s3" && scheme != "gs" && scheme != "az") {
+		return Destination{}, fmt.Errorf("invalid artifact destination %q (expected s3://, gs:// or az:// followed by a bucket)", s)
+	}
+	bucket, prefix, _ := strings.Cut(rest, "/")
+	if bucket == "" {
+		return Destination{}, fmt.Errorf("invalid artifact destination %q: no bucket", s)
+	}
+	return Destination{Scheme: scheme, Bucket: bucket, Prefix: strings.Trim(prefix, "/")}, nil
+}
+
+// Bundle writes a gzipped tarball to dest holding every file under root, except the
+// directories skip reports true for, plus the extra files given by their name in the
+// archive. Entries are stored relative to root.
+func Bundle(dest, root string, skip func(dir string) bool, extra map[string][]byte) error {
+	f, err := os.Create(dest)
+	if err != nil {
+		return fmt.Errorf("create bundle: %w", err)
+	}
+	gz := gzip.NewWriter(f)
+	tw := tar.NewWriter(gz)
+	err = filepath.WalkDir(root, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if p != root && skip != nil && skip(p) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		// The bundle itself and sockets, pipes and links are left out.
+		if p == dest || !d.Type().IsRegular() {
+			return nil
+		}
+		rel, err := filepath.Rel(root, p)
+		if err != nil {
+			return err
+		}
+		return addFile(tw, filepath.ToSlash(rel), p)
+	})
+	for _, name := range slices.Sorted(maps.Keys(extra)) {
+		if err != nil {
+			break
+		}
+		content := extra[name]
+		err = tw.WriteHeader(&tar.Header{Name: name, Mode: 0o644, Size: int64(len(content)), Typeflag: tar.TypeReg})
+		if err == nil {
+			_, err = tw.Write(content)
+		}
+	}
+	for _, c := range []io.Closer{tw, gz, f} {
+		if cerr := c.Close(); err == nil {
+			err = cerr
+		}
+	}
+	if err != nil {
+		_ = os.Remove(dest)
+		return fmt.Errorf("write bundle: %w", err)
+	}
+	return nil
+}
+
+func addFile(tw *tar.Writer, name, p string) error {
+	src, err := os.Open(p)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+	info, err := src.Stat()
+	if err != nil {
+		return err
+	}
+	hdr, err := tar.FileInfoHeader(info, "")
+	if err != nil {
+		return err
+	}
+	hdr.Name = name
+	if err := tw.WriteHeader(hdr); err != nil {
+		return err
+	}
+	// A log still being written may grow past the header's size; copy only that much.
+	_, err = io.CopyN(tw, src, hdr.Size)
+	return err
+}
+
+// Upload copies file to d with the provider's CLI, which reads its credentials from the
+// environment (AWS_* for aws, CLOUDSDK_* for gcloud, AZURE_STORAGE_* for az), and returns
+// the uploaded object's URL.
+func Upload(ctx context.Context, d Destination, file string) (string, error) {
+	key := path.Join(d.Prefix, filepath.Base(file))
+	var cmd *exec.Cmd
+	var url string
+	switch d.Scheme {
+	case "s3":
+		url = "s3://" + d.Bucket + "/" + key
+		cmd = exec.CommandContext(ctx, "aws", "s3", "cp", "--only-show-errors", file, url)
+	case "gs":
+		url = "gs://" + d.Bucket + "/" + key
+		cmd = exec.CommandContext(ctx, "gcloud", "storage", "cp", file, url)
+	case "az":
+		cmd = exec.CommandContext(ctx, "az", "storage", "blob", "upload", "--only-show-errors", "--overwrite",
+			"--container-name", d.Bucket, "--name", key, "--file", file)
+		url = "az://" + d.Bucket + "/" + key
+		if account := os.Getenv("AZURE_STORAGE_ACCOUNT"); account != "" {
+			url = fmt.Sprintf("https://%s.blob.core.windows.net/%s/%s", account, d.Bucket, key)
+		}
+	default:
+		return "", fmt.Errorf("unknown artifact destination scheme %q", d.Scheme)
+	}
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return "", fmt.Errorf("%s upload: %v: %s", d.CLI(), err, strings.TrimSpace(string(out)))
+	}
+	return url, nil
+}
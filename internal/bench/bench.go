@@ -0,0 +1,174 @@
+package bench
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"regexp"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/asynkron/Asynkron.SwarmGo/internal/pricing"
+)
+
+// Score is what the evaluation command reported for one worktree.
+type Score struct {
+	Passed int
+	Failed int
+}
+
+var (
+	passedCount = regexp.MustCompile(`(?i)\b(\d+)\s+(?:tests?\s+)?passed\b`)
+	failedCount = regexp.MustCompile(`(?i)\b(\d+)\s+(?:tests?\s+)?failed\b`)
+	goTestLine  = regexp.MustCompile(`(?m)^\s*--- (PASS|FAIL):`)
+)
+
+// ParseScore counts passing and failing tests in test runner output. Summary lines such as
+// "12 passed, 3 failed" (pytest, jest, dotnet test) are summed; go test -v output is counted
+// per test when no summary is found.
+func ParseScore(output string) Score {
+	var s Score
+	for _, m := range passedCount.FindAllStringSubmatch(output, -1) {
+		n, _ := strconv.Atoi(m[1])
+		s.Passed += n
+	}
+	for _, m := range failedCount.FindAllStringSubmatch(output, -1) {
+		n, _ := strconv.Atoi(m[1])
+		s.Failed += n
+	}
+	if s.Passed > 0 || s.Failed > 0 {
+		return s
+	}
+	for _, m := range goTestLine.FindAllStringSubmatch(output, -1) {
+		if m[1] == "PASS" {
+			s.Passed++
+		} else {
+			s.Failed++
+		}
+	}
+	return s
+}
+
+// Eval runs command in dir through the shell and scores its output. A failing exit status
+// is expected while tests fail and is not an error.
+func Eval(ctx context.Context, dir, command string) (Score, error) {
+	cmd := shell(ctx, command)
+	cmd.Dir = dir
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		if _, ok := err.(*exec.ExitError); !ok {
+			return Score{}, fmt.Errorf("run %q: %w", command, err)
+		}
+	}
+	return ParseScore(string(out)), nil
+}
+
+func shell(ctx context.Context, command string) *exec.Cmd {
+	if runtime.GOOS == "windows" {
+		return exec.CommandContext(ctx, "cmd", "/C", command)
+	}
+	return exec.CommandContext(ctx, "sh", "-c", command)
+}
+
+// Run is the outcome of one benchmark round of one agent type.
+type Run struct {
+	Agent     string
+	Index     int
+	SessionID string
+	Before    Score
+	After     Score
+	// Evaluated is false when no evaluation command was given.
+	Evaluated bool
+	TasksDone int
+	Tasks     int
+	Cost      float64
+	// CostKnown is false when no price was known for any of the run's usage.
+	CostKnown bool
+	Wall      time.Duration
+	Err       string
+}
+
+// Fixed is the number of tests that pass after the round and did not before.
+func (r Run) Fixed() int {
+	return r.After.Passed - r.Before.Passed
+}
+
+// Table renders a Markdown comparison of the runs, one row per agent type with the mean of
+// its runs, followed by the individual runs.
+func Table(runs []Run) string {
+	var agents []string
+	byAgent := map[string][]Run{}
+	for _, r := range runs {
+		if _, ok := byAgent[r.Agent]; !ok {
+			agents = append(agents, r.Agent)
+		}
+		byAgent[r.Agent] = append(byAgent[r.Agent], r)
+	}
+
+	var b strings.Builder
+	b.WriteString("| Agent | Runs | Tests fixed | Tasks done | Cost | Wall time | Failed runs |\n")
+	b.WriteString("|---|---|---|---|---|---|---|\n")
+	for _, agent := range agents {
+		list := byAgent[agent]
+		var fixed, done, tasks, failed, costRuns int
+		var cost float64
+		var wall time.Duration
+		evaluated := false
+		for _, r := range list {
+			if r.Err != "" {
+				failed++
+			}
+			if r.Evaluated {
+				evaluated = true
+			}
+			fixed += r.Fixed()
+			done += r.TasksDone
+			tasks += r.Tasks
+			wall += r.Wall
+			if r.CostKnown {
+				cost += r.Cost
+				costRuns++
+			}
+		}
+		n := float64(len(list))
+		fixedCell := "-"
+		if evaluated {
+			fixedCell = fmt.Sprintf("%.1f", float64(fixed)/n)
+		}
+		tasksCell := "-"
+		if tasks > 0 {
+			tasksCell = fmt.Sprintf("%.1f/%.1f", float64(done)/n, float64(tasks)/n)
+		}
+		costCell := "-"
+		if costRuns > 0 {
+			costCell = pricing.Format(cost / float64(costRuns))
+		}
+		fmt.Fprintf(&b, "| %s | %d | %s | %s | %s | %s | %d |\n", agent, len(list), fixedCell, tasksCell, costCell, (wall / time.Duration(len(list))).Round(time.Second), failed)
+	}
+
+	sorted := append([]Run(nil), runs...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if sorted[i].Agent != sorted[j].Agent {
+			return sorted[i].Agent < sorted[j].Agent
+		}
+		return sorted[i].Index < sorted[j].Index
+	})
+	b.WriteString("\n| Run | Session | Passed before | Passed after | Tasks done | Cost | Wall time | Error |\n")
+	b.WriteString("|---|---|---|---|---|---|---|---|\n")
+	for _, r := range sorted {
+		before, after := "-", "-"
+		if r.Evaluated {
+			before = fmt.Sprintf("%d (%d failed)", r.Before.Passed, r.Before.Failed)
+			after = fmt.Sprintf("%d (%d failed)", r.After.Passed, r.After.Failed)
+		}
+		cost := "-"
+		if r.CostKnown {
+			cost = pricing.Format(r.Cost)
+		}
+		fmt.Fprintf(&b, "| %s #%d | %s | %s | %s | %d/%d | %s | %s | %s |\n", r.Agent, r.Index, r.SessionID, before, after, r.TasksDone, r.Tasks, cost, r.Wall.Round(time.Second), r.Err)
+	}
+	return b.String()
+}
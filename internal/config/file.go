@@ -0,0 +1,161 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/asynkron/Asynkron.SwarmGo/internal/pricing"
+)
+
+// File is the optional JSON config file. Keys are flag names without dashes in front, e.g.
+//
+//	{
+//	  "defaults": {"supervisor": "codex", "stall-action": "nudge"},
+//	  "profiles": {"review": {"claude": 1, "codex": 1, "minutes": 20}},
+//	  "pricing": {"gpt-5.2": {"input": 1.75, "output": 14, "cacheRead": 0.175}},
+//	  "roles": {"lint": {"agent": "codex", "when": "post", "prompt": "..."}}
+//	}
+//
+// Defaults apply to every run; a profile is applied on top of them with --profile. Pricing
+// overrides the built-in per-model prices used for cost estimates. Roles define extra agents
+// that --role enables (see Role).
+type File struct {
+	Defaults map[string]Value            `json:"defaults"`
+	Profiles map[string]map[string]Value `json:"profiles"`
+	Pricing  pricing.Table               `json:"pricing"`
+	Roles    map[string]Role             `json:"roles"`
+}
+
+// Value is a config file setting: a string, number or boolean, or a list of them for
+// repeatable flags such as "repo".
+type Value []string
+
+// UnmarshalJSON accepts a scalar or an array of scalars.
+func (v *Value) UnmarshalJSON(data []byte) error {
+	var list []any
+	if err := json.Unmarshal(data, &list); err == nil {
+		*v = make(Value, 0, len(list))
+		for _, item := range list {
+			s, err := scalar(item)
+			if err != nil {
+				return err
+			}
+			*v = append(*v, s)
+		}
+		return nil
+	}
+	var item any
+	if err := json.Unmarshal(data, &item); err != nil {
+		return err
+	}
+	s, err := scalar(item)
+	if err != nil {
+		return err
+	}
+	*v = Value{s}
+	return nil
+}
+
+func scalar(item any) (string, error) {
+	switch x := item.(type) {
+	case string:
+		return x, nil
+	case float64, bool:
+		return fmt.Sprint(x), nil
+	default:
+		return "", fmt.Errorf("unsupported value %v (expected a string, number or boolean)", item)
+	}
+}
+
+// BuiltinProfiles are the run shapes available without a config file. A profile of the same
+// name in the config file replaces the built-in one. They leave --arena alone.
+var BuiltinProfiles = map[string]map[string]Value{
+	"quick": {
+		"claude":       {"1"},
+		"claude-model": {"sonnet"},
+		"minutes":      {"10"},
+	},
+	"standard": {
+		"claude":       {"2"},
+		"claude-model": {"opus"},
+		"codex":        {"1"},
+		"minutes":      {"30"},
+	},
+	"overnight": {
+		"claude":        {"3"},
+		"claude-model":  {"opus"},
+		"codex":         {"2"},
+		"codex-model":   {"gpt-5.1-codex-max"},
+		"minutes":       {"480"},
+		"start-stagger": {"30s"},
+		"stall-action":  {"restart"},
+		"max-restarts":  {"10"},
+	},
+}
+
+// DefaultFilePath returns where the config file is looked up when --config is not given,
+// or "" if the user config directory is unknown.
+func DefaultFilePath() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "swarmgo", "config.json")
+}
+
+// LoadFile reads the config file at path. A missing file is only an error when required.
+func LoadFile(path string, required bool) (File, error) {
+	var f File
+	if path == "" {
+		return f, nil
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) && !required {
+		return f, nil
+	}
+	if err != nil {
+		return f, fmt.Errorf("read config file: %w", err)
+	}
+	if err := json.Unmarshal(data, &f); err != nil {
+		return f, fmt.Errorf("parse config file %s: %w", path, err)
+	}
+	if err := f.Pricing.Validate(); err != nil {
+		return f, fmt.Errorf("config file %s: %w", path, err)
+	}
+	if err := validateRoles(f.Roles, filepath.Dir(path)); err != nil {
+		return f, fmt.Errorf("config file %s: %w", path, err)
+	}
+	return f, nil
+}
+
+// Profile returns the named profile, preferring the config file over the built-ins.
+func (f File) Profile(name string) (map[string]Value, error) {
+	if p, ok := f.Profiles[name]; ok {
+		return p, nil
+	}
+	if p, ok := BuiltinProfiles[name]; ok {
+		return p, nil
+	}
+	return nil, fmt.Errorf("unknown profile %q (available: %s)", name, strings.Join(f.ProfileNames(), ", "))
+}
+
+// ProfileNames lists every available profile name, sorted.
+func (f File) ProfileNames() []string {
+	seen := map[string]bool{}
+	var names []string
+	for _, profiles := range []map[string]map[string]Value{BuiltinProfiles, f.Profiles} {
+		for name := range profiles {
+			if !seen[name] {
+				seen[name] = true
+				names = append(names, name)
+			}
+		}
+	}
+	sort.Strings(names)
+	return names
+}
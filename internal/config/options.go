@@ -3,9 +3,17 @@ package config
 import (
 	"errors"
 	"fmt"
+	"math"
 	"os"
 	"path/filepath"
+	"slices"
+	"strings"
 	"time"
+
+	"github.com/asynkron/Asynkron.SwarmGo/agentrunner"
+	"github.com/asynkron/Asynkron.SwarmGo/internal/artifact"
+	"github.com/asynkron/Asynkron.SwarmGo/internal/pricing"
+	"github.com/asynkron/Asynkron.SwarmGo/internal/sink"
 )
 
 // Options contains runtime configuration parsed from CLI flags.
@@ -14,10 +22,21 @@ type Options struct {
 	CodexWorkers   int
 	CopilotWorkers int
 	GeminiWorkers  int
+	// MockWorkers run the scripted mock agent instead of a real CLI (CI and demos).
+	MockWorkers int
 
-	Repo       string
-	Todo       string
-	Minutes    int
+	Repo string
+	// Repos lists every repository of a multi-repo run, Repo first. It is empty for
+	// single-repo runs; use Repositories to iterate either way.
+	Repos   []string
+	Todo    string
+	Minutes int
+	// RunLength is a --duration time limit; when set it replaces Minutes, which Validate
+	// rounds it up into.
+	RunLength time.Duration
+	// Until is a local wall-clock end time such as "18:30"; Validate turns it into Minutes
+	// counted from now and clears it.
+	Until      string
 	MaxRounds  int
 	Arena      bool
 	Autopilot  bool
@@ -25,9 +44,157 @@ type Options struct {
 	PrepAgent  AgentType
 	AgentMode  bool
 	AgentType  AgentType
+	// MaxStaleRounds ends the arena after that many rounds in a row without more tests
+	// passing than in any round before; 0 never does.
+	MaxStaleRounds int
+	// StopWhenPassing ends the arena after a round in which a worker's tests all passed.
+	StopWhenPassing bool
+	// WorkerNames names workers in order, e.g. "backend" and "frontend"; workers past the
+	// end of the list are "Worker N". IDs stay worker-N either way.
+	WorkerNames []string
+	// Observe lists worktrees of agents started by other tooling, each "WORKTREE" or
+	// "WORKTREE=LOG". When set no workers are launched; only the supervisor and the metrics
+	// collectors run. Validate makes the paths absolute.
+	Observe []string
+	// ObserveAgent is the CLI whose output format the observed logs are in.
+	ObserveAgent AgentType
+
+	ClaudeModel  string
+	CodexModel   string
+	CopilotModel string
+	GeminiModel  string
+
+	// MockScript is the output file mock agents replay; empty uses the built-in demo script.
+	MockScript string
+	// MockDelay is the pause after each line a mock agent prints.
+	MockDelay time.Duration
+
+	// StartStagger is the delay between worker launches; each delay gets up to half of it
+	// again as random jitter so the first prompts don't reach a provider at the same moment.
+	StartStagger time.Duration
+	// JitterModels randomises which model of a CLI's rotation each worker gets.
+	JitterModels bool
+	// ModelOffset shifts the model rotation. It is drawn once per session when JitterModels
+	// is set and persisted so resumed workers keep their models.
+	ModelOffset int
+
+	// BaseRef is the branch or commit worker worktrees of the primary repository start from;
+	// empty means its current HEAD.
+	BaseRef string
+	// SparsePaths limits worktrees to these directories (relative to the repository root)
+	// plus top-level files, so large monorepos check out quickly.
+	SparsePaths []string
+	// Submodules initialises git submodules in every new worktree.
+	Submodules bool
+	// WorktreeJobs bounds how many worker worktrees are created, checked out and have their
+	// submodules initialised at once.
+	WorktreeJobs int
+	// NoGit runs against a plain directory: workers get copied sandboxes instead of git
+	// worktrees and git-based metrics are disabled.
+	NoGit bool
+	// KeepWorktrees leaves worker worktrees in place after a round instead of removing them.
+	KeepWorktrees bool
+
+	// Safe drops the CLIs' permission bypass flags so agents run in their default sandboxes.
+	Safe bool
+
+	// AutoMerge enables squash auto-merge after the round on autopilot pull requests whose
+	// checks pass and whose files no other swarm pull request touches.
+	AutoMerge bool
+	// TrackingIssue is the GitHub issue number the orchestrator posts progress comments to;
+	// 0 disables them. With it set the supervisor no longer files issues of its own.
+	TrackingIssue int
+	// TrackingInterval is how often a progress comment is posted to the tracking issue.
+	TrackingInterval time.Duration
+
+	// ExtendOnSleep moves the round deadline by the time the machine spent suspended, rather
+	// than only offering to.
+	ExtendOnSleep bool
+
+	// StopGrace is how long a stopped agent gets to exit after SIGTERM before it is killed.
+	StopGrace time.Duration
+	// StartRetries is how often a failed agent launch is retried before giving up.
+	StartRetries int
+	// MaxRestarts bounds how often a crashed worker is restarted automatically within a round.
+	MaxRestarts int
+	// StallTimeout is how long a worker may go without output before it counts as stalled; 0 disables the check.
+	StallTimeout time.Duration
+	// StallAction is what happens to a stalled worker besides the warning.
+	StallAction StallAction
+	// TimeboxAction is what happens to a worker that overruns the time box of its task.
+	TimeboxAction TimeboxAction
+	// DuplicateAction is what happens when two workers appear to work on the same task.
+	DuplicateAction DuplicateAction
+	// DiskWarn is the session directory size in bytes that triggers a warning; 0 disables it.
+	DiskWarn int64
+	// DiskLimit is the session directory size in bytes above which no more worktrees are
+	// created; 0 disables it.
+	DiskLimit int64
+	// RedactPatterns are extra regular expressions masked in agent logs, on top of the defaults.
+	RedactPatterns []string
+	// DeniedCommands are extra regular expressions for shell commands that raise an alert,
+	// on top of the built-in denylist.
+	DeniedCommands []string
+	// DenyAction is what happens to an agent that runs a denied command besides the alert.
+	DenyAction DenyAction
+	// Report is the format of the end-of-round report besides the Markdown one.
+	Report ReportFormat
+	// UploadArtifacts is where the end-of-round bundle of logs, snapshots, reports and
+	// diffs is uploaded: s3://, gs:// or az:// followed by a bucket and optional prefix.
+	// Empty means no upload.
+	UploadArtifacts string
+	// LogSinks are extra destinations the session's event records, agent output included,
+	// are streamed to: "file:PATH", "unix:PATH" or an http(s) URL.
+	LogSinks []string
+	// Plan has the prep agent split the todo into tasks per worker, which the workers'
+	// prompts then assign instead of every worker reading the whole todo.
+	Plan bool
+	// Reviewer is the agent type that reviews the workers' branches after the round; empty
+	// means no review.
+	Reviewer AgentType
+	// Docs is the agent type that drafts changelog entries and doc updates for the workers'
+	// branches at the end of the round; empty means no docs agent.
+	Docs AgentType
+	// DocsBranch, when set, is the branch the docs agent commits its updates to.
+	DocsBranch string
+	// Tester is the agent type that keeps testing the workers' latest commits during the
+	// round; empty means no tester.
+	Tester AgentType
+	// Roles names the config file roles to run this round, in order.
+	Roles []string
+	// RoleDefs are the roles defined in the config file. Validate checks that every entry
+	// of Roles is defined; they are kept in the session so a resumed round runs the same roles.
+	RoleDefs map[string]Role
+	// ApprovePRs holds autopilot pull requests until the user approves them in the TUI.
+	ApprovePRs bool
+	// Isolation is where workers run: on the host, each in its own Docker container, each
+	// in its worktree's devcontainer, or as Kubernetes Jobs.
+	Isolation Isolation
+	// DockerImage is the image worker containers are created from under --isolation docker.
+	DockerImage string
+	// KubeImage, KubeNamespace and KubeSecret are the image worker jobs run, the namespace
+	// they are created in and the secret holding their credentials under --isolation
+	// kubernetes. KubeSecret is optional.
+	KubeImage     string
+	KubeNamespace string
+	KubeSecret    string
+	// OpenCommand is the command the TUI's o key runs for the selected agent's worktree.
+	// "{worktree}" is replaced by the path, which is appended when absent. Empty means
+	// $VISUAL, $EDITOR or code, in that order.
+	OpenCommand string
+	// Accessible replaces the TUI with linear, labeled text updates for screen readers.
+	Accessible bool
+	// TimelineInRepo also saves the timeline report to .swarm/reports in the repository.
+	TimelineInRepo bool
+	// ReportsBranch, when set, is the branch the timeline report is committed to.
+	ReportsBranch string
+	// Pricing overrides the built-in model prices cost estimates use. It comes from the
+	// config file only and is re-read when a session is resumed.
+	Pricing pricing.Table
 
 	Resume     string
 	Detect     bool
+	DetectJSON bool
 	SkipDetect bool
 }
 
@@ -39,6 +206,88 @@ const (
 	AgentCodex   AgentType = "codex"
 	AgentCopilot AgentType = "copilot"
 	AgentGemini  AgentType = "gemini"
+	// AgentMock replays a scripted output file; it needs no installed CLI or tokens.
+	AgentMock AgentType = "mock"
+)
+
+// ParseAgentType converts a CLI name such as "codex" (any case) to its AgentType. Besides
+// mock, any name registered with agentrunner.Register is accepted.
+func ParseAgentType(value string) (AgentType, error) {
+	t := AgentType(strings.ToLower(strings.TrimSpace(value)))
+	if t == AgentMock || slices.Contains(agentrunner.Names(), string(t)) {
+		return t, nil
+	}
+	return "", fmt.Errorf("unknown agent %q (expected %s or mock)", value, strings.Join(agentrunner.Names(), ", "))
+}
+
+// StallAction selects how the orchestrator reacts to a worker that has stopped producing output.
+type StallAction string
+
+const (
+	// StallWarn only reports the stall in the UI.
+	StallWarn StallAction = "warn"
+	// StallNudge sends a status-check message, restarting CLIs that can't take input while running.
+	StallNudge StallAction = "nudge"
+	// StallRestart restarts the worker with its recovery prompt.
+	StallRestart StallAction = "restart"
+)
+
+// TimeboxAction selects how the orchestrator reacts to a worker that has been on one task
+// for longer than the task's time box.
+type TimeboxAction string
+
+const (
+	// TimeboxNudge reminds the worker of the time box and asks it to wrap up or move on.
+	TimeboxNudge TimeboxAction = "nudge"
+	// TimeboxReassign restarts the worker and tells it to leave the task for another one.
+	TimeboxReassign TimeboxAction = "reassign"
+)
+
+// DuplicateAction selects how the orchestrator reacts to two workers that appear to work
+// on the same todo task.
+type DuplicateAction string
+
+const (
+	// DuplicateWarn only raises the warning.
+	DuplicateWarn DuplicateAction = "warn"
+	// DuplicateSwitch also tells the worker that took the task last to pick another one.
+	DuplicateSwitch DuplicateAction = "switch"
+)
+
+// Isolation selects where worker agents run.
+type Isolation string
+
+const (
+	// IsolationNone runs workers as processes on the host.
+	IsolationNone Isolation = "none"
+	// IsolationDocker runs each worker in its own container that sees only its worktree.
+	IsolationDocker Isolation = "docker"
+	// IsolationDevcontainer runs each worker in its worktree's devcontainer, built from the
+	// repository's devcontainer.json.
+	IsolationDevcontainer Isolation = "devcontainer"
+	// IsolationKubernetes runs each worker agent as a Kubernetes Job whose pod clones the
+	// repository; the work comes back through the branches the workers push. Experimental.
+	IsolationKubernetes Isolation = "kubernetes"
+)
+
+// DenyAction selects how the orchestrator reacts to an agent running a denied command.
+type DenyAction string
+
+const (
+	// DenyAlert only raises the alert and records the incident.
+	DenyAlert DenyAction = "alert"
+	// DenyStop also stops the agent until the user starts it again.
+	DenyStop DenyAction = "stop"
+)
+
+// ReportFormat selects the end-of-round report written to the session directory.
+type ReportFormat string
+
+const (
+	// ReportMarkdown writes report.md only.
+	ReportMarkdown ReportFormat = "markdown"
+	// ReportHTML also writes a self-contained report.html for sharing outside the terminal.
+	ReportHTML ReportFormat = "html"
 )
 
 // Validate normalizes and validates the options. It also resolves the repo path.
@@ -48,10 +297,124 @@ func (o *Options) Validate() error {
 		return nil
 	}
 
-	if o.ClaudeWorkers < 0 || o.CodexWorkers < 0 || o.CopilotWorkers < 0 || o.GeminiWorkers < 0 {
+	if o.ClaudeWorkers < 0 || o.CodexWorkers < 0 || o.CopilotWorkers < 0 || o.GeminiWorkers < 0 || o.MockWorkers < 0 {
 		return errors.New("worker counts cannot be negative")
 	}
 
+	if o.StopGrace < 0 {
+		return errors.New("--stop-grace cannot be negative")
+	}
+
+	if o.MockScript != "" {
+		// Mock agents run inside worktrees, so the script path must not be relative.
+		abs, err := filepath.Abs(o.MockScript)
+		if err != nil {
+			return fmt.Errorf("invalid --mock-script: %w", err)
+		}
+		if _, err := os.Stat(abs); err != nil {
+			return fmt.Errorf("mock script not found: %s", abs)
+		}
+		o.MockScript = abs
+	}
+
+	if o.StartStagger < 0 {
+		return errors.New("--start-stagger cannot be negative")
+	}
+
+	if o.StartRetries < 0 {
+		return errors.New("--start-retries cannot be negative")
+	}
+
+	if o.MaxRestarts < 0 {
+		return errors.New("--max-restarts cannot be negative")
+	}
+
+	if o.WorktreeJobs < 0 {
+		return errors.New("--worktree-jobs cannot be negative")
+	}
+
+	if o.DiskWarn < 0 || o.DiskLimit < 0 {
+		return errors.New("disk thresholds cannot be negative")
+	}
+
+	if o.StallTimeout < 0 {
+		return errors.New("--stall-timeout cannot be negative")
+	}
+
+	if o.TrackingIssue < 0 {
+		return errors.New("--tracking-issue must be an issue number")
+	}
+	if o.TrackingIssue > 0 && o.TrackingInterval < time.Minute {
+		return errors.New("--tracking-interval must be at least 1m")
+	}
+
+	switch o.StallAction {
+	case "":
+		o.StallAction = StallWarn
+	case StallWarn, StallNudge, StallRestart:
+	default:
+		return fmt.Errorf("invalid --stall-action %q (expected warn, nudge or restart)", o.StallAction)
+	}
+
+	switch o.TimeboxAction {
+	case "":
+		o.TimeboxAction = TimeboxNudge
+	case TimeboxNudge, TimeboxReassign:
+	default:
+		return fmt.Errorf("invalid --timebox-action %q (expected nudge or reassign)", o.TimeboxAction)
+	}
+
+	switch o.DuplicateAction {
+	case "":
+		o.DuplicateAction = DuplicateWarn
+	case DuplicateWarn, DuplicateSwitch:
+	default:
+		return fmt.Errorf("invalid --duplicate-action %q (expected warn or switch)", o.DuplicateAction)
+	}
+
+	switch o.DenyAction {
+	case "":
+		o.DenyAction = DenyAlert
+	case DenyAlert, DenyStop:
+	default:
+		return fmt.Errorf("invalid --deny-action %q (expected alert or stop)", o.DenyAction)
+	}
+
+	switch o.Report {
+	case "":
+		o.Report = ReportMarkdown
+	case ReportMarkdown, ReportHTML:
+	default:
+		return fmt.Errorf("invalid --report %q (expected markdown or html)", o.Report)
+	}
+
+	if o.UploadArtifacts != "" {
+		if _, err := artifact.ParseDestination(o.UploadArtifacts); err != nil {
+			return fmt.Errorf("invalid --upload-artifacts: %w", err)
+		}
+	}
+
+	for _, s := range o.LogSinks {
+		if _, err := sink.Parse(s); err != nil {
+			return fmt.Errorf("invalid --log-sink: %w", err)
+		}
+	}
+
+	if o.Until != "" {
+		minutes, err := MinutesUntil(o.Until, time.Now())
+		if err != nil {
+			return err
+		}
+		o.Minutes, o.RunLength, o.Until = minutes, 0, ""
+	}
+
+	if o.RunLength < 0 {
+		return errors.New("duration must be positive")
+	}
+	if o.RunLength > 0 {
+		o.Minutes = int(math.Ceil(o.RunLength.Minutes()))
+	}
+
 	if !o.Arena && o.Minutes < 1 {
 		return errors.New("minutes must be at least 1")
 	}
@@ -59,56 +422,331 @@ func (o *Options) Validate() error {
 	if o.MaxRounds < 1 {
 		return errors.New("max rounds must be at least 1")
 	}
+	if o.MaxStaleRounds < 0 {
+		return errors.New("--max-stale-rounds cannot be negative")
+	}
+	if !o.Arena && (o.MaxStaleRounds > 0 || o.StopWhenPassing) {
+		return errors.New("--max-stale-rounds and --stop-when-passing need --arena")
+	}
+	if o.Arena {
+		// Every round starts fresh worktrees from the commit the previous winner was merged
+		// into, at the same paths.
+		switch {
+		case o.AgentMode || o.Observing():
+			return errors.New("--arena cannot be combined with --agent or --observe")
+		case o.NoGit:
+			return errors.New("--arena needs git; it cannot be combined with --no-git")
+		case o.KeepWorktrees:
+			return errors.New("--arena cannot be combined with --keep-worktrees; each round replaces the worktrees")
+		}
+		// The supervisor merges each round's winner locally for the next round to start
+		// from; autopilot's pull requests would leave it out.
+		o.Autopilot = false
+	}
 
-	if o.AgentMode {
-		o.ClaudeWorkers, o.CodexWorkers, o.CopilotWorkers, o.GeminiWorkers = 0, 0, 0, 0
+	if o.Observing() {
+		if o.AgentMode {
+			return errors.New("--observe and --agent cannot be combined")
+		}
+		// Worker counts may come from config file defaults; observation launches none.
+		o.ClaudeWorkers, o.CodexWorkers, o.CopilotWorkers, o.GeminiWorkers, o.MockWorkers = 0, 0, 0, 0, 0
+		if o.AutoMerge {
+			return errors.New("--auto-merge cannot be combined with --observe")
+		}
+		if o.ObserveAgent == "" {
+			o.ObserveAgent = AgentClaude
+		}
+		for i, spec := range o.Observe {
+			worktree, log, err := observedPaths(spec)
+			if err != nil {
+				return err
+			}
+			o.Observe[i] = worktree
+			if log != "" {
+				o.Observe[i] += "=" + log
+			}
+		}
+	} else if o.AgentMode {
+		o.ClaudeWorkers, o.CodexWorkers, o.CopilotWorkers, o.GeminiWorkers, o.MockWorkers = 0, 0, 0, 0, 0
 		if o.AgentType == "" {
 			o.AgentType = AgentCodex
 		}
 		// Single-agent mode runs directly in the repo; disable autopilot/branch creation.
 		o.Autopilot = false
-	} else if o.ClaudeWorkers+o.CodexWorkers+o.CopilotWorkers+o.GeminiWorkers == 0 {
+	} else if o.TotalWorkers() == 0 {
 		// Default to two Claude workers when nothing is specified.
 		o.ClaudeWorkers = 2
 	}
 
+	if err := o.validateWorkerNames(); err != nil {
+		return err
+	}
+
 	if o.PrepAgent == "" {
 		o.PrepAgent = AgentClaude
 	}
 
+	if o.Repo == "" && len(o.Repos) > 0 {
+		o.Repo = o.Repos[0]
+	}
+	if o.Repo == "" && o.NoGit {
+		wd, err := os.Getwd()
+		if err != nil {
+			return err
+		}
+		o.Repo = wd
+	}
 	if o.Repo == "" {
 		root, err := findGitRoot()
 		if err != nil {
 			return err
 		}
 		o.Repo = root
-	} else {
-		abs, err := filepath.Abs(o.Repo)
+	}
+	if o.NoGit {
+		if o.BaseRef != "" {
+			return errors.New("--base-ref needs git; it cannot be combined with --no-git")
+		}
+		if len(o.SparsePaths) > 0 {
+			return errors.New("--sparse needs git; it cannot be combined with --no-git")
+		}
+		if o.Submodules {
+			return errors.New("--submodules needs git; it cannot be combined with --no-git")
+		}
+		if o.TrackingIssue > 0 {
+			return errors.New("--tracking-issue needs a GitHub repository; it cannot be combined with --no-git")
+		}
+		if o.ReportsBranch != "" {
+			return errors.New("--reports-branch needs git; it cannot be combined with --no-git")
+		}
+		if o.Reviewer != "" {
+			return errors.New("--reviewer needs git; it cannot be combined with --no-git")
+		}
+		if o.Tester != "" {
+			return errors.New("--tester needs git; it cannot be combined with --no-git")
+		}
+		if o.Docs != "" {
+			return errors.New("--docs needs git; it cannot be combined with --no-git")
+		}
+		// Branches and PRs need git.
+		o.Autopilot = false
+	}
+	if o.AutoMerge && !o.Autopilot {
+		return errors.New("--auto-merge needs --autopilot (it is off in --agent, --arena and --no-git mode)")
+	}
+	if o.Plan && (o.AgentMode || o.Observing()) {
+		return errors.New("--plan assigns tasks to workers; it cannot be combined with --agent or --observe")
+	}
+	if o.Reviewer != "" {
+		if o.AgentMode || o.Observing() {
+			return errors.New("--reviewer reviews worker branches; it cannot be combined with --agent or --observe")
+		}
+		t, err := ParseAgentType(string(o.Reviewer))
+		if err != nil {
+			return fmt.Errorf("invalid --reviewer value: %w", err)
+		}
+		o.Reviewer = t
+	}
+	if o.Tester != "" {
+		if o.AgentMode || o.Observing() {
+			return errors.New("--tester tests worker commits; it cannot be combined with --agent or --observe")
+		}
+		t, err := ParseAgentType(string(o.Tester))
+		if err != nil {
+			return fmt.Errorf("invalid --tester value: %w", err)
+		}
+		o.Tester = t
+	}
+	if o.Docs != "" {
+		if o.AgentMode || o.Observing() {
+			return errors.New("--docs documents worker branches; it cannot be combined with --agent or --observe")
+		}
+		t, err := ParseAgentType(string(o.Docs))
 		if err != nil {
-			return fmt.Errorf("invalid repo path: %w", err)
+			return fmt.Errorf("invalid --docs value: %w", err)
 		}
-		o.Repo = abs
+		o.Docs = t
+	}
+	if o.DocsBranch != "" && o.Docs == "" {
+		return errors.New("--docs-branch needs --docs")
+	}
+	if len(o.Roles) > 0 && (o.AgentMode || o.Observing()) {
+		return errors.New("--role runs alongside a swarm round; it cannot be combined with --agent or --observe")
+	}
+	seenRoles := map[string]bool{}
+	for _, name := range o.Roles {
+		if _, ok := o.RoleDefs[name]; !ok {
+			return fmt.Errorf("unknown --role %q (define it under \"roles\" in the config file)", name)
+		}
+		if seenRoles[name] {
+			return fmt.Errorf("--role %q is given twice", name)
+		}
+		seenRoles[name] = true
+	}
+	if o.ApprovePRs && !o.Autopilot {
+		return errors.New("--approve-prs needs --autopilot (it is off in --agent, --arena and --no-git mode)")
+	}
+	switch o.Isolation {
+	case "":
+		o.Isolation = IsolationNone
+	case IsolationNone:
+	case IsolationDocker, IsolationDevcontainer, IsolationKubernetes:
+		if o.AgentMode || o.Observing() {
+			return fmt.Errorf("--isolation %s contains workers; it cannot be combined with --agent or --observe", o.Isolation)
+		}
+		if o.Isolation == IsolationDocker && o.DockerImage == "" {
+			return errors.New("--isolation docker needs --docker-image")
+		}
+		if o.Isolation == IsolationKubernetes {
+			if o.KubeImage == "" {
+				return errors.New("--isolation kubernetes needs --k8s-image")
+			}
+			// Pods share nothing with the host; worker commits only come back when pushed.
+			if !o.Autopilot {
+				return errors.New("--isolation kubernetes needs --autopilot, which has workers push their branches")
+			}
+			if o.KubeNamespace == "" {
+				o.KubeNamespace = "default"
+			}
+		}
+		if o.ApprovePRs && o.Isolation == IsolationKubernetes {
+			// Workers write their pull request drafts to the session directory, which pods
+			// can't see.
+			return errors.New("--approve-prs cannot be combined with --isolation kubernetes")
+		}
+	default:
+		return fmt.Errorf("invalid --isolation %q (expected none, docker, devcontainer or kubernetes)", o.Isolation)
+	}
+	if o.Todo == "" {
+		o.Todo = "todo.md"
+	}
+	for i, p := range o.SparsePaths {
+		dir, err := sparseDir(p)
+		if err != nil {
+			return err
+		}
+		o.SparsePaths[i] = dir
 	}
 
-	info, err := os.Stat(o.Repo)
-	if err != nil || !info.IsDir() {
-		return fmt.Errorf("repository path does not exist: %s", o.Repo)
+	repos := o.Repositories()
+	if len(repos) > 1 {
+		if o.AgentMode {
+			return errors.New("--agent runs in a single repository; pass --repo once")
+		}
+		if o.Observing() {
+			return errors.New("--observe watches a single repository; pass --repo once")
+		}
+		if o.TotalWorkers() < len(repos) {
+			return fmt.Errorf("%d repositories need at least %d workers", len(repos), len(repos))
+		}
+	}
+	seen := make(map[string]bool, len(repos))
+	for i, repo := range repos {
+		abs, err := validateRepo(repo, o.Todo, o.NoGit)
+		if err != nil {
+			return err
+		}
+		if seen[abs] {
+			return fmt.Errorf("repository given twice: %s", abs)
+		}
+		seen[abs] = true
+		repos[i] = abs
+	}
+	o.Repo = repos[0]
+	if len(repos) > 1 {
+		o.Repos = repos
 	}
 
-	gitDir := filepath.Join(o.Repo, ".git")
-	if _, err := os.Stat(gitDir); err != nil {
-		return fmt.Errorf("not a git repository: %s", o.Repo)
+	return nil
+}
+
+// sparseDir cleans a --sparse directory into the slash-separated, repository-relative form
+// git's sparse checkout takes on every platform.
+func sparseDir(p string) (string, error) {
+	clean := filepath.ToSlash(filepath.Clean(p))
+	// "/src" is not absolute to filepath on Windows, but git would still read it from the root.
+	if filepath.IsAbs(p) || strings.HasPrefix(clean, "/") || clean == "." || clean == ".." || strings.HasPrefix(clean, "../") {
+		return "", fmt.Errorf("invalid --sparse %q (expected a directory inside the repository)", p)
 	}
+	return clean, nil
+}
 
-	if o.Todo == "" {
-		o.Todo = "todo.md"
+// validateRepo resolves a repository path and checks it contains the todo file and, unless
+// noGit is set, is a git checkout.
+func validateRepo(repo, todo string, noGit bool) (string, error) {
+	abs, err := filepath.Abs(repo)
+	if err != nil {
+		return "", fmt.Errorf("invalid repo path: %w", err)
+	}
+
+	info, err := os.Stat(abs)
+	if err != nil || !info.IsDir() {
+		return "", fmt.Errorf("repository path does not exist: %s", abs)
+	}
+
+	gitDir := filepath.Join(abs, ".git")
+	if _, err := os.Stat(gitDir); err != nil && !noGit {
+		return "", fmt.Errorf("not a git repository: %s (use --no-git for a plain directory)", abs)
 	}
-	todoPath := filepath.Join(o.Repo, o.Todo)
+
+	todoPath := filepath.Join(abs, todo)
 	if _, err := os.Stat(todoPath); err != nil {
-		return fmt.Errorf("todo file not found: %s", todoPath)
+		return "", fmt.Errorf("todo file not found: %s", todoPath)
 	}
+	return abs, nil
+}
 
-	return nil
+// observedPaths splits an --observe value into its absolute worktree and log paths. The
+// worktree must exist; the log may not have been written yet.
+func observedPaths(spec string) (worktree, log string, err error) {
+	worktree, log, _ = strings.Cut(spec, "=")
+	if worktree == "" {
+		return "", "", fmt.Errorf("invalid --observe %q (expected WORKTREE or WORKTREE=LOG)", spec)
+	}
+	if worktree, err = filepath.Abs(worktree); err != nil {
+		return "", "", fmt.Errorf("invalid --observe worktree: %w", err)
+	}
+	if info, err := os.Stat(worktree); err != nil || !info.IsDir() {
+		return "", "", fmt.Errorf("observed worktree does not exist: %s", worktree)
+	}
+	if log != "" {
+		if log, err = filepath.Abs(log); err != nil {
+			return "", "", fmt.Errorf("invalid --observe log: %w", err)
+		}
+	}
+	return worktree, log, nil
+}
+
+// Observing reports whether the run watches externally managed agents instead of
+// launching workers.
+func (o Options) Observing() bool {
+	return len(o.Observe) > 0
+}
+
+// ObservedWorkers returns the worktree and log path of every observed agent; the log is
+// empty when only the worktree is watched.
+func (o Options) ObservedWorkers() (worktrees, logs []string) {
+	for _, spec := range o.Observe {
+		worktree, log, _ := strings.Cut(spec, "=")
+		worktrees = append(worktrees, worktree)
+		logs = append(logs, log)
+	}
+	return worktrees, logs
+}
+
+// Repositories returns every repository of the run, the primary Repo first.
+func (o Options) Repositories() []string {
+	if len(o.Repos) > 0 {
+		return append([]string(nil), o.Repos...)
+	}
+	return []string{o.Repo}
+}
+
+// RepoFor returns the repository worker index i (zero-based) works on. Workers are
+// distributed round-robin so every repository gets at least one.
+func (o Options) RepoFor(i int) string {
+	repos := o.Repositories()
+	return repos[i%len(repos)]
 }
 
 // TotalWorkers returns the sum of all configured worker counts.
@@ -116,11 +754,127 @@ func (o Options) TotalWorkers() int {
 	if o.AgentMode {
 		return 1
 	}
-	return o.ClaudeWorkers + o.CodexWorkers + o.CopilotWorkers + o.GeminiWorkers
+	return o.ClaudeWorkers + o.CodexWorkers + o.CopilotWorkers + o.GeminiWorkers + o.MockWorkers
+}
+
+// WorkerName returns the display name of worker index i (zero-based): its --worker-name,
+// else "Worker N".
+func (o Options) WorkerName(i int) string {
+	if i < len(o.WorkerNames) {
+		return o.WorkerNames[i]
+	}
+	return fmt.Sprintf("Worker %d", i+1)
+}
+
+// WorkerSlug returns the name of worker index i as used in branch names: its --worker-name
+// in lower case with anything but letters, digits, dots and dashes turned into dashes, else
+// "workerN".
+func (o Options) WorkerSlug(i int) string {
+	if i < len(o.WorkerNames) {
+		return slug(o.WorkerNames[i])
+	}
+	return fmt.Sprintf("worker%d", i+1)
+}
+
+func slug(name string) string {
+	s := strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9', r == '.', r == '-':
+			return r
+		case r >= 'A' && r <= 'Z':
+			return r + 'a' - 'A'
+		default:
+			return '-'
+		}
+	}, strings.TrimSpace(name))
+	return strings.Trim(s, "-.")
+}
+
+// validateWorkerNames checks that every --worker-name names a worker and that no two
+// workers share a name or a branch name.
+func (o *Options) validateWorkerNames() error {
+	if len(o.WorkerNames) == 0 {
+		return nil
+	}
+	count := o.TotalWorkers()
+	if o.Observing() {
+		count = len(o.Observe)
+	}
+	if len(o.WorkerNames) > count {
+		return fmt.Errorf("%d worker names given for %d workers", len(o.WorkerNames), count)
+	}
+	seen := make(map[string]string)
+	for i, name := range o.WorkerNames {
+		name = strings.TrimSpace(name)
+		o.WorkerNames[i] = name
+		s := slug(name)
+		if s == "" {
+			return fmt.Errorf("invalid --worker-name %q: it needs a letter or digit", name)
+		}
+		if prev, ok := seen[s]; ok {
+			return fmt.Errorf("--worker-name %q and %q are the same in branch names", prev, name)
+		}
+		seen[s] = name
+	}
+	return nil
+}
+
+// WorkerSummary describes the worker counts per agent type, e.g. "Claude 2, Codex 1, Copilot 0, Gemini 0".
+func (o Options) WorkerSummary() string {
+	s := fmt.Sprintf("Claude %d, Codex %d, Copilot %d, Gemini %d", o.ClaudeWorkers, o.CodexWorkers, o.CopilotWorkers, o.GeminiWorkers)
+	if o.MockWorkers > 0 {
+		s += fmt.Sprintf(", Mock %d", o.MockWorkers)
+	}
+	return s
+}
+
+// ModelFor returns the model override configured for an agent type, or "" for the CLI default.
+func (o Options) ModelFor(t AgentType) string {
+	switch t {
+	case AgentClaude:
+		return o.ClaudeModel
+	case AgentCodex:
+		return o.CodexModel
+	case AgentCopilot:
+		return o.CopilotModel
+	case AgentGemini:
+		return o.GeminiModel
+	default:
+		return ""
+	}
+}
+
+// untilLayouts are the accepted --until formats, tried in order.
+var untilLayouts = []string{"15:04", "3:04PM", "3PM"}
+
+// MinutesUntil returns the whole minutes from now until the next occurrence of clock, a local
+// time of day like "18:30" or "6:30pm". A time that has already passed today means tomorrow.
+func MinutesUntil(clock string, now time.Time) (int, error) {
+	value := strings.ToUpper(strings.ReplaceAll(clock, " ", ""))
+	for _, layout := range untilLayouts {
+		t, err := time.Parse(layout, value)
+		if err != nil {
+			continue
+		}
+		end := time.Date(now.Year(), now.Month(), now.Day(), t.Hour(), t.Minute(), 0, 0, now.Location())
+		if !end.After(now) {
+			end = end.AddDate(0, 0, 1)
+		}
+		// Round down so the run never ends after the requested time.
+		minutes := int(end.Sub(now) / time.Minute)
+		if minutes < 1 {
+			return 0, fmt.Errorf("--until %s is less than a minute away", clock)
+		}
+		return minutes, nil
+	}
+	return 0, fmt.Errorf("invalid --until %q (expected a time like 18:30 or 6:30pm)", clock)
 }
 
 // Duration returns the configured time limit for a round.
 func (o Options) Duration() time.Duration {
+	if o.RunLength > 0 {
+		return o.RunLength
+	}
 	return time.Duration(o.Minutes) * time.Minute
 }
 
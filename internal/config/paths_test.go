@@ -0,0 +1,88 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestSparseDir(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+		ok   bool
+	}{
+		{in: "src", want: "src", ok: true},
+		{in: "src/", want: "src", ok: true},
+		{in: filepath.Join("src", "pkg"), want: "src/pkg", ok: true},
+		{in: filepath.Join("src", "..", "lib"), want: "lib", ok: true},
+		{in: "./docs", want: "docs", ok: true},
+		{in: ".", ok: false},
+		{in: "..", ok: false},
+		{in: filepath.Join("..", "other"), ok: false},
+		{in: "/src", ok: false},
+		{in: filepath.Join(os.TempDir(), "src"), ok: false},
+	}
+	for _, tt := range tests {
+		got, err := sparseDir(tt.in)
+		if (err == nil) != tt.ok {
+			t.Errorf("sparseDir(%q) error = %v, want ok %v", tt.in, err, tt.ok)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("sparseDir(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestObservedPaths(t *testing.T) {
+	dir := t.TempDir()
+	wt := filepath.Join(dir, "wt")
+	if err := os.Mkdir(wt, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	log := filepath.Join(dir, "logs", "worker.log")
+
+	tests := []struct {
+		spec    string
+		wantWT  string
+		wantLog string
+		ok      bool
+	}{
+		{spec: wt, wantWT: wt, ok: true},
+		{spec: wt + "=" + log, wantWT: wt, wantLog: log, ok: true},
+		// The log need not exist yet.
+		{spec: wt + "=" + filepath.Join(dir, "missing.log"), wantWT: wt, wantLog: filepath.Join(dir, "missing.log"), ok: true},
+		{spec: "=" + log, ok: false},
+		{spec: filepath.Join(dir, "missing"), ok: false},
+	}
+	for _, tt := range tests {
+		gotWT, gotLog, err := observedPaths(tt.spec)
+		if (err == nil) != tt.ok {
+			t.Errorf("observedPaths(%q) error = %v, want ok %v", tt.spec, err, tt.ok)
+			continue
+		}
+		if gotWT != tt.wantWT || gotLog != tt.wantLog {
+			t.Errorf("observedPaths(%q) = %q, %q, want %q, %q", tt.spec, gotWT, gotLog, tt.wantWT, tt.wantLog)
+		}
+	}
+}
+
+func TestObservedPathsRelative(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.Mkdir(filepath.Join(dir, "wt"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	t.Chdir(dir)
+	wt, log, err := observedPaths("wt=" + filepath.Join("logs", "a.log"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Relative paths resolve against the working directory, with the platform's separators.
+	if !filepath.IsAbs(wt) || !filepath.IsAbs(log) {
+		t.Fatalf("observedPaths returned relative paths %q, %q", wt, log)
+	}
+	if filepath.Base(wt) != "wt" || filepath.Base(filepath.Dir(log)) != "logs" {
+		t.Errorf("observedPaths = %q, %q", wt, log)
+	}
+}
@@ -0,0 +1,105 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"text/template"
+	"time"
+)
+
+// RoleTiming is when a user-defined role runs in the round.
+type RoleTiming string
+
+const (
+	// RolePre runs in the prep worktree after the prep agent; what it commits is part of
+	// the commit workers start from.
+	RolePre RoleTiming = "pre"
+	// RoleDuring runs alongside the workers and is stopped with them.
+	RoleDuring RoleTiming = "during"
+	// RolePost runs after the workers stop, before the report is written.
+	RolePost RoleTiming = "post"
+)
+
+// Role is an agent defined in the config file's "roles" section, e.g.
+//
+//	"roles": {
+//	  "security-scan": {
+//	    "agent": "claude",
+//	    "when": "post",
+//	    "prompt": "Review the worker branches for security issues: {{range .Workers}}{{.Worktree}} {{end}}"
+//	  }
+//	}
+//
+// Defined roles run when named with --role (or "role" in the defaults or a profile).
+type Role struct {
+	Agent AgentType `json:"agent"`
+	// Model overrides the agent type's model; empty keeps its default.
+	Model string     `json:"model,omitempty"`
+	When  RoleTiming `json:"when"`
+	// Prompt is a text/template rendered with the round's details (see prompts.RoleData).
+	// PromptFile reads the template from a file instead, relative to the config file.
+	Prompt     string `json:"prompt,omitempty"`
+	PromptFile string `json:"promptFile,omitempty"`
+	// Minutes bounds a pre or post role; zero means 15.
+	Minutes int `json:"minutes,omitempty"`
+}
+
+var roleName = regexp.MustCompile(`^[a-z0-9][a-z0-9-]*$`)
+
+// validateRoles checks the roles of a config file, reading prompt files relative to dir.
+func validateRoles(roles map[string]Role, dir string) error {
+	names := make([]string, 0, len(roles))
+	for name := range roles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		r := roles[name]
+		if !roleName.MatchString(name) {
+			return fmt.Errorf("role %q: names may only hold lowercase letters, digits and dashes", name)
+		}
+		t, err := ParseAgentType(string(r.Agent))
+		if err != nil {
+			return fmt.Errorf("role %q: %w", name, err)
+		}
+		r.Agent = t
+		switch r.When {
+		case RolePre, RoleDuring, RolePost:
+		default:
+			return fmt.Errorf("role %q: when must be pre, during or post, not %q", name, r.When)
+		}
+		if r.PromptFile != "" {
+			path := r.PromptFile
+			if !filepath.IsAbs(path) {
+				path = filepath.Join(dir, path)
+			}
+			data, err := os.ReadFile(path)
+			if err != nil {
+				return fmt.Errorf("role %q: %w", name, err)
+			}
+			r.Prompt, r.PromptFile = string(data), ""
+		}
+		if r.Prompt == "" {
+			return fmt.Errorf("role %q: prompt or promptFile is required", name)
+		}
+		if _, err := template.New(name).Parse(r.Prompt); err != nil {
+			return fmt.Errorf("role %q: %w", name, err)
+		}
+		if r.Minutes < 0 {
+			return fmt.Errorf("role %q: minutes must not be negative", name)
+		}
+		roles[name] = r
+	}
+	return nil
+}
+
+// Timeout is how long a pre or post role may run.
+func (r Role) Timeout() time.Duration {
+	if r.Minutes == 0 {
+		return 15 * time.Minute
+	}
+	return time.Duration(r.Minutes) * time.Minute
+}
@@ -1,5 +1,11 @@
 package control
 
+import (
+	"time"
+
+	"github.com/asynkron/Asynkron.SwarmGo/internal/config"
+)
+
 // Command represents a UI-initiated action sent to the orchestrator.
 type Command interface{ isCommand() }
 
@@ -11,6 +17,39 @@ type RestartAgent struct {
 
 func (RestartAgent) isCommand() {}
 
+// SendMessage delivers a follow-up message to a running agent without restarting it.
+// Only agents whose CLI keeps stdin open (Claude) can receive it.
+type SendMessage struct {
+	AgentID string
+	Text    string
+}
+
+func (SendMessage) isCommand() {}
+
+// Broadcast delivers a message to every running worker: live to CLIs that keep stdin open,
+// by restarting the others with the message injected.
+type Broadcast struct {
+	Message string
+}
+
+func (Broadcast) isCommand() {}
+
+// AdjustTime moves the round deadline by Delta; negative values shorten the round.
+type AdjustTime struct {
+	Delta time.Duration
+}
+
+func (AdjustTime) isCommand() {}
+
+// SwapSupervisor replaces the running supervisor with one of another agent type, optionally
+// with a model override. Workers keep running.
+type SwapSupervisor struct {
+	AgentType config.AgentType
+	Model     string
+}
+
+func (SwapSupervisor) isCommand() {}
+
 // StopAgent requests that an agent be stopped.
 type StopAgent struct{ AgentID string }
 
@@ -21,9 +60,30 @@ type StartAgent struct{ AgentID string }
 
 func (StartAgent) isCommand() {}
 
+// TakeOver stops a worker so a human can continue its conversation in the CLI's
+// interactive mode.
+type TakeOver struct{ AgentID string }
+
+func (TakeOver) isCommand() {}
+
+// ApprovePR opens the pull request a worker is waiting to have approved.
+type ApprovePR struct{ AgentID string }
+
+func (ApprovePR) isCommand() {}
+
+// RejectPR discards the pull request a worker is waiting to have approved.
+type RejectPR struct{ AgentID string }
+
+func (RejectPR) isCommand() {}
+
 // StartUserCommand starts the one-off user agent with a specific prompt.
 type StartUserCommand struct {
 	Message string
 }
 
 func (StartUserCommand) isCommand() {}
+
+// Shutdown asks the orchestrator to stop every agent and end the run.
+type Shutdown struct{}
+
+func (Shutdown) isCommand() {}
@@ -0,0 +1,51 @@
+package denylist
+
+import (
+	"fmt"
+	"regexp"
+)
+
+// defaultPatterns catch the commands that do damage outside an agent's worktree or can't
+// be undone by resetting it.
+var defaultPatterns = []string{
+	// Force pushes rewrite shared history (--force-with-lease included).
+	`\bgit\s+push\b.*(?:\s--force\b|\s--force-with-lease\b|\s-[a-zA-Z]*f[a-zA-Z]*\b|\s\+\S+)`,
+	// Recursive deletes of the filesystem root or the home directory.
+	`\brm\s+(?:-\S+\s+)*-[a-zA-Z]*[rR][a-zA-Z]*\s+(?:-\S+\s+)*(?:/|~/?|\$HOME/?|/\*|~/\*)(?:\s|$|;|&)`,
+	// Piping a download straight into a shell.
+	`\b(?:curl|wget)\b[^|]*\|\s*(?:sudo\s+)?(?:ba|z|da)?sh\b`,
+	// Writing to raw devices and formatting disks.
+	`\bdd\b.*\bof=/dev/`,
+	`\bmkfs(?:\.\w+)?\b`,
+}
+
+// List matches shell commands against denied patterns.
+type List struct {
+	patterns []*regexp.Regexp
+}
+
+// New builds a List from the default patterns plus any extra regular expressions.
+func New(extra []string) (*List, error) {
+	l := &List{}
+	for _, expr := range append(append([]string(nil), defaultPatterns...), extra...) {
+		re, err := regexp.Compile(expr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid denied command pattern %q: %w", expr, err)
+		}
+		l.patterns = append(l.patterns, re)
+	}
+	return l, nil
+}
+
+// Match returns the first pattern command matches. A nil List matches nothing.
+func (l *List) Match(command string) (pattern string, ok bool) {
+	if l == nil {
+		return "", false
+	}
+	for _, re := range l.patterns {
+		if re.MatchString(command) {
+			return re.String(), true
+		}
+	}
+	return "", false
+}
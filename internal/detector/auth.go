@@ -0,0 +1,104 @@
+package detector
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"time"
+
+	"github.com/asynkron/Asynkron.SwarmGo/internal/config"
+)
+
+// AuthState describes whether a CLI appears to be logged in.
+type AuthState string
+
+const (
+	AuthUnknown       AuthState = "unknown"
+	AuthAuthenticated AuthState = "authenticated"
+	AuthMissing       AuthState = "not logged in"
+)
+
+// authProbe lists the signals used to decide whether an agent CLI is authenticated.
+type authProbe struct {
+	envVars   []string // any non-empty variable counts as credentials
+	credFiles []string // credential files relative to the home directory
+	configDir string   // home-relative config dir; absent means the CLI was never set up
+	command   []string // status command; exit 0 means logged in
+	hint      string
+}
+
+var authProbes = map[config.AgentType]authProbe{
+	config.AgentClaude: {
+		envVars:   []string{"ANTHROPIC_API_KEY", "CLAUDE_CODE_OAUTH_TOKEN"},
+		credFiles: []string{".claude/.credentials.json"},
+		configDir: ".claude",
+		command:   []string{"claude", "auth", "status"},
+		hint:      "run `claude` and complete /login, or set ANTHROPIC_API_KEY",
+	},
+	config.AgentCodex: {
+		envVars:   []string{"OPENAI_API_KEY", "CODEX_API_KEY"},
+		credFiles: []string{".codex/auth.json"},
+		configDir: ".codex",
+		command:   []string{"codex", "login", "status"},
+		hint:      "run `codex login`, or set OPENAI_API_KEY",
+	},
+	config.AgentCopilot: {
+		envVars:   []string{"COPILOT_GITHUB_TOKEN", "GH_TOKEN", "GITHUB_TOKEN"},
+		configDir: ".copilot",
+		command:   []string{"gh", "auth", "status"},
+		hint:      "run `copilot` and use /login, or `gh auth login`",
+	},
+	config.AgentGemini: {
+		envVars:   []string{"GEMINI_API_KEY", "GOOGLE_API_KEY", "GOOGLE_GENAI_USE_VERTEXAI"},
+		credFiles: []string{".gemini/oauth_creds.json"},
+		configDir: ".gemini",
+		hint:      "run `gemini` once to sign in, or set GEMINI_API_KEY",
+	},
+}
+
+var notLoggedInRegex = regexp.MustCompile(`(?i)(not logged in|not authenticated|logged out|login required|no credentials|please log ?in)`)
+
+const authProbeTimeout = 10 * time.Second
+
+// LoginHint returns a remediation hint for an agent that is not logged in.
+func LoginHint(t config.AgentType) string {
+	return authProbes[t].hint
+}
+
+func detectAuth(t config.AgentType) AuthState {
+	probe, ok := authProbes[t]
+	if !ok {
+		return AuthUnknown
+	}
+	for _, name := range probe.envVars {
+		if os.Getenv(name) != "" {
+			return AuthAuthenticated
+		}
+	}
+	home, _ := os.UserHomeDir()
+	if home != "" {
+		for _, f := range probe.credFiles {
+			if _, err := os.Stat(filepath.Join(home, f)); err == nil {
+				return AuthAuthenticated
+			}
+		}
+	}
+	if len(probe.command) > 0 {
+		if _, err := exec.LookPath(probe.command[0]); err == nil {
+			out, err := runProbe(authProbeTimeout, probe.command[0], probe.command[1:]...)
+			if err == nil && !notLoggedInRegex.Match(out) {
+				return AuthAuthenticated
+			}
+			if notLoggedInRegex.Match(out) {
+				return AuthMissing
+			}
+		}
+	}
+	if home != "" && probe.configDir != "" {
+		if _, err := os.Stat(filepath.Join(home, probe.configDir)); os.IsNotExist(err) {
+			return AuthMissing
+		}
+	}
+	return AuthUnknown
+}
@@ -2,21 +2,30 @@ package detector
 
 import (
 	"bytes"
+	"context"
+	"fmt"
 	"os/exec"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/asynkron/Asynkron.SwarmGo/internal/config"
 )
 
 // Status represents detection info for a single agent CLI.
 type Status struct {
-	Type       config.AgentType
-	Executable string
-	Installed  bool
-	Version    string
-	Error      string
+	Type       config.AgentType `json:"type"`
+	Executable string           `json:"path,omitempty"`
+	Installed  bool             `json:"installed"`
+	Version    string           `json:"version,omitempty"`
+	Outdated   bool             `json:"outdated"`
+	Auth       AuthState        `json:"auth,omitempty"`
+	Error      string           `json:"error,omitempty"`
 }
 
+// versionTimeout bounds a single `--version` probe; some CLIs touch the network on startup.
+const versionTimeout = 5 * time.Second
+
 // DetectAll checks every supported agent type with a lightweight PATH lookup and optional version probe.
 func DetectAll() []Status {
 	types := []config.AgentType{
@@ -26,10 +35,18 @@ func DetectAll() []Status {
 		config.AgentGemini,
 	}
 
-	results := make([]Status, 0, len(types))
-	for _, t := range types {
-		results = append(results, detect(t))
+	// Probes run concurrently; each is bounded by its own timeout so a single
+	// stalled CLI cannot hold up the others.
+	results := make([]Status, len(types))
+	var wg sync.WaitGroup
+	for i, t := range types {
+		wg.Add(1)
+		go func(i int, t config.AgentType) {
+			defer wg.Done()
+			results[i] = detect(t)
+		}(i, t)
 	}
+	wg.Wait()
 	return results
 }
 
@@ -37,7 +54,7 @@ func detect(t config.AgentType) Status {
 	exe := string(t)
 	path, err := exec.LookPath(exe)
 	if err != nil {
-		return Status{Type: t, Executable: exe, Installed: false, Error: "not found in PATH"}
+		return Status{Type: t, Installed: false, Error: "not found in PATH"}
 	}
 
 	version, vErr := getVersion(exe)
@@ -51,22 +68,38 @@ func detect(t config.AgentType) Status {
 		Executable: path,
 		Installed:  true,
 		Version:    version,
+		Outdated:   isOutdated(t, version),
+		Auth:       detectAuth(t),
 		Error:      errMsg,
 	}
 }
 
 func getVersion(exe string) (string, error) {
-	cmd := exec.Command(exe, "--version")
-	var out bytes.Buffer
-	cmd.Stdout = &out
-	cmd.Stderr = &out
-	if err := cmd.Run(); err != nil {
+	out, err := runProbe(versionTimeout, exe, "--version")
+	if err != nil {
 		return "", err
 	}
 
-	lines := strings.Split(out.String(), "\n")
+	lines := strings.Split(string(out), "\n")
 	if len(lines) > 0 {
 		return strings.TrimSpace(lines[0]), nil
 	}
-	return strings.TrimSpace(out.String()), nil
+	return strings.TrimSpace(string(out)), nil
+}
+
+// runProbe runs a CLI with combined output, killing it once timeout elapses.
+// WaitDelay also covers child processes that keep the output pipe open after the kill.
+func runProbe(timeout time.Duration, name string, args ...string) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	cmd := exec.CommandContext(ctx, name, args...)
+	cmd.WaitDelay = time.Second
+	var out bytes.Buffer
+	cmd.Stdout = &out
+	cmd.Stderr = &out
+	err := cmd.Run()
+	if ctx.Err() == context.DeadlineExceeded {
+		return out.Bytes(), fmt.Errorf("%s timed out after %s", strings.Join(append([]string{name}, args...), " "), timeout)
+	}
+	return out.Bytes(), err
 }
@@ -0,0 +1,86 @@
+package detector
+
+import (
+	"fmt"
+	"os/exec"
+	"regexp"
+	"strings"
+
+	"github.com/asynkron/Asynkron.SwarmGo/internal/config"
+)
+
+var (
+	choicesRegex = regexp.MustCompile(`choices:\s*([^)]*)`)
+	quotedRegex  = regexp.MustCompile(`"([^"]+)"`)
+)
+
+// claudeAliases are the model aliases the Claude CLI resolves itself.
+var claudeAliases = []string{"opus", "sonnet", "haiku", "opusplan"}
+
+// ListModels asks the CLI for the models it accepts. It returns nil when the CLI has no way to
+// enumerate them, in which case callers should not validate.
+func ListModels(t config.AgentType) ([]string, error) {
+	switch t {
+	case config.AgentCopilot:
+		return copilotModels()
+	case config.AgentClaude:
+		return append([]string(nil), claudeAliases...), nil
+	default:
+		return nil, nil
+	}
+}
+
+// ValidateModel checks a configured model against the models the CLI reports.
+func ValidateModel(t config.AgentType, model string) error {
+	if model == "" {
+		return nil
+	}
+	switch t {
+	case config.AgentClaude:
+		// Full model IDs are passed through by the CLI.
+		if strings.HasPrefix(model, "claude-") {
+			return nil
+		}
+	case config.AgentGemini:
+		if strings.HasPrefix(model, "gemini-") {
+			return nil
+		}
+		return fmt.Errorf("unknown gemini model %q (expected a gemini-* model name)", model)
+	}
+	models, err := ListModels(t)
+	if err != nil || len(models) == 0 {
+		return nil
+	}
+	for _, m := range models {
+		if m == model {
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown %s model %q; valid options: %s", t, model, strings.Join(models, ", "))
+}
+
+// copilotModels parses the --model choices out of `copilot --help`.
+func copilotModels() ([]string, error) {
+	if _, err := exec.LookPath("copilot"); err != nil {
+		return nil, nil
+	}
+	out, err := runProbe(authProbeTimeout, "copilot", "--help")
+	if err != nil {
+		return nil, err
+	}
+	for _, line := range strings.Split(string(out), "\n") {
+		if !strings.Contains(line, "--model") {
+			continue
+		}
+		m := choicesRegex.FindStringSubmatch(line)
+		if m == nil {
+			continue
+		}
+		var models []string
+		for _, q := range quotedRegex.FindAllStringSubmatch(m[1], -1) {
+			models = append(models, q[1])
+		}
+		return models, nil
+	}
+	return nil, nil
+}
@@ -0,0 +1,99 @@
+package detector
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/asynkron/Asynkron.SwarmGo/internal/config"
+)
+
+// versionRequirement is the oldest CLI release whose flags match what the arg builders emit.
+type versionRequirement struct {
+	minimum string
+	reason  string
+	upgrade string
+}
+
+var versionRequirements = map[config.AgentType]versionRequirement{
+	config.AgentClaude: {
+		minimum: "1.0.0",
+		reason:  "needs --output-format stream-json and --tools",
+		upgrade: "npm install -g @anthropic-ai/claude-code@latest",
+	},
+	config.AgentCodex: {
+		minimum: "0.46.0",
+		reason:  "needs `codex exec` with --dangerously-bypass-approvals-and-sandbox",
+		upgrade: "npm install -g @openai/codex@latest",
+	},
+	config.AgentCopilot: {
+		minimum: "0.0.330",
+		reason:  "needs --allow-all-paths and --stream",
+		upgrade: "npm install -g @github/copilot@latest",
+	},
+	config.AgentGemini: {
+		minimum: "0.11.0",
+		reason:  "needs --output-format stream-json",
+		upgrade: "npm install -g @google/gemini-cli@latest",
+	},
+}
+
+var versionRegex = regexp.MustCompile(`\d+\.\d+(?:\.\d+)?`)
+
+// MinimumVersion returns the oldest supported version for an agent, or "" when unconstrained.
+func MinimumVersion(t config.AgentType) string {
+	return versionRequirements[t].minimum
+}
+
+// UpgradeHint explains why an agent is too old and how to upgrade it.
+func UpgradeHint(t config.AgentType) string {
+	req, ok := versionRequirements[t]
+	if !ok {
+		return ""
+	}
+	return req.reason + "; upgrade with `" + req.upgrade + "`"
+}
+
+// InstallHint returns the command that installs an agent's CLI, or "" when unknown.
+func InstallHint(t config.AgentType) string {
+	req, ok := versionRequirements[t]
+	if !ok {
+		return ""
+	}
+	return "install with `" + req.upgrade + "`"
+}
+
+// isOutdated reports whether the version string is older than the agent's minimum. Unparseable
+// versions are treated as acceptable so detection never blocks on odd --version output.
+func isOutdated(t config.AgentType, version string) bool {
+	req, ok := versionRequirements[t]
+	if !ok || req.minimum == "" {
+		return false
+	}
+	found := versionRegex.FindString(version)
+	if found == "" {
+		return false
+	}
+	return compareVersions(found, req.minimum) < 0
+}
+
+func compareVersions(a, b string) int {
+	as := strings.Split(a, ".")
+	bs := strings.Split(b, ".")
+	for i := 0; i < len(as) || i < len(bs); i++ {
+		var av, bv int
+		if i < len(as) {
+			av, _ = strconv.Atoi(as[i])
+		}
+		if i < len(bs) {
+			bv, _ = strconv.Atoi(bs[i])
+		}
+		if av != bv {
+			if av < bv {
+				return -1
+			}
+			return 1
+		}
+	}
+	return 0
+}
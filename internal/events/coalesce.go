@@ -0,0 +1,51 @@
+package events
+
+import "time"
+
+const (
+	// coalesceDelay is how long Coalesce holds agent lines before passing them on.
+	coalesceDelay = 50 * time.Millisecond
+	// coalesceLines is the most lines Coalesce gathers into one AgentLines.
+	coalesceLines = 256
+)
+
+// Coalesce forwards the events from in to out, gathering AgentLine events into AgentLines
+// batches so chatty agents cost the UI one update per batch instead of one per line. A
+// batch is passed on after coalesceDelay, once it holds coalesceLines lines, or right
+// before any other event so the order is kept. out is closed once in is.
+func Coalesce(in <-chan Event, out chan<- Event) {
+	defer close(out)
+	var batch []AgentLine
+	var flushAt <-chan time.Time
+	flush := func() {
+		if len(batch) > 0 {
+			out <- AgentLines{Lines: batch}
+			batch = nil
+		}
+		flushAt = nil
+	}
+	for {
+		select {
+		case ev, ok := <-in:
+			if !ok {
+				flush()
+				return
+			}
+			line, isLine := ev.(AgentLine)
+			if !isLine {
+				flush()
+				out <- ev
+				continue
+			}
+			if len(batch) == 0 {
+				flushAt = time.After(coalesceDelay)
+			}
+			batch = append(batch, line)
+			if len(batch) >= coalesceLines {
+				flush()
+			}
+		case <-flushAt:
+			flush()
+		}
+	}
+}
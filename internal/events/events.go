@@ -13,6 +13,7 @@ type AgentAdded struct {
 	LogPath  string
 	Worktree string
 	Running  bool
+	Restarts int
 }
 
 type AgentRemoved struct{ ID string }
@@ -28,6 +29,12 @@ type AgentLine struct {
 	Line string
 }
 
+// AgentLines is a run of AgentLine events gathered by Coalesce for the UI. It only exists
+// downstream of the recorder; event logs keep the individual lines.
+type AgentLines struct {
+	Lines []AgentLine
+}
+
 type StatusMessage struct{ Message string }
 
 type PhaseChanged struct{ Phase string }
@@ -37,13 +44,188 @@ type RoundChanged struct {
 	Total   int
 }
 
-type RemainingTime struct{ Duration time.Duration }
+// RemainingTime reports the time left in the round. Deadline is when the round ends; it is
+// zero once the round is over.
+type RemainingTime struct {
+	Duration time.Duration
+	Deadline time.Time
+}
 
 type TodoLoaded struct {
 	Content string
 	Path    string
 }
 
+// TodoProgress reports how many tasks from the initial todo have been completed across workers.
+type TodoProgress struct {
+	Done  int
+	Total int
+}
+
+// Slept reports that the machine was suspended for Duration during the round. With
+// Extended the deadline has already moved by that much; otherwise Offer is how far to move
+// it to give the time back, and Held says the round ran out meanwhile and was kept open for
+// a short while to allow that.
+type Slept struct {
+	Duration time.Duration
+	Extended bool
+	Offer    time.Duration
+	Held     bool
+}
+
+// RateLimited reports that an agent's output showed a provider rate limit or usage cap.
+// Until is when the cooldown ends; zero means the provider did not say.
+type RateLimited struct {
+	ID      string
+	Until   time.Time
+	Message string
+}
+
+// CommandDenied reports that an agent ran a shell command matching the denylist. Stopped
+// is set when the agent was stopped for it.
+type CommandDenied struct {
+	ID      string
+	Command string
+	Pattern string
+	Stopped bool
+}
+
+// AgentTakeover reports that a worker has stopped so a human can take over its
+// conversation: the UI runs Command with Args in Dir attached to the terminal.
+type AgentTakeover struct {
+	ID      string
+	Command string
+	Args    []string
+	Dir     string
+}
+
+// PromptInjected reports a message given to an agent mid-run: injected into its prompt on
+// a restart, or sent live when Live is set.
+type PromptInjected struct {
+	ID      string
+	Time    time.Time
+	Message string
+	Live    bool
+}
+
+// PendingPR is a pull request a worker asked for while pull requests need approval, and
+// what became of it. State is pending, opening, opened, rejected or failed.
+type PendingPR struct {
+	ID      string
+	Branch  string
+	Title   string
+	Body    string
+	Commits []string
+	Stat    string
+	State   string
+	URL     string
+	Error   string
+}
+
+// ScratchpadChanged carries the shared scratchpad after an agent edited it. Edits are not
+// merged: Content is whatever the last writer left.
+type ScratchpadChanged struct {
+	Path    string
+	Content string
+	Time    time.Time
+}
+
+// Verdict is the supervisor's structured judgement of the round, from verdict.json.
+// Winner is a worker number, 0 for no winner.
+type Verdict struct {
+	Winner    int
+	Rationale string
+	Scores    []VerdictScore
+}
+
+// VerdictScore is how one worker's worktree did in the verdict, from 0 to 10.
+type VerdictScore struct {
+	Worker int
+	Score  float64
+	Tests  string
+	Notes  string
+}
+
+// FileConflict warns that two workers modify the same files, found by the coded supervisor.
+// IDs holds the two worker IDs and Files the files both touch so far.
+type FileConflict struct {
+	IDs   []string
+	Files []string
+}
+
+// DuplicateTask warns that two workers appear to work on the same todo task, judged by
+// the tasks they announced and the messages of their commits. IDs holds the two worker
+// IDs and Task the task's title, or a commit message when it matches no task.
+type DuplicateTask struct {
+	IDs  []string
+	Task string
+}
+
+// QuotaWarning carries a usage cap notice from an agent's output, such as "5-hour limit
+// reached" or a weekly usage warning.
+type QuotaWarning struct {
+	ID      string
+	Message string
+}
+
+// AgentUsage reports the token usage a CLI printed for one finished agent turn.
+type AgentUsage struct {
+	ID string
+	// Model is the model the agent was started with; empty means the CLI's default.
+	Model            string
+	InputTokens      int
+	OutputTokens     int
+	CacheReadTokens  int
+	CacheWriteTokens int
+	CostUSD          float64
+}
+
+// AgentStartFailed describes a failed attempt to launch an agent process.
+type AgentStartFailed struct {
+	ID       string
+	Attempt  int
+	Attempts int
+	// Command is the executable name; Path is where it resolved on PATH, empty if it didn't.
+	Command string
+	Path    string
+	Workdir string
+	Error   string
+}
+
+// AgentStalled warns that a running agent has produced no output for Idle.
+type AgentStalled struct {
+	ID   string
+	Idle time.Duration
+}
+
+// AgentHeartbeat is sent periodically for every agent whose process is running. LastActive
+// is when the process last wrote to its log, so a hung agent stands out.
+type AgentHeartbeat struct {
+	ID         string
+	LastActive time.Time
+}
+
+// DiskUsage reports how much space the session directory (worktrees and logs) takes.
+// Warn and Limit are the configured thresholds in bytes; 0 means unset.
+type DiskUsage struct {
+	Bytes int64
+	Warn  int64
+	Limit int64
+}
+
+// PRStatus reports the pull request an autopilot worker opened and the state of its CI
+// checks. It is sent when the pull request first appears and whenever it changes.
+type PRStatus struct {
+	ID      string
+	Branch  string
+	Number  int
+	URL     string
+	Title   string
+	State   string
+	Checks  string
+	Summary string
+}
+
 type CompletedWorker struct {
 	Worker  int
 	LogPath string
@@ -91,14 +273,33 @@ const (
 	MessageSee
 )
 
-func (AgentAdded) isEvent()      {}
-func (AgentRemoved) isEvent()    {}
-func (AgentStopped) isEvent()    {}
-func (AgentLine) isEvent()       {}
-func (StatusMessage) isEvent()   {}
-func (PhaseChanged) isEvent()    {}
-func (RoundChanged) isEvent()    {}
-func (RemainingTime) isEvent()   {}
-func (TodoLoaded) isEvent()      {}
-func (CompletedWorker) isEvent() {}
-func (AgentStatus) isEvent()     {}
+func (AgentAdded) isEvent()        {}
+func (AgentRemoved) isEvent()      {}
+func (AgentStopped) isEvent()      {}
+func (AgentLine) isEvent()         {}
+func (AgentLines) isEvent()        {}
+func (StatusMessage) isEvent()     {}
+func (PhaseChanged) isEvent()      {}
+func (RoundChanged) isEvent()      {}
+func (RemainingTime) isEvent()     {}
+func (Slept) isEvent()             {}
+func (TodoLoaded) isEvent()        {}
+func (TodoProgress) isEvent()      {}
+func (AgentUsage) isEvent()        {}
+func (RateLimited) isEvent()       {}
+func (CommandDenied) isEvent()     {}
+func (AgentTakeover) isEvent()     {}
+func (PromptInjected) isEvent()    {}
+func (PendingPR) isEvent()         {}
+func (QuotaWarning) isEvent()      {}
+func (AgentStartFailed) isEvent()  {}
+func (AgentStalled) isEvent()      {}
+func (AgentHeartbeat) isEvent()    {}
+func (DiskUsage) isEvent()         {}
+func (PRStatus) isEvent()          {}
+func (CompletedWorker) isEvent()   {}
+func (AgentStatus) isEvent()       {}
+func (ScratchpadChanged) isEvent() {}
+func (Verdict) isEvent()           {}
+func (FileConflict) isEvent()      {}
+func (DuplicateTask) isEvent()     {}
@@ -0,0 +1,161 @@
+package events
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// Priority says what Queue may do with an event when the consumer falls behind.
+type Priority int
+
+const (
+	// Critical events change state the UI can't rebuild later; they are never dropped.
+	Critical Priority = iota
+	// Latest events report a current value; a newer one with the same key replaces one
+	// still waiting, and only the newest is delivered.
+	Latest
+	// BestEffort events are agent output; past queueLines waiting they are dropped.
+	BestEffort
+)
+
+// queueLines is how many agent lines Queue holds before dropping new ones.
+const queueLines = 10000
+
+// PriorityOf classifies an event for Queue. For Latest events key identifies what the
+// event reports on, so only events about the same thing replace each other.
+func PriorityOf(ev Event) (p Priority, key string) {
+	switch e := ev.(type) {
+	case AgentLine, AgentLines:
+		return BestEffort, ""
+	case RemainingTime, TodoProgress, DiskUsage:
+		return Latest, reflect.TypeOf(ev).Name()
+	case AgentHeartbeat:
+		return Latest, "AgentHeartbeat/" + e.ID
+	case AgentStatus:
+		return Latest, "AgentStatus/" + e.ID
+	default:
+		return Critical, ""
+	}
+}
+
+// Droppable reports whether an emitter may drop ev rather than wait for room in a full
+// channel. Everything but agent output must wait; Queue keeps that wait short.
+func Droppable(ev Event) bool {
+	p, _ := PriorityOf(ev)
+	return p == BestEffort
+}
+
+// Queue forwards the events from in to out in order, always ready to receive so that
+// emitters never wait on a slow consumer. Events waiting for out are held by priority:
+// critical ones without bound, Latest ones compacted to the newest per key in the place
+// of the first, and agent lines up to queueLines; past that they are dropped, and a
+// StatusMessage says how many once the backlog has halved. out is closed once in is and
+// everything held has been delivered.
+func Queue(in <-chan Event, out chan<- Event) {
+	defer close(out)
+	q := newEventQueue()
+	for in != nil || q.len() > 0 {
+		var send chan<- Event
+		next, ok := q.peek()
+		if ok {
+			send = out
+		}
+		select {
+		case ev, open := <-in:
+			if !open {
+				in = nil
+				continue
+			}
+			q.push(ev)
+		case send <- next:
+			q.pop()
+		}
+	}
+}
+
+type queued struct {
+	ev  Event
+	key string
+}
+
+// eventQueue is a FIFO whose entries live in items[head:]; slots maps the key of each
+// waiting Latest event to its position, counted from the first entry ever pushed.
+type eventQueue struct {
+	items   []queued
+	head    int
+	base    int
+	slots   map[string]int
+	lines   int
+	dropped int
+}
+
+func newEventQueue() *eventQueue {
+	return &eventQueue{slots: make(map[string]int)}
+}
+
+func (q *eventQueue) len() int { return len(q.items) - q.head }
+
+func (q *eventQueue) push(ev Event) {
+	p, key := PriorityOf(ev)
+	switch p {
+	case Latest:
+		if pos, ok := q.slots[key]; ok {
+			q.items[pos-q.base].ev = ev
+			return
+		}
+		q.slots[key] = q.base + len(q.items)
+	case BestEffort:
+		if q.lines >= queueLines {
+			q.dropped += lineCount(ev)
+			return
+		}
+		q.lines++
+	}
+	q.items = append(q.items, queued{ev: ev, key: key})
+}
+
+func (q *eventQueue) peek() (Event, bool) {
+	if q.len() == 0 {
+		return nil, false
+	}
+	return q.items[q.head].ev, true
+}
+
+func (q *eventQueue) pop() {
+	it := q.items[q.head]
+	q.items[q.head] = queued{}
+	q.head++
+	if it.key != "" {
+		delete(q.slots, it.key)
+	}
+	if p, _ := PriorityOf(it.ev); p == BestEffort {
+		q.lines--
+		if q.dropped > 0 && q.lines < queueLines/2 {
+			q.items = append(q.items, queued{ev: q.droppedNote()})
+		}
+	}
+	if q.head == len(q.items) {
+		q.base += q.head
+		q.items, q.head = q.items[:0], 0
+	} else if q.head >= 1024 && q.head*2 >= len(q.items) {
+		// Reuse the delivered half of the backing array rather than grow it forever.
+		n := copy(q.items, q.items[q.head:])
+		clear(q.items[n:])
+		q.base += q.head
+		q.items, q.head = q.items[:n], 0
+	}
+}
+
+// droppedNote reports the agent lines dropped since the last note and resets the count.
+func (q *eventQueue) droppedNote() Event {
+	n := q.dropped
+	q.dropped = 0
+	return StatusMessage{Message: fmt.Sprintf("UI fell behind: dropped %d lines of agent output (full output is in the agent logs)", n)}
+}
+
+func lineCount(ev Event) int {
+	if batch, ok := ev.(AgentLines); ok {
+		return len(batch.Lines)
+	}
+	return 1
+}
@@ -0,0 +1,174 @@
+package events
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// drain pops everything q holds, in delivery order.
+func drain(q *eventQueue) []Event {
+	var out []Event
+	for {
+		ev, ok := q.peek()
+		if !ok {
+			return out
+		}
+		out = append(out, ev)
+		q.pop()
+	}
+}
+
+func isDroppedNote(ev Event) bool {
+	msg, ok := ev.(StatusMessage)
+	return ok && strings.HasPrefix(msg.Message, "UI fell behind")
+}
+
+func TestQueueNeverDropsCriticalEvents(t *testing.T) {
+	q := newEventQueue()
+	for i := range 3 * queueLines {
+		q.push(AgentLine{ID: "worker-1", Line: fmt.Sprint(i)})
+		if i%100 == 0 {
+			q.push(StatusMessage{Message: fmt.Sprint(i)})
+		}
+	}
+	var critical, lines int
+	for _, ev := range drain(q) {
+		switch e := ev.(type) {
+		case StatusMessage:
+			if isDroppedNote(e) {
+				continue
+			}
+			if want := fmt.Sprint(critical * 100); e.Message != want {
+				t.Fatalf("critical event %d = %q, want %q", critical, e.Message, want)
+			}
+			critical++
+		case AgentLine:
+			lines++
+		}
+	}
+	if critical != 3*queueLines/100 {
+		t.Errorf("delivered %d critical events, want %d", critical, 3*queueLines/100)
+	}
+	if lines != queueLines {
+		t.Errorf("delivered %d agent lines, want %d", lines, queueLines)
+	}
+}
+
+func TestQueueCollapsesLatestEvents(t *testing.T) {
+	q := newEventQueue()
+	beat := func(id string, sec int) AgentHeartbeat {
+		return AgentHeartbeat{ID: id, LastActive: time.Unix(int64(sec), 0)}
+	}
+	q.push(RemainingTime{Duration: 3 * time.Minute})
+	q.push(beat("worker-1", 1))
+	q.push(StatusMessage{Message: "a"})
+	q.push(beat("worker-2", 2))
+	q.push(RemainingTime{Duration: 2 * time.Minute})
+	q.push(beat("worker-1", 3))
+	q.push(RemainingTime{Duration: time.Minute})
+	want := []Event{
+		RemainingTime{Duration: time.Minute},
+		beat("worker-1", 3),
+		StatusMessage{Message: "a"},
+		beat("worker-2", 2),
+	}
+	if got := drain(q); !reflect.DeepEqual(got, want) {
+		t.Fatalf("delivered %v, want %v", got, want)
+	}
+	// Once delivered, a key starts over at the back of the queue.
+	q.push(StatusMessage{Message: "b"})
+	q.push(RemainingTime{Duration: time.Second})
+	want = []Event{StatusMessage{Message: "b"}, RemainingTime{Duration: time.Second}}
+	if got := drain(q); !reflect.DeepEqual(got, want) {
+		t.Fatalf("delivered %v, want %v", got, want)
+	}
+}
+
+func TestQueueReportsDroppedLinesOnceHalved(t *testing.T) {
+	q := newEventQueue()
+	for i := range queueLines + 5 {
+		q.push(AgentLine{ID: "worker-1", Line: fmt.Sprint(i)})
+	}
+	for range queueLines / 2 {
+		q.pop()
+	}
+	for _, it := range q.items[q.head:] {
+		if isDroppedNote(it.ev) {
+			t.Fatalf("dropped note queued with %d lines waiting", q.lines)
+		}
+	}
+	q.pop()
+	var notes []Event
+	rest := drain(q)
+	for _, ev := range rest {
+		if isDroppedNote(ev) {
+			notes = append(notes, ev)
+		}
+	}
+	if len(notes) != 1 {
+		t.Fatalf("got %d dropped notes, want 1", len(notes))
+	}
+	if !strings.Contains(notes[0].(StatusMessage).Message, "dropped 5 lines") {
+		t.Errorf("note = %q, want it to count 5 lines", notes[0].(StatusMessage).Message)
+	}
+	if !isDroppedNote(rest[len(rest)-1]) {
+		t.Errorf("note not queued behind the waiting lines")
+	}
+}
+
+// TestQueuePositionsSurviveCompaction checks the queue against a plain slice model over
+// enough pops with a backlog waiting that the queue compacts its array several times.
+func TestQueuePositionsSurviveCompaction(t *testing.T) {
+	q := newEventQueue()
+	var model []Event
+	rng := rand.New(rand.NewPCG(1, 2))
+	compactions := 0
+	for step := range 20000 {
+		if q.len() < 1500 || rng.IntN(2) == 0 {
+			var ev Event
+			switch rng.IntN(4) {
+			case 0:
+				ev = StatusMessage{Message: fmt.Sprint(step)}
+			case 1:
+				ev = AgentLine{ID: "worker-1", Line: fmt.Sprint(step)}
+			case 2:
+				ev = RemainingTime{Duration: time.Duration(step)}
+			default:
+				ev = AgentHeartbeat{ID: fmt.Sprintf("worker-%d", rng.IntN(3)), LastActive: time.Unix(int64(step), 0)}
+			}
+			q.push(ev)
+			_, key := PriorityOf(ev)
+			replaced := false
+			for i, m := range model {
+				if _, k := PriorityOf(m); key != "" && k == key {
+					model[i] = ev
+					replaced = true
+				}
+			}
+			if !replaced {
+				model = append(model, ev)
+			}
+			continue
+		}
+		got, _ := q.peek()
+		if !reflect.DeepEqual(got, model[0]) {
+			t.Fatalf("step %d: delivered %v, want %v", step, got, model[0])
+		}
+		head := q.head
+		q.pop()
+		model = model[1:]
+		if head >= 1024 && q.head == 0 && q.len() > 0 {
+			compactions++
+		}
+	}
+	if compactions == 0 {
+		t.Fatal("the queue never compacted with a backlog waiting")
+	}
+	if got := drain(q); !reflect.DeepEqual(got, model) {
+		t.Fatalf("final backlog differs from the model (%d events, want %d)", len(got), len(model))
+	}
+}
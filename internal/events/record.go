@@ -0,0 +1,201 @@
+package events
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"reflect"
+	"sync"
+	"time"
+)
+
+// Record is a single persisted event with its position in the stream.
+type Record struct {
+	Seq   int64           `json:"seq"`
+	Time  time.Time       `json:"time"`
+	Type  string          `json:"type"`
+	Data  json.RawMessage `json:"data"`
+	Event Event           `json:"-"`
+}
+
+// registry maps persisted type names back to concrete event types.
+var registry = func() map[string]reflect.Type {
+	known := []Event{
+		AgentAdded{},
+		AgentRemoved{},
+		AgentStopped{},
+		AgentLine{},
+		StatusMessage{},
+		PhaseChanged{},
+		RoundChanged{},
+		RemainingTime{},
+		Slept{},
+		TodoLoaded{},
+		TodoProgress{},
+		AgentUsage{},
+		RateLimited{},
+		CommandDenied{},
+		AgentTakeover{},
+		PromptInjected{},
+		PendingPR{},
+		QuotaWarning{},
+		AgentStartFailed{},
+		AgentStalled{},
+		AgentHeartbeat{},
+		DiskUsage{},
+		PRStatus{},
+		CompletedWorker{},
+		AgentStatus{},
+		ScratchpadChanged{},
+		Verdict{},
+		FileConflict{},
+		DuplicateTask{},
+	}
+	m := make(map[string]reflect.Type, len(known))
+	for _, ev := range known {
+		t := reflect.TypeOf(ev)
+		m[t.Name()] = t
+	}
+	return m
+}()
+
+// Recorder appends events to a JSONL file with sequence numbers and timestamps.
+type Recorder struct {
+	mu    sync.Mutex
+	file  *os.File
+	seq   int64
+	sinks []Sink
+}
+
+// Sink receives a copy of every record the Recorder persists, as one JSON line. Send
+// must not block.
+type Sink interface {
+	Send(line []byte)
+}
+
+// AddSink streams every record written from now on to s as well.
+func (r *Recorder) AddSink(s Sink) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.sinks = append(r.sinks, s)
+}
+
+// NewRecorder opens (or continues) the event log at path. Resumed sessions keep counting
+// from the last persisted sequence number.
+func NewRecorder(path string) (*Recorder, error) {
+	last, err := lastSeq(path)
+	if err != nil {
+		return nil, err
+	}
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("open event log: %w", err)
+	}
+	return &Recorder{file: f, seq: last}, nil
+}
+
+// Write persists one event.
+func (r *Recorder) Write(ev Event) error {
+	data, err := json.Marshal(ev)
+	if err != nil {
+		return fmt.Errorf("encode event: %w", err)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.seq++
+	line, err := json.Marshal(Record{
+		Seq:  r.seq,
+		Time: time.Now(),
+		Type: reflect.TypeOf(ev).Name(),
+		Data: data,
+	})
+	if err != nil {
+		return fmt.Errorf("encode event record: %w", err)
+	}
+	line = append(line, '\n')
+	for _, s := range r.sinks {
+		s.Send(line)
+	}
+	_, err = r.file.Write(line)
+	return err
+}
+
+// Close closes the underlying file.
+func (r *Recorder) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.file.Close()
+}
+
+// Tee forwards every event from in to out, persisting it on the way. out is closed
+// once in is drained. Write errors are ignored so a full disk never stalls the UI.
+func (r *Recorder) Tee(in <-chan Event, out chan<- Event) {
+	defer close(out)
+	for ev := range in {
+		_ = r.Write(ev)
+		out <- ev
+	}
+}
+
+// ReadLog loads all records from an event log, decoding each payload into its event type.
+// Records with unknown types are skipped so older binaries can read newer logs.
+func ReadLog(path string) ([]Record, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("open event log: %w", err)
+	}
+	defer f.Close()
+
+	var records []Record
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var rec Record
+		if err := json.Unmarshal(scanner.Bytes(), &rec); err != nil {
+			return nil, fmt.Errorf("parse event record: %w", err)
+		}
+		t, ok := registry[rec.Type]
+		if !ok {
+			continue
+		}
+		v := reflect.New(t)
+		if err := json.Unmarshal(rec.Data, v.Interface()); err != nil {
+			return nil, fmt.Errorf("decode %s event %d: %w", rec.Type, rec.Seq, err)
+		}
+		rec.Event = v.Elem().Interface().(Event)
+		records = append(records, rec)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("read event log: %w", err)
+	}
+	return records, nil
+}
+
+func lastSeq(path string) (int64, error) {
+	f, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("open event log: %w", err)
+	}
+	defer f.Close()
+
+	var last int64
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		var rec struct {
+			Seq int64 `json:"seq"`
+		}
+		if json.Unmarshal(scanner.Bytes(), &rec) == nil && rec.Seq > last {
+			last = rec.Seq
+		}
+	}
+	return last, scanner.Err()
+}
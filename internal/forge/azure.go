@@ -0,0 +1,81 @@
+package forge
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// azure drives the az CLI with the azure-devops extension. az reads the organization,
+// project and repository from the checkout's remote.
+type azure struct{}
+
+func (azure) Kind() Kind { return AzureDevOps }
+
+type azurePR struct {
+	ID            int    `json:"pullRequestId"`
+	Title         string `json:"title"`
+	Status        string `json:"status"`
+	SourceRefName string `json:"sourceRefName"`
+	Repository    struct {
+		WebURL string `json:"webUrl"`
+	} `json:"repository"`
+}
+
+func (p azurePR) pr() PR {
+	return PR{
+		Number: p.ID,
+		Title:  p.Title,
+		URL:    fmt.Sprintf("%s/pullrequest/%d", p.Repository.WebURL, p.ID),
+		Branch: strings.TrimPrefix(p.SourceRefName, "refs/heads/"),
+		State:  strings.ToUpper(p.Status),
+	}
+}
+
+func (azure) CreatePR(ctx context.Context, dir string, pr NewPR) (PR, error) {
+	if pr.Branch == "" {
+		branch, err := currentBranch(ctx, dir)
+		if err != nil {
+			return PR{}, err
+		}
+		pr.Branch = branch
+	}
+	args := []string{"repos", "pr", "create", "--title", pr.Title, "--description", pr.Body, "--source-branch", pr.Branch, "--output", "json"}
+	if pr.Base != "" {
+		args = append(args, "--target-branch", pr.Base)
+	}
+	out, err := run(ctx, dir, "", "az", args...)
+	if err != nil {
+		return PR{}, err
+	}
+	var created azurePR
+	if err := json.Unmarshal(out, &created); err != nil {
+		return PR{}, fmt.Errorf("parse az repos pr create output: %w", err)
+	}
+	return created.pr(), nil
+}
+
+func (azure) ListPRs(ctx context.Context, dir string) ([]PR, error) {
+	out, err := run(ctx, dir, "", "az", "repos", "pr", "list", "--status", "active", "--output", "json")
+	if err != nil {
+		return nil, err
+	}
+	var list []azurePR
+	if err := json.Unmarshal(out, &list); err != nil {
+		return nil, fmt.Errorf("parse az repos pr list output: %w", err)
+	}
+	prs := make([]PR, 0, len(list))
+	for _, p := range list {
+		prs = append(prs, p.pr())
+	}
+	return prs, nil
+}
+
+// CommentIssue adds to the discussion of work item number; Azure DevOps tracks issues as
+// work items.
+func (azure) CommentIssue(ctx context.Context, dir string, number int, body string) error {
+	_, err := run(ctx, dir, "", "az", "boards", "work-item", "update", "--id", strconv.Itoa(number), "--discussion", body, "--output", "none")
+	return err
+}
@@ -0,0 +1,157 @@
+package forge
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"regexp"
+	"strings"
+)
+
+// bitbucketAPI is the Bitbucket Cloud REST API root.
+const bitbucketAPI = "https://api.bitbucket.org/2.0"
+
+var bitbucketRemote = regexp.MustCompile(`bitbucket\.org[:/]([^/]+)/([^/]+?)(?:\.git)?/?$`)
+
+// bitbucket talks to the Bitbucket Cloud REST API. It authenticates with BITBUCKET_TOKEN
+// (an access token) or BITBUCKET_USERNAME and BITBUCKET_APP_PASSWORD.
+type bitbucket struct {
+	workspace string
+	repo      string
+}
+
+func newBitbucket(remote string) (Forge, error) {
+	m := bitbucketRemote.FindStringSubmatch(remote)
+	if m == nil {
+		return nil, fmt.Errorf("cannot read the Bitbucket workspace and repository from %q", remote)
+	}
+	return bitbucket{workspace: m[1], repo: m[2]}, nil
+}
+
+// bitbucketCredentials returns the Authorization header value from the environment, or ""
+// if none is configured.
+func bitbucketCredentials() string {
+	if token := os.Getenv("BITBUCKET_TOKEN"); token != "" {
+		return "Bearer " + token
+	}
+	user, pass := os.Getenv("BITBUCKET_USERNAME"), os.Getenv("BITBUCKET_APP_PASSWORD")
+	if user != "" && pass != "" {
+		return "Basic " + base64.StdEncoding.EncodeToString([]byte(user+":"+pass))
+	}
+	return ""
+}
+
+func (bitbucket) Kind() Kind { return Bitbucket }
+
+type bitbucketPR struct {
+	ID     int    `json:"id"`
+	Title  string `json:"title"`
+	State  string `json:"state"`
+	Source struct {
+		Branch struct {
+			Name string `json:"name"`
+		} `json:"branch"`
+	} `json:"source"`
+	Links struct {
+		HTML struct {
+			Href string `json:"href"`
+		} `json:"html"`
+	} `json:"links"`
+}
+
+func (p bitbucketPR) pr() PR {
+	return PR{Number: p.ID, Title: p.Title, URL: p.Links.HTML.Href, Branch: p.Source.Branch.Name, State: p.State}
+}
+
+func (b bitbucket) CreatePR(ctx context.Context, dir string, pr NewPR) (PR, error) {
+	if pr.Branch == "" {
+		branch, err := currentBranch(ctx, dir)
+		if err != nil {
+			return PR{}, err
+		}
+		pr.Branch = branch
+	}
+	req := map[string]any{
+		"title":       pr.Title,
+		"description": pr.Body,
+		"source":      map[string]any{"branch": map[string]string{"name": pr.Branch}},
+	}
+	if pr.Base != "" {
+		req["destination"] = map[string]any{"branch": map[string]string{"name": pr.Base}}
+	}
+	var created bitbucketPR
+	if err := b.call(ctx, http.MethodPost, "/pullrequests", req, &created); err != nil {
+		return PR{}, err
+	}
+	return created.pr(), nil
+}
+
+func (b bitbucket) ListPRs(ctx context.Context, dir string) ([]PR, error) {
+	var page struct {
+		Values []bitbucketPR `json:"values"`
+	}
+	if err := b.call(ctx, http.MethodGet, "/pullrequests?state=OPEN", nil, &page); err != nil {
+		return nil, err
+	}
+	prs := make([]PR, 0, len(page.Values))
+	for _, p := range page.Values {
+		prs = append(prs, p.pr())
+	}
+	return prs, nil
+}
+
+func (b bitbucket) CommentIssue(ctx context.Context, dir string, number int, body string) error {
+	req := map[string]any{"content": map[string]string{"raw": body}}
+	return b.call(ctx, http.MethodPost, fmt.Sprintf("/issues/%d/comments", number), req, nil)
+}
+
+// call sends a request for the repository's resource at path and decodes the response
+// into out when it is not nil.
+func (b bitbucket) call(ctx context.Context, method, path string, in, out any) error {
+	auth := bitbucketCredentials()
+	if auth == "" {
+		return fmt.Errorf("no Bitbucket credentials: set BITBUCKET_TOKEN, or BITBUCKET_USERNAME and BITBUCKET_APP_PASSWORD")
+	}
+	var body io.Reader
+	if in != nil {
+		data, err := json.Marshal(in)
+		if err != nil {
+			return err
+		}
+		body = bytes.NewReader(data)
+	}
+	url := fmt.Sprintf("%s/repositories/%s/%s%s", bitbucketAPI, b.workspace, b.repo, path)
+	req, err := http.NewRequestWithContext(ctx, method, url, body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", auth)
+	req.Header.Set("Accept", "application/json")
+	if in != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("bitbucket %s %s: %w", method, path, err)
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("bitbucket %s %s: %w", method, path, err)
+	}
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("bitbucket %s %s: %s: %s", method, path, resp.Status, strings.TrimSpace(string(data)))
+	}
+	if out == nil {
+		return nil
+	}
+	if err := json.Unmarshal(data, out); err != nil {
+		return fmt.Errorf("parse bitbucket response: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,192 @@
+package forge
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// Kind is the code hosting service a repository's origin remote points at.
+type Kind string
+
+const (
+	// None is any origin that is not on a supported forge, or no origin at all.
+	None        Kind = ""
+	GitHub      Kind = "github"
+	GitLab      Kind = "gitlab"
+	Bitbucket   Kind = "bitbucket"
+	AzureDevOps Kind = "azure"
+)
+
+// PR is a pull (or merge) request as the forges list it.
+type PR struct {
+	Number int    `json:"number"`
+	Title  string `json:"title"`
+	URL    string `json:"url"`
+	Branch string `json:"branch"`
+	State  string `json:"state"`
+}
+
+// NewPR describes a pull request to open. An empty Branch means the branch checked out in
+// the working directory; an empty Base means the repository's default branch.
+type NewPR struct {
+	Title  string
+	Body   string
+	Branch string
+	Base   string
+}
+
+// Forge is the part of a code hosting service autopilot mode needs. dir is a checkout of
+// the repository; it tells CLI-based forges which repository to act on.
+type Forge interface {
+	Kind() Kind
+	CreatePR(ctx context.Context, dir string, pr NewPR) (PR, error)
+	ListPRs(ctx context.Context, dir string) ([]PR, error)
+	CommentIssue(ctx context.Context, dir string, number int, body string) error
+}
+
+// ErrUnsupported is returned by Open for repositories that are not on a supported forge.
+var ErrUnsupported = errors.New("the origin remote is not on GitHub, GitLab, Bitbucket Cloud or Azure DevOps")
+
+// Open returns the forge the origin remote of the repository at repoPath is hosted on.
+func Open(repoPath string) (Forge, error) {
+	remote := Remote(repoPath)
+	switch KindOf(remote) {
+	case GitHub:
+		return github{}, nil
+	case GitLab:
+		return gitlab{}, nil
+	case Bitbucket:
+		return newBitbucket(remote)
+	case AzureDevOps:
+		return azure{}, nil
+	}
+	return nil, ErrUnsupported
+}
+
+// Remote returns the origin URL of the repository at repoPath, or "" if it has none.
+func Remote(repoPath string) string {
+	if repoPath == "" {
+		return ""
+	}
+	cmd := exec.Command("git", "config", "--get", "remote.origin.url")
+	cmd.Dir = repoPath
+	out, err := cmd.Output()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(out))
+}
+
+// KindOf classifies a remote URL. Self-hosted GitLab is recognised by "gitlab" in the URL,
+// Azure DevOps by dev.azure.com or the older visualstudio.com hosts.
+func KindOf(remote string) Kind {
+	remote = strings.ToLower(remote)
+	switch {
+	case strings.Contains(remote, "github.com"):
+		return GitHub
+	case strings.Contains(remote, "bitbucket.org"):
+		return Bitbucket
+	case strings.Contains(remote, "dev.azure.com"), strings.Contains(remote, "visualstudio.com"):
+		return AzureDevOps
+	case strings.Contains(remote, "gitlab"):
+		return GitLab
+	}
+	return None
+}
+
+// Detect inspects the origin remote of the repository at repoPath.
+func Detect(repoPath string) Kind {
+	return KindOf(Remote(repoPath))
+}
+
+// Name is the display name, e.g. "GitLab".
+func (k Kind) Name() string {
+	switch k {
+	case GitHub:
+		return "GitHub"
+	case GitLab:
+		return "GitLab"
+	case Bitbucket:
+		return "Bitbucket"
+	case AzureDevOps:
+		return "Azure DevOps"
+	}
+	return "non-GitHub"
+}
+
+// CLI is the command line client agents use for the forge. Bitbucket has no official one,
+// so agents go through `swarm forge`; repositories on no forge get gh, matching the
+// prompts' GitHub defaults.
+func (k Kind) CLI() string {
+	switch k {
+	case GitLab:
+		return "glab"
+	case Bitbucket:
+		return "swarm forge"
+	case AzureDevOps:
+		return "az"
+	}
+	return "gh"
+}
+
+// Request is what the forge calls a change proposal: "PR" or "MR".
+func (k Kind) Request() string {
+	if k == GitLab {
+		return "MR"
+	}
+	return "PR"
+}
+
+// CLIAvailable reports whether agents can reach the forge: its CLI is on PATH, or for
+// Bitbucket, API credentials are set.
+func (k Kind) CLIAvailable() bool {
+	if k == Bitbucket {
+		return bitbucketCredentials() != ""
+	}
+	_, err := exec.LookPath(k.CLI())
+	return err == nil
+}
+
+// HelperCommand is the shell command that runs `swarm forge` with the current binary, for
+// prompts that send agents through it.
+func HelperCommand() string {
+	exe, err := os.Executable()
+	if err != nil {
+		exe = "swarm"
+	}
+	return fmt.Sprintf("%q forge", exe)
+}
+
+func currentBranch(ctx context.Context, dir string) (string, error) {
+	cmd := exec.CommandContext(ctx, "git", "rev-parse", "--abbrev-ref", "HEAD")
+	cmd.Dir = dir
+	out, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("read current branch: %w", err)
+	}
+	branch := strings.TrimSpace(string(out))
+	if branch == "HEAD" {
+		return "", errors.New("HEAD is detached; switch to a branch first")
+	}
+	return branch, nil
+}
+
+// run executes a forge CLI in dir and returns its output, with stderr in the error.
+func run(ctx context.Context, dir string, stdin string, name string, args ...string) ([]byte, error) {
+	cmd := exec.CommandContext(ctx, name, args...)
+	cmd.Dir = dir
+	if stdin != "" {
+		cmd.Stdin = strings.NewReader(stdin)
+	}
+	var stderr strings.Builder
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("%s %s: %v: %s", name, args[0], err, strings.TrimSpace(stderr.String()))
+	}
+	return out, nil
+}
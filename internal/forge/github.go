@@ -0,0 +1,67 @@
+package forge
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// github drives the gh CLI.
+type github struct{}
+
+func (github) Kind() Kind { return GitHub }
+
+func (github) CreatePR(ctx context.Context, dir string, pr NewPR) (PR, error) {
+	if pr.Branch == "" {
+		branch, err := currentBranch(ctx, dir)
+		if err != nil {
+			return PR{}, err
+		}
+		pr.Branch = branch
+	}
+	args := []string{"pr", "create", "--title", pr.Title, "--body", pr.Body, "--head", pr.Branch}
+	if pr.Base != "" {
+		args = append(args, "--base", pr.Base)
+	}
+	out, err := run(ctx, dir, "", "gh", args...)
+	if err != nil {
+		return PR{}, err
+	}
+	// gh prints the new pull request's URL last.
+	fields := strings.Fields(string(out))
+	if len(fields) == 0 {
+		return PR{}, fmt.Errorf("gh pr create printed no URL")
+	}
+	url := fields[len(fields)-1]
+	number, _ := strconv.Atoi(url[strings.LastIndex(url, "/")+1:])
+	return PR{Number: number, Title: pr.Title, URL: url, Branch: pr.Branch, State: "OPEN"}, nil
+}
+
+func (github) ListPRs(ctx context.Context, dir string) ([]PR, error) {
+	out, err := run(ctx, dir, "", "gh", "pr", "list", "--json", "number,title,url,headRefName,state")
+	if err != nil {
+		return nil, err
+	}
+	var list []struct {
+		Number      int    `json:"number"`
+		Title       string `json:"title"`
+		URL         string `json:"url"`
+		HeadRefName string `json:"headRefName"`
+		State       string `json:"state"`
+	}
+	if err := json.Unmarshal(out, &list); err != nil {
+		return nil, fmt.Errorf("parse gh pr list output: %w", err)
+	}
+	prs := make([]PR, 0, len(list))
+	for _, p := range list {
+		prs = append(prs, PR{Number: p.Number, Title: p.Title, URL: p.URL, Branch: p.HeadRefName, State: p.State})
+	}
+	return prs, nil
+}
+
+func (github) CommentIssue(ctx context.Context, dir string, number int, body string) error {
+	_, err := run(ctx, dir, body, "gh", "issue", "comment", strconv.Itoa(number), "--body-file", "-")
+	return err
+}
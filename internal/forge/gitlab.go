@@ -0,0 +1,68 @@
+package forge
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// gitlab drives the glab CLI.
+type gitlab struct{}
+
+var mergeRequestURL = regexp.MustCompile(`https://\S+/-/merge_requests/(\d+)`)
+
+func (gitlab) Kind() Kind { return GitLab }
+
+func (gitlab) CreatePR(ctx context.Context, dir string, pr NewPR) (PR, error) {
+	if pr.Branch == "" {
+		branch, err := currentBranch(ctx, dir)
+		if err != nil {
+			return PR{}, err
+		}
+		pr.Branch = branch
+	}
+	args := []string{"mr", "create", "--title", pr.Title, "--description", pr.Body, "--source-branch", pr.Branch, "--yes"}
+	if pr.Base != "" {
+		args = append(args, "--target-branch", pr.Base)
+	}
+	out, err := run(ctx, dir, "", "glab", args...)
+	if err != nil {
+		return PR{}, err
+	}
+	m := mergeRequestURL.FindStringSubmatch(string(out))
+	if m == nil {
+		return PR{}, fmt.Errorf("glab mr create printed no URL: %s", strings.TrimSpace(string(out)))
+	}
+	number, _ := strconv.Atoi(m[1])
+	return PR{Number: number, Title: pr.Title, URL: m[0], Branch: pr.Branch, State: "OPEN"}, nil
+}
+
+func (gitlab) ListPRs(ctx context.Context, dir string) ([]PR, error) {
+	out, err := run(ctx, dir, "", "glab", "mr", "list", "--output", "json")
+	if err != nil {
+		return nil, err
+	}
+	var list []struct {
+		IID          int    `json:"iid"`
+		Title        string `json:"title"`
+		WebURL       string `json:"web_url"`
+		SourceBranch string `json:"source_branch"`
+		State        string `json:"state"`
+	}
+	if err := json.Unmarshal(out, &list); err != nil {
+		return nil, fmt.Errorf("parse glab mr list output: %w", err)
+	}
+	prs := make([]PR, 0, len(list))
+	for _, m := range list {
+		prs = append(prs, PR{Number: m.IID, Title: m.Title, URL: m.WebURL, Branch: m.SourceBranch, State: strings.ToUpper(m.State)})
+	}
+	return prs, nil
+}
+
+func (gitlab) CommentIssue(ctx context.Context, dir string, number int, body string) error {
+	_, err := run(ctx, dir, "", "glab", "issue", "note", strconv.Itoa(number), "--message", body)
+	return err
+}
@@ -0,0 +1,187 @@
+package kube
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// readyTimeout bounds the wait for a job's pod to start, image pull included.
+	readyTimeout = 15 * time.Minute
+	// exitTimeout bounds the wait for the pod's exit code once its output has ended.
+	exitTimeout = time.Minute
+)
+
+// cloneScript clones the repository into the working directory, creates the branch from
+// the ref (a remote branch, or else a commit or tag) and then becomes the agent command
+// passed as its arguments.
+const cloneScript = `mkdir -p "$SWARM_WORKDIR" && git clone --quiet "$SWARM_REPO" "$SWARM_WORKDIR" && cd "$SWARM_WORKDIR" && ` +
+	`{ git checkout --quiet -B "$SWARM_BRANCH" "origin/$SWARM_REF" 2>/dev/null || git checkout --quiet -B "$SWARM_BRANCH" "$SWARM_REF"; } && exec "$@"`
+
+// Job is one agent run scheduled as a Kubernetes Job. Its pod clones Repo into Workdir,
+// creates Branch from Ref and runs Command there; the work only survives the pod if the
+// agent pushes it.
+type Job struct {
+	Name      string
+	Namespace string
+	Image     string
+	// Secret is a Secret whose keys become the container's environment, for the agent's
+	// and forge's credentials; empty means none.
+	Secret  string
+	Labels  map[string]string
+	Repo    string
+	Ref     string
+	Branch  string
+	Workdir string
+	Command []string
+	// Stdin keeps the container's stdin open for agents that read their prompt or
+	// follow-up messages from it.
+	Stdin bool
+}
+
+// Manifest renders the Job as JSON for kubectl apply.
+func (j Job) Manifest() ([]byte, error) {
+	container := map[string]any{
+		"name":    "agent",
+		"image":   j.Image,
+		"command": append([]string{"sh", "-c", cloneScript, "sh"}, j.Command...),
+		"env": []map[string]string{
+			{"name": "SWARM_REPO", "value": j.Repo},
+			{"name": "SWARM_REF", "value": j.Ref},
+			{"name": "SWARM_BRANCH", "value": j.Branch},
+			{"name": "SWARM_WORKDIR", "value": j.Workdir},
+		},
+	}
+	if j.Stdin {
+		container["stdin"] = true
+		container["stdinOnce"] = true
+	}
+	if j.Secret != "" {
+		container["envFrom"] = []map[string]any{{"secretRef": map[string]string{"name": j.Secret}}}
+	}
+	labels := map[string]string{"app.kubernetes.io/managed-by": "swarm"}
+	for k, v := range j.Labels {
+		labels[k] = v
+	}
+	return json.Marshal(map[string]any{
+		"apiVersion": "batch/v1",
+		"kind":       "Job",
+		"metadata":   map[string]any{"name": j.Name, "namespace": j.Namespace, "labels": labels},
+		"spec": map[string]any{
+			"backoffLimit":            0,
+			"ttlSecondsAfterFinished": 3600,
+			"template": map[string]any{
+				"metadata": map[string]any{"labels": labels},
+				"spec": map[string]any{
+					"restartPolicy": "Never",
+					"containers":    []any{container},
+				},
+			},
+		},
+	})
+}
+
+// Run creates the Job, waits for its pod and streams the agent's output to stdout and
+// stderr until it exits, returning its exit code. stdin is attached when Job.Stdin is set.
+// Canceling ctx deletes the Job.
+func Run(ctx context.Context, j Job, stdin io.Reader, stdout, stderr io.Writer) (int, error) {
+	manifest, err := j.Manifest()
+	if err != nil {
+		return 1, err
+	}
+	apply := exec.CommandContext(ctx, "kubectl", "apply", "--namespace", j.Namespace, "--filename", "-")
+	apply.Stdin = bytes.NewReader(manifest)
+	if out, err := apply.CombinedOutput(); err != nil {
+		return 1, fmt.Errorf("create job %s: %v: %s", j.Name, err, strings.TrimSpace(string(out)))
+	}
+	defer func() {
+		if ctx.Err() != nil {
+			_ = Delete(context.Background(), j.Namespace, "job-name="+j.Name)
+		}
+	}()
+
+	pod, err := waitForPod(ctx, j)
+	if err != nil {
+		return 1, err
+	}
+	var stream *exec.Cmd
+	if j.Stdin {
+		stream = exec.CommandContext(ctx, "kubectl", "attach", "--namespace", j.Namespace, "--stdin", "--container", "agent", "pod/"+pod)
+		stream.Stdin = stdin
+	} else {
+		stream = exec.CommandContext(ctx, "kubectl", "logs", "--namespace", j.Namespace, "--follow", "--container", "agent", "pod/"+pod)
+	}
+	stream.Stdout, stream.Stderr = stdout, stderr
+	if err := stream.Run(); err != nil && ctx.Err() != nil {
+		return 143, ctx.Err()
+	}
+	return exitCode(ctx, j.Namespace, pod)
+}
+
+// waitForPod returns the name of the job's pod once it runs or has already finished.
+func waitForPod(ctx context.Context, j Job) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, readyTimeout)
+	defer cancel()
+	for {
+		out, err := kubectl(ctx, "get", "pods", "--namespace", j.Namespace, "--selector", "job-name="+j.Name,
+			"--output", "jsonpath={.items[0].metadata.name} {.items[0].status.phase}")
+		if name, phase, _ := strings.Cut(out, " "); err == nil && name != "" {
+			switch phase {
+			case "Running", "Succeeded", "Failed":
+				return name, nil
+			}
+		}
+		select {
+		case <-ctx.Done():
+			return "", fmt.Errorf("job %s: pod did not start within %s", j.Name, readyTimeout)
+		case <-time.After(2 * time.Second):
+		}
+	}
+}
+
+// exitCode waits for the agent container to terminate and returns its exit code.
+func exitCode(ctx context.Context, namespace, pod string) (int, error) {
+	ctx, cancel := context.WithTimeout(ctx, exitTimeout)
+	defer cancel()
+	for {
+		out, err := kubectl(ctx, "get", "pod", pod, "--namespace", namespace,
+			"--output", `jsonpath={.status.containerStatuses[?(@.name=="agent")].state.terminated.exitCode}`)
+		if code, convErr := strconv.Atoi(out); err == nil && convErr == nil {
+			return code, nil
+		}
+		if err != nil && strings.Contains(err.Error(), "NotFound") {
+			// The job was deleted, which is how a stopped agent ends.
+			return 143, fmt.Errorf("pod %s was deleted", pod)
+		}
+		select {
+		case <-ctx.Done():
+			return 1, fmt.Errorf("pod %s: no exit code within %s", pod, exitTimeout)
+		case <-time.After(2 * time.Second):
+		}
+	}
+}
+
+// Delete removes the jobs matching selector along with their pods.
+func Delete(ctx context.Context, namespace, selector string) error {
+	ctx, cancel := context.WithTimeout(ctx, time.Minute)
+	defer cancel()
+	if _, err := kubectl(ctx, "delete", "jobs", "--namespace", namespace, "--selector", selector, "--cascade=foreground", "--wait=false", "--ignore-not-found"); err != nil {
+		return fmt.Errorf("delete jobs %s: %w", selector, err)
+	}
+	return nil
+}
+
+func kubectl(ctx context.Context, args ...string) (string, error) {
+	out, err := exec.CommandContext(ctx, "kubectl", args...).CombinedOutput()
+	if err != nil {
+		return "", fmt.Errorf("kubectl %s: %v: %s", args[0], err, strings.TrimSpace(string(out)))
+	}
+	return strings.TrimSpace(string(out)), nil
+}
@@ -0,0 +1,119 @@
+package mock
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultDelay is the pause after each printed line when no delay is configured.
+const DefaultDelay = 300 * time.Millisecond
+
+// DefaultScript imitates a worker reading the todo, failing a test and fixing it. It only
+// prints, so it is safe to run in a real checkout (--agent mode). Lines starting with "$ "
+// render as actions and "> " as tool output in the UI.
+const DefaultScript = `Reading the todo file to pick a task.
+$ cat todo.md
+> - [ ] Fix the failing parser test
+I'll start with the failing parser test.
+$ ./test.sh
+> --- FAIL: TestParse (0.01s)
+> tests failed
+@sleep 2s
+The parser drops the last token; fixing the loop bound.
+$ git commit -am "Fix parser loop bound"
+$ ./test.sh
+> ok  all tests passed
+Task done: the parser test passes now.
+`
+
+// Play prints script to out line by line, waiting delay after each printed line. Directive
+// lines control the run:
+//
+//	@sleep <duration>  pause without printing
+//	@sh <command>      run a shell command in the working directory (output is printed)
+//	@exit <code>       stop immediately with the given exit code
+//
+// It returns the exit code the mock process should end with.
+func Play(ctx context.Context, script io.Reader, out io.Writer, delay time.Duration) (int, error) {
+	scanner := bufio.NewScanner(script)
+	for scanner.Scan() {
+		line := scanner.Text()
+		directive, arg, _ := strings.Cut(strings.TrimSpace(line), " ")
+		switch directive {
+		case "@sleep":
+			d, err := time.ParseDuration(strings.TrimSpace(arg))
+			if err != nil {
+				return 1, fmt.Errorf("@sleep: %w", err)
+			}
+			if err := sleep(ctx, d); err != nil {
+				return 1, err
+			}
+			continue
+		case "@exit":
+			code, err := strconv.Atoi(strings.TrimSpace(arg))
+			if err != nil {
+				return 1, fmt.Errorf("@exit: %w", err)
+			}
+			return code, nil
+		case "@sh":
+			cmd := shell(ctx, arg)
+			cmd.Stdout = out
+			cmd.Stderr = out
+			if err := cmd.Run(); err != nil {
+				fmt.Fprintf(out, "@sh %s: %v\n", arg, err)
+			}
+			continue
+		}
+		if _, err := fmt.Fprintln(out, line); err != nil {
+			return 1, err
+		}
+		if err := sleep(ctx, delay); err != nil {
+			return 1, err
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return 1, err
+	}
+	return 0, nil
+}
+
+// Open returns the script at path, or DefaultScript when path is empty.
+func Open(path string) (io.ReadCloser, error) {
+	if path == "" {
+		return io.NopCloser(strings.NewReader(DefaultScript)), nil
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("open mock script: %w", err)
+	}
+	return f, nil
+}
+
+func sleep(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return nil
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+func shell(ctx context.Context, command string) *exec.Cmd {
+	if runtime.GOOS == "windows" {
+		return exec.CommandContext(ctx, "cmd", "/C", command)
+	}
+	return exec.CommandContext(ctx, "sh", "-c", command)
+}
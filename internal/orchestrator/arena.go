@@ -0,0 +1,106 @@
+package orchestrator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/asynkron/Asynkron.SwarmGo/internal/config"
+	"github.com/asynkron/Asynkron.SwarmGo/internal/control"
+	"github.com/asynkron/Asynkron.SwarmGo/internal/events"
+	"github.com/asynkron/Asynkron.SwarmGo/internal/session"
+	"github.com/asynkron/Asynkron.SwarmGo/internal/supervisor"
+)
+
+// RunArena runs --arena: up to --max-rounds rounds in the session, each with fresh worktrees
+// made from the repository's HEAD, where the supervisor merged the previous round's winner.
+// Between rounds the coded supervisor's snapshot decides whether to go on: with
+// --stop-when-passing the arena ends once a worker's tests all pass, and with
+// --max-stale-rounds once that many rounds in a row passed no more tests than the best
+// round before. A resumed session continues with the round it was checkpointed in.
+func RunArena(ctx context.Context, sess *session.Session, opts config.Options, resume bool, eventCh chan<- events.Event, control <-chan control.Command) error {
+	first := 1
+	if resume {
+		if cp, ok, err := sess.LoadCheckpoint(); err == nil && ok && cp.Round > 1 {
+			first = cp.Round
+		}
+	}
+	stop := arenaStop{maxStale: opts.MaxStaleRounds, untilPassing: opts.StopWhenPassing}
+	for round := first; round <= opts.MaxRounds; round++ {
+		resuming := resume && round == first
+		if !resuming {
+			if err := sess.ClearCompleted(); err != nil {
+				return err
+			}
+			// The last round's verdict is in its report; this round's supervisor writes anew.
+			if err := os.Remove(sess.VerdictPath()); err != nil && !errors.Is(err, os.ErrNotExist) {
+				return err
+			}
+		}
+		eventCh <- events.RoundChanged{Current: round, Total: opts.MaxRounds}
+		o := New(sess, opts, resuming, eventCh, control)
+		o.arenaRound = round
+		if err := o.Run(ctx); err != nil || o.quit || ctx.Err() != nil {
+			return err
+		}
+		if round == opts.MaxRounds {
+			break
+		}
+		result, err := supervisor.ReadRoundResult(sess.CodedSupervisorPath())
+		if err != nil {
+			eventCh <- events.StatusMessage{Message: fmt.Sprintf("Arena: no test results for round %d: %v", round, err)}
+		}
+		reason := stop.after(result)
+		if reason == "" {
+			reason = keptWorktree(sess, opts)
+		}
+		if reason != "" {
+			eventCh <- events.StatusMessage{Message: fmt.Sprintf("Arena ends after round %d of %d: %s", round, opts.MaxRounds, reason)}
+			return nil
+		}
+		eventCh <- events.StatusMessage{Message: fmt.Sprintf("Round %d done (best worker passed %d tests); starting round %d", round, result.Passed, round+1)}
+	}
+	return nil
+}
+
+// arenaStop applies the arena's early-exit criteria to the result of each round.
+type arenaStop struct {
+	maxStale     int
+	untilPassing bool
+	// best is the most tests passed in any round so far; stale counts the rounds since
+	// that number last went up.
+	best  int
+	stale int
+}
+
+// after records the result of a round and returns why the arena should end, or "" to go on.
+func (s *arenaStop) after(r supervisor.RoundResult) string {
+	if r.Passed > s.best {
+		s.best, s.stale = r.Passed, 0
+	} else {
+		s.stale++
+	}
+	switch {
+	case s.untilPassing && r.Passing:
+		return "all tests pass"
+	case s.maxStale > 0 && s.stale >= s.maxStale:
+		return fmt.Sprintf("no round passed more than %d tests for --max-stale-rounds %d", s.best, s.maxStale)
+	}
+	return ""
+}
+
+// keptWorktree says which worktree a round left in place because it holds unsaved work, or
+// "" when there is none. The next round would need its path, so the arena ends instead.
+func keptWorktree(sess *session.Session, opts config.Options) string {
+	paths := []string{sess.PrepWorktreePath()}
+	for i := range opts.TotalWorkers() {
+		paths = append(paths, sess.WorktreePath(i+1))
+	}
+	for _, p := range paths {
+		if _, err := os.Stat(p); err == nil {
+			return fmt.Sprintf("%s was kept with unsaved work", p)
+		}
+	}
+	return ""
+}
@@ -0,0 +1,49 @@
+package orchestrator
+
+import (
+	"testing"
+
+	"github.com/asynkron/Asynkron.SwarmGo/internal/supervisor"
+)
+
+func TestArenaStop(t *testing.T) {
+	tests := []struct {
+		name   string
+		stop   arenaStop
+		rounds []supervisor.RoundResult
+		// want is the reason after each round; the arena ends at the first non-empty one.
+		want []string
+	}{
+		{
+			name:   "no criteria",
+			stop:   arenaStop{},
+			rounds: []supervisor.RoundResult{{Passed: 3}, {Passed: 3, Passing: true}, {}},
+			want:   []string{"", "", ""},
+		},
+		{
+			name:   "until passing",
+			stop:   arenaStop{untilPassing: true},
+			rounds: []supervisor.RoundResult{{Passed: 3}, {Passed: 5, Passing: true}},
+			want:   []string{"", "all tests pass"},
+		},
+		{
+			name:   "stale rounds",
+			stop:   arenaStop{maxStale: 2},
+			rounds: []supervisor.RoundResult{{Passed: 3}, {Passed: 3}, {Passed: 4}, {Passed: 2}, {}},
+			want:   []string{"", "", "", "", "no round passed more than 4 tests for --max-stale-rounds 2"},
+		},
+		{
+			name:   "rounds without tests are stale",
+			stop:   arenaStop{maxStale: 1},
+			rounds: []supervisor.RoundResult{{}},
+			want:   []string{"no round passed more than 0 tests for --max-stale-rounds 1"},
+		},
+	}
+	for _, tc := range tests {
+		for i, r := range tc.rounds {
+			if got := tc.stop.after(r); got != tc.want[i] {
+				t.Errorf("%s: after round %d = %q, want %q", tc.name, i+1, got, tc.want[i])
+			}
+		}
+	}
+}
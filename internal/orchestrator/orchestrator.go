@@ -2,22 +2,40 @@ package orchestrator
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"math/rand/v2"
 	"os"
 	"os/exec"
+	"path"
 	"path/filepath"
+	"runtime"
+	"slices"
+	"sort"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/asynkron/Asynkron.SwarmGo/internal/agents"
+	"github.com/asynkron/Asynkron.SwarmGo/internal/artifact"
 	"github.com/asynkron/Asynkron.SwarmGo/internal/config"
 	"github.com/asynkron/Asynkron.SwarmGo/internal/control"
+	"github.com/asynkron/Asynkron.SwarmGo/internal/denylist"
+	"github.com/asynkron/Asynkron.SwarmGo/internal/disk"
 	"github.com/asynkron/Asynkron.SwarmGo/internal/events"
+	"github.com/asynkron/Asynkron.SwarmGo/internal/forge"
+	"github.com/asynkron/Asynkron.SwarmGo/internal/plan"
+	"github.com/asynkron/Asynkron.SwarmGo/internal/pricing"
 	"github.com/asynkron/Asynkron.SwarmGo/internal/prompts"
+	"github.com/asynkron/Asynkron.SwarmGo/internal/prstatus"
+	"github.com/asynkron/Asynkron.SwarmGo/internal/redact"
+	"github.com/asynkron/Asynkron.SwarmGo/internal/report"
 	"github.com/asynkron/Asynkron.SwarmGo/internal/session"
 	"github.com/asynkron/Asynkron.SwarmGo/internal/status"
 	"github.com/asynkron/Asynkron.SwarmGo/internal/supervisor"
+	"github.com/asynkron/Asynkron.SwarmGo/internal/todo"
+	"github.com/asynkron/Asynkron.SwarmGo/internal/verdict"
 	"github.com/asynkron/Asynkron.SwarmGo/internal/worktree"
 )
 
@@ -32,6 +50,10 @@ type Orchestrator struct {
 	mu      sync.Mutex
 	agents  []*agents.Agent
 	started bool
+	// arenaRound is the arena round being run, 0 outside --arena; quit is set once the
+	// user shut the session down, so no further round starts.
+	arenaRound int
+	quit       bool
 
 	codedSupervisor *supervisor.CodedSupervisor
 	appLog          *appLogger
@@ -39,20 +61,107 @@ type Orchestrator struct {
 	supervisorSpec  *supervisorSpec
 	userSpec        *userCommandSpec
 	agentRestarts   map[string]int
-	collectors      map[string]context.CancelFunc
+	autoRestarts    map[string]int
+	exits           chan workerExit
+	// rateLimitHits counts each worker's consecutive rate-limited exits for the backoff;
+	// coolingDown holds when waiting workers restart, and cooledDown receives their IDs.
+	rateLimitHits map[string]int
+	coolingDown   map[string]time.Time
+	cooledDown    chan string
+	stallWarned   map[string]time.Time
+	// timeboxWarned holds, per worker, when the claim it was last acted on for overrunning
+	// its time box began.
+	timeboxWarned map[string]time.Time
+	// duplicatesWarned holds the worker pairs and tasks already reported as duplicate work.
+	duplicatesWarned map[string]bool
+	collectors       map[string]context.CancelFunc
+	prWatchers       map[string]context.CancelFunc
+	todoBaseline     []todo.Task
+	todoProgress     todo.Progress
+	roundStarted     time.Time
+	deadline         time.Time
+	timeout          *time.Timer
+	reported         bool
+	// lastTick is when the round clock last ticked, to notice the machine sleeping.
+	lastTick time.Time
+	// suspended accumulates the time the machine slept as seen by watchSuspend, for
+	// platforms whose monotonic clock runs on through suspend.
+	suspended atomic.Int64
+	// uploaded is set once the --upload-artifacts bundle has been attempted.
+	uploaded bool
+	redactor *redact.Redactor
+	commands *agents.CommandLog
+	denylist *denylist.List
+	denials  chan agents.Denial
+	// conflicts receives the worker pairs the coded supervisor finds modifying the same files.
+	conflicts chan supervisor.Conflict
+	// phase is the last announced phase and worktreeBase the commit the primary
+	// repository's worktrees start from; both go into checkpoints. resumed is the
+	// checkpoint a resumed session was loaded from (hasCheckpoint false when it had none).
+	phase         string
+	worktreeBase  string
+	resumed       session.Checkpoint
+	hasCheckpoint bool
+	// plan is the task assignment the prep agent wrote under --plan; nil without one.
+	plan *plan.Plan
+	// testReports receives the --tester agent's result lines and testStatus holds the last
+	// status it reported per worker number.
+	testReports chan agents.TestReport
+	testStatus  map[int]string
+	// tells receives the messages agents address to one another; inbox holds those whose
+	// recipient could not take them live, until its next start.
+	tells   chan agents.Tell
+	inboxMu sync.Mutex
+	inbox   map[string][]agents.Tell
+	// verdict is the supervisor's last valid verdict.json, nil before it writes one;
+	// verdictMod is the modification time of the file last read.
+	verdict    *verdict.Verdict
+	verdictMod time.Time
+	// scratchpad is set once the round's shared scratchpad exists; scratchpadMod and
+	// scratchpadSize identify the version last announced.
+	scratchpad     bool
+	scratchpadMod  time.Time
+	scratchpadSize int64
+	// sandboxes are the workers' --isolation containers, removed when Run returns.
+	sandboxes []agents.Sandbox
+	// roleSpecs restart the running roles, built-in and from the config file, by agent ID.
+	roleSpecs map[string]roleSpec
+	// pendingPRs are the pull requests workers asked for under --approve-prs, by worker ID.
+	pendingMu  sync.Mutex
+	pendingPRs map[string]*pendingPR
+	// background tracks fire-and-forget work that logs or emits events, so Run can wait
+	// for it before the event channel closes.
+	background sync.WaitGroup
 }
 
 // New constructs a new Orchestrator.
 func New(sess *session.Session, opts config.Options, resume bool, events chan<- events.Event, control <-chan control.Command) *Orchestrator {
 	return &Orchestrator{
-		session:       sess,
-		opts:          opts,
-		resume:        resume,
-		events:        events,
-		control:       control,
-		workerSpecs:   make(map[string]workerSpec),
-		agentRestarts: make(map[string]int),
-		collectors:    make(map[string]context.CancelFunc),
+		session:          sess,
+		opts:             opts,
+		resume:           resume,
+		events:           events,
+		control:          control,
+		workerSpecs:      make(map[string]workerSpec),
+		pendingPRs:       make(map[string]*pendingPR),
+		agentRestarts:    make(map[string]int),
+		autoRestarts:     make(map[string]int),
+		exits:            make(chan workerExit, 16),
+		rateLimitHits:    make(map[string]int),
+		coolingDown:      make(map[string]time.Time),
+		cooledDown:       make(chan string, 16),
+		denials:          make(chan agents.Denial, 16),
+		conflicts:        make(chan supervisor.Conflict, 16),
+		testReports:      make(chan agents.TestReport, 16),
+		testStatus:       make(map[int]string),
+		roleSpecs:        make(map[string]roleSpec),
+		tells:            make(chan agents.Tell, 16),
+		inbox:            make(map[string][]agents.Tell),
+		stallWarned:      make(map[string]time.Time),
+		timeboxWarned:    make(map[string]time.Time),
+		duplicatesWarned: make(map[string]bool),
+		collectors:       make(map[string]context.CancelFunc),
+		prWatchers:       make(map[string]context.CancelFunc),
 	}
 }
 
@@ -62,15 +171,30 @@ func (o *Orchestrator) Run(ctx context.Context) error {
 		return fmt.Errorf("orchestrator already running")
 	}
 	o.started = true
+	o.roundStarted = time.Now()
+	redactor, err := redact.New(o.opts.RedactPatterns)
+	if err != nil {
+		return err
+	}
+	o.redactor = redactor
+	if o.denylist, err = denylist.New(o.opts.DeniedCommands); err != nil {
+		return err
+	}
 	defer func() {
+		if len(o.workerSpecs) > 0 && !o.reported {
+			o.writeReport()
+		}
 		if o.appLog != nil {
 			o.appLog.Close()
 		}
 		if o.codedSupervisor != nil {
 			o.codedSupervisor.Close()
 		}
+		_ = o.commands.Close()
 		o.stopAllCollectors()
+		o.removeSandboxes()
 	}()
+	defer o.background.Wait()
 
 	if log, err := newAppLogger(o.session.AppLogPath(), o.events); err == nil {
 		o.appLog = log
@@ -87,13 +211,25 @@ func (o *Orchestrator) Run(ctx context.Context) error {
 	} else {
 		o.emit(events.StatusMessage{Message: fmt.Sprintf("app log unavailable: %v", err)})
 	}
+	if commands, err := agents.OpenCommandLog(o.session.CommandLogPath()); err == nil {
+		o.commands = commands
+	} else {
+		o.emit(events.StatusMessage{Message: fmt.Sprintf("command audit log unavailable: %v", err)})
+	}
+	if o.opts.ApprovePRs {
+		if err := os.MkdirAll(filepath.Dir(o.session.PRDraftPath(1)), 0o755); err != nil {
+			return fmt.Errorf("create pr drafts directory: %w", err)
+		}
+	}
 
 	o.emit(events.StatusMessage{Message: fmt.Sprintf("Session: %s", o.session.ID)})
-	o.emit(events.StatusMessage{Message: fmt.Sprintf("Repository: %s", o.opts.Repo)})
+	o.emit(events.StatusMessage{Message: fmt.Sprintf("Repository: %s", strings.Join(o.opts.Repositories(), ", "))})
 	if o.opts.AgentMode {
-		o.emit(events.StatusMessage{Message: fmt.Sprintf("Agent mode: %s", strings.Title(string(o.opts.AgentType)))})
+		o.emit(events.StatusMessage{Message: fmt.Sprintf("Agent mode: %s", title(string(o.opts.AgentType)))})
+	} else if o.opts.Observing() {
+		o.emit(events.StatusMessage{Message: fmt.Sprintf("Observing %d worktrees (%s logs)", len(o.opts.Observe), title(string(o.opts.ObserveAgent)))})
 	} else {
-		o.emit(events.StatusMessage{Message: fmt.Sprintf("Workers: Claude %d, Codex %d, Copilot %d, Gemini %d", o.opts.ClaudeWorkers, o.opts.CodexWorkers, o.opts.CopilotWorkers, o.opts.GeminiWorkers)})
+		o.emit(events.StatusMessage{Message: fmt.Sprintf("Workers: %s", o.opts.WorkerSummary())})
 	}
 	if o.resume {
 		o.logf("resuming session %s", o.session.ID)
@@ -101,32 +237,67 @@ func (o *Orchestrator) Run(ctx context.Context) error {
 		o.logf("new session %s", o.session.ID)
 	}
 
+	// The watcher emits events, so it must be gone before Run returns and the channel closes.
+	diskCtx, stopDisk := context.WithCancel(ctx)
+	diskDone := make(chan struct{})
+	go func() {
+		defer close(diskDone)
+		o.watchDisk(diskCtx)
+	}()
+	defer func() {
+		stopDisk()
+		<-diskDone
+	}()
+
+	if o.resume {
+		cp, ok, err := o.session.LoadCheckpoint()
+		if err != nil {
+			o.logf("checkpoint unavailable: %v", err)
+		} else if ok {
+			o.resumed, o.hasCheckpoint = cp, true
+			o.logf("resuming from checkpoint of %s (phase %q)", cp.Saved.Format(time.RFC3339), cp.Phase)
+			o.emit(events.StatusMessage{Message: fmt.Sprintf("Resuming from the checkpoint of %s (%s)", cp.Saved.Format("15:04:05"), strings.TrimSuffix(cp.Phase, "..."))})
+		}
+	}
+
 	// Prime todo content
 	o.loadTodo()
 
 	if o.opts.AgentMode {
 		return o.runAgentMode(ctx)
 	}
+	if o.opts.Observing() {
+		return o.runObserveMode(ctx)
+	}
 
 	restartCount := 0
 	if o.resume {
 		restartCount = 1
 	}
+	o.initScratchpad()
 
 	worktrees := o.buildWorktreePaths()
 	workerTypes := o.buildWorkerTypes()
 
-	if o.resume {
-		o.emit(events.PhaseChanged{Phase: "Resuming session..."})
-		if err := o.ensureWorktrees(worktrees); err != nil {
+	// A checkpoint without a base ref was taken before the worktrees existed, so the prep
+	// step runs again; sessions from before checkpoints resume as they always did.
+	if o.resume && (!o.hasCheckpoint || o.resumed.BaseRef != "") {
+		o.setPhase("Resuming session...")
+		o.worktreeBase = o.resumed.BaseRef
+		if err := o.ensureWorktrees(ctx, worktrees, o.worktreeBase); err != nil {
 			o.logf("worktree check failed: %v", err)
 			return err
 		}
 	} else {
-		o.emit(events.PhaseChanged{Phase: "Preparing test script..."})
+		o.setPhase("Preparing test script...")
 		prepPath := o.session.PrepWorktreePath()
-		baseRef := "HEAD"
-		if err := worktree.CreateFromRef(ctx, o.opts.Repo, []string{prepPath}, baseRef); err != nil {
+		baseRef, err := o.baseRef(ctx)
+		if err != nil {
+			return err
+		}
+		if _, err := os.Stat(prepPath); err == nil && o.resume {
+			o.logf("reusing prep worktree %s", prepPath)
+		} else if err := o.checkout(ctx, o.opts.Repo, []string{prepPath}, baseRef); err != nil {
 			return err
 		}
 		ref, err := o.runPrep(ctx, prepPath)
@@ -135,24 +306,40 @@ func (o *Orchestrator) Run(ctx context.Context) error {
 		}
 		baseRef = ref
 
-		o.emit(events.PhaseChanged{Phase: "Creating worktrees..."})
-		if err := worktree.CreateFromRef(ctx, o.opts.Repo, worktrees, baseRef); err != nil {
+		o.setPhase("Creating worktrees...")
+		if o.resume {
+			// Some worktrees may have been created before the crash.
+			err = o.ensureWorktrees(ctx, worktrees, baseRef)
+		} else {
+			err = o.createWorktrees(ctx, worktrees, baseRef)
+		}
+		if err != nil {
 			return err
 		}
+		o.worktreeBase = baseRef
+		o.saveCheckpoint()
+	}
+
+	if o.opts.Plan {
+		o.loadPlan()
 	}
 
 	// Start agents
 	if o.resume {
-		o.emit(events.PhaseChanged{Phase: "Resuming workers..."})
+		o.setPhase("Resuming workers...")
 		o.logf("resuming workers")
 	} else {
-		o.emit(events.PhaseChanged{Phase: "Starting workers..."})
+		o.setPhase("Starting workers...")
 		o.logf("starting workers")
 	}
-	ghAvailable := checkGhAvailable()
-	isGitHubRepo := checkGitHubRepo(o.opts.Repo)
+	repoForge := forge.Detect(o.opts.Repo)
+	forgeCLI := repoForge.CLIAvailable()
+	forges := make(map[string]forge.Kind)
+	for _, repo := range o.opts.Repositories() {
+		forges[repo] = forge.Detect(repo)
+	}
 
-	workers, workerLogs, workerTypes, err := o.startWorkers(ctx, worktrees, workerTypes, ghAvailable, isGitHubRepo, restartCount)
+	workers, workerLogs, workerTypes, err := o.startWorkers(ctx, worktrees, workerTypes, forges, restartCount)
 	if err != nil {
 		o.stopAll()
 		o.logf("worker start failed: %v", err)
@@ -161,32 +348,31 @@ func (o *Orchestrator) Run(ctx context.Context) error {
 	o.logf("workers started/resumed: %d active", len(workers))
 
 	if o.resume {
-		o.emit(events.PhaseChanged{Phase: "Resuming supervisor..."})
+		o.setPhase("Resuming supervisor...")
 		o.logf("resuming supervisor")
 	} else {
-		o.emit(events.PhaseChanged{Phase: "Starting supervisor..."})
+		o.setPhase("Starting supervisor...")
 		o.logf("starting supervisor")
 	}
-	supervisor, err := o.startSupervisor(ctx, worktrees, workerLogs, workerTypes, ghAvailable, isGitHubRepo, restartCount)
-	if err != nil {
+	if _, err := o.startSupervisor(ctx, worktrees, workerLogs, workerTypes, repoForge, forgeCLI, restartCount); err != nil {
 		o.stopAll()
 		o.logf("supervisor start failed: %v", err)
 		return err
 	}
 
-	userCLI := agents.NewCLI(o.opts.Supervisor)
+	o.startRoles(ctx, restartCount)
+
+	userCLI := o.newCLI(o.opts.Supervisor)
 	userLog := o.session.UserCommandLogPath()
-	_, userDisplay := userCLI.Model(len(worktrees) + 2)
-	if sm, ok := userCLI.(agents.SupervisorModeler); ok {
-		_, userDisplay = sm.SupervisorModel()
-	}
+	_, userDisplay := agents.SupervisorModel(userCLI, len(worktrees)+2)
 	o.userSpec = &userCommandSpec{
-		worktrees:    worktrees,
-		repoPath:     o.opts.Repo,
-		cli:          userCLI,
-		logPath:      userLog,
-		ghAvailable:  ghAvailable,
-		isGitHubRepo: isGitHubRepo,
+		worktrees: worktrees,
+		repoPath:  o.opts.Repo,
+		repoPaths: o.opts.Repositories(),
+		cli:       userCLI,
+		logPath:   userLog,
+		forgeCLI:  forgeCLI,
+		repoForge: repoForge,
 	}
 	o.agentRestarts["user-command"] = 0
 	o.emit(events.AgentAdded{
@@ -199,53 +385,257 @@ func (o *Orchestrator) Run(ctx context.Context) error {
 		Running:  false,
 	})
 	o.emit(events.StatusMessage{Message: "User command agent is stopped; press Enter to inject a prompt or Space to start/stop."})
-	o.emit(events.PhaseChanged{Phase: "Workers running..."})
+	o.setPhase("Workers running...")
 
 	// Tick remaining time
-	deadline := time.Now().Add(o.opts.Duration())
-	timeout := time.NewTimer(o.opts.Duration())
-	ticker := time.NewTicker(time.Second)
-	defer ticker.Stop()
-	defer timeout.Stop()
+	timers := o.startTimers()
+	defer timers.Stop()
+	checkpointTicker := time.NewTicker(checkpointInterval)
+	defer checkpointTicker.Stop()
+	o.saveCheckpoint()
+	o.emitTodoProgress(worktrees)
+	var trackingC <-chan time.Time
+	if o.opts.TrackingIssue > 0 {
+		trackingTicker := time.NewTicker(o.opts.TrackingInterval)
+		defer trackingTicker.Stop()
+		trackingC = trackingTicker.C
+		started := fmt.Sprintf("### Swarm session %s started\n\n- Repository: `%s`\n- Workers: %s\n- Time limit: %s\n",
+			o.session.ID, strings.Join(o.opts.Repositories(), ", "), o.opts.WorkerSummary(), o.opts.Duration())
+		o.postTracking(ctx, func(context.Context) string { return started })
+	}
 
 loop:
 	for {
 		select {
 		case cmd := <-o.control:
 			if err := o.handleControl(ctx, cmd); err != nil {
+				if errors.Is(err, errShutdown) {
+					o.shutdown()
+					return nil
+				}
 				o.emit(events.StatusMessage{Message: fmt.Sprintf("control error: %v", err)})
 			}
 		case <-ctx.Done():
 			o.emit(events.StatusMessage{Message: "Cancellation requested, stopping agents..."})
 			o.stopAll()
 			return ctx.Err()
-		case <-timeout.C:
-			o.emit(events.StatusMessage{Message: "Time limit reached, stopping workers..."})
-			o.emit(events.PhaseChanged{Phase: "Stopping workers..."})
-			for _, w := range workers {
-				w.Stop()
+		case exit := <-o.exits:
+			o.recoverWorker(ctx, exit, o.deadline)
+			if reason := o.roundComplete(); reason != "" {
+				o.endEarly(reason, o.deadline)
+				break loop
 			}
-			// Wait a short grace period for supervisor to finish.
-			go func() {
-				time.Sleep(30 * time.Second)
-				supervisor.Stop()
-			}()
-			break loop
-		case <-ticker.C:
-			remaining := time.Until(deadline)
-			if remaining < 0 {
-				remaining = 0
+		case id := <-o.cooledDown:
+			o.resumeAfterCooldown(ctx, id, o.deadline)
+		case d := <-o.denials:
+			o.handleDenial(d)
+		case c := <-o.conflicts:
+			o.handleConflict(c)
+		case r := <-o.testReports:
+			o.handleTestReport(ctx, r)
+		case t := <-o.tells:
+			o.deliverTell(t)
+		case <-checkpointTicker.C:
+			o.saveCheckpoint()
+		case tick := <-timers.C:
+			if !o.handleTick(tick) {
+				continue
+			}
+			switch tick {
+			case tickDeadline:
+				o.emit(events.StatusMessage{Message: "Time limit reached, stopping workers..."})
+				break loop
+			case tickSecond:
+				o.checkStalls(ctx)
+				o.checkTimeboxes(ctx)
+				o.checkPRDrafts(ctx)
+				o.checkScratchpad()
+				o.checkVerdict()
+			case tickProgress:
+				o.emitTodoProgress(worktrees)
+				o.checkDuplicates(ctx)
+				if reason := o.roundComplete(); reason != "" {
+					o.endEarly(reason, o.deadline)
+					break loop
+				}
 			}
-			o.emit(events.RemainingTime{Duration: remaining})
+		case <-trackingC:
+			o.postProgress(ctx)
+		}
+	}
+
+	o.setPhase("Stopping workers...")
+	stopping := o.currentWorkers()
+	for id := range o.roleSpecs {
+		if a := o.find(id); a != nil {
+			stopping = append(stopping, a)
 		}
 	}
+	stopAgents(stopping)
+	// Wait a short grace period for supervisor to finish.
+	go func() {
+		time.Sleep(30 * time.Second)
+		if sup := o.find("supervisor"); sup != nil {
+			sup.Stop()
+		}
+	}()
 
 	o.emit(events.RemainingTime{Duration: 0})
-	o.emit(events.PhaseChanged{Phase: "Round finished"})
+	o.runRoles(ctx, config.RolePost, o.opts.Repo)
+	o.checkScratchpad()
+	o.setPhase("Round finished")
 	o.emit(events.StatusMessage{Message: "Round finished"})
+
+	if !o.opts.KeepWorktrees && !o.opts.NoGit {
+		// The supervisor may still be evaluating worktrees; clean up once it has exited.
+		if sup := o.find("supervisor"); sup != nil {
+			select {
+			case <-sup.Done():
+			case <-ctx.Done():
+				return nil
+			}
+		}
+		o.writeReport()
+		o.uploadArtifacts(ctx, worktrees)
+		o.cleanupWorktrees(ctx, worktrees)
+	}
+	if o.opts.AutoMerge {
+		if !o.reported {
+			// The report records the pull requests found in the worker logs.
+			o.writeReport()
+		}
+		o.setPhase("Enabling auto-merge...")
+		o.autoMerge(ctx)
+		o.setPhase("Round finished")
+	}
+	if o.opts.UploadArtifacts != "" && !o.uploaded {
+		if !o.reported {
+			o.writeReport()
+		}
+		o.uploadArtifacts(ctx, worktrees)
+	}
 	return nil
 }
 
+// uploadArtifacts bundles the session directory's logs, snapshots and reports with each
+// worker's final diff into a tarball and uploads it to --upload-artifacts. The URL goes to
+// the status history and the session's artifacts; the tarball stays in the session
+// directory either way.
+func (o *Orchestrator) uploadArtifacts(ctx context.Context, worktrees []string) {
+	if o.opts.UploadArtifacts == "" {
+		return
+	}
+	o.uploaded = true
+	dest, err := artifact.ParseDestination(o.opts.UploadArtifacts)
+	if err != nil {
+		o.emit(events.StatusMessage{Message: fmt.Sprintf("artifact upload: %v", err)})
+		return
+	}
+	o.setPhase("Uploading artifacts...")
+	defer o.setPhase("Round finished")
+
+	diffs := make(map[string][]byte)
+	skip := make(map[string]bool)
+	for i, wt := range worktrees {
+		skip[filepath.Clean(wt)] = true
+		if o.opts.NoGit {
+			continue
+		}
+		base, err := o.workerBase(ctx, i)
+		if err != nil {
+			o.logf("artifact diff of worker %d: %v", i+1, err)
+			continue
+		}
+		// Against the starting commit, so commits and uncommitted edits both show.
+		out, err := gitOutput(ctx, wt, "diff", "--binary", base)
+		if err != nil {
+			o.logf("artifact diff of worker %d: %v", i+1, err)
+			continue
+		}
+		if out != "" {
+			diffs[fmt.Sprintf("diffs/worker-%d.patch", i+1)] = []byte(out)
+		}
+	}
+	for _, p := range []string{o.session.PrepWorktreePath(), o.session.TesterWorktreePath(), o.session.DocsWorktreePath(), o.session.ScrollbackDir()} {
+		skip[filepath.Clean(p)] = true
+	}
+	bundle := o.session.BundlePath()
+	err = artifact.Bundle(bundle, o.session.Path, func(dir string) bool {
+		if skip[filepath.Clean(dir)] {
+			return true
+		}
+		// Any other checkout, such as a role's, is source rather than session data.
+		_, err := os.Stat(filepath.Join(dir, ".git"))
+		return err == nil
+	}, diffs)
+	if err != nil {
+		o.logf("artifact bundle: %v", err)
+		o.emit(events.StatusMessage{Message: fmt.Sprintf("artifact bundle failed: %v", err)})
+		return
+	}
+	o.logf("wrote artifact bundle %s", bundle)
+
+	ctx, cancel := context.WithTimeout(ctx, uploadTimeout)
+	defer cancel()
+	url, err := artifact.Upload(ctx, dest, bundle)
+	if err != nil {
+		o.logf("artifact upload: %v", err)
+		o.emit(events.StatusMessage{Message: fmt.Sprintf("Artifact upload failed, bundle kept at %s: %v", bundle, err)})
+		return
+	}
+	o.logf("uploaded artifacts to %s", url)
+	o.emit(events.StatusMessage{Message: "Uploaded artifacts to " + url})
+	if err := o.session.RecordArtifacts(session.Artifact{Kind: session.ArtifactBundle, URL: url, Found: time.Now()}); err != nil {
+		o.logf("record artifacts: %v", err)
+	}
+}
+
+// uploadTimeout bounds the artifact upload.
+const uploadTimeout = 10 * time.Minute
+
+// autoMerge enables auto-merge on the workers' pull requests that are green and touch no
+// file another one touches. Everything else is left for a human.
+func (o *Orchestrator) autoMerge(ctx context.Context) {
+	var candidates []prstatus.Candidate
+	for _, a := range o.session.ArtifactsOf(session.ArtifactPullRequest) {
+		if _, ok := o.workerSpecs[a.Agent]; !ok {
+			// Only pull requests a worker opened; the supervisor may mention unrelated ones.
+			continue
+		}
+		if !strings.HasPrefix(a.URL, "https://github.com/") {
+			o.emit(events.StatusMessage{Message: fmt.Sprintf("Auto-merge skipped %s: only GitHub pull requests are supported", a.URL)})
+			continue
+		}
+		c, err := prstatus.Inspect(ctx, o.opts.Repo, a.URL)
+		if err != nil {
+			o.logf("auto-merge: %v", err)
+			o.emit(events.StatusMessage{Message: fmt.Sprintf("Auto-merge skipped %s: cannot inspect it", a.URL)})
+			continue
+		}
+		candidates = append(candidates, c)
+	}
+	if len(candidates) == 0 {
+		o.emit(events.StatusMessage{Message: "Auto-merge: the workers opened no pull requests"})
+		return
+	}
+	merge, skipped := prstatus.SelectForMerge(candidates)
+	for _, c := range candidates {
+		if reason, ok := skipped[c.URL]; ok {
+			o.logf("auto-merge skipped %s: %s", c.URL, reason)
+			o.emit(events.StatusMessage{Message: fmt.Sprintf("Auto-merge skipped PR #%d: %s", c.Number, reason)})
+		}
+	}
+	for _, c := range merge {
+		if err := prstatus.EnableAutoMerge(ctx, o.opts.Repo, c.URL); err != nil {
+			o.logf("auto-merge: %v", err)
+			o.emit(events.StatusMessage{Message: fmt.Sprintf("Auto-merge failed for PR #%d: %v", c.Number, err)})
+			continue
+		}
+		o.logf("auto-merge enabled for %s", c.URL)
+		o.emit(events.StatusMessage{Message: fmt.Sprintf("Auto-merge enabled for PR #%d %s", c.Number, c.URL)})
+	}
+}
+
 func (o *Orchestrator) runAgentMode(ctx context.Context) error {
 	restartCount := 0
 	if o.resume {
@@ -253,17 +643,17 @@ func (o *Orchestrator) runAgentMode(ctx context.Context) error {
 	}
 
 	if o.resume {
-		o.emit(events.PhaseChanged{Phase: "Resuming agent..."})
+		o.setPhase("Resuming agent...")
 		o.logf("resuming single agent")
 	} else {
-		o.emit(events.PhaseChanged{Phase: "Starting agent..."})
+		o.setPhase("Starting agent...")
 		o.logf("starting single agent")
 	}
 
-	ghAvailable := checkGhAvailable()
-	isGitHubRepo := checkGitHubRepo(o.opts.Repo)
+	repoForge := forge.Detect(o.opts.Repo)
+	forgeCLI := repoForge.CLIAvailable()
 	logPath := o.session.WorkerLogPath(1)
-	cli := agents.NewCLI(o.opts.AgentType)
+	cli := o.newCLI(o.opts.AgentType)
 	_, display := cli.Model(0)
 
 	o.emit(events.AgentAdded{
@@ -274,23 +664,25 @@ func (o *Orchestrator) runAgentMode(ctx context.Context) error {
 		LogPath:  logPath,
 		Worktree: o.opts.Repo,
 		Running:  true,
+		Restarts: restartCount,
 	})
 
-	worker := agents.NewWorker(0, o.opts.Repo, o.opts.Todo, cli, logPath, false, "", restartCount, ghAvailable, isGitHubRepo, o.events)
-	if err := worker.Start(ctx); err != nil {
+	worker := agents.NewWorker(0, o.opts.WorkerName(0), o.opts.Repo, o.opts.Todo, cli, logPath, false, "", restartCount, repoForge, forgeCLI, o.events)
+	o.configure(worker)
+	if err := o.start(ctx, worker); err != nil {
 		return fmt.Errorf("start agent: %w", err)
 	}
 	go o.trackCompletion(1, worker)
 	o.workerSpecs["worker-1"] = workerSpec{
-		index:        0,
-		worktree:     o.opts.Repo,
-		todoFile:     o.opts.Todo,
-		cli:          cli,
-		logPath:      logPath,
-		autopilot:    false,
-		branchName:   "",
-		ghAvailable:  ghAvailable,
-		isGitHubRepo: isGitHubRepo,
+		index:      0,
+		worktree:   o.opts.Repo,
+		todoFile:   o.opts.Todo,
+		cli:        cli,
+		logPath:    logPath,
+		autopilot:  false,
+		branchName: "",
+		forgeCLI:   forgeCLI,
+		repoForge:  repoForge,
 	}
 	o.agentRestarts["worker-1"] = restartCount
 	o.track(worker)
@@ -300,42 +692,167 @@ func (o *Orchestrator) runAgentMode(ctx context.Context) error {
 	} else {
 		o.emit(events.StatusMessage{Message: fmt.Sprintf("Started agent (%s)", cli.Name())})
 	}
-	o.emit(events.PhaseChanged{Phase: "Agent running..."})
+	o.setPhase("Agent running...")
 
-	deadline := time.Now().Add(o.opts.Duration())
-	timeout := time.NewTimer(o.opts.Duration())
-	ticker := time.NewTicker(time.Second)
-	defer ticker.Stop()
-	defer timeout.Stop()
+	timers := o.startTimers()
+	defer timers.Stop()
+	checkpointTicker := time.NewTicker(checkpointInterval)
+	defer checkpointTicker.Stop()
+	o.saveCheckpoint()
+	o.emitTodoProgress([]string{o.opts.Repo})
 
+loop:
 	for {
 		select {
 		case cmd := <-o.control:
 			if err := o.handleControl(ctx, cmd); err != nil {
+				if errors.Is(err, errShutdown) {
+					o.shutdown()
+					return nil
+				}
 				o.emit(events.StatusMessage{Message: fmt.Sprintf("control error: %v", err)})
 			}
 		case <-ctx.Done():
 			o.emit(events.StatusMessage{Message: "Cancellation requested, stopping agent..."})
 			o.stopAll()
 			return ctx.Err()
-		case <-timeout.C:
-			o.emit(events.StatusMessage{Message: "Time limit reached, stopping agent..."})
-			o.emit(events.PhaseChanged{Phase: "Stopping agent..."})
-			worker.Stop()
-			o.emit(events.RemainingTime{Duration: 0})
-			o.emit(events.PhaseChanged{Phase: "Agent finished"})
-			return nil
-		case <-ticker.C:
-			remaining := time.Until(deadline)
-			if remaining < 0 {
-				remaining = 0
+		case exit := <-o.exits:
+			o.recoverWorker(ctx, exit, o.deadline)
+			if reason := o.roundComplete(); reason != "" {
+				o.endEarly(reason, o.deadline)
+				break loop
+			}
+		case id := <-o.cooledDown:
+			o.resumeAfterCooldown(ctx, id, o.deadline)
+		case d := <-o.denials:
+			o.handleDenial(d)
+		case <-checkpointTicker.C:
+			o.saveCheckpoint()
+		case tick := <-timers.C:
+			if !o.handleTick(tick) {
+				continue
+			}
+			switch tick {
+			case tickDeadline:
+				o.emit(events.StatusMessage{Message: "Time limit reached, stopping agent..."})
+				break loop
+			case tickSecond:
+				o.checkStalls(ctx)
+				o.checkTimeboxes(ctx)
+			case tickProgress:
+				o.emitTodoProgress([]string{o.opts.Repo})
+				if reason := o.roundComplete(); reason != "" {
+					o.endEarly(reason, o.deadline)
+					break loop
+				}
+			}
+		}
+	}
+
+	o.setPhase("Stopping agent...")
+	stopAgents(o.currentWorkers())
+	o.emit(events.RemainingTime{Duration: 0})
+	o.setPhase("Agent finished")
+	return nil
+}
+
+// runObserveMode follows agents started by other tooling: their logs stream to the UI and
+// the collectors, coded supervisor and supervisor watch their worktrees. Nothing is
+// launched in the worktrees and nothing is cleaned up afterwards.
+func (o *Orchestrator) runObserveMode(ctx context.Context) error {
+	restartCount := 0
+	if o.resume {
+		restartCount = 1
+	}
+	o.setPhase("Observing workers...")
+	o.logf("observing %d worktrees", len(o.opts.Observe))
+
+	// The followers emit events, so they must be gone before Run returns.
+	observeCtx, stopObserving := context.WithCancel(ctx)
+	defer stopObserving()
+
+	worktrees, logs := o.opts.ObservedWorkers()
+	workerTypes := make([]config.AgentType, len(worktrees))
+	for i := range worktrees {
+		workerTypes[i] = o.opts.ObserveAgent
+		// Every follower parses with its own CLI; Codex's parser keeps state between lines.
+		cli := o.newCLI(o.opts.ObserveAgent)
+		id := fmt.Sprintf("worker-%d", i+1)
+		o.emit(events.AgentAdded{
+			ID:       id,
+			Name:     o.opts.WorkerName(i),
+			Kind:     cli.Name(),
+			Model:    "observed",
+			LogPath:  logs[i],
+			Worktree: worktrees[i],
+			Running:  true,
+		})
+		if logs[i] != "" {
+			follower := agents.NewObserved(i, o.opts.WorkerName(i), worktrees[i], cli, logs[i], o.events)
+			o.configure(follower)
+			o.background.Add(1)
+			go func() {
+				defer o.background.Done()
+				follower.Observe(observeCtx)
+			}()
+		}
+		o.startCollector(ctx, id, worktrees[i], logs[i], cli)
+		o.logf("observing %s -> %s (log: %s)", id, worktrees[i], logs[i])
+	}
+
+	repoForge := forge.Detect(o.opts.Repo)
+	if _, err := o.startSupervisor(ctx, worktrees, logs, workerTypes, repoForge, repoForge.CLIAvailable(), restartCount); err != nil {
+		o.stopAll()
+		o.logf("supervisor start failed: %v", err)
+		return err
+	}
+	o.emit(events.StatusMessage{Message: fmt.Sprintf("Observing %d workers; nothing is launched in their worktrees", len(worktrees))})
+
+	timers := o.startTimers()
+	defer timers.Stop()
+	o.emitTodoProgress(worktrees)
+
+loop:
+	for {
+		select {
+		case cmd := <-o.control:
+			if err := o.handleControl(ctx, cmd); err != nil {
+				if errors.Is(err, errShutdown) {
+					o.shutdown()
+					return nil
+				}
+				o.emit(events.StatusMessage{Message: fmt.Sprintf("control error: %v", err)})
+			}
+		case <-ctx.Done():
+			o.emit(events.StatusMessage{Message: "Cancellation requested, stopping supervisor..."})
+			o.stopAll()
+			return ctx.Err()
+		case d := <-o.denials:
+			o.handleDenial(d)
+		case c := <-o.conflicts:
+			o.handleConflict(c)
+		case tick := <-timers.C:
+			if !o.handleTick(tick) {
+				continue
+			}
+			switch tick {
+			case tickDeadline:
+				o.emit(events.StatusMessage{Message: "Time limit reached, stopping observation..."})
+				break loop
+			case tickProgress:
+				o.emitTodoProgress(worktrees)
 			}
-			o.emit(events.RemainingTime{Duration: remaining})
 		}
 	}
+
+	o.setPhase("Stopping supervisor...")
+	o.stopAll()
+	o.emit(events.RemainingTime{Duration: 0})
+	o.setPhase("Observation finished")
+	return nil
 }
 
-func (o *Orchestrator) startWorkers(ctx context.Context, worktrees []string, workerTypes []config.AgentType, ghAvailable bool, isGitHubRepo bool, restartCount int) ([]*agents.Agent, []string, []config.AgentType, error) {
+func (o *Orchestrator) startWorkers(ctx context.Context, worktrees []string, workerTypes []config.AgentType, forges map[string]forge.Kind, restartCount int) ([]*agents.Agent, []string, []config.AgentType, error) {
 	var workers []*agents.Agent
 	var logs []string
 
@@ -362,54 +879,103 @@ func (o *Orchestrator) startWorkers(ctx context.Context, worktrees []string, wor
 			o.emit(events.StatusMessage{Message: err.Error()})
 			return nil, nil, nil, fmt.Errorf("missing worker type for index %d", i)
 		}
+		if i > 0 {
+			if err := o.staggerStart(ctx); err != nil {
+				return nil, nil, nil, err
+			}
+		}
 		workerNum := i + 1
 		logPath := o.session.WorkerLogPath(workerNum)
+		repoForge := forges[o.opts.RepoFor(i)]
+		forgeCLI := repoForge.CLIAvailable()
 		prevComplete := o.session.IsWorkerCompleted(workerNum)
 		agentType := workerTypes[i]
-		cli := agents.NewCLI(agentType)
+		cli := o.newCLI(agentType)
+		if o.opts.Isolation != config.IsolationNone {
+			box, err := o.startSandbox(ctx, i, worktrees[i])
+			if err != nil {
+				o.emit(events.StatusMessage{Message: err.Error()})
+				return nil, nil, nil, fmt.Errorf("start worker %d: %w", workerNum, err)
+			}
+			cli = agents.WithSandbox(cli, box)
+		}
 		_, display := cli.Model(i)
-		branchName := ""
-		if o.opts.Autopilot {
-			branchName = fmt.Sprintf("autopilot/worker%d-%s", i+1, timestamp)
-			if restartCount > 0 {
-				// Avoid telling the worker to create a new branch on resume; stick with whatever exists.
-				branchName = ""
+		id := fmt.Sprintf("worker-%d", workerNum)
+		restarts, branchName := restartCount, ""
+		prev, checkpointed := o.resumed.Agent(id)
+		if o.hasCheckpoint {
+			// The checkpoint knows each worker's restarts and branch; a worker missing from it
+			// never started before the crash.
+			restarts = 0
+			if checkpointed {
+				restarts, branchName = prev.Restarts+1, prev.Branch
 			}
 		}
+		if o.opts.Autopilot && restarts == 0 {
+			// Resumed workers aren't told to create a new branch; they stick with whatever exists.
+			branchName = fmt.Sprintf("autopilot/%s-%s", o.opts.WorkerSlug(i), timestamp)
+		}
+		// Workers stopped from the UI stay stopped when a crashed round is resumed.
+		keepStopped := checkpointed && prev.Stopped && !prev.Running && time.Now().Before(o.resumed.Deadline)
+
+		spec := workerSpec{
+			index:      i,
+			worktree:   worktrees[i],
+			todoFile:   o.opts.Todo,
+			cli:        cli,
+			logPath:    logPath,
+			autopilot:  o.opts.Autopilot,
+			branchName: branchName,
+			forgeCLI:   forgeCLI,
+			repoForge:  repoForge,
+		}
+		if keepStopped {
+			o.emit(events.AgentAdded{
+				ID:       id,
+				Name:     o.opts.WorkerName(i),
+				Kind:     cli.Name(),
+				Model:    display,
+				LogPath:  logPath,
+				Worktree: worktrees[i],
+				Running:  false,
+				Restarts: prev.Restarts,
+			})
+			o.workerSpecs[id] = spec
+			o.agentRestarts[id] = prev.Restarts
+			logs = append(logs, logPath)
+			o.startCollector(ctx, id, worktrees[i], logPath, cli)
+			o.emit(events.StatusMessage{Message: fmt.Sprintf("%s was stopped before the crash; press Space to start it", o.opts.WorkerName(i))})
+			o.logf("left worker %d stopped as checkpointed", workerNum)
+			continue
+		}
 
 		o.logf("starting worker %d (%s) worktree=%s log=%s", workerNum, cli.Name(), worktrees[i], logPath)
 		o.emit(events.AgentAdded{
-			ID:       fmt.Sprintf("worker-%d", workerNum),
-			Name:     fmt.Sprintf("Worker %d", workerNum),
+			ID:       id,
+			Name:     o.opts.WorkerName(i),
 			Kind:     cli.Name(),
 			Model:    display,
 			LogPath:  logPath,
 			Worktree: worktrees[i],
 			Running:  true,
+			Restarts: restarts,
 		})
-		worker := agents.NewWorker(i, worktrees[i], o.opts.Todo, cli, logPath, o.opts.Autopilot, branchName, restartCount, ghAvailable, isGitHubRepo, o.events)
-		if err := worker.Start(ctx); err != nil {
+		worker := agents.NewWorker(i, o.opts.WorkerName(i), worktrees[i], o.opts.Todo, cli, logPath, o.opts.Autopilot, branchName, restarts, repoForge, forgeCLI, o.events)
+		// configure looks up the spec for worker-specific prompt notes.
+		o.workerSpecs[id] = spec
+		o.configure(worker)
+		if err := o.start(ctx, worker); err != nil {
 			return nil, nil, nil, fmt.Errorf("start worker %d: %w", workerNum, err)
 		}
 		go o.trackCompletion(workerNum, worker)
-		o.workerSpecs[fmt.Sprintf("worker-%d", workerNum)] = workerSpec{
-			index:        i,
-			worktree:     worktrees[i],
-			todoFile:     o.opts.Todo,
-			cli:          cli,
-			logPath:      logPath,
-			autopilot:    o.opts.Autopilot,
-			branchName:   branchName,
-			ghAvailable:  ghAvailable,
-			isGitHubRepo: isGitHubRepo,
-		}
-		o.agentRestarts[fmt.Sprintf("worker-%d", workerNum)] = restartCount
+		o.agentRestarts[id] = restarts
 
 		workers = append(workers, worker)
 		logs = append(logs, logPath)
 		o.track(worker)
-		o.startCollector(ctx, fmt.Sprintf("worker-%d", workerNum), worktrees[i], logPath, cli)
-		if restartCount > 0 {
+		o.startCollector(ctx, id, worktrees[i], logPath, cli)
+		o.startPRWatcher(ctx, id, spec)
+		if restarts > 0 {
 			o.emit(events.StatusMessage{Message: fmt.Sprintf("Resumed %s (%s) -> %s", worker.Name, cli.Name(), worktrees[i])})
 			o.logf("resumed %s (%s) -> %s (log: %s; previously complete=%v)", worker.Name, cli.Name(), worktrees[i], logPath, prevComplete)
 		} else {
@@ -422,19 +988,78 @@ func (o *Orchestrator) startWorkers(ctx context.Context, worktrees []string, wor
 	return workers, logs, workerTypes, nil
 }
 
-func (o *Orchestrator) startSupervisor(ctx context.Context, worktrees, workerLogs []string, workerTypes []config.AgentType, ghAvailable bool, isGitHubRepo bool, restartCount int) (*agents.Agent, error) {
+// startRetryDelay is the pause before the first retry of a failed launch; later retries wait longer.
+const startRetryDelay = 2 * time.Second
+
+// start launches an agent, retrying up to --start-retries times. Every failed attempt is
+// reported with enough context (command, resolved path, workdir) to diagnose it.
+func (o *Orchestrator) start(ctx context.Context, a *agents.Agent) error {
+	attempts := o.opts.StartRetries + 1
+	for attempt := 1; ; attempt++ {
+		err := a.Start(ctx)
+		if err == nil {
+			return nil
+		}
+		command := a.CLI.Command()
+		path, _ := exec.LookPath(command)
+		o.logf("start %s failed (attempt %d/%d): %v", a.ID, attempt, attempts, err)
+		o.emit(events.AgentStartFailed{
+			ID:       a.ID,
+			Attempt:  attempt,
+			Attempts: attempts,
+			Command:  command,
+			Path:     path,
+			Workdir:  a.Workdir,
+			Error:    err.Error(),
+		})
+		if attempt >= attempts {
+			return err
+		}
+		timer := time.NewTimer(time.Duration(attempt) * startRetryDelay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return err
+		case <-timer.C:
+		}
+	}
+}
+
+// staggerStart waits --start-stagger plus up to half of it again as jitter before the next
+// worker launch, so workers don't all send their first prompt to a provider at once.
+func (o *Orchestrator) staggerStart(ctx context.Context) error {
+	delay := o.opts.StartStagger
+	if delay <= 0 {
+		return nil
+	}
+	if half := int64(delay / 2); half > 0 {
+		delay += time.Duration(rand.Int64N(half))
+	}
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+func (o *Orchestrator) startSupervisor(ctx context.Context, worktrees, workerLogs []string, workerTypes []config.AgentType, repoForge forge.Kind, forgeCLI bool, restartCount int) (*agents.Agent, error) {
 	// Start coded supervisor collector in the background for aggregated signals.
 	if o.codedSupervisor == nil {
 		o.codedSupervisor = supervisor.NewCodedSupervisor(o.session.CodedSupervisorPath(), worktrees, workerLogs, workerTypes, o.session.Created, 5*time.Second)
+		o.codedSupervisor.SkipGit = o.opts.NoGit
+		o.codedSupervisor.BaseRef = o.worktreeBase
+		o.codedSupervisor.Repos = o.workerRepos(len(worktrees))
+		o.codedSupervisor.Conflicts = o.conflicts
+		o.codedSupervisor.SkipExistingLogs = o.arenaRound > 1 && !o.resume
 		o.codedSupervisor.Start()
 	}
 
-	cli := agents.NewCLI(o.opts.Supervisor)
+	cli := o.newCLI(o.opts.Supervisor)
 	o.logf("starting supervisor (%s)", cli.Name())
-	_, display := cli.Model(len(worktrees) + 1)
-	if sm, ok := cli.(agents.SupervisorModeler); ok {
-		_, display = sm.SupervisorModel()
-	}
+	_, display := agents.SupervisorModel(cli, len(worktrees)+1)
 	o.emit(events.AgentAdded{
 		ID:       "supervisor",
 		Name:     "Supervisor",
@@ -443,9 +1068,14 @@ func (o *Orchestrator) startSupervisor(ctx context.Context, worktrees, workerLog
 		LogPath:  o.session.SupervisorLogPath(),
 		Worktree: o.opts.Repo,
 		Running:  true,
+		Restarts: restartCount,
 	})
-	supervisor := agents.NewSupervisor(worktrees, workerLogs, o.opts.Repo, o.session.CodedSupervisorPath(), cli, o.session.SupervisorLogPath(), o.opts.Autopilot, restartCount, ghAvailable, isGitHubRepo, o.events)
-	if err := supervisor.Start(ctx); err != nil {
+	workerRepos := o.workerRepos(len(worktrees))
+	// Observed workers aren't ours to merge; the autopilot prompt only monitors.
+	autopilot := o.opts.Autopilot || o.opts.Observing()
+	supervisor := agents.NewSupervisor(worktrees, workerLogs, workerRepos, o.opts.WorkerNames, o.opts.Repo, o.session.CodedSupervisorPath(), cli, o.session.SupervisorLogPath(), autopilot, restartCount, repoForge, forgeCLI, o.events)
+	o.configure(supervisor)
+	if err := o.start(ctx, supervisor); err != nil {
 		return nil, err
 	}
 
@@ -457,13 +1087,14 @@ func (o *Orchestrator) startSupervisor(ctx context.Context, worktrees, workerLog
 		worktrees:    worktrees,
 		workerLogs:   workerLogs,
 		workerTypes:  workerTypes,
+		workerRepos:  workerRepos,
 		repoPath:     o.opts.Repo,
 		codedPath:    o.session.CodedSupervisorPath(),
 		cli:          cli,
 		logPath:      o.session.SupervisorLogPath(),
-		autopilot:    o.opts.Autopilot,
-		ghAvailable:  ghAvailable,
-		isGitHubRepo: isGitHubRepo,
+		autopilot:    autopilot,
+		forgeCLI:     forgeCLI,
+		repoForge:    repoForge,
 		restartCount: restartCount,
 	}
 	o.agentRestarts["supervisor"] = restartCount
@@ -474,15 +1105,30 @@ func (o *Orchestrator) stopAll() {
 	o.mu.Lock()
 	defer o.mu.Unlock()
 
-	for _, a := range o.agents {
-		a.Stop()
+	stopAgents(o.agents)
+}
+
+// stopAgents stops agents concurrently so their SIGTERM grace periods overlap.
+func stopAgents(list []*agents.Agent) {
+	var wg sync.WaitGroup
+	for _, a := range list {
+		wg.Add(1)
+		go func(a *agents.Agent) {
+			defer wg.Done()
+			a.Stop()
+		}(a)
 	}
+	wg.Wait()
 }
 
 func (o *Orchestrator) stopAllCollectors() {
 	for id := range o.collectors {
 		o.stopCollector(id)
 	}
+	for id, cancel := range o.prWatchers {
+		cancel()
+		delete(o.prWatchers, id)
+	}
 }
 
 func (o *Orchestrator) stopCollector(id string) {
@@ -496,12 +1142,49 @@ func (o *Orchestrator) startCollector(ctx context.Context, id, worktree, logPath
 	o.stopCollector(id)
 	cctx, cancel := context.WithCancel(ctx)
 	o.collectors[id] = cancel
+	if o.opts.NoGit {
+		worktree = ""
+	}
 	coll := status.NewCollector(worktree, logPath, cli, o.session.Created, 5*time.Second)
 	go coll.Start(cctx, func(s status.Snapshot) {
 		o.emit(events.AgentStatus{ID: id, Snapshot: convertStatusSnapshot(s)})
 	})
 }
 
+// prPollInterval is how often gh is asked about an autopilot worker's pull request.
+const prPollInterval = time.Minute
+
+// startPRWatcher follows the pull request of an autopilot worker's branch. It keeps running
+// after the worker stops, since CI usually finishes later; a restart replaces it.
+func (o *Orchestrator) startPRWatcher(ctx context.Context, id string, spec workerSpec) {
+	if !spec.autopilot || spec.repoForge != forge.GitHub || !spec.forgeCLI {
+		return
+	}
+	if cancel, ok := o.prWatchers[id]; ok {
+		cancel()
+	}
+	wctx, cancel := context.WithCancel(ctx)
+	o.prWatchers[id] = cancel
+	watcher := prstatus.NewWatcher(spec.worktree, spec.branchName, prPollInterval)
+	go watcher.Start(wctx, func(st prstatus.Status) {
+		o.emit(events.PRStatus{
+			ID:      id,
+			Branch:  st.Branch,
+			Number:  st.Number,
+			URL:     st.URL,
+			Title:   st.Title,
+			State:   st.State,
+			Checks:  st.Checks,
+			Summary: st.Summary(),
+		})
+		if err := o.session.RecordArtifacts(session.Artifact{Kind: session.ArtifactPullRequest, URL: st.URL, Agent: id, Found: time.Now()}); err != nil {
+			o.logf("record pull request %s: %v", st.URL, err)
+		}
+		o.emit(events.StatusMessage{Message: fmt.Sprintf("%s PR #%d (%s): %s", id, st.Number, strings.ToLower(st.State), st.Summary())})
+		o.logf("%s PR #%d %s: state=%s %s", id, st.Number, st.URL, st.State, st.Summary())
+	})
+}
+
 func convertStatusSnapshot(s status.Snapshot) events.StatusSnapshot {
 	out := events.StatusSnapshot{
 		Branch:        s.Branch,
@@ -541,18 +1224,78 @@ func (o *Orchestrator) handleControl(ctx context.Context, cmd control.Command) e
 	switch c := cmd.(type) {
 	case control.RestartAgent:
 		return o.restartAgent(ctx, c.AgentID, c.Message)
+	case control.SendMessage:
+		return o.sendMessage(c.AgentID, c.Text)
+	case control.Broadcast:
+		return o.broadcast(ctx, c.Message)
+	case control.AdjustTime:
+		return o.adjustTime(c.Delta)
+	case control.SwapSupervisor:
+		return o.swapSupervisor(ctx, c.AgentType, c.Model)
 	case control.StopAgent:
 		return o.stopAgent(c.AgentID)
 	case control.StartAgent:
 		return o.restartAgent(ctx, c.AgentID, "")
+	case control.TakeOver:
+		return o.takeOver(c.AgentID)
+	case control.ApprovePR:
+		return o.approvePR(c.AgentID)
+	case control.RejectPR:
+		return o.rejectPR(c.AgentID)
 	case control.StartUserCommand:
 		return o.restartAgent(ctx, "user-command", c.Message)
+	case control.Shutdown:
+		return errShutdown
 	default:
 		return fmt.Errorf("unknown control command %T", cmd)
 	}
 }
 
+// errShutdown signals the run loop that the user asked to quit.
+var errShutdown = errors.New("shutdown requested")
+
+const shutdownTimeout = 10 * time.Second
+
+// shutdown stops every tracked agent and waits briefly for them to exit so the UI sees each stop.
+func (o *Orchestrator) shutdown() {
+	o.logf("control: shutdown requested")
+	o.quit = true
+	o.setPhase("Shutting down...")
+	o.stopAll()
+
+	o.mu.Lock()
+	tracked := append([]*agents.Agent(nil), o.agents...)
+	o.mu.Unlock()
+
+	deadline := time.After(shutdownTimeout)
+	for _, a := range tracked {
+		ch := a.Done()
+		if ch == nil {
+			continue
+		}
+		select {
+		case <-ch:
+		case <-deadline:
+			o.logf("shutdown: timed out waiting for agents to exit")
+			return
+		}
+	}
+	o.setPhase("Shutdown complete")
+}
+
 func (o *Orchestrator) restartAgent(ctx context.Context, id string, message string) error {
+	if err := o.relaunch(ctx, id, message); err != nil {
+		return err
+	}
+	if message != "" {
+		o.recordInjection(id, message, false)
+	}
+	return nil
+}
+
+// relaunch stops the agent with id if it runs and starts it again from its spec with
+// message injected into the prompt.
+func (o *Orchestrator) relaunch(ctx context.Context, id string, message string) error {
 	o.logf("control: restarting %s with injected message length=%d", id, len(message))
 	o.stopCollector(id)
 	o.mu.Lock()
@@ -578,6 +1321,9 @@ func (o *Orchestrator) restartAgent(ctx context.Context, id string, message stri
 	if o.supervisorSpec != nil && id == "supervisor" {
 		return o.restartSupervisor(ctx, id, message)
 	}
+	if spec, ok := o.roleSpecs[id]; ok {
+		return o.restartRole(ctx, id, spec, message)
+	}
 	if o.userSpec != nil && id == "user-command" {
 		return o.restartUserCommand(ctx, id, message)
 	}
@@ -587,9 +1333,8 @@ func (o *Orchestrator) restartAgent(ctx context.Context, id string, message stri
 	return fmt.Errorf("no restart spec for %s", id)
 }
 
-func (o *Orchestrator) stopAgent(id string) error {
-	o.logf("control: stopping %s", id)
-	o.stopCollector(id)
+func (o *Orchestrator) sendMessage(id string, text string) error {
+	o.logf("control: sending message to %s length=%d", id, len(text))
 	o.mu.Lock()
 	var target *agents.Agent
 	for _, a := range o.agents {
@@ -602,47 +1347,2078 @@ func (o *Orchestrator) stopAgent(id string) error {
 	if target == nil {
 		return fmt.Errorf("agent %s not found", id)
 	}
-	target.Stop()
-	o.emit(events.StatusMessage{Message: fmt.Sprintf("Stopped %s", id)})
+	if err := target.Send(text); err != nil {
+		return err
+	}
+	o.recordInjection(id, text, true)
+	o.emit(events.StatusMessage{Message: fmt.Sprintf("Sent message to %s", id)})
 	return nil
 }
 
-func (o *Orchestrator) buildWorktreePaths() []string {
-	paths := make([]string, 0, o.opts.TotalWorkers())
-	for i := 0; i < o.opts.TotalWorkers(); i++ {
-		paths = append(paths, o.session.WorktreePath(i+1))
+// recordInjection keeps a message given to an agent in the session's injection log and
+// shows it in the agent's panel.
+func (o *Orchestrator) recordInjection(id, message string, live bool) {
+	in := session.Injection{Time: time.Now(), Agent: id, Message: message, Live: live}
+	if err := o.session.RecordInjection(in); err != nil {
+		o.logf("record injection for %s: %v", id, err)
 	}
-	return paths
+	o.emit(events.PromptInjected{ID: id, Time: in.Time, Message: message, Live: live})
 }
 
-func (o *Orchestrator) ensureWorktrees(paths []string) error {
-	for i, p := range paths {
-		workerNum := i + 1
-		if _, err := os.Stat(p); err != nil {
-			return fmt.Errorf("worktree missing for worker %d: %s (%v)", workerNum, p, err)
-		}
+// swapSupervisor restarts the supervisor on another CLI (and optionally model), e.g. when
+// its provider is rate limited. The new supervisor appends to the same log and is told to
+// pick up from it; workers are not touched.
+func (o *Orchestrator) swapSupervisor(ctx context.Context, t config.AgentType, model string) error {
+	if o.supervisorSpec == nil {
+		return errors.New("no supervisor is running")
 	}
-	return nil
+	previous := o.supervisorSpec.cli.Name()
+	cli := o.newCLI(t)
+	if model != "" {
+		cli = agents.WithModel(cli, model)
+	}
+	o.supervisorSpec.cli = cli
+	o.logf("control: swapping supervisor %s -> %s (model %q)", previous, cli.Name(), model)
+	note := fmt.Sprintf("You are replacing the previous supervisor (%s) mid-run. Its output is at the start of your log file (%s); read it to see what was already evaluated, then continue supervising.", previous, o.supervisorSpec.logPath)
+	return o.restartAgent(ctx, "supervisor", note)
 }
 
-func (o *Orchestrator) trackCompletion(workerNumber int, agent *agents.Agent) {
-	ch := agent.Done()
-	if ch == nil {
-		return
-	}
-	<-ch
-	if agent.ExitCode() == 0 {
-		if err := o.session.MarkWorkerCompleted(workerNumber); err != nil {
-			o.emit(events.StatusMessage{Message: fmt.Sprintf("save session: %v", err)})
-		}
-	}
+// sleepGap is the least time between two ticks of the round clock taken to mean the
+// machine was suspended rather than busy.
+const sleepGap = time.Minute
+
+// sleepHold is how long a round that ran out while the machine slept stays open, so the
+// time can still be given back.
+const sleepHold = 2 * time.Minute
+
+// startClock starts the round timer. The deadline is kept on the wall clock, without the
+// monotonic reading, so time the machine spends suspended counts against the round on
+// every platform and noticeSleep can give it back.
+func (o *Orchestrator) startClock() {
+	now := time.Now()
+	o.deadline = now.Add(o.opts.Duration()).Round(0)
+	o.timeout = time.NewTimer(o.opts.Duration())
+	o.lastTick = now
 }
 
-func (o *Orchestrator) buildWorkerTypes() []config.AgentType {
-	var types []config.AgentType
-	for i := 0; i < o.opts.ClaudeWorkers; i++ {
-		types = append(types, config.AgentClaude)
-	}
+// runTick says which of a run loop's timers fired.
+type runTick int
+
+const (
+	// tickSecond fires every second, after the round clock was updated.
+	tickSecond runTick = iota
+	// tickProgress fires every todoProgressInterval.
+	tickProgress
+	// tickHeartbeat fires every heartbeatInterval; handleTick sends the heartbeats.
+	tickHeartbeat
+	// tickDeadline fires once the round's time is up.
+	tickDeadline
+)
+
+// runTimers merges the timers every run loop waits on into C, so the loops share one
+// select case for them and handleTick does the work they have in common.
+type runTimers struct {
+	C <-chan runTick
+
+	tickers []*time.Ticker
+	timeout *time.Timer
+	done    chan struct{}
+}
+
+// startTimers starts the round clock and the run loop's timers. Ticks are only forwarded
+// here; everything else happens on the loop's goroutine.
+func (o *Orchestrator) startTimers() *runTimers {
+	o.startClock()
+	second := time.NewTicker(time.Second)
+	progress := time.NewTicker(todoProgressInterval)
+	heartbeat := time.NewTicker(heartbeatInterval)
+	ticks := make(chan runTick)
+	t := &runTimers{C: ticks, tickers: []*time.Ticker{second, progress, heartbeat}, timeout: o.timeout, done: make(chan struct{})}
+	if runtime.GOOS == "windows" {
+		go o.watchSuspend(t.done)
+	}
+	go func() {
+		for {
+			var tick runTick
+			select {
+			case <-t.done:
+				return
+			case <-second.C:
+				tick = tickSecond
+			case <-progress.C:
+				tick = tickProgress
+			case <-heartbeat.C:
+				tick = tickHeartbeat
+			case <-t.timeout.C:
+				tick = tickDeadline
+			}
+			select {
+			case ticks <- tick:
+			case <-t.done:
+				return
+			}
+		}
+	}()
+	return t
+}
+
+// Stop stops the timers and the round clock.
+func (t *runTimers) Stop() {
+	close(t.done)
+	for _, ticker := range t.tickers {
+		ticker.Stop()
+	}
+	t.timeout.Stop()
+}
+
+// watchSuspend adds gaps of at least sleepGap between its own ticks to o.suspended until
+// done is closed. Windows' monotonic clock keeps running through suspend, so only a gap
+// shows it; sampling here rather than on the run loop keeps the loop's own blocking work,
+// such as restarts waiting out the stop grace, from passing for sleep.
+func (o *Orchestrator) watchSuspend(done <-chan struct{}) {
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+	last := time.Now()
+	for {
+		select {
+		case <-done:
+			return
+		case now := <-ticker.C:
+			if gap := now.Sub(last) - time.Second; gap >= sleepGap {
+				o.suspended.Add(int64(gap))
+			}
+			last = now
+		}
+	}
+}
+
+// handleTick does what every run loop does on a tick: it sends heartbeats, updates the
+// round clock and keeps the round open when the deadline moved, because the machine slept
+// or the time was extended after the tick was sent. It reports whether the loop should act
+// on the tick as well.
+func (o *Orchestrator) handleTick(tick runTick) bool {
+	switch tick {
+	case tickHeartbeat:
+		o.emitHeartbeats()
+		return false
+	case tickSecond:
+		o.tickClock()
+	case tickDeadline:
+		if o.noticeSleep() || time.Now().Before(o.deadline) {
+			return false
+		}
+	}
+	return true
+}
+
+// tickClock reports the time left in the round and ends it once the deadline has passed,
+// which the timer alone misses on systems whose monotonic clock stops during suspend.
+func (o *Orchestrator) tickClock() {
+	o.noticeSleep()
+	remaining := time.Until(o.deadline)
+	if remaining <= 0 {
+		remaining = 0
+		o.timeout.Reset(0)
+	}
+	o.emit(events.RemainingTime{Duration: remaining, Deadline: o.deadline})
+}
+
+// noticeSleep detects from a jump in the wall clock, or on Windows from watchSuspend, that
+// the machine was suspended since the last tick. With --extend-on-sleep it moves the deadline by the time slept; otherwise
+// it offers to, keeping a round that ran out meanwhile open for sleepHold. Workers' silence
+// while suspended doesn't count as a stall. It reports whether the deadline moved.
+func (o *Orchestrator) noticeSleep() bool {
+	now := time.Now()
+	wall := now.Round(0).Sub(o.lastTick.Round(0))
+	slept := wall - now.Sub(o.lastTick)
+	if runtime.GOOS == "windows" {
+		slept = time.Duration(o.suspended.Swap(0))
+	}
+	o.lastTick = now
+	if slept < sleepGap {
+		return false
+	}
+	slept = slept.Round(time.Second)
+	for _, a := range o.currentWorkers() {
+		o.stallWarned[a.ID] = now
+	}
+	o.logf("machine was suspended for %s (deadline %s)", slept, o.deadline.Format(time.TimeOnly))
+	if o.opts.ExtendOnSleep {
+		if err := o.adjustTime(slept); err != nil {
+			return false
+		}
+		o.emit(events.Slept{Duration: slept, Extended: true})
+		o.emit(events.StatusMessage{Message: fmt.Sprintf("The machine slept for %s; round extended to %s", slept, o.deadline.Format("15:04"))})
+		return true
+	}
+	target := o.deadline.Add(slept)
+	held := time.Until(o.deadline) <= 0
+	if held {
+		o.deadline = now.Add(sleepHold).Round(0)
+		o.timeout.Reset(sleepHold)
+	}
+	ev := events.Slept{Duration: slept, Offer: target.Sub(o.deadline).Round(time.Second), Held: held}
+	o.emit(ev)
+	switch {
+	case held:
+		o.emit(events.StatusMessage{Message: fmt.Sprintf("The machine slept for %s and the round ran out meanwhile; it ends in %s unless you press w to extend it by %s", slept, sleepHold, ev.Offer)})
+	case ev.Offer > 0:
+		o.emit(events.StatusMessage{Message: fmt.Sprintf("The machine slept for %s, which counted against the round; press w to extend it by %s", slept, ev.Offer)})
+	}
+	return held
+}
+
+// adjustTime moves the round deadline by delta and resets the round timer. A deadline moved
+// into the past ends the round right away.
+func (o *Orchestrator) adjustTime(delta time.Duration) error {
+	if o.timeout == nil {
+		return errors.New("no round is running yet")
+	}
+	o.deadline = o.deadline.Add(delta)
+	remaining := time.Until(o.deadline)
+	if remaining < 0 {
+		remaining = 0
+	}
+	o.timeout.Reset(remaining)
+	o.logf("control: deadline moved by %s to %s", delta, o.deadline.Format(time.TimeOnly))
+	o.emit(events.RemainingTime{Duration: remaining, Deadline: o.deadline})
+	o.emit(events.StatusMessage{Message: fmt.Sprintf("Round deadline moved to %s (%s left)", o.deadline.Format("15:04"), remaining.Round(time.Second))})
+	return nil
+}
+
+// broadcast delivers text to every running worker, sending it live where the CLI accepts
+// input and restarting the worker with it injected otherwise.
+func (o *Orchestrator) broadcast(ctx context.Context, text string) error {
+	o.logf("control: broadcasting message length=%d", len(text))
+	sent, restarted := 0, 0
+	for _, a := range o.currentWorkers() {
+		if a.Stopped() || a.Done() == nil {
+			continue
+		}
+		select {
+		case <-a.Done():
+			continue
+		default:
+		}
+		if a.AcceptsInput() {
+			err := a.Send(text)
+			if err == nil {
+				o.recordInjection(a.ID, text, true)
+				sent++
+				continue
+			}
+			o.logf("broadcast to %s failed: %v; restarting instead", a.ID, err)
+		}
+		if err := o.restartAgent(ctx, a.ID, text); err != nil {
+			o.emit(events.StatusMessage{Message: fmt.Sprintf("restart %s: %v", a.ID, err)})
+			continue
+		}
+		restarted++
+	}
+	if sent+restarted == 0 {
+		return errors.New("no running workers to broadcast to")
+	}
+	o.emit(events.StatusMessage{Message: fmt.Sprintf("Broadcast delivered: %d sent live, %d restarted with the message", sent, restarted)})
+	return nil
+}
+
+func (o *Orchestrator) stopAgent(id string) error {
+	o.logf("control: stopping %s", id)
+	o.stopCollector(id)
+	o.mu.Lock()
+	var target *agents.Agent
+	for _, a := range o.agents {
+		if a.ID == id {
+			target = a
+			break
+		}
+	}
+	o.mu.Unlock()
+	if target == nil {
+		return fmt.Errorf("agent %s not found", id)
+	}
+	target.Stop()
+	o.emit(events.StatusMessage{Message: fmt.Sprintf("Stopped %s", id)})
+	return nil
+}
+
+// takeOver stops a worker so a human can continue its conversation in the CLI's
+// interactive mode. The UI is told which command to attach once the worker has exited.
+func (o *Orchestrator) takeOver(id string) error {
+	spec, ok := o.workerSpecs[id]
+	if !ok {
+		return fmt.Errorf("%s is not a worker swarm launched; only those can be taken over", id)
+	}
+	command, args, ok := agents.InteractiveCommand(spec.cli)
+	if !ok {
+		return fmt.Errorf("%s has no interactive mode to take over", spec.cli.Name())
+	}
+	ready := events.AgentTakeover{ID: id, Command: command, Args: args, Dir: spec.worktree}
+	o.logf("control: %s taken over", id)
+	a := o.find(id)
+	if a == nil || a.Done() == nil {
+		o.emit(ready)
+		return nil
+	}
+	if !a.Stopped() {
+		if err := o.stopAgent(id); err != nil {
+			return err
+		}
+	}
+	o.background.Add(1)
+	go func() {
+		defer o.background.Done()
+		<-a.Done()
+		o.emit(ready)
+	}()
+	return nil
+}
+
+func (o *Orchestrator) buildWorktreePaths() []string {
+	paths := make([]string, 0, o.opts.TotalWorkers())
+	for i := 0; i < o.opts.TotalWorkers(); i++ {
+		paths = append(paths, o.session.WorktreePath(i+1))
+	}
+	return paths
+}
+
+// createWorktrees adds each worker's worktree to the repository it is assigned to, up to
+// --worktree-jobs at a time. The primary repository branches from the prep snapshot; other
+// repositories from their HEAD. The phase counts the worktrees as they finish.
+func (o *Orchestrator) createWorktrees(ctx context.Context, worktrees []string, baseRef string) error {
+	if !o.opts.NoGit {
+		for _, repo := range o.opts.Repositories() {
+			if err := worktree.Prepare(ctx, repo, len(o.sparseDirs()) > 0); err != nil {
+				return err
+			}
+		}
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	slots := make(chan struct{}, max(o.opts.WorktreeJobs, 1))
+	results := make(chan error, len(worktrees))
+	for i, wt := range worktrees {
+		repo, ref := o.opts.RepoFor(i), "HEAD"
+		if repo == o.opts.Repo {
+			ref = baseRef
+		}
+		go func() {
+			slots <- struct{}{}
+			defer func() { <-slots }()
+			if err := ctx.Err(); err != nil {
+				results <- err
+				return
+			}
+			started := time.Now()
+			err := o.checkoutOne(ctx, repo, wt, ref)
+			if err == nil {
+				o.logf("created worktree for worker %d in %s", i+1, time.Since(started).Round(time.Millisecond))
+			}
+			results <- err
+		}()
+	}
+	var firstErr error
+	created := 0
+	for range worktrees {
+		if err := <-results; err != nil {
+			// The first failure stops the checkouts still waiting; the others finish.
+			if firstErr == nil {
+				firstErr = err
+				cancel()
+			}
+			continue
+		}
+		created++
+		o.emit(events.PhaseChanged{Phase: fmt.Sprintf("Creating worktrees... %d/%d", created, len(worktrees))})
+	}
+	return firstErr
+}
+
+// checkout creates worktrees of repo at ref, one after the other.
+func (o *Orchestrator) checkout(ctx context.Context, repo string, paths []string, ref string) error {
+	if !o.opts.NoGit {
+		if err := worktree.Prepare(ctx, repo, len(o.sparseDirs()) > 0); err != nil {
+			return err
+		}
+	}
+	for _, p := range paths {
+		if err := o.checkoutOne(ctx, repo, p, ref); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// checkoutOne creates the worktree of repo at ref at path p; with git, worktree.Prepare
+// must have run for repo. Without git, p becomes a copy of the project (ref "HEAD") or of
+// the directory ref names, such as the prep sandbox.
+func (o *Orchestrator) checkoutOne(ctx context.Context, repo, p, ref string) error {
+	if err := o.checkDiskLimit(ctx, p); err != nil {
+		return err
+	}
+	if o.opts.NoGit {
+		src := repo
+		if ref != "HEAD" {
+			src = ref
+		}
+		if err := os.RemoveAll(p); err != nil {
+			return fmt.Errorf("reset sandbox %s: %w", p, err)
+		}
+		return worktree.CopyTree(src, p)
+	}
+	if err := worktree.Add(ctx, repo, p, ref, o.sparseDirs()); err != nil {
+		return err
+	}
+	if o.opts.Submodules {
+		return worktree.InitSubmodules(ctx, p)
+	}
+	return nil
+}
+
+// diskCheckInterval is how often the session directory size is measured.
+const diskCheckInterval = 30 * time.Second
+
+// watchDisk reports the session directory size until ctx ends and warns once each time it
+// crosses --disk-warn or --disk-limit.
+func (o *Orchestrator) watchDisk(ctx context.Context) {
+	ticker := time.NewTicker(diskCheckInterval)
+	defer ticker.Stop()
+	var overWarn, overLimit bool
+	for {
+		used, err := disk.Usage(ctx, o.session.Path)
+		if ctx.Err() != nil {
+			return
+		}
+		if err != nil {
+			o.logf("disk usage: %v", err)
+		} else {
+			o.emit(events.DiskUsage{Bytes: used, Warn: o.opts.DiskWarn, Limit: o.opts.DiskLimit})
+			warn := o.opts.DiskWarn > 0 && used >= o.opts.DiskWarn
+			if warn && !overWarn {
+				o.emit(events.StatusMessage{Message: fmt.Sprintf("Session directory uses %s (--disk-warn %s)", disk.FormatSize(used), disk.FormatSize(o.opts.DiskWarn))})
+			}
+			limit := o.opts.DiskLimit > 0 && used >= o.opts.DiskLimit
+			if limit && !overLimit {
+				o.emit(events.StatusMessage{Message: fmt.Sprintf("Session directory uses %s, over --disk-limit %s; no more worktrees will be created", disk.FormatSize(used), disk.FormatSize(o.opts.DiskLimit))})
+			}
+			overWarn, overLimit = warn, limit
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// checkDiskLimit refuses to create the worktree at path once the session directory has
+// reached --disk-limit.
+func (o *Orchestrator) checkDiskLimit(ctx context.Context, path string) error {
+	if o.opts.DiskLimit <= 0 {
+		return nil
+	}
+	used, err := disk.Usage(ctx, o.session.Path)
+	if err != nil {
+		return fmt.Errorf("measure session directory: %w", err)
+	}
+	if used >= o.opts.DiskLimit {
+		return fmt.Errorf("session directory uses %s, over --disk-limit %s; not creating %s", disk.FormatSize(used), disk.FormatSize(o.opts.DiskLimit), path)
+	}
+	return nil
+}
+
+// sparseDirs returns the --sparse directories, adding the todo file's directory so workers
+// can always read it. It returns nil for full checkouts.
+func (o *Orchestrator) sparseDirs() []string {
+	if len(o.opts.SparsePaths) == 0 {
+		return nil
+	}
+	dirs := append([]string(nil), o.opts.SparsePaths...)
+	if dir := filepath.ToSlash(filepath.Dir(o.opts.Todo)); dir != "." {
+		dirs = append(dirs, dir)
+	}
+	return dirs
+}
+
+// baseRef returns the ref the primary repository's worktrees start from: --base-ref
+// resolved to a commit, or "HEAD".
+func (o *Orchestrator) baseRef(ctx context.Context) (string, error) {
+	if o.opts.BaseRef == "" {
+		return "HEAD", nil
+	}
+	sha, err := gitOutput(ctx, o.opts.Repo, "rev-parse", "--verify", "--quiet", o.opts.BaseRef+"^{commit}")
+	if err != nil {
+		return "", fmt.Errorf("--base-ref %s is not a branch or commit in %s", o.opts.BaseRef, o.opts.Repo)
+	}
+	return strings.TrimSpace(sha), nil
+}
+
+// workerRepos returns the repository of each of the first n workers.
+func (o *Orchestrator) workerRepos(n int) []string {
+	repos := make([]string, n)
+	for i := range repos {
+		repos[i] = o.opts.RepoFor(i)
+	}
+	return repos
+}
+
+// ensureWorktrees checks the worktrees of a resumed session, recreating any that were
+// removed after an earlier round from their repository's HEAD (or --base-ref). A non-empty
+// primaryRef, such as the checkpointed prep commit, replaces both for the primary repository.
+func (o *Orchestrator) ensureWorktrees(ctx context.Context, paths []string, primaryRef string) error {
+	for i, p := range paths {
+		workerNum := i + 1
+		if _, err := os.Stat(p); err == nil {
+			continue
+		}
+		repo := o.opts.RepoFor(i)
+		ref := "HEAD"
+		if repo == o.opts.Repo && primaryRef != "" {
+			ref = primaryRef
+		} else if repo == o.opts.Repo {
+			var err error
+			if ref, err = o.baseRef(ctx); err != nil {
+				return err
+			}
+		}
+		if err := o.checkout(ctx, repo, []string{p}, ref); err != nil {
+			return fmt.Errorf("worktree missing for worker %d and could not be recreated: %w", workerNum, err)
+		}
+		o.logf("recreated worktree for worker %d: %s", workerNum, p)
+		o.emit(events.StatusMessage{Message: fmt.Sprintf("Recreated worktree for worker %d from %s %s", workerNum, repo, ref)})
+	}
+	return nil
+}
+
+// cleanupWorktrees removes the round's worker and prep worktrees and prunes git's worktree
+// records so long runs don't pile up full checkouts. Worktrees holding uncommitted changes
+// or commits no branch reaches are kept so no work is lost.
+func (o *Orchestrator) cleanupWorktrees(ctx context.Context, worktrees []string) {
+	o.setPhase("Removing worktrees...")
+	prepPath := o.session.PrepWorktreePath()
+	base, _ := gitOutput(ctx, prepPath, "rev-parse", "HEAD")
+	base = strings.TrimSpace(base)
+
+	removed, kept := 0, 0
+	for i, wt := range worktrees {
+		repo := o.opts.RepoFor(i)
+		repoBase := ""
+		if repo == o.opts.Repo {
+			repoBase = base
+		}
+		reason, err := worktree.Unsaved(ctx, wt, repoBase)
+		if err != nil {
+			reason = err.Error()
+		}
+		if reason != "" {
+			kept++
+			o.logf("keeping worktree %s: %s", wt, reason)
+			o.emit(events.StatusMessage{Message: fmt.Sprintf("Kept worktree %s (%s)", wt, reason)})
+			continue
+		}
+		if err := worktree.Remove(ctx, repo, wt); err != nil {
+			kept++
+			o.logf("remove worktree %s: %v", wt, err)
+			continue
+		}
+		removed++
+	}
+	if _, err := os.Stat(prepPath); err == nil && kept == 0 {
+		if err := worktree.Remove(ctx, o.opts.Repo, prepPath); err != nil {
+			o.logf("remove prep worktree: %v", err)
+		}
+	}
+	// The tester only checks out commits the workers' worktrees hold.
+	if spec, ok := o.roleSpecs["tester"]; ok {
+		if err := worktree.Remove(ctx, o.opts.Repo, spec.workdir); err != nil {
+			o.logf("remove tester worktree: %v", err)
+		}
+	}
+	for _, repo := range o.opts.Repositories() {
+		if err := worktree.Prune(ctx, repo); err != nil {
+			o.logf("git worktree prune in %s: %v", repo, err)
+		}
+	}
+	o.logf("worktree cleanup: removed=%d kept=%d", removed, kept)
+	o.emit(events.StatusMessage{Message: fmt.Sprintf("Removed %d worktrees (%d kept)", removed, kept)})
+	o.setPhase("Round finished")
+}
+
+func (o *Orchestrator) trackCompletion(workerNumber int, agent *agents.Agent) {
+	ch := agent.Done()
+	if ch == nil {
+		return
+	}
+	<-ch
+	code := agent.ExitCode()
+	// A CLI that gives up on a usage cap may still exit cleanly; its work isn't done.
+	if _, limited := agent.RateLimited(); code == 0 && !limited {
+		if err := o.session.MarkWorkerCompleted(workerNumber); err != nil {
+			o.emit(events.StatusMessage{Message: fmt.Sprintf("save session: %v", err)})
+		}
+	}
+	// Hand the exit to the run loop; once the round is over nobody listens, so never block.
+	select {
+	case o.exits <- workerExit{agent: agent, code: code}:
+	default:
+	}
+}
+
+// workerExit reports a worker process that has exited.
+type workerExit struct {
+	agent *agents.Agent
+	code  int
+}
+
+// minRecoveryWindow is the least time that must be left in the round for a crashed
+// worker to be worth restarting.
+const minRecoveryWindow = 2 * time.Minute
+
+// recoverWorker restarts a worker that crashed on its own with the recovery prompt, so its
+// slot doesn't sit idle for the rest of the round. Clean exits, user or deadline stops,
+// exits of already replaced agents and workers over --max-restarts are left alone. Workers
+// that hit a rate limit are restarted once it has cooled down instead.
+func (o *Orchestrator) recoverWorker(ctx context.Context, exit workerExit, deadline time.Time) {
+	id := exit.agent.ID
+	if exit.agent.Stopped() || ctx.Err() != nil || !o.isTracked(exit.agent) {
+		return
+	}
+	if until, limited := exit.agent.RateLimited(); limited {
+		o.coolDown(id, until, deadline)
+		return
+	}
+	o.rateLimitHits[id] = 0
+	if exit.code == 0 {
+		return
+	}
+	if remaining := time.Until(deadline); remaining < minRecoveryWindow {
+		o.logf("%s exited with code %d; %s left in round, not restarting", id, exit.code, remaining.Round(time.Second))
+		return
+	}
+	if o.autoRestarts[id] >= o.opts.MaxRestarts {
+		o.logf("%s exited with code %d; restart limit (%d) reached", id, exit.code, o.opts.MaxRestarts)
+		o.emit(events.StatusMessage{Message: fmt.Sprintf("%s crashed (exit %d); restart limit reached", id, exit.code)})
+		return
+	}
+	o.autoRestarts[id]++
+	o.emit(events.StatusMessage{Message: fmt.Sprintf("%s crashed (exit %d); restarting (%d/%d)", id, exit.code, o.autoRestarts[id], o.opts.MaxRestarts)})
+	note := fmt.Sprintf("Your previous run exited unexpectedly with code %d. Check your log for what you were doing and continue from there.", exit.code)
+	if err := o.restartAgent(ctx, id, note); err != nil {
+		o.emit(events.StatusMessage{Message: fmt.Sprintf("restart %s: %v", id, err)})
+	}
+}
+
+const (
+	// rateLimitBackoff is the first cooldown of a worker whose provider did not say when
+	// its rate limit ends; it doubles with every further limit up to maxRateLimitBackoff.
+	rateLimitBackoff    = time.Minute
+	maxRateLimitBackoff = 30 * time.Minute
+	// rateLimitMargin is added to advertised reset times to absorb clock skew.
+	rateLimitMargin = 10 * time.Second
+)
+
+// coolDown schedules the restart of a worker that exited on a rate limit for when the
+// cooldown its provider advertised ends, or after an exponential backoff. These restarts
+// don't count against --max-restarts; a cooldown past the end of the round leaves the
+// worker stopped.
+func (o *Orchestrator) coolDown(id string, until time.Time, deadline time.Time) {
+	o.rateLimitHits[id]++
+	if until.IsZero() {
+		backoff := rateLimitBackoff << min(o.rateLimitHits[id]-1, 5)
+		until = time.Now().Add(min(backoff, maxRateLimitBackoff))
+	} else {
+		until = until.Add(rateLimitMargin)
+	}
+	if deadline.Sub(until) < minRecoveryWindow {
+		o.logf("%s rate limited until %s; too close to the end of the round, not restarting", id, until.Format(time.TimeOnly))
+		o.emit(events.StatusMessage{Message: fmt.Sprintf("%s rate limited until %s; not restarting this round", id, until.Format("15:04"))})
+		return
+	}
+	o.coolingDown[id] = until
+	o.logf("%s rate limited (%d in a row); restarting at %s", id, o.rateLimitHits[id], until.Format(time.TimeOnly))
+	o.emit(events.RateLimited{ID: id, Until: until})
+	o.emit(events.StatusMessage{Message: fmt.Sprintf("%s rate limited; restarting at %s", id, until.Format("15:04"))})
+	// The send never blocks, so a timer firing after the round is harmless.
+	time.AfterFunc(time.Until(until), func() {
+		select {
+		case o.cooledDown <- id:
+		default:
+		}
+	})
+}
+
+// resumeAfterCooldown restarts a rate-limited worker once its cooldown is over, unless it
+// was started again from the UI in the meantime or the round is nearly over.
+func (o *Orchestrator) resumeAfterCooldown(ctx context.Context, id string, deadline time.Time) {
+	until, ok := o.coolingDown[id]
+	if !ok {
+		return
+	}
+	delete(o.coolingDown, id)
+	if o.isRunning(id) || ctx.Err() != nil {
+		return
+	}
+	if remaining := time.Until(deadline); remaining < minRecoveryWindow {
+		o.logf("%s cooldown over; %s left in round, not restarting", id, remaining.Round(time.Second))
+		return
+	}
+	note := fmt.Sprintf("Your previous run hit the provider's rate limit and was paused until %s. Check your log for what you were doing and continue from there.", until.Format("15:04"))
+	if err := o.restartAgent(ctx, id, note); err != nil {
+		o.emit(events.StatusMessage{Message: fmt.Sprintf("restart %s: %v", id, err)})
+	}
+}
+
+// roundComplete reports why the round can end before its time limit: every baseline todo
+// task is checked off, or every worker has exited on its own and none is left to restart.
+// It returns "" while work remains. Workers stopped from the UI keep the round open.
+func (o *Orchestrator) roundComplete() string {
+	if p := o.todoProgress; p.Total > 0 && p.Done >= p.Total {
+		return "All todo tasks are done"
+	}
+	workers := o.currentWorkers()
+	// A worker left stopped on resume has no agent yet; like any stopped worker it keeps
+	// the round open.
+	if len(workers) == 0 || len(workers) < len(o.workerSpecs) || len(o.coolingDown) > 0 {
+		return ""
+	}
+	for _, a := range workers {
+		done := a.Done()
+		if done == nil || a.Stopped() {
+			return ""
+		}
+		select {
+		case <-done:
+		default:
+			return ""
+		}
+	}
+	return "All workers have finished"
+}
+
+// endEarly announces that the round stops before deadline and how much time that saves.
+func (o *Orchestrator) endEarly(reason string, deadline time.Time) {
+	left := time.Until(deadline).Round(time.Second)
+	o.logf("ending round early: %s (%s before the time limit)", reason, left)
+	o.emit(events.StatusMessage{Message: fmt.Sprintf("%s, ending the round %s early...", reason, left)})
+}
+
+// stallNudge is sent to a stalled worker when --stall-action is nudge.
+const stallNudge = "Status check: you have produced no output for a while. If a command or network call is hanging, abort it. Briefly report your progress, then continue with your task."
+
+// checkStalls warns about workers that have produced no output for --stall-timeout and
+// applies the configured stall action. A worker that stays silent is reported again after
+// every further timeout.
+func (o *Orchestrator) checkStalls(ctx context.Context) {
+	timeout := o.opts.StallTimeout
+	if timeout <= 0 {
+		return
+	}
+	for _, a := range o.currentWorkers() {
+		select {
+		case <-a.Done():
+			continue
+		default:
+		}
+		// A worker waiting out a rate limit is expected to be quiet.
+		if until, limited := a.RateLimited(); limited && (until.IsZero() || time.Now().Before(until)) {
+			continue
+		}
+		since := a.LastOutput()
+		if warned := o.stallWarned[a.ID]; warned.After(since) {
+			since = warned
+		}
+		if since.IsZero() || time.Since(since) < timeout {
+			continue
+		}
+		idle := time.Since(a.LastOutput()).Round(time.Second)
+		o.stallWarned[a.ID] = time.Now()
+		o.logf("%s stalled: no output for %s (action=%s)", a.ID, idle, o.opts.StallAction)
+		o.emit(events.AgentStalled{ID: a.ID, Idle: idle})
+		o.emit(events.StatusMessage{Message: fmt.Sprintf("Warning: %s has produced no output for %s", a.ID, idle)})
+
+		switch o.opts.StallAction {
+		case config.StallNudge:
+			if a.AcceptsInput() {
+				err := o.sendMessage(a.ID, stallNudge)
+				if err == nil {
+					continue
+				}
+				o.logf("nudge %s failed: %v; restarting instead", a.ID, err)
+			}
+			fallthrough
+		case config.StallRestart:
+			note := fmt.Sprintf("Your previous run produced no output for %s and was restarted. Do not repeat whatever command or network call was hanging; continue with your task.", idle)
+			if err := o.restartAgent(ctx, a.ID, note); err != nil {
+				o.emit(events.StatusMessage{Message: fmt.Sprintf("restart %s: %v", a.ID, err)})
+			}
+		}
+	}
+}
+
+// handleDenial raises the alert for a denied command, records the incident and, with
+// --deny-action stop, stops the agent. The command has usually finished by now; the stop
+// keeps the agent from building on it until the user has looked.
+func (o *Orchestrator) handleDenial(d agents.Denial) {
+	id := d.Agent.ID
+	stop := o.opts.DenyAction == config.DenyStop && o.isTracked(d.Agent)
+	o.logf("%s ran denied command %q (pattern %s, action=%s)", id, d.Command, d.Pattern, o.opts.DenyAction)
+	o.emit(events.CommandDenied{ID: id, Command: d.Command, Pattern: d.Pattern, Stopped: stop})
+	o.emit(events.StatusMessage{Message: fmt.Sprintf("ALERT: %s ran a denied command: %s", id, d.Command)})
+	if err := o.recordIncident(d); err != nil {
+		o.logf("record incident: %v", err)
+	}
+	if stop {
+		if err := o.stopAgent(id); err != nil {
+			o.emit(events.StatusMessage{Message: fmt.Sprintf("stop %s: %v", id, err)})
+		}
+	}
+}
+
+// handleConflict warns that two workers modify the same files, so the overlap is dealt
+// with while they work rather than when their branches are merged.
+func (o *Orchestrator) handleConflict(c supervisor.Conflict) {
+	a, b := fmt.Sprintf("worker-%d", c.Workers[0]), fmt.Sprintf("worker-%d", c.Workers[1])
+	files := c.Files
+	more := ""
+	if len(files) > 5 {
+		files, more = files[:5], fmt.Sprintf(" and %d more", len(c.Files)-5)
+	}
+	o.logf("file conflict: %s and %s both modify %s", a, b, strings.Join(c.Files, ", "))
+	o.emit(events.FileConflict{IDs: []string{a, b}, Files: c.Files})
+	o.emit(events.StatusMessage{Message: fmt.Sprintf("WARNING: %s and %s both modify %s%s", a, b, strings.Join(files, ", "), more)})
+}
+
+// recordIncident appends a denied command to the session's incident log as tab-separated
+// time, agent ID, working directory, matched pattern and command.
+func (o *Orchestrator) recordIncident(d agents.Denial) error {
+	f, err := os.OpenFile(o.session.IncidentLogPath(), os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return err
+	}
+	escape := strings.NewReplacer("\\", `\\`, "\n", `\n`, "\t", `\t`)
+	_, err = fmt.Fprintf(f, "%s\t%s\t%s\t%s\t%s\n", time.Now().Format(time.RFC3339), d.Agent.ID, d.Agent.Workdir, escape.Replace(d.Pattern), escape.Replace(d.Command))
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	return err
+}
+
+// testerRole checks out the tester's worktree at the workers' starting commit, where the
+// --tester agent tests the workers of the primary repository.
+func (o *Orchestrator) testerRole(ctx context.Context, _ string) (roleSpec, error) {
+	base := o.worktreeBase
+	if base == "" {
+		out, err := gitOutput(ctx, o.opts.Repo, "rev-parse", "HEAD")
+		if err != nil {
+			return roleSpec{}, err
+		}
+		base = strings.TrimSpace(out)
+	}
+	path := o.session.TesterWorktreePath()
+	if _, err := os.Stat(path); err != nil {
+		if err := o.checkout(ctx, o.opts.Repo, []string{path}, base); err != nil {
+			return roleSpec{}, err
+		}
+	}
+	targets := make(map[int]string)
+	for i, wt := range o.buildWorktreePaths() {
+		if o.opts.RepoFor(i) == o.opts.Repo {
+			targets[i+1] = wt
+		}
+	}
+	cli := o.newCLI(o.opts.Tester)
+	logPath := o.session.TesterLogPath()
+	return roleSpec{
+		name:    "tester",
+		workdir: path,
+		cli:     cli,
+		logPath: logPath,
+		build: func(restartCount int) *agents.Agent {
+			return agents.NewTester(path, targets, base, cli, logPath, restartCount, o.events)
+		},
+	}, nil
+}
+
+// handleTestReport records a result of the tester in the coded supervisor snapshot and the
+// status history. A worker whose commit newly regressed is told right away: live where its
+// CLI takes input, otherwise by restarting it with the report.
+func (o *Orchestrator) handleTestReport(ctx context.Context, r agents.TestReport) {
+	id := fmt.Sprintf("worker-%d", r.Worker)
+	if _, ok := o.workerSpecs[id]; !ok {
+		o.logf("tester reported on unknown %s", id)
+		return
+	}
+	previous := o.testStatus[r.Worker]
+	o.testStatus[r.Worker] = r.Status
+	if o.codedSupervisor != nil {
+		o.codedSupervisor.RecordTests(r.Worker, r.Status, r.Detail)
+	}
+	o.logf("tester: %s %s %s", id, r.Status, r.Detail)
+	if r.Status == previous && r.Status != "REGRESSION" {
+		return
+	}
+	o.emit(events.StatusMessage{Message: strings.TrimSpace(fmt.Sprintf("Tester: %s %s %s", id, strings.ToLower(r.Status), r.Detail))})
+	if r.Status != "REGRESSION" || previous == "REGRESSION" {
+		return
+	}
+
+	a := o.find(id)
+	if a == nil {
+		return
+	}
+	select {
+	case <-a.Done():
+		return
+	default:
+	}
+	note := fmt.Sprintf("The swarm's tester ran the tests against your latest commit and found a regression: %s. Fix it before you continue with other work.", r.Detail)
+	if a.AcceptsInput() {
+		err := o.sendMessage(id, note)
+		if err == nil {
+			return
+		}
+		o.logf("tester report to %s failed: %v; restarting instead", id, err)
+	}
+	if err := o.restartAgent(ctx, id, note); err != nil {
+		o.emit(events.StatusMessage{Message: fmt.Sprintf("restart %s: %v", id, err)})
+	}
+}
+
+// deliverTell hands a message one agent addressed to another to that agent alone: live
+// where its CLI takes input, otherwise with its next start. Other agents never see it.
+func (o *Orchestrator) deliverTell(t agents.Tell) {
+	if !o.isAgent(t.To) {
+		o.logf("%s addressed a message to unknown agent %s", t.From, t.To)
+		o.emit(events.StatusMessage{Message: fmt.Sprintf("%s addressed a message to %s, which is not part of this round", t.From, t.To)})
+		return
+	}
+	text := fmt.Sprintf("Message from %s: %s", t.From, t.Text)
+	o.logf("tell: %s -> %s length=%d", t.From, t.To, len(t.Text))
+	if a := o.find(t.To); a != nil && a.AcceptsInput() && !a.Stopped() {
+		select {
+		case <-a.Done():
+		default:
+			err := a.Send(text)
+			if err == nil {
+				o.recordInjection(t.To, text, true)
+				o.emit(events.StatusMessage{Message: fmt.Sprintf("%s told %s: %s", t.From, t.To, t.Text)})
+				return
+			}
+			o.logf("tell to %s failed: %v; queueing it", t.To, err)
+		}
+	}
+	o.inboxMu.Lock()
+	o.inbox[t.To] = append(o.inbox[t.To], t)
+	o.inboxMu.Unlock()
+	o.emit(events.StatusMessage{Message: fmt.Sprintf("%s told %s (delivered when %s next starts): %s", t.From, t.To, t.To, t.Text)})
+}
+
+// isAgent reports whether id names an agent of this round: a worker, the supervisor or a
+// role, including post roles that have not started yet and get their messages when they do.
+func (o *Orchestrator) isAgent(id string) bool {
+	if _, ok := o.workerSpecs[id]; ok || id == "supervisor" {
+		return true
+	}
+	return slices.ContainsFunc(o.roles(), func(r role) bool { return r.name == id })
+}
+
+// inboxNote takes the messages queued for id and turns them into a prompt section, or ""
+// when there are none.
+func (o *Orchestrator) inboxNote(id string) string {
+	o.inboxMu.Lock()
+	queued := o.inbox[id]
+	delete(o.inbox, id)
+	o.inboxMu.Unlock()
+	if len(queued) == 0 {
+		return ""
+	}
+	var b strings.Builder
+	b.WriteString("\n\nMESSAGES FROM OTHER AGENTS (sent to you while you could not receive them):\n")
+	for _, t := range queued {
+		text := fmt.Sprintf("Message from %s: %s", t.From, t.Text)
+		fmt.Fprintf(&b, "- %s\n", text)
+		o.recordInjection(id, text, false)
+	}
+	return b.String()
+}
+
+// reviewerRole sets up the --reviewer agent over the branches workers committed to. It runs
+// before the report is written, so the report includes its reviews.
+func (o *Orchestrator) reviewerRole(ctx context.Context, workdir string) (roleSpec, error) {
+	branches := o.workerBranches(ctx, o.buildWorktreePaths())
+	if len(branches) == 0 {
+		return roleSpec{}, errors.New("reviewer: no worker committed anything")
+	}
+	if err := os.MkdirAll(filepath.Dir(o.session.ReviewPath(1)), 0o755); err != nil {
+		return roleSpec{}, fmt.Errorf("reviewer: %w", err)
+	}
+	targets := make([]prompts.ReviewTarget, len(branches))
+	for i, b := range branches {
+		targets[i] = prompts.ReviewTarget{
+			Name:       b.name,
+			Worktree:   b.worktree,
+			Branch:     b.branch,
+			Base:       b.base,
+			ReviewPath: o.session.ReviewPath(b.number),
+		}
+	}
+	cli := o.newCLI(o.opts.Reviewer)
+	repoForge := forge.Detect(workdir)
+	todoFile := filepath.Join(o.opts.Repo, o.opts.Todo)
+	logPath := o.session.ReviewerLogPath()
+	return roleSpec{
+		name:    "reviewer",
+		workdir: workdir,
+		cli:     cli,
+		logPath: logPath,
+		task:    fmt.Sprintf("reviewing %d branches", len(targets)),
+		build: func(int) *agents.Agent {
+			return agents.NewReviewer(workdir, todoFile, targets, cli, logPath, repoForge, repoForge.CLIAvailable(), o.events)
+		},
+		finish: func(ran bool) {
+			if !ran {
+				return
+			}
+			written := 0
+			for _, t := range targets {
+				if _, err := os.Stat(t.ReviewPath); err == nil {
+					written++
+				}
+			}
+			o.logf("reviewer finished: %d of %d reviews written", written, len(targets))
+			o.emit(events.StatusMessage{Message: fmt.Sprintf("Reviewer wrote %d of %d reviews to %s", written, len(targets), filepath.Dir(o.session.ReviewPath(1)))})
+		},
+	}, nil
+}
+
+// docsRole sets up the --docs agent over the branches workers committed to. It always
+// drafts the changelog and doc updates into the session's docs.md for the report; with
+// --docs-branch it also applies them on that branch in a worktree of its own and commits.
+func (o *Orchestrator) docsRole(ctx context.Context, workdir string) (roleSpec, error) {
+	branches := o.workerBranches(ctx, o.buildWorktreePaths())
+	if len(branches) == 0 {
+		return roleSpec{}, errors.New("docs: no worker committed anything")
+	}
+	sources := make([]prompts.DocsSource, len(branches))
+	for i, b := range branches {
+		sources[i] = prompts.DocsSource{Name: b.name, Worktree: b.worktree, Branch: b.branch, Base: b.base}
+	}
+
+	repo := workdir
+	if o.opts.DocsBranch != "" {
+		workdir = o.session.DocsWorktreePath()
+		if _, err := os.Stat(workdir); err == nil {
+			// Left over from before a resume.
+			_ = worktree.Remove(ctx, repo, workdir)
+		}
+		if err := worktree.CheckoutBranch(ctx, repo, workdir, o.opts.DocsBranch, branches[0].base); err != nil {
+			o.logf("docs: %v", err)
+			o.emit(events.StatusMessage{Message: fmt.Sprintf("Could not check out %s, drafting docs into the report only: %v", o.opts.DocsBranch, err)})
+			workdir = repo
+		}
+	}
+	branch, head := "", ""
+	if workdir != repo {
+		branch = o.opts.DocsBranch
+		head, _ = gitOutput(ctx, workdir, "rev-parse", "HEAD")
+	}
+
+	cli := o.newCLI(o.opts.Docs)
+	logPath := o.session.DocsLogPath()
+	return roleSpec{
+		name:    "docs",
+		workdir: workdir,
+		cli:     cli,
+		logPath: logPath,
+		task:    fmt.Sprintf("drafting docs for %d branches", len(sources)),
+		build: func(int) *agents.Agent {
+			return agents.NewDocs(workdir, sources, o.session.DocsPath(), branch, cli, logPath, o.events)
+		},
+		finish: func(ran bool) {
+			if workdir != repo {
+				defer func() {
+					if err := worktree.Remove(context.Background(), repo, workdir); err != nil {
+						o.logf("remove docs worktree: %v", err)
+					}
+				}()
+			}
+			if !ran {
+				return
+			}
+			if _, err := os.Stat(o.session.DocsPath()); err != nil {
+				o.emit(events.StatusMessage{Message: "Docs agent finished without writing a draft"})
+				return
+			}
+			message := fmt.Sprintf("Docs draft written to %s", o.session.DocsPath())
+			if branch != "" {
+				if now, err := gitOutput(ctx, workdir, "rev-parse", "HEAD"); err == nil && now != head {
+					message += fmt.Sprintf(" and committed to %s", branch)
+				}
+			}
+			o.logf("%s", message)
+			o.emit(events.StatusMessage{Message: message})
+		},
+	}, nil
+}
+
+// runToCompletion starts a one-off agent of the end of the round, shows it in the UI and
+// waits until it exits, timeout passes or ctx is canceled. It reports whether the agent ran
+// to the end or timed out, as opposed to failing to start or being canceled.
+func (o *Orchestrator) runToCompletion(ctx context.Context, a *agents.Agent, timeout time.Duration, task string) bool {
+	o.configure(a)
+	o.emit(events.AgentAdded{
+		ID:       a.ID,
+		Name:     a.Name,
+		Kind:     a.CLI.Name(),
+		Model:    a.Display,
+		LogPath:  a.LogPath,
+		Worktree: a.Workdir,
+		Running:  true,
+	})
+	o.logf("starting %s (%s): %s", a.ID, a.CLI.Name(), task)
+	if err := o.start(ctx, a); err != nil {
+		o.logf("%s start failed: %v", a.ID, err)
+		o.emit(events.StatusMessage{Message: fmt.Sprintf("Could not start %s: %v", a.Name, err)})
+		return false
+	}
+	o.track(a)
+	o.startCollector(ctx, a.ID, a.Workdir, a.LogPath, a.CLI)
+	defer o.stopCollector(a.ID)
+	o.emit(events.StatusMessage{Message: fmt.Sprintf("%s (%s) is %s", a.Name, a.CLI.Name(), task)})
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	select {
+	case <-a.Done():
+	case <-timer.C:
+		o.emit(events.StatusMessage{Message: fmt.Sprintf("%s did not finish within %s, stopping it", a.Name, timeout)})
+		a.Stop()
+	case <-ctx.Done():
+		a.Stop()
+		return false
+	}
+	return true
+}
+
+// workerBranch is a worker's branch with commits since the worker's starting point.
+type workerBranch struct {
+	number   int
+	name     string
+	worktree string
+	branch   string
+	base     string
+}
+
+// startSandbox creates the --isolation container of the worker with the given index.
+// Besides the worker's worktree it sees the repository's git directory the worktree's
+// commits go to and the shared scratchpad, each at its host path. Kubernetes jobs see
+// none of them.
+func (o *Orchestrator) startSandbox(ctx context.Context, i int, wt string) (agents.Sandbox, error) {
+	var gitDir string
+	if !o.opts.NoGit {
+		out, err := gitOutput(ctx, o.opts.RepoFor(i), "rev-parse", "--path-format=absolute", "--git-common-dir")
+		if err != nil {
+			return nil, fmt.Errorf("git directory of %s: %w", o.opts.RepoFor(i), err)
+		}
+		gitDir = strings.TrimSpace(out)
+	}
+	var mounts []string
+	if o.scratchpad {
+		mounts = append(mounts, o.session.ScratchpadPath())
+	}
+	if o.opts.ApprovePRs {
+		mounts = append(mounts, filepath.Dir(o.session.PRDraftPath(i+1)))
+	}
+	var box agents.Sandbox
+	switch o.opts.Isolation {
+	case config.IsolationKubernetes:
+		job, err := o.kubeJob(ctx, i, wt)
+		if err != nil {
+			return nil, err
+		}
+		o.logf("worker %d runs as jobs in namespace %s (%s) from %s at %s", i+1, job.Namespace, job.Image, job.Repo, job.Ref)
+		box = job
+	case config.IsolationDevcontainer:
+		o.logf("starting devcontainer for worker %d in %s", i+1, wt)
+		o.emit(events.StatusMessage{Message: fmt.Sprintf("Building the devcontainer of worker %d...", i+1)})
+		if gitDir != "" {
+			mounts = append(mounts, gitDir)
+		}
+		box = &agents.Devcontainer{Workspace: wt, Mounts: mounts}
+	default:
+		name := fmt.Sprintf("swarm-%s-worker-%d", o.session.ID, i+1)
+		container := &agents.Container{Name: name, Image: o.opts.DockerImage, Workdir: wt, Mounts: append([]string{wt}, mounts...)}
+		if gitDir != "" {
+			writable, err := worktreeGitMounts(ctx, gitDir, wt)
+			if err != nil {
+				return nil, err
+			}
+			container.ReadOnly = []string{gitDir}
+			container.Mounts = append(container.Mounts, writable...)
+		}
+		o.logf("starting container %s (%s) for worker %d", name, o.opts.DockerImage, i+1)
+		box = container
+	}
+	if err := box.Start(ctx); err != nil {
+		return nil, err
+	}
+	o.mu.Lock()
+	o.sandboxes = append(o.sandboxes, box)
+	o.mu.Unlock()
+	return box, nil
+}
+
+// worktreeGitMounts returns the parts of the git directory gitDir that commits in the
+// worktree wt write to: the object store, the refs and their logs, and the worktree's own
+// directory under worktrees/. Containers get only these writable, so an agent can't
+// rewrite the repository's config or hooks, or the other worktrees.
+func worktreeGitMounts(ctx context.Context, gitDir, wt string) ([]string, error) {
+	out, err := gitOutput(ctx, wt, "rev-parse", "--absolute-git-dir")
+	if err != nil {
+		return nil, fmt.Errorf("git directory of %s: %w", wt, err)
+	}
+	var paths []string
+	for _, sub := range []string{"objects", "refs", "logs"} {
+		p := filepath.Join(gitDir, sub)
+		// Docker would create a missing mount source as root.
+		if err := os.MkdirAll(p, 0o755); err != nil {
+			return nil, fmt.Errorf("create %s: %w", p, err)
+		}
+		paths = append(paths, p)
+	}
+	return append(paths, strings.TrimSpace(out)), nil
+}
+
+// kubeJob describes the Kubernetes jobs of the worker with the given index. Pods can't
+// reach the prep commit or the worktree, so they clone the repository's origin at
+// --base-ref, or the branch checked out in the repository, and work on a branch of the
+// worktree's name at the worktree's path.
+func (o *Orchestrator) kubeJob(ctx context.Context, i int, wt string) (*agents.KubeJob, error) {
+	repo := o.opts.RepoFor(i)
+	url, err := gitOutput(ctx, repo, "remote", "get-url", "origin")
+	if err != nil {
+		return nil, fmt.Errorf("--isolation kubernetes clones the origin remote of %s: %w", repo, err)
+	}
+	ref := o.opts.BaseRef
+	if repo != o.opts.Repo || ref == "" {
+		out, err := gitOutput(ctx, repo, "rev-parse", "--abbrev-ref", "HEAD")
+		if err != nil {
+			return nil, fmt.Errorf("current branch of %s: %w", repo, err)
+		}
+		if ref = strings.TrimSpace(out); ref == "HEAD" {
+			// Detached: the commit itself, which must have been pushed.
+			out, err := gitOutput(ctx, repo, "rev-parse", "HEAD")
+			if err != nil {
+				return nil, err
+			}
+			ref = strings.TrimSpace(out)
+		}
+	}
+	branch, err := gitOutput(ctx, wt, "rev-parse", "--abbrev-ref", "HEAD")
+	if err != nil {
+		return nil, fmt.Errorf("branch of worker %d: %w", i+1, err)
+	}
+	return &agents.KubeJob{
+		Worker:    fmt.Sprintf("%s-%d", o.session.ID, i+1),
+		Namespace: o.opts.KubeNamespace,
+		Image:     o.opts.KubeImage,
+		Secret:    o.opts.KubeSecret,
+		Repo:      strings.TrimSpace(url),
+		Ref:       ref,
+		Branch:    strings.TrimSpace(branch),
+		Workdir:   wt,
+	}, nil
+}
+
+// removeSandboxes removes the workers' containers and anything still running in them.
+func (o *Orchestrator) removeSandboxes() {
+	o.mu.Lock()
+	sandboxes := o.sandboxes
+	o.sandboxes = nil
+	o.mu.Unlock()
+	for _, box := range sandboxes {
+		if err := box.Remove(); err != nil {
+			o.logf("%v", err)
+		}
+	}
+}
+
+// workerBase returns the commit the worker with the given index started from: the prep
+// commit for the primary repository, otherwise its repository's HEAD.
+func (o *Orchestrator) workerBase(ctx context.Context, i int) (string, error) {
+	if repo := o.opts.RepoFor(i); repo != o.opts.Repo || o.worktreeBase == "" {
+		out, err := gitOutput(ctx, repo, "rev-parse", "HEAD")
+		if err != nil {
+			return "", err
+		}
+		return strings.TrimSpace(out), nil
+	}
+	return o.worktreeBase, nil
+}
+
+// workerBranches lists the worker branches with commits since the worker's starting
+// point. Workers of the primary repository started from the prep commit, others from their
+// repository's HEAD.
+func (o *Orchestrator) workerBranches(ctx context.Context, worktrees []string) []workerBranch {
+	var branches []workerBranch
+	for i, wt := range worktrees {
+		base, err := o.workerBase(ctx, i)
+		if err != nil {
+			o.logf("worker %d branch: %v", i+1, err)
+			continue
+		}
+		commits, _, err := worktree.BranchSummary(ctx, wt, "HEAD", base)
+		if err != nil {
+			o.logf("worker %d branch: %v", i+1, err)
+			continue
+		}
+		if len(commits) == 0 {
+			continue
+		}
+		branch := "HEAD (detached)"
+		if out, err := gitOutput(ctx, wt, "rev-parse", "--abbrev-ref", "HEAD"); err == nil && strings.TrimSpace(out) != "HEAD" {
+			branch = strings.TrimSpace(out)
+		}
+		branches = append(branches, workerBranch{
+			number:   i + 1,
+			name:     o.opts.WorkerName(i),
+			worktree: wt,
+			branch:   branch,
+			base:     base,
+		})
+	}
+	return branches
+}
+
+// role is an agent the round schedules by when it runs: one of the built-in agents enabled
+// by its own flag, or a config file role enabled with --role.
+type role struct {
+	name string
+	config.Role
+	// setup builds the spec of a built-in role in workdir; nil renders Prompt instead.
+	setup func(ctx context.Context, workdir string) (roleSpec, error)
+}
+
+// roles lists the enabled roles in the order they run: the tester, reviewer and docs
+// agents, then the roles named with --role.
+func (o *Orchestrator) roles() []role {
+	var roles []role
+	if o.opts.Tester != "" {
+		roles = append(roles, role{name: "tester", Role: config.Role{Agent: o.opts.Tester, When: config.RoleDuring}, setup: o.testerRole})
+	}
+	if o.opts.Reviewer != "" {
+		roles = append(roles, role{name: "reviewer", Role: config.Role{Agent: o.opts.Reviewer, When: config.RolePost, Minutes: 20}, setup: o.reviewerRole})
+	}
+	if o.opts.Docs != "" {
+		roles = append(roles, role{name: "docs", Role: config.Role{Agent: o.opts.Docs, When: config.RolePost, Minutes: 15}, setup: o.docsRole})
+	}
+	for _, name := range o.opts.Roles {
+		roles = append(roles, role{name: name, Role: o.opts.RoleDefs[name]})
+	}
+	return roles
+}
+
+// newRole builds the spec of a role whose agent runs in workdir. Config file roles get
+// their prompt template rendered with the round's details.
+func (o *Orchestrator) newRole(ctx context.Context, r role, workdir string) (roleSpec, error) {
+	if r.setup != nil {
+		return r.setup(ctx, workdir)
+	}
+	data := prompts.RoleData{
+		Role:    r.name,
+		When:    string(r.When),
+		Repo:    o.opts.Repo,
+		Workdir: workdir,
+		Todo:    filepath.Join(o.opts.Repo, o.opts.Todo),
+		Session: o.session.Path,
+		Base:    o.worktreeBase,
+	}
+	for i, wt := range o.buildWorktreePaths() {
+		data.Workers = append(data.Workers, prompts.RoleWorker{
+			Number:   i + 1,
+			Name:     o.opts.WorkerName(i),
+			Worktree: wt,
+			LogPath:  o.session.WorkerLogPath(i + 1),
+		})
+	}
+	prompt, err := prompts.RolePrompt(r.Prompt, data)
+	if err != nil {
+		return roleSpec{}, fmt.Errorf("role %s: %w", r.name, err)
+	}
+	cli := agents.WithModel(o.newCLI(r.Agent), r.Model)
+	logPath := o.session.RoleLogPath(r.name)
+	return roleSpec{
+		name:    r.name,
+		workdir: workdir,
+		cli:     cli,
+		logPath: logPath,
+		build: func(restartCount int) *agents.Agent {
+			return agents.NewRole(r.name, workdir, prompt, cli, logPath, restartCount, o.events)
+		},
+	}, nil
+}
+
+// runRoles runs the pre or post roles one after another in workdir, each until it exits or
+// its time is up. A role that fails is reported and the round goes on without it.
+func (o *Orchestrator) runRoles(ctx context.Context, when config.RoleTiming, workdir string) {
+	for _, r := range o.roles() {
+		if r.When != when {
+			continue
+		}
+		if ctx.Err() != nil {
+			return
+		}
+		spec, err := o.newRole(ctx, r, workdir)
+		if err != nil {
+			o.logf("%v", err)
+			o.emit(events.StatusMessage{Message: fmt.Sprintf("Skipping %v", err)})
+			continue
+		}
+		a := spec.build(0)
+		task := spec.task
+		if task == "" {
+			task = fmt.Sprintf("running (%s)", when)
+		}
+		o.setPhase(fmt.Sprintf("Running %s...", strings.ToLower(a.Name)))
+		ran := o.runToCompletion(ctx, a, r.Timeout(), task)
+		if ran {
+			o.logf("%s finished with exit code %d", a.ID, a.ExitCode())
+		}
+		if spec.finish != nil {
+			spec.finish(ran)
+		}
+	}
+}
+
+// startRoles starts the roles that run alongside the workers. They are stopped with the
+// workers and can be restarted from the UI like them.
+func (o *Orchestrator) startRoles(ctx context.Context, restartCount int) {
+	for _, r := range o.roles() {
+		if r.When != config.RoleDuring {
+			continue
+		}
+		spec, err := o.newRole(ctx, r, o.opts.Repo)
+		var a *agents.Agent
+		if err == nil {
+			a = spec.build(restartCount)
+			o.configure(a)
+			err = o.start(ctx, a)
+		}
+		if err != nil {
+			o.logf("%s start failed: %v", r.name, err)
+			o.emit(events.StatusMessage{Message: fmt.Sprintf("Could not start %s: %v", r.name, err)})
+			continue
+		}
+		o.emit(events.AgentAdded{
+			ID:       a.ID,
+			Name:     a.Name,
+			Kind:     spec.cli.Name(),
+			Model:    a.Display,
+			LogPath:  spec.logPath,
+			Worktree: spec.workdir,
+			Running:  true,
+			Restarts: restartCount,
+		})
+		o.track(a)
+		o.startCollector(ctx, a.ID, spec.workdir, spec.logPath, spec.cli)
+		o.roleSpecs[a.ID] = spec
+		o.agentRestarts[a.ID] = restartCount
+		o.logf("%s started (%s)", a.ID, spec.cli.Name())
+		o.emit(events.StatusMessage{Message: fmt.Sprintf("Started %s (%s)", a.Name, spec.cli.Name())})
+	}
+}
+
+// verdictNote asks the supervisor for its judgement of the round in a form the
+// orchestrator can read, next to its prose summary.
+func (o *Orchestrator) verdictNote() string {
+	return fmt.Sprintf(`
+
+## Verdict File
+
+When the workers have stopped and you have evaluated them, also write your verdict as JSON
+to %[1]s (not into the repository), in exactly this shape:
+{"winner": 2, "rationale": "<why it won, 1-3 sentences>", "scores": [{"worker": 1, "score": 6.5, "tests": "41/43 passing", "notes": "<short>"}]}
+- winner is the number of the best worker (1-%[2]d), or 0 if none produced usable work
+- score every worker from 0 (nothing usable) to 10 (all its tasks done, all tests pass)
+- check it parses: python3 -m json.tool %[1]s
+If your judgement changes, write the file again; the last version counts.`, o.session.VerdictPath(), o.opts.TotalWorkers())
+}
+
+// checkVerdict reads verdict.json when it changed and announces a valid verdict. An
+// invalid file is reported once per version and the previous verdict stays.
+func (o *Orchestrator) checkVerdict() {
+	path := o.session.VerdictPath()
+	info, err := os.Stat(path)
+	if err != nil || info.ModTime().Equal(o.verdictMod) {
+		return
+	}
+	o.verdictMod = info.ModTime()
+	v, err := verdict.Load(path, o.opts.TotalWorkers())
+	if err != nil {
+		o.logf("verdict unusable: %v", err)
+		o.emit(events.StatusMessage{Message: fmt.Sprintf("Ignoring the supervisor's verdict: %v", err)})
+		return
+	}
+	v.Names = o.opts.WorkerNames
+	o.verdict = &v
+	ev := events.Verdict{Winner: v.Winner, Rationale: v.Rationale}
+	for _, s := range v.Scores {
+		ev.Scores = append(ev.Scores, events.VerdictScore{Worker: s.Worker, Score: s.Score, Tests: s.Tests, Notes: s.Notes})
+	}
+	o.logf("verdict: %s", v.WinnerName())
+	o.emit(ev)
+	o.emit(events.StatusMessage{Message: fmt.Sprintf("Verdict: %s. %s", v.WinnerName(), v.Rationale)})
+}
+
+// scratchpadHeader starts a new session's scratchpad.
+const scratchpadHeader = `# Swarm scratchpad
+
+Shared notes of this round's agents: design decisions, conventions and interfaces to agree on.
+
+`
+
+// initScratchpad creates the session's shared scratchpad unless a resumed session already
+// has one, and shows it in the UI.
+func (o *Orchestrator) initScratchpad() {
+	path := o.session.ScratchpadPath()
+	if _, err := os.Stat(path); errors.Is(err, os.ErrNotExist) {
+		if err := os.WriteFile(path, []byte(scratchpadHeader), 0o644); err != nil {
+			o.logf("create scratchpad: %v", err)
+			return
+		}
+	}
+	o.scratchpad = true
+	o.checkScratchpad()
+}
+
+// checkScratchpad announces the scratchpad when it changed since it was last announced.
+// Agents write it directly, so concurrent edits are not merged; the last write wins.
+func (o *Orchestrator) checkScratchpad() {
+	if !o.scratchpad {
+		return
+	}
+	path := o.session.ScratchpadPath()
+	info, err := os.Stat(path)
+	if err != nil || (info.ModTime().Equal(o.scratchpadMod) && info.Size() == o.scratchpadSize) {
+		return
+	}
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return
+	}
+	first := o.scratchpadMod.IsZero()
+	o.scratchpadMod, o.scratchpadSize = info.ModTime(), info.Size()
+	o.emit(events.ScratchpadChanged{Path: path, Content: string(content), Time: info.ModTime()})
+	if !first {
+		o.logf("scratchpad changed (%d bytes)", info.Size())
+		o.emit(events.StatusMessage{Message: fmt.Sprintf("Scratchpad updated (%d lines)", strings.Count(strings.TrimRight(string(content), "\n"), "\n")+1)})
+	}
+}
+
+// scratchpadNote tells an agent about the shared scratchpad.
+func (o *Orchestrator) scratchpadNote() string {
+	return fmt.Sprintf(`
+
+## Shared Scratchpad
+
+%s is the swarm's shared memory. Read it before you start a task, and add decisions the
+other agents should follow (naming, interfaces, conventions, approaches that failed) as
+short bullets with your name. Edit the file in place and keep the other notes unless they
+are wrong. Others may write at the same time and the last write wins, so re-read it right
+before editing and keep each edit small.`, o.session.ScratchpadPath())
+}
+
+// approvalNote replaces the autopilot step that opens the pull request when pull requests
+// need the user's approval: the worker writes a draft the orchestrator picks up instead.
+func (o *Orchestrator) approvalNote(worker int) string {
+	return fmt.Sprintf(`
+
+NOTE: In this session pull requests need a human's approval. Do NOT create the pull request
+yourself; skip that step of the autopilot instructions. Once your branch is pushed, write the
+pull request to %s instead: its title on the first line, a blank line, then the description.
+The orchestrator opens it after a human approves it. Write the file again to change it.`, o.session.PRDraftPath(worker))
+}
+
+// pendingPR is a pull request a worker asked for under --approve-prs. modTime is the draft
+// file's, so a rewritten draft is offered again.
+type pendingPR struct {
+	modTime time.Time
+	event   events.PendingPR
+}
+
+// checkPRDrafts picks up the pull request drafts workers wrote since the last check and
+// offers them for approval with the branch's commits and diff stats.
+func (o *Orchestrator) checkPRDrafts(ctx context.Context) {
+	if !o.opts.ApprovePRs {
+		return
+	}
+	for id, spec := range o.workerSpecs {
+		if !spec.autopilot || spec.branchName == "" {
+			continue
+		}
+		info, err := os.Stat(o.session.PRDraftPath(spec.index + 1))
+		if err != nil {
+			continue
+		}
+		o.pendingMu.Lock()
+		p := o.pendingPRs[id]
+		known := p != nil && (p.modTime.Equal(info.ModTime()) || p.event.State == "opening" || p.event.State == "opened")
+		o.pendingMu.Unlock()
+		if known {
+			continue
+		}
+		content, err := os.ReadFile(o.session.PRDraftPath(spec.index + 1))
+		if err != nil {
+			continue
+		}
+		title, body := parsePRDraft(string(content))
+		if title == "" {
+			continue
+		}
+		base := ""
+		if o.opts.RepoFor(spec.index) == o.opts.Repo {
+			base = o.worktreeBase
+		}
+		commits, stat, err := worktree.BranchSummary(ctx, spec.worktree, spec.branchName, base)
+		if err != nil {
+			o.logf("pr draft of %s: %v", id, err)
+		}
+		ev := events.PendingPR{ID: id, Branch: spec.branchName, Title: title, Body: body, Commits: commits, Stat: stat, State: "pending"}
+		o.pendingMu.Lock()
+		o.pendingPRs[id] = &pendingPR{modTime: info.ModTime(), event: ev}
+		o.pendingMu.Unlock()
+		o.logf("%s asks to open a pull request: %s", id, title)
+		o.emit(ev)
+		o.emit(events.StatusMessage{Message: fmt.Sprintf("%s asks to open a pull request: %s (approve in Pending PRs)", id, title)})
+	}
+}
+
+// parsePRDraft splits a draft into its title, the first non-empty line without Markdown
+// heading marks, and the body after it.
+func parsePRDraft(content string) (title, body string) {
+	lines := strings.Split(strings.ReplaceAll(content, "\r\n", "\n"), "\n")
+	for i, line := range lines {
+		if line = strings.TrimSpace(line); line != "" {
+			return strings.TrimSpace(strings.TrimLeft(line, "# ")), strings.TrimSpace(strings.Join(lines[i+1:], "\n"))
+		}
+	}
+	return "", ""
+}
+
+// approvePR pushes the worker's branch and opens its pending pull request in the background.
+func (o *Orchestrator) approvePR(id string) error {
+	o.pendingMu.Lock()
+	p := o.pendingPRs[id]
+	if p == nil || (p.event.State != "pending" && p.event.State != "failed") {
+		o.pendingMu.Unlock()
+		return fmt.Errorf("%s has no pull request waiting for approval", id)
+	}
+	p.event.State, p.event.Error = "opening", ""
+	ev := p.event
+	o.pendingMu.Unlock()
+	o.emit(ev)
+
+	spec := o.workerSpecs[id]
+	o.background.Add(1)
+	go func() {
+		defer o.background.Done()
+		ctx, cancel := context.WithTimeout(context.Background(), prOpenTimeout)
+		defer cancel()
+		url, err := o.openPR(ctx, spec, ev)
+		o.pendingMu.Lock()
+		if err != nil {
+			p.event.State, p.event.Error = "failed", err.Error()
+		} else {
+			p.event.State, p.event.URL = "opened", url
+		}
+		ev := p.event
+		o.pendingMu.Unlock()
+		o.emit(ev)
+		if err != nil {
+			o.logf("open pull request for %s: %v", id, err)
+			o.emit(events.StatusMessage{Message: fmt.Sprintf("Could not open the pull request of %s: %v", id, err)})
+			return
+		}
+		o.logf("opened pull request for %s: %s", id, url)
+		o.emit(events.StatusMessage{Message: fmt.Sprintf("Opened pull request of %s: %s", id, url)})
+		artifact := session.Artifact{Kind: session.ArtifactPullRequest, URL: url, Agent: id, Found: time.Now()}
+		if err := o.session.RecordArtifacts(artifact); err != nil {
+			o.logf("record artifacts: %v", err)
+		}
+	}()
+	return nil
+}
+
+// prOpenTimeout bounds pushing a branch and opening its pull request.
+const prOpenTimeout = 2 * time.Minute
+
+func (o *Orchestrator) openPR(ctx context.Context, spec workerSpec, ev events.PendingPR) (string, error) {
+	f, err := forge.Open(spec.worktree)
+	if err != nil {
+		return "", err
+	}
+	push := exec.CommandContext(ctx, "git", "push", "origin", ev.Branch)
+	push.Dir = spec.worktree
+	if out, err := push.CombinedOutput(); err != nil {
+		return "", fmt.Errorf("push %s: %v: %s", ev.Branch, err, strings.TrimSpace(string(out)))
+	}
+	body := ev.Body
+	if !strings.Contains(body, o.session.Tag()) {
+		body = strings.TrimSpace(body + "\n\n" + o.session.Tag())
+	}
+	pr, err := f.CreatePR(ctx, spec.worktree, forge.NewPR{Title: ev.Title, Body: body, Branch: ev.Branch})
+	if err != nil {
+		return "", err
+	}
+	return pr.URL, nil
+}
+
+// rejectPR discards a worker's pending pull request. A rewritten draft is offered again.
+func (o *Orchestrator) rejectPR(id string) error {
+	o.pendingMu.Lock()
+	p := o.pendingPRs[id]
+	if p == nil || (p.event.State != "pending" && p.event.State != "failed") {
+		o.pendingMu.Unlock()
+		return fmt.Errorf("%s has no pull request waiting for approval", id)
+	}
+	p.event.State = "rejected"
+	ev := p.event
+	o.pendingMu.Unlock()
+	o.logf("rejected pull request of %s: %s", id, ev.Title)
+	o.emit(ev)
+	o.emit(events.StatusMessage{Message: fmt.Sprintf("Rejected the pull request of %s", id)})
+	return nil
+}
+
+// timeboxNote lists the time boxes of the todo's tasks and asks workers to announce the
+// task they work on, so overruns can be spotted. It is empty when no task has a time box.
+func (o *Orchestrator) timeboxNote() string {
+	var boxes []string
+	for _, t := range o.todoBaseline {
+		if t.Budget > 0 && !t.Done {
+			boxes = append(boxes, fmt.Sprintf("- %s: %s", t.Title, t.Budget))
+		}
+	}
+	if len(boxes) == 0 {
+		return ""
+	}
+	return fmt.Sprintf(`
+
+NOTE: Some tasks are time-boxed. Whenever you start a task from the todo file, first print
+a line "TASK: <the task's title>" so the orchestrator can track it. If you go over a task's
+time box, wrap up: commit what works, note your findings under the task and move on.
+%s`, strings.Join(boxes, "\n"))
+}
+
+// checkTimeboxes applies --timebox-action to workers that have been on their announced task
+// for longer than its time box. Each claim is acted on once.
+func (o *Orchestrator) checkTimeboxes(ctx context.Context) {
+	for _, a := range o.currentWorkers() {
+		select {
+		case <-a.Done():
+			continue
+		default:
+		}
+		claim, since := a.Claim()
+		if claim == "" || o.timeboxWarned[a.ID].Equal(since) {
+			continue
+		}
+		task, ok := todo.Find(o.todoBaseline, claim)
+		if !ok || task.Budget <= 0 || time.Since(since) < task.Budget {
+			continue
+		}
+		o.timeboxWarned[a.ID] = since
+		spent := time.Since(since).Round(time.Minute)
+		o.logf("%s over time box: %q for %s (box %s, action=%s)", a.ID, task.Title, spent, task.Budget, o.opts.TimeboxAction)
+		o.emit(events.StatusMessage{Message: fmt.Sprintf("%s has spent %s on %q, over its %s time box", a.ID, spent, task.Title, task.Budget)})
+
+		if o.opts.TimeboxAction == config.TimeboxNudge && a.AcceptsInput() {
+			msg := fmt.Sprintf("Time box: you have spent %s on %q, over its %s time box. Wrap up now: commit what works, note your findings under the task in the todo file and move on to another task.", spent, task.Title, task.Budget)
+			err := o.sendMessage(a.ID, msg)
+			if err == nil {
+				continue
+			}
+			o.logf("time box nudge %s failed: %v; restarting instead", a.ID, err)
+		}
+		note := fmt.Sprintf("Your previous run spent %s on %q, over its %s time box. Commit what works and note your findings under the task in the todo file, then move on to another task.", spent, task.Title, task.Budget)
+		if o.opts.TimeboxAction == config.TimeboxReassign {
+			note = fmt.Sprintf("Your previous run spent %s on %q, over its %s time box, and was stopped. Do not continue that task: note your findings under it in the todo file, then pick a different task.", spent, task.Title, task.Budget)
+		}
+		if err := o.restartAgent(ctx, a.ID, note); err != nil {
+			o.emit(events.StatusMessage{Message: fmt.Sprintf("restart %s: %v", a.ID, err)})
+		}
+	}
+}
+
+// claimNote asks workers sharing a todo to announce the task they start, so two workers on
+// the same task can be spotted. timeboxNote asks the same when the todo has time boxes.
+const claimNote = `
+
+NOTE: Other workers pick tasks from the same todo file. Whenever you start a task from it,
+first print a line "TASK: <the task's title>" so the orchestrator can tell you when another
+worker is already on it.`
+
+// workerActivity is what a worker appears to work on, for checkDuplicates.
+type workerActivity struct {
+	agent *agents.Agent
+	// since is when the worker announced its current task, zero without one.
+	since time.Time
+	// tasks are the todo tasks its claim and commit messages match; loose are the claim and
+	// commit messages that match none.
+	tasks []string
+	loose []string
+}
+
+// checkDuplicates warns about workers that appear to work on the same todo task, judged
+// by the tasks they announced and the messages of their commits, and under
+// --duplicate-action switch tells the one that took the task last to pick another.
+func (o *Orchestrator) checkDuplicates(ctx context.Context) {
+	var acts []workerActivity
+	for _, a := range o.currentWorkers() {
+		select {
+		case <-a.Done():
+			continue
+		default:
+		}
+		act := workerActivity{agent: a}
+		claim, since := a.Claim()
+		texts := o.workerCommits(ctx, a.ID)
+		if claim != "" {
+			act.since = since
+			texts = append([]string{claim}, texts...)
+		}
+		for _, text := range texts {
+			if t, ok := todo.Match(o.todoBaseline, text); ok {
+				if !slices.Contains(act.tasks, t.Title) {
+					act.tasks = append(act.tasks, t.Title)
+				}
+			} else {
+				act.loose = append(act.loose, text)
+			}
+		}
+		acts = append(acts, act)
+	}
+	for i := range acts {
+		for j := i + 1; j < len(acts); j++ {
+			if task := sharedTask(acts[i], acts[j]); task != "" {
+				o.handleDuplicate(ctx, acts[i], acts[j], task)
+			}
+		}
+	}
+}
+
+// sharedTask returns a task both workers appear to work on, or "" when there is none.
+func sharedTask(a, b workerActivity) string {
+	for _, t := range a.tasks {
+		if slices.Contains(b.tasks, t) {
+			return t
+		}
+	}
+	for _, x := range a.loose {
+		for _, y := range b.loose {
+			if todo.Similar(x, y) {
+				return x
+			}
+		}
+	}
+	return ""
+}
+
+// workerCommits returns the messages of the commits a worker made since it started,
+// newest first; nil without git.
+func (o *Orchestrator) workerCommits(ctx context.Context, id string) []string {
+	spec, ok := o.workerSpecs[id]
+	if !ok || o.opts.NoGit {
+		return nil
+	}
+	base := ""
+	if o.opts.RepoFor(spec.index) == o.opts.Repo {
+		base = o.worktreeBase
+	}
+	commits, _, err := worktree.BranchSummary(ctx, spec.worktree, "HEAD", base)
+	if err != nil {
+		return nil
+	}
+	return commits
+}
+
+// handleDuplicate warns once per pair and task that two workers appear to work on the
+// same task and applies --duplicate-action to the one that took it last.
+func (o *Orchestrator) handleDuplicate(ctx context.Context, a, b workerActivity, task string) {
+	key := a.agent.ID + "\x00" + b.agent.ID + "\x00" + task
+	if o.duplicatesWarned[key] {
+		return
+	}
+	o.duplicatesWarned[key] = true
+	o.logf("duplicate task: %s and %s both appear to work on %q (action=%s)", a.agent.ID, b.agent.ID, task, o.opts.DuplicateAction)
+	o.emit(events.DuplicateTask{IDs: []string{a.agent.ID, b.agent.ID}, Task: task})
+	o.emit(events.StatusMessage{Message: fmt.Sprintf("WARNING: %s and %s both appear to work on %q", a.agent.ID, b.agent.ID, task)})
+	if o.opts.DuplicateAction != config.DuplicateSwitch {
+		return
+	}
+
+	// The worker that announced the task last gives way; unless both announced it, the one
+	// with the higher number does.
+	keep, move := a, b
+	if a.since.IsZero() || b.since.IsZero() {
+		if o.workerSpecs[a.agent.ID].index > o.workerSpecs[b.agent.ID].index {
+			keep, move = b, a
+		}
+	} else if a.since.After(b.since) {
+		keep, move = b, a
+	}
+	msg := fmt.Sprintf("%s is also working on %q. Leave that task to them: commit anything useful, note your findings under the task in the todo file and pick a different task.", keep.agent.ID, task)
+	if move.agent.AcceptsInput() {
+		err := o.sendMessage(move.agent.ID, msg)
+		if err == nil {
+			return
+		}
+		o.logf("duplicate task message to %s failed: %v; restarting instead", move.agent.ID, err)
+	}
+	note := fmt.Sprintf("Your previous run and %s both worked on %q, and it was left to %s. Do not continue that task: note your findings under it in the todo file, then pick a different task.", keep.agent.ID, task, keep.agent.ID)
+	if err := o.restartAgent(ctx, move.agent.ID, note); err != nil {
+		o.emit(events.StatusMessage{Message: fmt.Sprintf("restart %s: %v", move.agent.ID, err)})
+	}
+}
+
+// find returns the live agent with the given ID, or nil.
+func (o *Orchestrator) find(id string) *agents.Agent {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	for _, a := range o.agents {
+		if a.ID == id {
+			return a
+		}
+	}
+	return nil
+}
+
+// isTracked reports whether a is still the live agent for its ID.
+func (o *Orchestrator) isTracked(a *agents.Agent) bool {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	for _, t := range o.agents {
+		if t == a {
+			return true
+		}
+	}
+	return false
+}
+
+// currentWorkers returns the live worker agents, including any restarted during the round.
+func (o *Orchestrator) currentWorkers() []*agents.Agent {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	var list []*agents.Agent
+	for _, a := range o.agents {
+		if _, ok := o.workerSpecs[a.ID]; ok {
+			list = append(list, a)
+		}
+	}
+	return list
+}
+
+// noGitNote is appended to every prompt when the project is not under version control.
+const noGitNote = `
+
+NOTE: This project is not a git repository. Your working directory is a private copy of it.
+Ignore any instructions about commits, branches, merges or pull requests and do not run git
+commands; just edit the files in place.`
+
+// observeNote keeps the supervisor of an observation run from touching the observed work.
+const observeNote = `
+
+NOTE: The workers are run by other tooling and this swarm only observes them. Do not edit,
+commit, merge or push anything in their worktrees or the repository, and do not create
+pull requests or issues; report what you see in your summaries.`
+
+// sessionNote asks agents to tag what they create on GitHub with the session.
+func (o *Orchestrator) sessionNote() string {
+	return fmt.Sprintf(`
+
+NOTE: When you create a GitHub pull request or issue, end its body with a line containing
+only %s so it can be traced back to this swarm session.`, o.session.Tag())
+}
+
+// trackingNote keeps the supervisor from filing issues when progress goes to one issue.
+func (o *Orchestrator) trackingNote() string {
+	return fmt.Sprintf(`
+
+NOTE: Do not create GitHub issues or comment on them. The orchestrator posts progress to
+issue #%d itself; report collisions and findings in your summaries instead.`, o.opts.TrackingIssue)
+}
+
+// configure applies run-wide process settings to an agent before it starts.
+func (o *Orchestrator) configure(a *agents.Agent) {
+	a.StopGrace = o.opts.StopGrace
+	a.Redactor = o.redactor
+	a.Commands = o.commands
+	a.Denylist = o.denylist
+	a.Denials = o.denials
+	if a.ID == "tester" {
+		a.TestReports = o.testReports
+	}
+	a.Tells = o.tells
+	if o.scratchpad {
+		if note := o.scratchpadNote(); !strings.Contains(a.Prompt, note) {
+			a.Prompt += note
+		}
+	}
+	if note := o.inboxNote(a.ID); note != "" {
+		a.Prompt += note
+	}
+	if o.opts.NoGit {
+		if !strings.HasSuffix(a.Prompt, noGitNote) {
+			a.Prompt += noGitNote
+		}
+	} else if note := o.sessionNote(); !strings.Contains(a.Prompt, note) {
+		a.Prompt += note
+	}
+	if note := o.timeboxNote(); note != "" && strings.HasPrefix(a.ID, "worker-") && !strings.Contains(a.Prompt, note) {
+		a.Prompt += note
+	} else if note == "" && o.opts.TotalWorkers() > 1 && strings.HasPrefix(a.ID, "worker-") && !strings.Contains(a.Prompt, claimNote) {
+		a.Prompt += claimNote
+	}
+	if spec, ok := o.workerSpecs[a.ID]; ok && o.plan != nil {
+		if note := prompts.PlanAssignment(*o.plan, spec.index+1); !strings.Contains(a.Prompt, note) {
+			a.Prompt += note
+		}
+	}
+	if spec, ok := o.workerSpecs[a.ID]; ok && o.opts.ApprovePRs && spec.autopilot {
+		if note := o.approvalNote(spec.index + 1); !strings.Contains(a.Prompt, note) {
+			a.Prompt += note
+		}
+	}
+	if a.ID == "supervisor" && o.opts.Observing() && !strings.HasSuffix(a.Prompt, observeNote) {
+		a.Prompt += observeNote
+	}
+	if a.ID == "supervisor" && !o.opts.Observing() && !strings.Contains(a.Prompt, o.verdictNote()) {
+		a.Prompt += o.verdictNote()
+	}
+	if a.ID == "supervisor" && o.opts.TrackingIssue > 0 && !strings.Contains(a.Prompt, o.trackingNote()) {
+		a.Prompt += o.trackingNote()
+	}
+}
+
+// newCLI builds the CLI adapter for an agent type, applying the session's model rotation
+// offset and any configured model override.
+func (o *Orchestrator) newCLI(t config.AgentType) agents.CLI {
+	base := agents.NewCLI(t)
+	if t == config.AgentMock {
+		base = agents.MockCLI(o.opts.MockScript, o.opts.MockDelay)
+	}
+	cli := agents.WithModelOffset(base, o.opts.ModelOffset)
+	if o.opts.Safe {
+		cli = agents.WithSafeMode(cli)
+	}
+	return agents.WithModel(cli, o.opts.ModelFor(t))
+}
+
+func (o *Orchestrator) buildWorkerTypes() []config.AgentType {
+	var types []config.AgentType
+	for i := 0; i < o.opts.ClaudeWorkers; i++ {
+		types = append(types, config.AgentClaude)
+	}
 	for i := 0; i < o.opts.CodexWorkers; i++ {
 		types = append(types, config.AgentCodex)
 	}
@@ -652,6 +3428,9 @@ func (o *Orchestrator) buildWorkerTypes() []config.AgentType {
 	for i := 0; i < o.opts.GeminiWorkers; i++ {
 		types = append(types, config.AgentGemini)
 	}
+	for i := 0; i < o.opts.MockWorkers; i++ {
+		types = append(types, config.AgentMock)
+	}
 	return types
 }
 
@@ -662,16 +3441,283 @@ func (o *Orchestrator) loadTodo() {
 		o.logf("todo load failed: %v", err)
 		return
 	}
+	o.todoBaseline = todo.Parse(string(content))
 	o.emit(events.TodoLoaded{Content: string(content), Path: todoPath})
 }
 
+const todoProgressInterval = 5 * time.Second
+
+// emitTodoProgress compares the initial todo against each worktree's copy and reports completion.
+func (o *Orchestrator) emitTodoProgress(worktrees []string) {
+	if len(o.todoBaseline) == 0 {
+		return
+	}
+	var copies [][]todo.Task
+	for _, wt := range worktrees {
+		tasks, err := todo.Load(filepath.Join(wt, o.opts.Todo))
+		if err != nil {
+			continue
+		}
+		copies = append(copies, tasks)
+	}
+	progress := todo.Compute(o.todoBaseline, copies)
+	o.todoProgress = progress
+	o.emit(events.TodoProgress{Done: progress.Done, Total: progress.Total})
+}
+
+// reportTimeout bounds the git and log scanning done for the end-of-round report.
+const reportTimeout = 30 * time.Second
+
+// writeReport summarises the round into report.md in the session directory. It runs on
+// every exit path, including cancellation, so it uses its own context.
+func (o *Orchestrator) writeReport() {
+	ctx, cancel := context.WithTimeout(context.Background(), reportTimeout)
+	defer cancel()
+
+	o.checkVerdict()
+	round, ids := o.round()
+	o.reported = true
+	o.recordArtifacts(ids)
+	path := o.session.ReportPath()
+	if err := report.Write(ctx, path, round); err != nil {
+		o.logf("report failed: %v", err)
+		return
+	}
+	o.logf("wrote round report to %s", path)
+	round.Timeline = o.timeline()
+	if o.supervisorSpec != nil {
+		round.Verdict = report.LastMessage(o.supervisorSpec.cli, o.supervisorSpec.logPath)
+	}
+	o.writeTimeline(ctx, round)
+	if o.opts.Report == config.ReportHTML {
+		htmlPath := o.session.HTMLReportPath()
+		if err := report.WriteHTML(ctx, htmlPath, round); err != nil {
+			o.logf("html report failed: %v", err)
+		} else {
+			o.logf("wrote html report to %s", htmlPath)
+		}
+	}
+	if o.opts.TrackingIssue > 0 {
+		content, err := os.ReadFile(path)
+		if err != nil {
+			o.logf("tracking issue: %v", err)
+			return
+		}
+		if err := o.commentTracking(ctx, string(content)); err != nil {
+			o.logf("tracking issue: %v", err)
+		}
+	}
+}
+
+// timeline lists the phases, agent starts and stops, alerts, pull requests and status
+// messages recorded in the session's event log, for the HTML and timeline reports.
+func (o *Orchestrator) timeline() []report.Entry {
+	records, err := events.ReadLog(o.session.EventLogPath())
+	if err != nil {
+		o.logf("timeline: %v", err)
+		return nil
+	}
+	var entries []report.Entry
+	seenPR := map[string]bool{}
+	for _, rec := range records {
+		entry := report.Entry{Time: rec.Time}
+		switch e := rec.Event.(type) {
+		case events.PhaseChanged:
+			entry.Kind, entry.Text = "phase", e.Phase
+		case events.AgentAdded:
+			entry.Kind, entry.Text = "start", fmt.Sprintf("%s started (%s %s)", e.ID, e.Kind, e.Model)
+		case events.AgentStopped:
+			entry.Kind, entry.Text = "stop", fmt.Sprintf("%s exited with code %d", e.ID, e.ExitCode)
+		case events.RateLimited:
+			entry.Kind, entry.Text = "alert", fmt.Sprintf("%s rate limited: %s", e.ID, e.Message)
+		case events.AgentStalled:
+			entry.Kind, entry.Text = "alert", fmt.Sprintf("%s stalled: no output for %s", e.ID, e.Idle)
+		case events.PRStatus:
+			if e.URL == "" || seenPR[e.URL] {
+				continue
+			}
+			seenPR[e.URL] = true
+			entry.Kind, entry.Text = "pr", fmt.Sprintf("%s opened #%d %s (%s)", e.ID, e.Number, e.Title, e.URL)
+		case events.StatusMessage:
+			entry.Kind, entry.Text = "status", e.Message
+		default:
+			// CommandDenied is left out: handleDenial also reports it as a status message.
+			continue
+		}
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+// writeTimeline saves the Markdown timeline report to the session directory and, when
+// configured, to the repository's .swarm/reports directory and the reports branch.
+func (o *Orchestrator) writeTimeline(ctx context.Context, round report.Round) {
+	content := []byte(report.Timeline(round))
+	if err := os.WriteFile(o.session.TimelinePath(), content, 0o644); err != nil {
+		o.logf("timeline report failed: %v", err)
+		return
+	}
+	file := path.Join(".swarm", "reports", o.session.ID+".md")
+	if o.opts.TimelineInRepo {
+		dest := filepath.Join(o.opts.Repo, filepath.FromSlash(file))
+		if err := os.MkdirAll(filepath.Dir(dest), 0o755); err != nil {
+			o.logf("timeline report: %v", err)
+		} else if err := os.WriteFile(dest, content, 0o644); err != nil {
+			o.logf("timeline report: %v", err)
+		}
+	}
+	if o.opts.ReportsBranch != "" {
+		message := fmt.Sprintf("Add swarm timeline for session %s", o.session.ID)
+		if err := worktree.CommitFile(ctx, o.opts.Repo, o.opts.ReportsBranch, file, content, message); err != nil {
+			o.logf("timeline report: %v", err)
+			o.emit(events.StatusMessage{Message: fmt.Sprintf("Could not commit the timeline to %s: %v", o.opts.ReportsBranch, err)})
+			return
+		}
+		o.logf("committed timeline report to %s", o.opts.ReportsBranch)
+	}
+}
+
+// round describes the workers of the current round for reports, along with their IDs in
+// worker order.
+func (o *Orchestrator) round() (report.Round, []string) {
+	round := report.Round{
+		SessionID: o.session.ID,
+		Repo:      strings.Join(o.opts.Repositories(), ", "),
+		Started:   o.roundStarted,
+		Finished:  time.Now(),
+		TodoDone:  o.todoProgress.Done,
+		TodoTotal: o.todoProgress.Total,
+		NoGit:     o.opts.NoGit,
+		Prices:    pricing.Default.With(o.opts.Pricing),
+	}
+	ids := make([]string, 0, len(o.workerSpecs))
+	for id := range o.workerSpecs {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return o.workerSpecs[ids[i]].index < o.workerSpecs[ids[j]].index })
+	for _, id := range ids {
+		spec := o.workerSpecs[id]
+		model, display := spec.cli.Model(spec.index)
+		round.Workers = append(round.Workers, report.Worker{
+			Number:    spec.index + 1,
+			Name:      o.opts.WorkerName(spec.index),
+			Kind:      spec.cli.Name(),
+			Model:     display,
+			ModelID:   model,
+			Repo:      o.opts.RepoFor(spec.index),
+			Worktree:  spec.worktree,
+			LogPath:   spec.logPath,
+			CLI:       spec.cli,
+			Restarts:  o.agentRestarts[id],
+			Completed: o.session.IsWorkerCompleted(spec.index + 1),
+			Running:   o.isRunning(id),
+		})
+		if o.opts.Reviewer != "" {
+			round.Workers[len(round.Workers)-1].ReviewPath = o.session.ReviewPath(spec.index + 1)
+		}
+	}
+	if o.opts.Docs != "" {
+		round.DocsPath = o.session.DocsPath()
+	}
+	round.Outcome = o.verdict
+	return round, ids
+}
+
+// isRunning reports whether the agent with id has a live process. Agents that crashed or
+// finished stay tracked until they are restarted, so their process is what counts.
+func (o *Orchestrator) isRunning(id string) bool {
+	a := o.find(id)
+	return a != nil && a.Alive()
+}
+
+// maxCommentLen keeps tracking issue comments below GitHub's 65536 character limit.
+const maxCommentLen = 60000
+
+// postProgress comments a progress table on the tracking issue. The worker state is taken
+// here; collecting git and log details and posting happen in the background.
+func (o *Orchestrator) postProgress(ctx context.Context) {
+	round, _ := o.round()
+	remaining := time.Until(o.deadline)
+	if remaining < 0 {
+		remaining = 0
+	}
+	o.postTracking(ctx, func(ctx context.Context) string {
+		return report.Progress(ctx, round, remaining)
+	})
+}
+
+// postTracking builds a comment and posts it on the tracking issue in the background.
+func (o *Orchestrator) postTracking(ctx context.Context, body func(context.Context) string) {
+	o.background.Add(1)
+	go func() {
+		defer o.background.Done()
+		cctx, cancel := context.WithTimeout(ctx, reportTimeout)
+		defer cancel()
+		if err := o.commentTracking(cctx, body(cctx)); err != nil {
+			o.logf("tracking issue: %v", err)
+		}
+	}()
+}
+
+// commentTracking adds a comment to --tracking-issue through the repository's forge,
+// tagged with the session.
+func (o *Orchestrator) commentTracking(ctx context.Context, body string) error {
+	if len(body) > maxCommentLen {
+		body = strings.ToValidUTF8(body[:maxCommentLen], "") + "\n\n_(truncated)_\n"
+	}
+	body = strings.TrimRight(body, "\n") + "\n\n" + o.session.Tag() + "\n"
+	f, err := forge.Open(o.opts.Repo)
+	if err != nil {
+		return err
+	}
+	if err := f.CommentIssue(ctx, o.opts.Repo, o.opts.TrackingIssue, body); err != nil {
+		return fmt.Errorf("comment on issue #%d: %w", o.opts.TrackingIssue, err)
+	}
+	o.logf("commented on tracking issue #%d", o.opts.TrackingIssue)
+	return nil
+}
+
+// recordArtifacts stores the pull request and issue links found in the worker, supervisor
+// and user command logs in session.json.
+func (o *Orchestrator) recordArtifacts(workerIDs []string) {
+	logs := map[string]string{
+		"supervisor":   o.session.SupervisorLogPath(),
+		"user-command": o.session.UserCommandLogPath(),
+	}
+	// Workers come first so a pull request is credited to the worker that opened it rather
+	// than the supervisor that mentioned it.
+	order := append([]string(nil), workerIDs...)
+	for _, id := range workerIDs {
+		logs[id] = o.workerSpecs[id].logPath
+	}
+	order = append(order, "supervisor", "user-command")
+	now := time.Now()
+	var found []session.Artifact
+	for _, id := range order {
+		prs, issues := report.Links(logs[id])
+		for _, url := range prs {
+			found = append(found, session.Artifact{Kind: session.ArtifactPullRequest, URL: url, Agent: id, Found: now})
+		}
+		for _, url := range issues {
+			found = append(found, session.Artifact{Kind: session.ArtifactIssue, URL: url, Agent: id, Found: now})
+		}
+	}
+	if err := o.session.RecordArtifacts(found...); err != nil {
+		o.logf("record artifacts: %v", err)
+	}
+}
+
 func (o *Orchestrator) runPrep(ctx context.Context, prepPath string) (string, error) {
-	cli := agents.NewCLI(o.opts.PrepAgent)
+	cli := o.newCLI(o.opts.PrepAgent)
 	logPath := o.session.PrepLogPath()
 
 	prep := agents.NewPrep(prepPath, filepath.Join(prepPath, o.opts.Todo), cli, logPath, o.events)
+	if o.opts.Plan {
+		prep.Prompt += prompts.PlanningPrompt(filepath.Join(prepPath, o.opts.Todo), o.session.PlanPath(), o.opts.TotalWorkers())
+	}
 
-	if err := prep.Start(ctx); err != nil {
+	o.configure(prep)
+	if err := o.start(ctx, prep); err != nil {
 		return "", fmt.Errorf("start prep agent: %w", err)
 	}
 	select {
@@ -684,6 +3730,11 @@ func (o *Orchestrator) runPrep(ctx context.Context, prepPath string) (string, er
 	if exit := prep.ExitCode(); exit != 0 {
 		return "", fmt.Errorf("prep agent failed with exit code %d (see %s)", exit, logPath)
 	}
+	// What pre roles leave in the prep worktree is part of the commit workers start from.
+	o.runRoles(ctx, config.RolePre, prepPath)
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
 
 	ref, err := o.snapshotPrep(ctx, prepPath)
 	if err != nil {
@@ -694,7 +3745,34 @@ func (o *Orchestrator) runPrep(ctx context.Context, prepPath string) (string, er
 	return ref, nil
 }
 
+// loadPlan reads the plan the prep agent wrote. Without a usable one the round goes on the
+// usual way, with every worker reading the todo.
+func (o *Orchestrator) loadPlan() {
+	p, err := plan.Load(o.session.PlanPath(), o.opts.TotalWorkers())
+	if err != nil {
+		o.logf("plan unavailable: %v", err)
+		o.emit(events.StatusMessage{Message: fmt.Sprintf("No usable plan, workers pick tasks from the todo themselves: %v", err)})
+		return
+	}
+	o.plan = &p
+	o.logf("loaded plan with %d tasks", len(p.Tasks))
+	for w := 1; w <= o.opts.TotalWorkers(); w++ {
+		var titles []string
+		for _, t := range p.For(w) {
+			titles = append(titles, t.Title)
+		}
+		if len(titles) == 0 {
+			titles = append(titles, "no tasks")
+		}
+		o.emit(events.StatusMessage{Message: fmt.Sprintf("Plan for worker %d: %s", w, strings.Join(titles, "; "))})
+	}
+}
+
 func (o *Orchestrator) snapshotPrep(ctx context.Context, prepPath string) (string, error) {
+	if o.opts.NoGit {
+		// Worker sandboxes are copied straight from the prep sandbox.
+		return prepPath, nil
+	}
 	status, err := gitOutput(ctx, prepPath, "status", "--porcelain")
 	if err != nil {
 		o.logf("prep git status failed: %v", err)
@@ -735,39 +3813,87 @@ func runGit(ctx context.Context, dir string, args ...string) error {
 	return err
 }
 
-func checkGhAvailable() bool {
-	_, err := exec.LookPath("gh")
-	return err == nil
+// setPhase announces a phase of the round and checkpoints it.
+func (o *Orchestrator) setPhase(phase string) {
+	o.phase = phase
+	o.emit(events.PhaseChanged{Phase: phase})
+	o.saveCheckpoint()
 }
 
-func checkGitHubRepo(repoPath string) bool {
-	if repoPath == "" {
-		return false
+// heartbeatInterval is how often every running agent's heartbeat is sent.
+const heartbeatInterval = 15 * time.Second
+
+// emitHeartbeats reports when each agent whose process is running last wrote to its log.
+func (o *Orchestrator) emitHeartbeats() {
+	o.mu.Lock()
+	list := slices.Clone(o.agents)
+	o.mu.Unlock()
+	for _, a := range list {
+		// Once the exit is seen the agent is only winding down; AgentStopped follows.
+		if !a.Alive() {
+			continue
+		}
+		o.emit(events.AgentHeartbeat{ID: a.ID, LastActive: a.LastOutput()})
 	}
-	cmd := exec.Command("git", "config", "--get", "remote.origin.url")
-	cmd.Dir = repoPath
-	out, err := cmd.Output()
-	if err != nil {
-		return false
+}
+
+// checkpointInterval is how often the round's state is checkpointed besides phase changes.
+const checkpointInterval = 15 * time.Second
+
+// saveCheckpoint persists the phase, deadline, worktree base and per-agent state so a
+// crashed run can be resumed where it was.
+func (o *Orchestrator) saveCheckpoint() {
+	cp := session.Checkpoint{
+		Saved:    time.Now(),
+		Phase:    o.phase,
+		Round:    max(o.arenaRound, 1),
+		Deadline: o.deadline,
+		BaseRef:  o.worktreeBase,
+	}
+	ids := make([]string, 0, len(o.workerSpecs)+1)
+	for id := range o.workerSpecs {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	if o.supervisorSpec != nil {
+		ids = append(ids, "supervisor")
+	}
+	for _, id := range ids {
+		ac := session.AgentCheckpoint{ID: id, Restarts: o.agentRestarts[id]}
+		if spec, ok := o.workerSpecs[id]; ok {
+			ac.Branch = spec.branchName
+		}
+		if a := o.find(id); a != nil {
+			select {
+			case <-a.Done():
+				ac.ExitCode = a.ExitCode()
+			default:
+				ac.Running = a.Done() != nil
+			}
+			ac.Stopped = a.Stopped()
+		} else if prev, ok := o.resumed.Agent(id); ok {
+			// A worker left stopped on resume stays stopped in later checkpoints.
+			ac.Stopped = prev.Stopped
+		}
+		cp.Agents = append(cp.Agents, ac)
+	}
+	if err := o.session.SaveCheckpoint(cp); err != nil {
+		o.logf("checkpoint: %v", err)
 	}
-	remote := strings.ToLower(strings.TrimSpace(string(out)))
-	return strings.Contains(remote, "github.com")
 }
 
 func (o *Orchestrator) emit(ev events.Event) {
 	if o.events == nil {
 		return
 	}
-	switch ev.(type) {
-	case events.AgentAdded:
-		// Agent listings are critical to the UI; block briefly rather than drop.
+	if !events.Droppable(ev) {
+		// State changes must reach the UI; events.Queue drains the channel, so this is brief.
 		o.events <- ev
+		return
+	}
+	select {
+	case o.events <- ev:
 	default:
-		select {
-		case o.events <- ev:
-		default:
-			o.logf("dropping event %T: channel full", ev)
-		}
 	}
 }
 
@@ -812,61 +3938,80 @@ func (l *appLogger) Close() {
 }
 
 type workerSpec struct {
-	index        int
-	worktree     string
-	todoFile     string
-	cli          agents.CLI
-	logPath      string
-	autopilot    bool
-	branchName   string
-	ghAvailable  bool
-	isGitHubRepo bool
+	index      int
+	worktree   string
+	todoFile   string
+	cli        agents.CLI
+	logPath    string
+	autopilot  bool
+	branchName string
+	forgeCLI   bool
+	repoForge  forge.Kind
+}
+
+// roleSpec (re)builds the agent of a role the round started.
+type roleSpec struct {
+	name    string
+	workdir string
+	cli     agents.CLI
+	logPath string
+	// task describes what a pre or post role does, for the status line; empty is generic.
+	task string
+	// build creates the role's agent for a start or restart.
+	build func(restartCount int) *agents.Agent
+	// finish runs after a pre or post role, reporting whether it ran to the end.
+	finish func(ran bool)
 }
 
 type supervisorSpec struct {
 	worktrees    []string
 	workerLogs   []string
 	workerTypes  []config.AgentType
+	workerRepos  []string
 	repoPath     string
 	codedPath    string
 	cli          agents.CLI
 	logPath      string
 	autopilot    bool
-	ghAvailable  bool
-	isGitHubRepo bool
+	forgeCLI     bool
+	repoForge    forge.Kind
 	restartCount int
 }
 
 type userCommandSpec struct {
-	worktrees    []string
-	repoPath     string
-	cli          agents.CLI
-	logPath      string
-	ghAvailable  bool
-	isGitHubRepo bool
+	worktrees []string
+	repoPath  string
+	repoPaths []string
+	cli       agents.CLI
+	logPath   string
+	forgeCLI  bool
+	repoForge forge.Kind
 }
 
 func (o *Orchestrator) restartWorker(ctx context.Context, id string, spec workerSpec, message string) error {
 	restartCount := o.agentRestarts[id] + 1
-	prompt := prompts.WorkerPrompt(spec.todoFile, fmt.Sprintf("Worker %d", spec.index+1), spec.autopilot, spec.branchName, spec.logPath, restartCount, spec.ghAvailable, spec.isGitHubRepo)
+	prompt := prompts.WorkerPrompt(spec.todoFile, o.opts.WorkerName(spec.index), spec.autopilot, spec.branchName, spec.logPath, restartCount, spec.repoForge, spec.forgeCLI)
 	if strings.TrimSpace(message) != "" {
 		prompt = fmt.Sprintf("SYSTEM RESUME NOTE: %s\n\n%s", message, prompt)
 	}
 
-	worker := agents.NewWorker(spec.index, spec.worktree, spec.todoFile, spec.cli, spec.logPath, spec.autopilot, spec.branchName, restartCount, spec.ghAvailable, spec.isGitHubRepo, o.events)
+	worker := agents.NewWorker(spec.index, o.opts.WorkerName(spec.index), spec.worktree, spec.todoFile, spec.cli, spec.logPath, spec.autopilot, spec.branchName, restartCount, spec.repoForge, spec.forgeCLI, o.events)
 	worker.Prompt = prompt
-	if err := worker.Start(ctx); err != nil {
+	o.configure(worker)
+	if err := o.start(ctx, worker); err != nil {
 		return fmt.Errorf("restart %s: %w", id, err)
 	}
+	go o.trackCompletion(spec.index+1, worker)
 	_, display := spec.cli.Model(spec.index)
 	o.emit(events.AgentAdded{
 		ID:       id,
-		Name:     fmt.Sprintf("Worker %d", spec.index+1),
+		Name:     o.opts.WorkerName(spec.index),
 		Kind:     spec.cli.Name(),
 		Model:    display,
 		LogPath:  spec.logPath,
 		Worktree: spec.worktree,
 		Running:  true,
+		Restarts: restartCount,
 	})
 
 	o.mu.Lock()
@@ -874,6 +4019,7 @@ func (o *Orchestrator) restartWorker(ctx context.Context, id string, spec worker
 	o.agentRestarts[id] = restartCount
 	o.mu.Unlock()
 	o.startCollector(ctx, id, spec.worktree, spec.logPath, spec.cli)
+	o.startPRWatcher(ctx, id, spec)
 	o.logf("restarted %s (restartCount=%d)", id, restartCount)
 	o.emit(events.StatusMessage{Message: fmt.Sprintf("Restarted %s with injected note", id)})
 	return nil
@@ -885,20 +4031,18 @@ func (o *Orchestrator) restartSupervisor(ctx context.Context, id string, message
 	}
 	restartCount := o.agentRestarts[id] + 1
 	spec := o.supervisorSpec
-	prompt := prompts.SupervisorPrompt(spec.worktrees, spec.workerLogs, spec.repoPath, spec.codedPath, spec.autopilot, restartCount, spec.ghAvailable, spec.isGitHubRepo)
+	prompt := prompts.SupervisorPrompt(spec.worktrees, spec.workerLogs, spec.workerRepos, o.opts.WorkerNames, spec.repoPath, spec.codedPath, spec.autopilot, restartCount, spec.repoForge, spec.forgeCLI)
 	if strings.TrimSpace(message) != "" {
 		prompt = fmt.Sprintf("SYSTEM RESUME NOTE: %s\n\n%s", message, prompt)
 	}
 
-	sup := agents.NewSupervisor(spec.worktrees, spec.workerLogs, spec.repoPath, spec.codedPath, spec.cli, spec.logPath, spec.autopilot, restartCount, spec.ghAvailable, spec.isGitHubRepo, o.events)
+	sup := agents.NewSupervisor(spec.worktrees, spec.workerLogs, spec.workerRepos, o.opts.WorkerNames, spec.repoPath, spec.codedPath, spec.cli, spec.logPath, spec.autopilot, restartCount, spec.repoForge, spec.forgeCLI, o.events)
 	sup.Prompt = prompt
-	if err := sup.Start(ctx); err != nil {
+	o.configure(sup)
+	if err := o.start(ctx, sup); err != nil {
 		return fmt.Errorf("restart %s: %w", id, err)
 	}
-	_, display := spec.cli.Model(len(spec.worktrees) + 1)
-	if sm, ok := spec.cli.(agents.SupervisorModeler); ok {
-		_, display = sm.SupervisorModel()
-	}
+	_, display := agents.SupervisorModel(spec.cli, len(spec.worktrees)+1)
 	o.emit(events.AgentAdded{
 		ID:       "supervisor",
 		Name:     "Supervisor",
@@ -907,6 +4051,7 @@ func (o *Orchestrator) restartSupervisor(ctx context.Context, id string, message
 		LogPath:  spec.logPath,
 		Worktree: spec.repoPath,
 		Running:  true,
+		Restarts: restartCount,
 	})
 
 	o.mu.Lock()
@@ -919,13 +4064,45 @@ func (o *Orchestrator) restartSupervisor(ctx context.Context, id string, message
 	return nil
 }
 
+func (o *Orchestrator) restartRole(ctx context.Context, id string, spec roleSpec, message string) error {
+	restartCount := o.agentRestarts[id] + 1
+	role := spec.build(restartCount)
+	if strings.TrimSpace(message) != "" {
+		role.Prompt = fmt.Sprintf("SYSTEM RESUME NOTE: %s\n\n%s", message, role.Prompt)
+	}
+	o.configure(role)
+	if err := o.start(ctx, role); err != nil {
+		return fmt.Errorf("restart %s: %w", id, err)
+	}
+	o.emit(events.AgentAdded{
+		ID:       id,
+		Name:     role.Name,
+		Kind:     spec.cli.Name(),
+		Model:    role.Display,
+		LogPath:  spec.logPath,
+		Worktree: spec.workdir,
+		Running:  true,
+		Restarts: restartCount,
+	})
+
+	o.mu.Lock()
+	o.agents = append(o.agents, role)
+	o.agentRestarts[id] = restartCount
+	o.mu.Unlock()
+	o.startCollector(ctx, id, spec.workdir, spec.logPath, spec.cli)
+	o.logf("restarted %s (restartCount=%d)", id, restartCount)
+	o.emit(events.StatusMessage{Message: fmt.Sprintf("Restarted %s with injected note", id)})
+	return nil
+}
+
 func (o *Orchestrator) restartUserCommand(ctx context.Context, id string, message string) error {
 	if o.userSpec == nil {
 		return fmt.Errorf("no user command spec to start")
 	}
 	startCount := o.agentRestarts[id] + 1
-	agent := agents.NewUserCommand(o.userSpec.worktrees, o.userSpec.repoPath, o.userSpec.cli, o.userSpec.logPath, message, o.events)
-	if err := agent.Start(ctx); err != nil {
+	agent := agents.NewUserCommand(o.userSpec.worktrees, o.userSpec.repoPaths, o.userSpec.cli, o.userSpec.logPath, message, o.events)
+	o.configure(agent)
+	if err := o.start(ctx, agent); err != nil {
 		return fmt.Errorf("start %s: %w", id, err)
 	}
 	o.mu.Lock()
@@ -941,3 +4118,11 @@ func (o *Orchestrator) restartUserCommand(ctx context.Context, id string, messag
 	}
 	return nil
 }
+
+// title upper-cases the first letter of s, e.g. an agent type for a status message.
+func title(s string) string {
+	if s == "" {
+		return s
+	}
+	return strings.ToUpper(s[:1]) + s[1:]
+}
@@ -0,0 +1,62 @@
+//go:build !windows
+
+package orchestrator
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/asynkron/Asynkron.SwarmGo/internal/agents"
+	"github.com/asynkron/Asynkron.SwarmGo/internal/config"
+	"github.com/asynkron/Asynkron.SwarmGo/internal/session"
+)
+
+// shellCLI stands in for an agent CLI by running a shell script.
+type shellCLI struct{ script string }
+
+func (shellCLI) Name() string                             { return "Shell" }
+func (shellCLI) Command() string                          { return "sh" }
+func (shellCLI) UseStdin() bool                           { return false }
+func (c shellCLI) BuildArgs(string, string) []string      { return []string{"-c", c.script} }
+func (shellCLI) Model(int) (string, string)               { return "shell", "shell" }
+func (shellCLI) Parse(line string) []agents.ParsedMessage { return nil }
+
+func TestRateLimitedExitRestartsAfterCooldown(t *testing.T) {
+	old := session.Root
+	session.Root = t.TempDir()
+	t.Cleanup(func() { session.Root = old })
+	opts := config.Options{NoGit: true, ClaudeWorkers: 1}
+	sess, err := session.New(opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	o := New(sess, opts, false, nil, nil)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	wt := t.TempDir()
+	spec := workerSpec{worktree: wt, todoFile: "todo.md", cli: shellCLI{"sleep 30"}, logPath: filepath.Join(wt, "worker-1.log")}
+	o.workerSpecs["worker-1"] = spec
+	// The first run exits on its own, as a CLI does when it hits its rate limit.
+	exited := agents.NewWorker(0, "worker-1", wt, spec.todoFile, shellCLI{"exit 1"}, spec.logPath, false, "", 0, "", false, nil)
+	if err := exited.Start(ctx); err != nil {
+		t.Fatal(err)
+	}
+	<-exited.Done()
+	o.agents = append(o.agents, exited)
+	o.coolingDown["worker-1"] = time.Now()
+
+	o.resumeAfterCooldown(ctx, "worker-1", time.Now().Add(time.Hour))
+
+	restarted := o.find("worker-1")
+	if restarted == nil || restarted == exited || !restarted.Alive() {
+		t.Fatalf("worker-1 not restarted after its cooldown (agent %p, exited %p)", restarted, exited)
+	}
+	if _, cooling := o.coolingDown["worker-1"]; cooling {
+		t.Fatal("worker-1 still cooling down")
+	}
+	cancel()
+	<-restarted.Done()
+}
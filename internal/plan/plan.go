@@ -0,0 +1,117 @@
+package plan
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// Plan is the machine-readable work breakdown the prep agent writes in planning mode
+// (--plan): the todo split into tasks, each owned by one worker, in the order they should
+// be done.
+type Plan struct {
+	Tasks []Task `json:"tasks"`
+}
+
+// Task is one unit of work of a plan.
+type Task struct {
+	ID      int    `json:"id"`
+	Title   string `json:"title"`
+	Details string `json:"details,omitempty"`
+	// Worker is the number of the worker that owns the task, starting at 1.
+	Worker int `json:"worker"`
+	// After lists the IDs of tasks that must be finished before this one starts.
+	After []int `json:"after,omitempty"`
+}
+
+// Load reads a plan and checks it against the number of workers; see Normalize.
+func Load(path string, workers int) (Plan, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Plan{}, fmt.Errorf("read plan: %w", err)
+	}
+	var p Plan
+	if err := json.Unmarshal(data, &p); err != nil {
+		return Plan{}, fmt.Errorf("parse plan %s: %w", path, err)
+	}
+	if err := p.Normalize(workers); err != nil {
+		return Plan{}, fmt.Errorf("plan %s: %w", path, err)
+	}
+	return p, nil
+}
+
+// Normalize numbers tasks without an ID and gives tasks without a valid owner to the
+// worker with the fewest tasks. It fails on plans without tasks, untitled tasks, duplicate
+// IDs and dependencies on tasks that don't exist.
+func (p *Plan) Normalize(workers int) error {
+	if workers < 1 {
+		return errors.New("no workers to assign tasks to")
+	}
+	if len(p.Tasks) == 0 {
+		return errors.New("the plan has no tasks")
+	}
+	ids := make(map[int]bool, len(p.Tasks))
+	for i := range p.Tasks {
+		t := &p.Tasks[i]
+		t.Title = strings.TrimSpace(t.Title)
+		if t.Title == "" {
+			return fmt.Errorf("task %d has no title", i+1)
+		}
+		if t.ID == 0 {
+			t.ID = i + 1
+		}
+		if ids[t.ID] {
+			return fmt.Errorf("task ID %d is used twice", t.ID)
+		}
+		ids[t.ID] = true
+	}
+	load := make([]int, workers+1)
+	for _, t := range p.Tasks {
+		if t.Worker >= 1 && t.Worker <= workers {
+			load[t.Worker]++
+		}
+	}
+	for i := range p.Tasks {
+		t := &p.Tasks[i]
+		for _, dep := range t.After {
+			if !ids[dep] {
+				return fmt.Errorf("task %d depends on unknown task %d", t.ID, dep)
+			}
+		}
+		if t.Worker >= 1 && t.Worker <= workers {
+			continue
+		}
+		least := 1
+		for w := 2; w <= workers; w++ {
+			if load[w] < load[least] {
+				least = w
+			}
+		}
+		t.Worker = least
+		load[least]++
+	}
+	return nil
+}
+
+// For returns the tasks worker owns, in plan order.
+func (p Plan) For(worker int) []Task {
+	var tasks []Task
+	for _, t := range p.Tasks {
+		if t.Worker == worker {
+			tasks = append(tasks, t)
+		}
+	}
+	return tasks
+}
+
+// Owner returns the worker that owns the task with id, or 0.
+func (p Plan) Owner(id int) int {
+	for _, t := range p.Tasks {
+		if t.ID == id {
+			return t.Worker
+		}
+	}
+	return 0
+}
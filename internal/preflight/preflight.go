@@ -0,0 +1,419 @@
+package preflight
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/asynkron/Asynkron.SwarmGo/internal/agents"
+	"github.com/asynkron/Asynkron.SwarmGo/internal/artifact"
+	"github.com/asynkron/Asynkron.SwarmGo/internal/config"
+	"github.com/asynkron/Asynkron.SwarmGo/internal/detector"
+	"github.com/asynkron/Asynkron.SwarmGo/internal/disk"
+	"github.com/asynkron/Asynkron.SwarmGo/internal/forge"
+	"github.com/asynkron/Asynkron.SwarmGo/internal/session"
+	"github.com/asynkron/Asynkron.SwarmGo/internal/todo"
+)
+
+// Status is the outcome of a single check.
+type Status int
+
+const (
+	Pass Status = iota
+	// Warn is shown but does not stop the run.
+	Warn
+	// Fail aborts the run before any agent starts.
+	Fail
+)
+
+// Result is one line of the preflight report. Fix tells the user how to resolve a warning
+// or failure.
+type Result struct {
+	Name   string
+	Status Status
+	Detail string
+	Fix    string
+}
+
+const (
+	// minFreeSpace is the least free space in the temp dir a run may start with.
+	minFreeSpace = 1 << 30
+	// lowFreeSpace triggers a warning; a few worktrees of a mid-sized repo fill it quickly.
+	lowFreeSpace = 5 << 30
+)
+
+// Run executes every check that applies to opts, which must already be validated. Agent
+// checks are skipped with --skip-detect; resumed sessions skip the working tree check
+// because their worktrees already exist.
+func Run(ctx context.Context, opts config.Options, resume bool) []Result {
+	var results []Result
+	for _, repo := range opts.Repositories() {
+		results = append(results, checkRepo(ctx, repo, opts, resume)...)
+		results = append(results, checkTodo(filepath.Join(repo, opts.Todo)))
+	}
+	results = append(results, checkDisk(session.Root))
+	if opts.Autopilot || opts.TrackingIssue > 0 {
+		results = append(results, checkForge(ctx, opts))
+	}
+	switch opts.Isolation {
+	case config.IsolationDocker:
+		results = append(results, checkDocker(ctx, opts.DockerImage)...)
+	case config.IsolationDevcontainer:
+		results = append(results, checkDevcontainer(ctx, opts.Repositories())...)
+	case config.IsolationKubernetes:
+		results = append(results, checkKubernetes(ctx, opts)...)
+	}
+	if opts.UploadArtifacts != "" {
+		results = append(results, checkUpload(opts.UploadArtifacts))
+	}
+	if !opts.SkipDetect {
+		results = append(results, checkAgents(opts)...)
+	}
+	return results
+}
+
+// checkDocker verifies that the Docker daemon is reachable for --isolation docker and
+// whether the worker image is already pulled.
+func checkDocker(ctx context.Context, image string) []Result {
+	name := "Docker"
+	if _, err := exec.LookPath("docker"); err != nil {
+		return []Result{{Name: name, Status: Fail, Detail: "docker not found; --isolation docker cannot start worker containers", Fix: "install Docker, or drop --isolation docker"}}
+	}
+	out, err := exec.CommandContext(ctx, "docker", "info", "--format", "{{.ServerVersion}}").Output()
+	if err != nil {
+		return []Result{{Name: name, Status: Fail, Detail: "the Docker daemon is not reachable", Fix: "start Docker, or drop --isolation docker"}}
+	}
+	results := []Result{{Name: name, Status: Pass, Detail: "daemon " + strings.TrimSpace(string(out))}}
+	if err := exec.CommandContext(ctx, "docker", "image", "inspect", image).Run(); err != nil {
+		return append(results, Result{Name: "Image " + image, Status: Warn, Detail: "not pulled yet; the first worker waits for the download", Fix: "run `docker pull " + image + "`"})
+	}
+	return append(results, Result{Name: "Image " + image, Status: Pass, Detail: "available; the agent CLIs it holds are not checked"})
+}
+
+// checkDevcontainer verifies that the devcontainer CLI and Docker are available for
+// --isolation devcontainer and that every repository has a devcontainer configuration.
+func checkDevcontainer(ctx context.Context, repos []string) []Result {
+	name := "Devcontainer CLI"
+	if _, err := exec.LookPath("devcontainer"); err != nil {
+		return []Result{{Name: name, Status: Fail, Detail: "devcontainer not found; --isolation devcontainer cannot start worker containers", Fix: "run `npm install -g @devcontainers/cli`, or drop --isolation devcontainer"}}
+	}
+	results := []Result{{Name: name, Status: Pass, Detail: "installed"}}
+	if err := exec.CommandContext(ctx, "docker", "info").Run(); err != nil {
+		results = append(results, Result{Name: "Docker", Status: Fail, Detail: "the Docker daemon is not reachable", Fix: "start Docker, or drop --isolation devcontainer"})
+	}
+	for _, repo := range repos {
+		name := "Devcontainer " + repo
+		if !agents.HasDevcontainer(repo) {
+			results = append(results, Result{Name: name, Status: Fail, Detail: "no .devcontainer/devcontainer.json", Fix: "add a devcontainer configuration, or use --isolation docker"})
+			continue
+		}
+		results = append(results, Result{Name: name, Status: Pass, Detail: "configured; each worktree builds and starts its own"})
+	}
+	return results
+}
+
+// checkKubernetes verifies that kubectl reaches a cluster for --isolation kubernetes,
+// that jobs can be created in the namespace and that every repository has an origin
+// remote for the pods to clone.
+func checkKubernetes(ctx context.Context, opts config.Options) []Result {
+	name := "Kubernetes"
+	if _, err := exec.LookPath("kubectl"); err != nil {
+		return []Result{{Name: name, Status: Fail, Detail: "kubectl not found; --isolation kubernetes cannot create worker jobs", Fix: "install kubectl, or drop --isolation kubernetes"}}
+	}
+	out, err := exec.CommandContext(ctx, "kubectl", "config", "current-context").Output()
+	if err != nil {
+		return []Result{{Name: name, Status: Fail, Detail: "kubectl has no current context", Fix: "run `kubectl config use-context NAME`"}}
+	}
+	current := strings.TrimSpace(string(out))
+	out, err = exec.CommandContext(ctx, "kubectl", "auth", "can-i", "create", "jobs", "--namespace", opts.KubeNamespace).Output()
+	if err != nil || strings.TrimSpace(string(out)) != "yes" {
+		return []Result{{Name: name, Status: Fail, Detail: fmt.Sprintf("context %s cannot create jobs in namespace %s", current, opts.KubeNamespace), Fix: "pick another --k8s-namespace, or ask for access"}}
+	}
+	results := []Result{{Name: name, Status: Pass, Detail: fmt.Sprintf("context %s, namespace %s; image %s is not checked", current, opts.KubeNamespace, opts.KubeImage)}}
+	if opts.KubeSecret != "" {
+		if err := exec.CommandContext(ctx, "kubectl", "get", "secret", opts.KubeSecret, "--namespace", opts.KubeNamespace).Run(); err != nil {
+			results = append(results, Result{Name: "Secret " + opts.KubeSecret, Status: Fail, Detail: "not found in namespace " + opts.KubeNamespace, Fix: "create it with the agent and forge tokens, e.g. `kubectl create secret generic " + opts.KubeSecret + " --from-literal=ANTHROPIC_API_KEY=...`"})
+		}
+	}
+	for _, repo := range opts.Repositories() {
+		if err := exec.CommandContext(ctx, "git", "-C", repo, "remote", "get-url", "origin").Run(); err != nil {
+			results = append(results, Result{Name: "Origin " + repo, Status: Fail, Detail: "no origin remote for worker pods to clone", Fix: "add an origin remote the cluster can reach"})
+		}
+	}
+	return results
+}
+
+// checkUpload verifies that the CLI --upload-artifacts runs is installed. Credentials come
+// from the environment and are only tried at upload time.
+func checkUpload(dest string) Result {
+	name := "Artifact upload"
+	d, err := artifact.ParseDestination(dest)
+	if err != nil {
+		return Result{Name: name, Status: Fail, Detail: err.Error(), Fix: "pass s3://BUCKET/PREFIX, gs://BUCKET/PREFIX or az://CONTAINER/PREFIX"}
+	}
+	if _, err := exec.LookPath(d.CLI()); err != nil {
+		return Result{Name: name, Status: Warn, Detail: d.CLI() + " not found; the bundle will only be kept in the session directory", Fix: "install the " + d.CLI() + " CLI, or drop --upload-artifacts"}
+	}
+	return Result{Name: name, Status: Pass, Detail: fmt.Sprintf("%s via %s", d, d.CLI())}
+}
+
+// Failed reports whether any check failed.
+func Failed(results []Result) bool {
+	for _, r := range results {
+		if r.Status == Fail {
+			return true
+		}
+	}
+	return false
+}
+
+// Print writes the results as a pass/warn/fail list with remediation steps.
+func Print(w io.Writer, results []Result) {
+	fmt.Fprintln(w, "Preflight checks:")
+	for _, r := range results {
+		mark := "✓"
+		switch r.Status {
+		case Warn:
+			mark = "!"
+		case Fail:
+			mark = "✗"
+		}
+		fmt.Fprintf(w, "  %s %s: %s\n", mark, r.Name, r.Detail)
+		if r.Status != Pass && r.Fix != "" {
+			fmt.Fprintf(w, "      fix: %s\n", r.Fix)
+		}
+	}
+}
+
+func checkRepo(ctx context.Context, repo string, opts config.Options, resume bool) []Result {
+	name := "Repository " + repo
+	if opts.NoGit {
+		return []Result{{Name: name, Status: Pass, Detail: "plain directory (--no-git)"}}
+	}
+	if _, err := git(ctx, repo, "rev-parse", "--is-inside-work-tree"); err != nil {
+		return []Result{{Name: name, Status: Fail, Detail: "not a git repository", Fix: "run `git init` there, or use --no-git"}}
+	}
+	if _, err := git(ctx, repo, "rev-parse", "--verify", "--quiet", "HEAD"); err != nil {
+		return []Result{{Name: name, Status: Fail, Detail: "the repository has no commits, so worktrees have nothing to start from", Fix: "create an initial commit"}}
+	}
+	results := []Result{{Name: name, Status: Pass, Detail: "git repository"}}
+	if resume || opts.AgentMode || opts.Observing() {
+		// Agent mode works in the checkout itself, so its uncommitted changes are visible;
+		// observation creates no worktrees.
+		return results
+	}
+	status, err := git(ctx, repo, "status", "--porcelain")
+	switch {
+	case err != nil:
+		results = append(results, Result{Name: "Working tree", Status: Warn, Detail: err.Error()})
+	case strings.TrimSpace(status) != "":
+		n := len(strings.Split(strings.TrimSpace(status), "\n"))
+		results = append(results, Result{
+			Name:   "Working tree",
+			Status: Warn,
+			Detail: fmt.Sprintf("%d uncommitted changes in %s; worktrees start from committed state without them", n, repo),
+			Fix:    "commit or stash them first if the workers need them",
+		})
+	default:
+		results = append(results, Result{Name: "Working tree", Status: Pass, Detail: "clean"})
+	}
+	return results
+}
+
+func checkTodo(path string) Result {
+	name := "Todo " + path
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return Result{Name: name, Status: Fail, Detail: "cannot read the todo file", Fix: "create it, or point --todo at the right file"}
+	}
+	if strings.TrimSpace(string(content)) == "" {
+		return Result{Name: name, Status: Fail, Detail: "the todo file is empty", Fix: "describe the work for the workers, ideally as `- [ ] task` items"}
+	}
+	tasks := todo.Parse(string(content))
+	if len(tasks) == 0 {
+		return Result{Name: name, Status: Warn, Detail: "no `- [ ]` tasks; progress tracking and the early round end are off", Fix: "list the work as `- [ ] task` items"}
+	}
+	open := 0
+	for _, t := range tasks {
+		if !t.Done {
+			open++
+		}
+	}
+	if open == 0 {
+		return Result{Name: name, Status: Warn, Detail: fmt.Sprintf("all %d tasks are already checked off", len(tasks)), Fix: "add open `- [ ]` tasks"}
+	}
+	return Result{Name: name, Status: Pass, Detail: fmt.Sprintf("%d tasks, %d open", len(tasks), open)}
+}
+
+func checkDisk(dir string) Result {
+	name := "Disk space"
+	// The session directory may not exist yet; measure the nearest existing parent.
+	for {
+		if _, err := os.Stat(dir); err == nil || filepath.Dir(dir) == dir {
+			break
+		}
+		dir = filepath.Dir(dir)
+	}
+	free, err := freeSpace(dir)
+	if err != nil {
+		return Result{Name: name, Status: Warn, Detail: fmt.Sprintf("cannot measure free space in %s: %v", dir, err)}
+	}
+	detail := fmt.Sprintf("%s free in %s", disk.FormatSize(int64(free)), dir)
+	fix := "free up space, or point --session-dir at a larger disk"
+	switch {
+	case free < minFreeSpace:
+		return Result{Name: name, Status: Fail, Detail: detail, Fix: fix}
+	case free < lowFreeSpace:
+		return Result{Name: name, Status: Warn, Detail: detail + "; worktrees may fill it", Fix: fix}
+	}
+	return Result{Name: name, Status: Pass, Detail: detail}
+}
+
+// checkForge verifies that agents can reach the primary repository's forge: glab for
+// GitLab, Bitbucket API credentials for Bitbucket Cloud and gh otherwise.
+func checkForge(ctx context.Context, opts config.Options) Result {
+	kind := forge.Detect(opts.Repo)
+	if kind != forge.GitHub && kind != forge.None && opts.AutoMerge {
+		return Result{Name: kind.Name(), Status: Warn, Detail: "--auto-merge only works with GitHub; every pull request will be skipped", Fix: "drop --auto-merge for this repository"}
+	}
+	switch kind {
+	case forge.GitLab:
+		name := "GitLab CLI"
+		if _, err := exec.LookPath("glab"); err != nil {
+			return Result{Name: name, Status: Warn, Detail: "glab not found; autopilot workers cannot open merge requests", Fix: "install it from https://gitlab.com/gitlab-org/cli, or run with --autopilot=false"}
+		}
+		if _, err := exec.CommandContext(ctx, "glab", "auth", "status").CombinedOutput(); err != nil {
+			return Result{Name: name, Status: Warn, Detail: "glab is not logged in; autopilot workers cannot open merge requests", Fix: "run `glab auth login`"}
+		}
+		return Result{Name: name, Status: Pass, Detail: "installed and logged in"}
+	case forge.AzureDevOps:
+		name := "Azure CLI"
+		if _, err := exec.LookPath("az"); err != nil {
+			return Result{Name: name, Status: Warn, Detail: "az not found; autopilot workers cannot open pull requests", Fix: "install the Azure CLI and run `az extension add --name azure-devops`, or run with --autopilot=false"}
+		}
+		if _, err := exec.CommandContext(ctx, "az", "extension", "show", "--name", "azure-devops").CombinedOutput(); err != nil {
+			return Result{Name: name, Status: Warn, Detail: "the azure-devops extension is missing; `az repos` is unavailable", Fix: "run `az extension add --name azure-devops`"}
+		}
+		if _, err := exec.CommandContext(ctx, "az", "account", "show").CombinedOutput(); err != nil {
+			return Result{Name: name, Status: Warn, Detail: "az is not logged in; autopilot workers cannot open pull requests", Fix: "run `az login` (or `az devops login` with a personal access token)"}
+		}
+		return Result{Name: name, Status: Pass, Detail: "installed with azure-devops, logged in"}
+	case forge.Bitbucket:
+		name := "Bitbucket API"
+		if !kind.CLIAvailable() {
+			return Result{Name: name, Status: Warn, Detail: "no credentials; autopilot workers cannot open pull requests", Fix: "set BITBUCKET_TOKEN, or BITBUCKET_USERNAME and BITBUCKET_APP_PASSWORD"}
+		}
+		return Result{Name: name, Status: Pass, Detail: "credentials set"}
+	}
+	name := "GitHub CLI"
+	impact, optOut := "autopilot workers cannot open pull requests", "run with --autopilot=false"
+	if !opts.Autopilot {
+		impact, optOut = "progress comments on the tracking issue will fail", "drop --tracking-issue"
+	}
+	if _, err := exec.LookPath("gh"); err != nil {
+		return Result{Name: name, Status: Warn, Detail: "gh not found; " + impact, Fix: "install it from https://cli.github.com, or " + optOut}
+	}
+	if _, err := exec.CommandContext(ctx, "gh", "auth", "status").CombinedOutput(); err != nil {
+		return Result{Name: name, Status: Warn, Detail: "gh is not logged in; " + impact, Fix: "run `gh auth login`"}
+	}
+	return Result{Name: name, Status: Pass, Detail: "installed and logged in"}
+}
+
+// checkAgents verifies that every agent CLI the run needs is installed, recent enough and
+// logged in, and that model overrides are valid.
+func checkAgents(opts config.Options) []Result {
+	required := requiredAgents(opts)
+	var results []Result
+	for _, st := range detector.DetectAll() {
+		if !required[st.Type] {
+			continue
+		}
+		name := "Agent " + string(st.Type)
+		switch {
+		case !st.Installed:
+			results = append(results, Result{Name: name, Status: Fail, Detail: "not found in PATH", Fix: detector.InstallHint(st.Type)})
+		case st.Outdated:
+			results = append(results, Result{
+				Name:   name,
+				Status: Fail,
+				Detail: fmt.Sprintf("%s is older than %s", st.Version, detector.MinimumVersion(st.Type)),
+				Fix:    detector.UpgradeHint(st.Type) + " (or use --skip-detect to start anyway)",
+			})
+		case st.Auth == detector.AuthMissing:
+			results = append(results, Result{Name: name, Status: Fail, Detail: "installed but not logged in", Fix: detector.LoginHint(st.Type) + " (or use --skip-detect to start anyway)"})
+		default:
+			results = append(results, Result{Name: name, Status: Pass, Detail: strings.TrimSpace(st.Version + " " + authDetail(st.Auth))})
+		}
+	}
+	for _, t := range []config.AgentType{config.AgentClaude, config.AgentCodex, config.AgentCopilot, config.AgentGemini} {
+		if err := detector.ValidateModel(t, opts.ModelFor(t)); err != nil {
+			results = append(results, Result{Name: "Model " + string(t), Status: Fail, Detail: err.Error(), Fix: fmt.Sprintf("change or drop --%s-model", t)})
+		}
+	}
+	return results
+}
+
+func requiredAgents(opts config.Options) map[config.AgentType]bool {
+	required := map[config.AgentType]bool{}
+	if opts.AgentMode {
+		required[opts.AgentType] = true
+		return required
+	}
+	if opts.Observing() {
+		// Observed logs are only parsed; the supervisor is the one CLI that runs.
+		required[opts.Supervisor] = true
+		return required
+	}
+	// Contained workers run the CLIs installed in their container, which can't be checked
+	// from the host.
+	if opts.Isolation == config.IsolationNone {
+		if opts.ClaudeWorkers > 0 {
+			required[config.AgentClaude] = true
+		}
+		if opts.CodexWorkers > 0 {
+			required[config.AgentCodex] = true
+		}
+		if opts.CopilotWorkers > 0 {
+			required[config.AgentCopilot] = true
+		}
+		if opts.GeminiWorkers > 0 {
+			required[config.AgentGemini] = true
+		}
+	}
+	required[opts.Supervisor] = true
+	required[opts.PrepAgent] = true
+	if opts.Reviewer != "" {
+		required[opts.Reviewer] = true
+	}
+	if opts.Tester != "" {
+		required[opts.Tester] = true
+	}
+	if opts.Docs != "" {
+		required[opts.Docs] = true
+	}
+	for _, name := range opts.Roles {
+		required[opts.RoleDefs[name].Agent] = true
+	}
+	return required
+}
+
+func authDetail(state detector.AuthState) string {
+	if state == detector.AuthAuthenticated {
+		return "(logged in)"
+	}
+	return ""
+}
+
+func git(ctx context.Context, dir string, args ...string) (string, error) {
+	cmd := exec.CommandContext(ctx, "git", args...)
+	cmd.Dir = dir
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		return "", fmt.Errorf("git %s: %v: %s", strings.Join(args, " "), err, strings.TrimSpace(string(out)))
+	}
+	return string(out), nil
+}
@@ -0,0 +1,137 @@
+package pricing
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Price is what a model charges in US dollars per million tokens.
+type Price struct {
+	Input      float64 `json:"input"`
+	Output     float64 `json:"output"`
+	CacheRead  float64 `json:"cacheRead"`
+	CacheWrite float64 `json:"cacheWrite"`
+}
+
+// Usage is the token count of one or more agent turns.
+type Usage struct {
+	InputTokens      int
+	OutputTokens     int
+	CacheReadTokens  int
+	CacheWriteTokens int
+	// CostUSD is the CLI's own cost estimate, when it reports one.
+	CostUSD float64
+}
+
+// Tokens is the total number of tokens of every kind.
+func (u Usage) Tokens() int {
+	return u.InputTokens + u.OutputTokens + u.CacheReadTokens + u.CacheWriteTokens
+}
+
+// Add sums two usages.
+func (u Usage) Add(o Usage) Usage {
+	return Usage{
+		InputTokens:      u.InputTokens + o.InputTokens,
+		OutputTokens:     u.OutputTokens + o.OutputTokens,
+		CacheReadTokens:  u.CacheReadTokens + o.CacheReadTokens,
+		CacheWriteTokens: u.CacheWriteTokens + o.CacheWriteTokens,
+		CostUSD:          u.CostUSD + o.CostUSD,
+	}
+}
+
+// Cost multiplies the usage by the price.
+func (p Price) Cost(u Usage) float64 {
+	return (float64(u.InputTokens)*p.Input +
+		float64(u.OutputTokens)*p.Output +
+		float64(u.CacheReadTokens)*p.CacheRead +
+		float64(u.CacheWriteTokens)*p.CacheWrite) / 1_000_000
+}
+
+// Table maps model names, or prefixes of them, to prices. A CLI's name in lower case
+// (e.g. "gemini") prices its agents when they run on the CLI's default model.
+type Table map[string]Price
+
+// Default holds list prices at the time of writing. Providers change them; override
+// entries with the "pricing" section of the config file.
+var Default = Table{
+	"opus":               {Input: 5, Output: 25, CacheRead: 0.5, CacheWrite: 6.25},
+	"claude-opus-4-5":    {Input: 5, Output: 25, CacheRead: 0.5, CacheWrite: 6.25},
+	"claude-opus-4":      {Input: 15, Output: 75, CacheRead: 1.5, CacheWrite: 18.75},
+	"sonnet":             {Input: 3, Output: 15, CacheRead: 0.3, CacheWrite: 3.75},
+	"claude-sonnet-4":    {Input: 3, Output: 15, CacheRead: 0.3, CacheWrite: 3.75},
+	"haiku":              {Input: 1, Output: 5, CacheRead: 0.1, CacheWrite: 1.25},
+	"claude-haiku-4-5":   {Input: 1, Output: 5, CacheRead: 0.1, CacheWrite: 1.25},
+	"gpt-5":              {Input: 1.25, Output: 10, CacheRead: 0.125},
+	"gpt-5.1-codex-mini": {Input: 0.25, Output: 2, CacheRead: 0.025},
+	"gpt-5.2":            {Input: 1.75, Output: 14, CacheRead: 0.175},
+	"gemini":             {Input: 1.25, Output: 10, CacheRead: 0.125},
+	"gemini-2.5-flash":   {Input: 0.3, Output: 2.5, CacheRead: 0.03},
+}
+
+// With returns a copy of the table with overrides replacing or adding entries.
+func (t Table) With(overrides Table) Table {
+	merged := make(Table, len(t)+len(overrides))
+	for name, p := range t {
+		merged[name] = p
+	}
+	for name, p := range overrides {
+		merged[strings.ToLower(name)] = p
+	}
+	return merged
+}
+
+// Validate rejects negative prices.
+func (t Table) Validate() error {
+	for name, p := range t {
+		if p.Input < 0 || p.Output < 0 || p.CacheRead < 0 || p.CacheWrite < 0 {
+			return fmt.Errorf("pricing for %q has a negative price", name)
+		}
+	}
+	return nil
+}
+
+// Lookup finds the price for the first of names the table knows, matching exactly or by
+// the longest prefix, so "gpt-5.2-codex" falls back to "gpt-5.2". Empty names are skipped.
+func (t Table) Lookup(names ...string) (Price, bool) {
+	for _, name := range names {
+		name = strings.ToLower(name)
+		if name == "" {
+			continue
+		}
+		if p, ok := t[name]; ok {
+			return p, true
+		}
+		best := ""
+		for key := range t {
+			if strings.HasPrefix(name, key) && len(key) > len(best) {
+				best = key
+			}
+		}
+		if best != "" {
+			return t[best], true
+		}
+	}
+	return Price{}, false
+}
+
+// Estimate returns the cost of usage in US dollars: the CLI's own figure when it reported
+// one, otherwise the usage priced by the first of names the table knows. ok is false when
+// neither is available.
+func (t Table) Estimate(u Usage, names ...string) (cost float64, ok bool) {
+	if u.CostUSD > 0 {
+		return u.CostUSD, true
+	}
+	p, ok := t.Lookup(names...)
+	if !ok {
+		return 0, false
+	}
+	return p.Cost(u), true
+}
+
+// Format renders a dollar amount with cent precision, or more digits below a cent.
+func Format(usd float64) string {
+	if usd > 0 && usd < 0.01 {
+		return fmt.Sprintf("$%.4f", usd)
+	}
+	return fmt.Sprintf("$%.2f", usd)
+}
@@ -2,11 +2,16 @@ package prompts
 
 import (
 	"fmt"
+	"sort"
 	"strings"
+	"text/template"
+
+	"github.com/asynkron/Asynkron.SwarmGo/internal/forge"
+	"github.com/asynkron/Asynkron.SwarmGo/internal/plan"
 )
 
 // WorkerPrompt mirrors the .NET worker prompt with Go-friendly formatting.
-func WorkerPrompt(todoFile, agentName string, autopilot bool, branchName string, logPath string, restartCount int, ghAvailable bool, isGitHubRepo bool) string {
+func WorkerPrompt(todoFile, agentName string, autopilot bool, branchName string, logPath string, restartCount int, repoForge forge.Kind, forgeCLI bool) string {
 	base := fmt.Sprintf("run `cat %s` to read the todo file (use cat/tail, not Read tool - files can be large), then follow the instructions", todoFile)
 
 	waysOfWorking := `
@@ -37,16 +42,20 @@ This broadcasts messages to all other agents in the swarm.
 
 ### Using the tell command
 Document ALL relevant findings by using:
-tell "%s: <your message here>"
+tell "%[1]s: <your message here>"
 
-Repository origin: %s
-GitHub CLI (gh): %s
+Repository origin: %[2]s
+%[3]s
 
 Examples:
-- tell "%s: I found a bug in CopycatProxy.cs at lines 2013-2015"
-- tell "%s: Tests now pass after fixing the null check in UserService"
-- tell "%s: The API endpoint requires authentication - add Bearer token"
-- tell "%s: Build fails due to missing dependency - run dotnet restore"
+- tell "%[1]s: I found a bug in CopycatProxy.cs at lines 2013-2015"
+- tell "%[1]s: Tests now pass after fixing the null check in UserService"
+- tell "%[1]s: The API endpoint requires authentication - add Bearer token"
+- tell "%[1]s: Build fails due to missing dependency - run dotnet restore"
+
+To hand a finding to ONE agent instead of the whole swarm, put its ID first
+(worker-1, worker-2, ... or supervisor). Only that agent receives it:
+tell worker-2 "%[1]s: UserService.Save needs the null check I added in a1b2c3d"
 
 What to communicate:
 - Bug locations and descriptions
@@ -57,22 +66,22 @@ What to communicate:
 - Any insight that might help other agents
 
 IMPORTANT: Use tell frequently to share your findings with the swarm.
-`, agentName, agentName, agentName, agentName, agentName, githubRepoHint(isGitHubRepo), ghHint(ghAvailable))
+`, agentName, repoForge.Name(), cliHint(repoForge, forgeCLI))
 
 	autopilotBlock := ""
 	if autopilot && branchName != "" {
 		autopilotBlock = fmt.Sprintf(`
-## Autopilot Mode - GitHub PR Required
+## Autopilot Mode - %[1]s Required
 
 You are running in autopilot mode. When you have completed your work:
 1. Commit all your changes with a descriptive commit message
-2. Create a new branch named: %s
-3. Push the branch to origin: git push origin %s
-4. Create a GitHub PR using: gh pr create --title "<descriptive title>" --body "<summary of changes>"
+2. Create a new branch named: %[2]s
+3. Push the branch to origin: git push origin %[2]s
+4. Create a %[1]s using: %[3]s
 5. Exit when done - do not wait for further instructions
 
-IMPORTANT: You MUST create a GitHub PR before exiting. This is required in autopilot mode.
-`, branchName, branchName)
+IMPORTANT: You MUST create a %[1]s before exiting. This is required in autopilot mode.
+`, requestName(repoForge), branchName, createRequestCommand(repoForge))
 	}
 
 	if restartCount > 0 && logPath != "" {
@@ -95,15 +104,28 @@ Instead, recover your previous work:
 	return strings.TrimSpace(base + shared + autopilotBlock + waysOfWorking)
 }
 
-// SupervisorPrompt mirrors the supervisor prompt for both modes.
-func SupervisorPrompt(worktreePaths []string, workerLogPaths []string, repoPath string, codedSupervisorPath string, autopilot bool, restartCount int, ghAvailable bool, isGitHubRepo bool) string {
+// SupervisorPrompt mirrors the supervisor prompt for both modes. workerRepos holds the
+// repository of each worktree; when it names more than one, the prompt lists them all.
+// workerNames holds the names given to the first workers, if any.
+func SupervisorPrompt(worktreePaths []string, workerLogPaths []string, workerRepos []string, workerNames []string, repoPath string, codedSupervisorPath string, autopilot bool, restartCount int, repoForge forge.Kind, forgeCLI bool) string {
+	repos := distinct(workerRepos)
+	multiRepo := len(repos) > 1
 	workerList := make([]string, len(worktreePaths))
 	for i, wt := range worktreePaths {
-		workerList[i] = fmt.Sprintf("- Worker %d: %s", i+1, wt)
+		workerList[i] = fmt.Sprintf("- %s: %s", workerLabel(i, workerNames), wt)
+		if multiRepo && i < len(workerRepos) {
+			workerList[i] += fmt.Sprintf(" (repository: %s)", workerRepos[i])
+		}
+	}
+	mergeTarget := repoPath
+	mainRepo := "Path: " + repoPath
+	if multiRepo {
+		mergeTarget = "the repository the winner's worktree belongs to (see Worker Locations). Pick one winner per repository"
+		mainRepo = "Workers are spread across these repositories:\n- " + strings.Join(repos, "\n- ")
 	}
 	logList := make([]string, len(workerLogPaths))
 	for i, log := range workerLogPaths {
-		logList[i] = fmt.Sprintf("- Worker %d log: %s", i+1, log)
+		logList[i] = fmt.Sprintf("- %s log: %s", workerLabel(i, workerNames), log)
 	}
 
 	restart := ""
@@ -117,9 +139,9 @@ Check worker logs to understand current state and continue monitoring from where
 	if autopilot {
 		return fmt.Sprintf(`
 You are a supervisor agent overseeing multiple worker agents in AUTOPILOT mode.
-Workers will create their own GitHub PRs when done. Your job is to monitor and summarize their progress.
+Workers will create their own %s when done. Your job is to monitor and summarize their progress.
 Repository origin: %s
-GitHub CLI (gh): %s
+%s
 %s
 ## Your Task: Monitor and Summarize
 
@@ -132,10 +154,7 @@ DO NOT WRITE SCRIPTS. Just run shell commands directly one by one.
 2. After checking all workers:
     * Write a short summary (look for test pass/fail in logs) use markdown format, headers, bullet points etc.
     * When presenting markdown tables to the user, make sure to preformat those with spaces for padding so the table look visually good for a human.
-3. If gh is available and the repo is on GitHub:
-   - For each significant finding/progress from a worker, try to match an existing issue: gh issue list --label swarm --search "<keywords>"
-   - If a rough match exists, reply with gh issue comment <number> summarizing the finding; include code snippets (code fences) from touched files.
-   - If no match exists, create one: gh issue create --title "<concise summary>" --body "<details + snippets>" --label swarm --label bug|research
+%s
    - Choose label "bug" when it's a defect, otherwise "research".
    - If two or more workers are touching the same files or chasing the same failure, create/update a swarm issue summarizing the collision and list the affected workers/branches/log snippets.
 
@@ -147,7 +166,13 @@ DO NOT:
 - Write Python/bash scripts
 - Read code files
 - Run tests or builds yourself
-- Cherry-pick or merge anything (workers create their own PRs)
+- Cherry-pick or merge anything (workers create their own %ss)
+
+## Directed Messages
+
+To hand a finding to one worker only (a fix another worker already has, a collision, a
+pitfall in its task), run: tell worker-N "Supervisor: <message>"
+Only that worker receives it.
 
 ## Worker Locations
 
@@ -159,16 +184,17 @@ DO NOT:
 
 Coded supervisor summary: %s
 Treat this file like the worker logs and read it for up-to-date git status and test signals.
+Its "conflicts" list names worker pairs modifying the same files; treat them as collisions.
 
 START NOW: Begin monitoring immediately. Print status summary every cycle.
 When all workers have finished, provide a final summary and exit.
-`, githubRepoHint(isGitHubRepo), ghHint(ghAvailable), restart, strings.Join(workerList, "\n"), strings.Join(logList, "\n"), codedSupervisorPath)
+`, requestName(repoForge)+"s", repoForge.Name(), cliHint(repoForge, forgeCLI), restart, issueSteps(repoForge), repoForge.Request(), strings.Join(workerList, "\n"), strings.Join(logList, "\n"), codedSupervisorPath)
 	}
 
 	return fmt.Sprintf(`
 You are a supervisor agent overseeing multiple worker agents competing to fix issues.
 Repository origin: %s
-GitHub CLI (gh): %s
+%s
 %s
 IMPORTANT: Do NOT exit until you have completed ALL phases below. This is a long-running task.
 
@@ -185,7 +211,7 @@ DO NOT WRITE SCRIPTS. Just run shell commands directly one by one.
 2. After checking all workers:
     * Write a short summary (look for test pass/fail in logs) use markdown format, headers, bullet points etc.
     * When presenting markdown tables to the user, make sure to preformat those with spaces for padding so the table look visually good for a human.
-    * If gh is available and the repo is on GitHub and you see overlapping work (multiple workers on the same file/bug), create/update a swarm issue describing the overlap and include worker/log references.
+    * If %s is available and the repo is on %s and you see overlapping work (multiple workers on the same file/bug), create/update a swarm issue describing the overlap and include worker/log references.
 
 3. If all logs contain "<<worker has been stopped>>" → go to Phase 2
 4. wait 5 seconds
@@ -207,7 +233,7 @@ At this point:
 ### Phase 3: Merge Winner to Local Main
 Once you've picked a winner:
 1. Go to the winner's worktree and get the list of commits since it diverged from main
-2. Cherry-pick those commits into the LOCAL main branch at: %s
+2. Cherry-pick those commits into the LOCAL main branch of %s
    - Do NOT push to remote
    - This merges the winner's work into local main
 3. Report which items from the todo were fixed
@@ -218,6 +244,12 @@ This way each round builds upon the previous winner's work.
 
 Only exit AFTER Phase 3 is complete.
 
+## Directed Messages
+
+To hand a finding to one worker only (a fix another worker already has, a collision, a
+pitfall in its task), run: tell worker-N "Supervisor: <message>"
+Only that worker receives it.
+
 ## Worker Locations
 
 %s
@@ -228,17 +260,19 @@ Only exit AFTER Phase 3 is complete.
 
 ## Main Repository
 
-Path: %s
+%s
 
 Coded supervisor summary: %s
 Treat this file like the worker logs and read it for up-to-date git status and test signals.
+Its "conflicts" list names worker pairs modifying the same files; treat them as collisions.
 
 START NOW: Begin Phase 1 loop immediately. Print status table every 30 seconds.
-`, githubRepoHint(isGitHubRepo), ghHint(ghAvailable), restart, repoPath, strings.Join(workerList, "\n"), strings.Join(logList, "\n"), repoPath, codedSupervisorPath)
+`, repoForge.Name(), cliHint(repoForge, forgeCLI), restart, repoForge.CLI(), forgeName(repoForge), mergeTarget, strings.Join(workerList, "\n"), strings.Join(logList, "\n"), mainRepo, codedSupervisorPath)
 }
 
-// UserCommandPrompt guides a one-off agent that runs only when asked.
-func UserCommandPrompt(worktreePaths []string, repoPath string, message string) string {
+// UserCommandPrompt guides a one-off agent that runs only when asked. repoPaths lists the
+// run's repositories, the main one first.
+func UserCommandPrompt(worktreePaths []string, repoPaths []string, message string) string {
 	workerList := make([]string, len(worktreePaths))
 	for i, wt := range worktreePaths {
 		workerList[i] = fmt.Sprintf("- Worktree %d: %s", i+1, wt)
@@ -249,7 +283,7 @@ func UserCommandPrompt(worktreePaths []string, repoPath string, message string)
 	}
 	base := fmt.Sprintf(`You are a one-off "user command" agent. You only run when explicitly started by the user. Execute the request below, using any listed worktrees or the main repo, then exit immediately after completing it.
 
-Main repo: %s
+%s
 %s
 Guidelines:
 - You may run tests, inspect git status, and edit files inside the worktrees as needed.
@@ -257,22 +291,280 @@ Guidelines:
 - Stop as soon as the request is finished.
 
 User request:
-%s`, repoPath, worktrees, strings.TrimSpace(message))
+%s`, repoLine(repoPaths), worktrees, strings.TrimSpace(message))
 	return strings.TrimSpace(base)
 }
 
-func ghHint(available bool) string {
+func repoLine(repoPaths []string) string {
+	if len(repoPaths) == 1 {
+		return "Main repo: " + repoPaths[0]
+	}
+	return fmt.Sprintf("Main repo: %s\nOther repos: %s", repoPaths[0], strings.Join(repoPaths[1:], ", "))
+}
+
+// distinct returns the unique non-empty entries of list in first-seen order.
+func distinct(list []string) []string {
+	seen := make(map[string]bool, len(list))
+	var out []string
+	for _, s := range list {
+		if s != "" && !seen[s] {
+			seen[s] = true
+			out = append(out, s)
+		}
+	}
+	return out
+}
+
+// cliHint is the prompt line saying whether the forge's CLI is installed.
+func cliHint(k forge.Kind, available bool) string {
+	if k == forge.Bitbucket {
+		if available {
+			return "Bitbucket API: available via " + forge.HelperCommand()
+		}
+		return "Bitbucket API: no credentials (BITBUCKET_TOKEN is not set)"
+	}
+	state := "not installed"
 	if available {
-		return "available (gh)"
+		state = fmt.Sprintf("available (%s)", k.CLI())
 	}
-	return "not installed"
+	return fmt.Sprintf("%s CLI (%s): %s", forgeName(k), k.CLI(), state)
 }
 
-func githubRepoHint(isGitHub bool) string {
-	if isGitHub {
-		return "GitHub"
+// forgeName names the forge whose CLI the prompts use; other origins get GitHub's.
+func forgeName(k forge.Kind) string {
+	if k == forge.GitLab || k == forge.Bitbucket || k == forge.AzureDevOps {
+		return k.Name()
 	}
-	return "non-GitHub"
+	return "GitHub"
+}
+
+// requestName is the full name of a change proposal, e.g. "GitLab MR".
+func requestName(k forge.Kind) string {
+	return forgeName(k) + " " + k.Request()
+}
+
+func createRequestCommand(k forge.Kind) string {
+	switch k {
+	case forge.GitLab:
+		return `glab mr create --title "<descriptive title>" --description "<summary of changes>" --yes`
+	case forge.Bitbucket:
+		return forge.HelperCommand() + ` create-pr --title "<descriptive title>" --body "<summary of changes>"`
+	case forge.AzureDevOps:
+		return `az repos pr create --title "<descriptive title>" --description "<summary of changes>"`
+	}
+	return `gh pr create --title "<descriptive title>" --body "<summary of changes>"`
+}
+
+// issueSteps tells the autopilot supervisor how to file findings as issues.
+func issueSteps(k forge.Kind) string {
+	switch k {
+	case forge.Bitbucket:
+		return fmt.Sprintf(`3. The repo is on Bitbucket, where swarm does not file issues:
+   - List open pull requests with %s list-prs to see what workers have submitted.
+   - Report significant findings and collisions between workers in your summaries instead.`, forge.HelperCommand())
+	case forge.AzureDevOps:
+		return `3. The repo is on Azure DevOps, where swarm does not file work items:
+   - List active pull requests with az repos pr list --status active --output table to see what workers have submitted.
+   - Report significant findings and collisions between workers in your summaries instead.`
+	case forge.GitLab:
+		return `3. If glab is available and the repo is on GitLab:
+   - For each significant finding/progress from a worker, try to match an existing issue: glab issue list --label swarm --search "<keywords>"
+   - If a rough match exists, reply with glab issue note <number> --message "<finding>" summarizing the finding; include code snippets (code fences) from touched files.
+   - If no match exists, create one: glab issue create --title "<concise summary>" --description "<details + snippets>" --label swarm,bug|research --yes`
+	}
+	return `3. If gh is available and the repo is on GitHub:
+   - For each significant finding/progress from a worker, try to match an existing issue: gh issue list --label swarm --search "<keywords>"
+   - If a rough match exists, reply with gh issue comment <number> summarizing the finding; include code snippets (code fences) from touched files.
+   - If no match exists, create one: gh issue create --title "<concise summary>" --body "<details + snippets>" --label swarm --label bug|research`
+}
+
+// ReviewTarget is one worker branch the reviewer looks at.
+type ReviewTarget struct {
+	Name     string
+	Worktree string
+	Branch   string
+	// Base is the commit the worker started from; the diff under review is Base..Branch.
+	Base string
+	// ReviewPath is where the reviewer writes its review of this branch.
+	ReviewPath string
+}
+
+// ReviewerPrompt guides the agent that reviews the workers' branches after the round, as a
+// code reviewer rather than a monitor. Reviews go to files swarm adds to the report and,
+// when the forge CLI is available, to the branch's pull request.
+func ReviewerPrompt(todoFile string, targets []ReviewTarget, repoForge forge.Kind, forgeCLI bool) string {
+	branches := make([]string, len(targets))
+	for i, t := range targets {
+		branches[i] = fmt.Sprintf(`- %s
+  - Worktree: %s
+  - Branch: %s
+  - Diff: git -C %s diff %s..HEAD (commits: git -C %s log --oneline %s..HEAD)
+  - Write the review to: %s`, t.Name, t.Worktree, t.Branch, t.Worktree, t.Base, t.Worktree, t.Base, t.ReviewPath)
+	}
+
+	post := "Swarm adds the review files to its report; do not post them anywhere else."
+	if forgeCLI && forgeName(repoForge) == "GitHub" {
+		post = `If the branch has a pull request (gh pr view <branch> --json url), also post the review on it:
+   gh pr review <branch> --comment --body-file <review file>
+   Never approve or request changes through gh; a human makes that call. Swarm adds the review files to its report either way.`
+	}
+
+	return fmt.Sprintf(`
+You are a one-off reviewer agent. The workers of a swarm round have stopped; review each branch they produced the way a careful maintainer of this repository reviews a pull request. You do not monitor workers and you do not change code.
+
+Todo file the workers were given: %[1]s (run cat %[1]s; use cat/tail, not the Read tool)
+
+## Branches to Review
+
+%[2]s
+
+## For Each Branch
+
+1. Read the diff and the commit messages. Open the surrounding code where the diff alone is not enough to judge it.
+2. Check the change against the todo: which tasks does it address, and does it actually do what they ask?
+3. Check it against the repository's conventions: naming, error handling, tests, documentation, file layout. Read CONTRIBUTING, AGENTS.md, CLAUDE.md or similar files if the repository has them.
+4. Look for bugs, missing edge cases, leftover debugging code and unrelated changes.
+5. Write the review file in exactly this format:
+
+   # Review: <worker name> (<branch>)
+
+   Verdict: approve | request-changes | comment
+
+   ## Summary
+   <two or three sentences on what the branch does and whether it is ready>
+
+   ## Todo coverage
+   - <task>: done | partial | missing
+
+   ## Findings
+   - [blocker|major|minor|nit] <path>:<line> <what is wrong and how to fix it>
+
+   Write "- none" under Findings when there is nothing to report.
+6. %[3]s
+
+DO NOT:
+- Edit, commit, push or merge anything in the worktrees
+- Run long test suites; a quick build or a focused test is fine when it settles a question
+
+Exit when every branch has a review file.`, todoFile, strings.Join(branches, "\n\n"), post)
+}
+
+// DocsSource is one worker branch the docs agent writes changelog entries and doc updates
+// for.
+type DocsSource struct {
+	Name     string
+	Worktree string
+	Branch   string
+	// Base is the commit the worker started from; the change is Base..Branch.
+	Base string
+}
+
+// DocsPrompt guides the agent that drafts changelog entries and documentation updates for
+// the workers' branches at the end of the round. The draft always goes to draftPath; when
+// branch is set the agent also applies it in its working directory, which has that branch
+// checked out, and commits.
+func DocsPrompt(sources []DocsSource, draftPath, branch string) string {
+	list := make([]string, len(sources))
+	for i, src := range sources {
+		list[i] = fmt.Sprintf(`- %s
+  - Worktree: %s
+  - Branch: %s
+  - Diff: git -C %s diff %s..HEAD (commits: git -C %s log --oneline %s..HEAD)`, src.Name, src.Worktree, src.Branch, src.Worktree, src.Base, src.Worktree, src.Base)
+	}
+
+	apply := `Do not edit, commit or push anything in the repository or the worktrees; the draft file is your only output.`
+	if branch != "" {
+		apply = fmt.Sprintf(`Then apply the draft in your current working directory, which has the branch %[1]s checked out:
+- Add the entries to the changelog (CHANGELOG.md or whatever the repository uses; create CHANGELOG.md in Keep a Changelog style if there is none) under an Unreleased heading.
+- Make the documentation edits you proposed.
+- Commit with the message "docs: changelog and documentation for swarm session changes". Do not push, and do not touch the workers' worktrees.`, branch)
+	}
+
+	return fmt.Sprintf(`
+You are a one-off docs agent. The workers of a swarm round have stopped. Write the changelog entries and documentation updates their branches need, the way the maintainers of this repository would write them.
+
+## Branches
+
+%[1]s
+
+## Steps
+
+1. For each branch, read the commits and the diff and work out what changed for users of the project: new features, changed behaviour, fixes, new flags or settings, removals.
+2. Look at how the repository documents things: CHANGELOG.md or similar, README.md, a docs directory, doc comments. Match their style, headings and tone.
+3. Write the draft to %[2]s in this format:
+
+   # Docs draft
+
+   ## Changelog
+   ### <worker name> (<branch>)
+   - <Added|Changed|Fixed|Removed>: <one line for users, not implementation details>
+
+   ## Documentation updates
+   ### <file>
+   <what to change and the new or changed text, ready to paste>
+
+   Skip branches with nothing user-facing, and say so in one line.
+4. %[3]s
+
+Exit when you are done.`, strings.Join(list, "\n\n"), draftPath, apply)
+}
+
+// TesterPrompt guides the agent that keeps testing the workers' latest commits while they
+// work, in its own worktree, and reports each result on a TEST line the orchestrator reads.
+// worktrees maps worker numbers to their worktrees; base is the commit workers started from.
+func TesterPrompt(worktree string, worktrees map[int]string, base string, restartCount int) string {
+	numbers := make([]int, 0, len(worktrees))
+	for n := range worktrees {
+		numbers = append(numbers, n)
+	}
+	sort.Ints(numbers)
+	workerList := make([]string, len(numbers))
+	for i, n := range numbers {
+		workerList[i] = fmt.Sprintf("- worker-%d: %s", n, worktrees[n])
+	}
+
+	restart := ""
+	if restartCount > 0 {
+		restart = fmt.Sprintf(`
+IMPORTANT: You have been restarted (restart #%d). Start again with the baseline, then continue the loop.
+`, restartCount)
+	}
+
+	return fmt.Sprintf(`
+You are the tester agent of a multi-agent swarm. Workers are changing the code in their own worktrees. Your job is to keep running the test suite against each worker's latest commit and report regressions quickly, so workers get feedback while they still remember what they changed. You never change code.
+
+Your worktree: %[1]s (run everything here; never run tests or edit files in the workers' worktrees)
+Commit the workers started from: %[2]s
+%[3]s
+## Workers
+
+%[4]s
+
+## Baseline
+
+1. git checkout -q --detach %[2]s
+2. Run ./test.sh if it exists, otherwise the project's usual test command. Note which tests fail: they failed before any worker started and are not regressions.
+
+## Loop
+
+1. For each worker:
+   - sha=$(git -C <worker worktree> rev-parse HEAD)
+   - If sha is the commit you tested for this worker last time, skip the worker.
+   - git checkout -q --detach $sha (in your worktree; the commit is already there because worktrees share the repository)
+   - Run the same tests as the baseline. Keep runs short: stop a run that takes more than a few minutes.
+   - Print exactly one result line on its own, with no formatting:
+     TEST: worker-<n> PASS <passed/failed counts>
+     TEST: worker-<n> FAIL <what fails; nothing that passed in the baseline or in this worker's previous commit>
+     TEST: worker-<n> REGRESSION <the tests that broke, the short sha, and the first lines of the failure>
+     A test counts as a regression when it passed in the baseline or in this worker's previously tested commit and fails now.
+2. Wait 30 seconds, then repeat from step 1. Keep looping until you are stopped.
+
+DO NOT:
+- Edit, commit, push or stash anything in any worktree
+- Install dependencies or change global state
+- Print TEST lines for anything but a finished test run
+
+START NOW: run the baseline, then begin the loop.`, worktree, base, restart, strings.Join(workerList, "\n"))
 }
 
 // PrepTestsPrompt guides a one-off agent to generate a focused test script.
@@ -294,3 +586,103 @@ Steps:
 
 Output only necessary commands; avoid extra chatter.`, todoPath)
 }
+
+// PlanningPrompt extends the prep prompt in planning mode (--plan): besides test.sh the prep
+// agent splits the todo into tasks for the round's workers and writes them to planPath.
+func PlanningPrompt(todoPath, planPath string, workers int) string {
+	return fmt.Sprintf(`
+
+## Planning
+
+This round has %[3]d workers. After test.sh is done, plan their work so that each worker gets its own tasks instead of all of them reading the same todo:
+
+1) Split the open items of %[1]s into tasks a single worker can finish in one go. Keep each task's wording close to the todo so progress can be matched to it.
+2) Give every task an owner (worker 1 to %[3]d). Spread the work evenly, and give tasks that touch the same files to the same worker so workers don't collide.
+3) Order each worker's tasks the way they should be done. When a task needs another worker's task finished first, list that task's id under "after".
+4) Write the plan as JSON to %[2]s (not into the repository), in exactly this shape:
+   {"tasks": [{"id": 1, "title": "<short title>", "details": "<what to change and where; optional>", "worker": 1, "after": []}]}
+5) Check it parses: python3 -m json.tool %[2]s or jq . %[2]s`, todoPath, planPath, workers)
+}
+
+// PlanAssignment tells a worker which tasks of the plan it owns, in order, and who owns the
+// rest, so it can coordinate instead of picking tasks from the todo itself.
+func PlanAssignment(p plan.Plan, worker int) string {
+	var b strings.Builder
+	b.WriteString("\n\n## Your Assignment\n\nThe todo has been split between the workers. Work ONLY on your tasks below, in this order, instead of choosing tasks from the todo yourself:\n\n")
+	mine := p.For(worker)
+	for i, t := range mine {
+		fmt.Fprintf(&b, "%d. [task %d] %s\n", i+1, t.ID, t.Title)
+		if t.Details != "" {
+			fmt.Fprintf(&b, "   %s\n", t.Details)
+		}
+		for _, dep := range t.After {
+			if owner := p.Owner(dep); owner != worker {
+				fmt.Fprintf(&b, "   Needs task %d (Worker %d) first; check its worktree or messages before starting.\n", dep, owner)
+			}
+		}
+	}
+	if len(mine) == 0 {
+		b.WriteString("You have no tasks of your own. Help where the other workers are stuck, without editing the files they are working on.\n")
+	}
+	var others []string
+	for _, t := range p.Tasks {
+		if t.Worker != worker {
+			others = append(others, fmt.Sprintf("- [task %d] %s (Worker %d)", t.ID, t.Title, t.Worker))
+		}
+	}
+	if len(others) > 0 {
+		fmt.Fprintf(&b, "\nOther workers own these tasks; leave them alone:\n%s\n", strings.Join(others, "\n"))
+	}
+	b.WriteString("\nWhen all your tasks are done, mark them done in the todo as usual and finish.")
+	return b.String()
+}
+
+// RoleData is what the prompt template of a config file role is rendered with, e.g.
+// {{.Repo}} or {{range .Workers}}{{.Worktree}}{{end}}.
+type RoleData struct {
+	// Role is the role's name and When its timing: pre, during or post.
+	Role string
+	When string
+	// Repo is the primary repository and Workdir the directory the role runs in: the prep
+	// worktree for pre roles, the repository otherwise.
+	Repo    string
+	Workdir string
+	// Todo is the todo file and Session the session directory.
+	Todo    string
+	Session string
+	// Base is the commit worker worktrees start from; empty for pre roles, which run before
+	// it is known.
+	Base    string
+	Workers []RoleWorker
+}
+
+// RoleWorker is one worker of the round as seen by a role's prompt template.
+type RoleWorker struct {
+	Number   int
+	Name     string
+	Worktree string
+	LogPath  string
+}
+
+// RolePrompt renders the prompt template of a config file role.
+func RolePrompt(tmpl string, data RoleData) (string, error) {
+	t, err := template.New(data.Role).Parse(tmpl)
+	if err != nil {
+		return "", err
+	}
+	var b strings.Builder
+	if err := t.Execute(&b, data); err != nil {
+		return "", err
+	}
+	return b.String(), nil
+}
+
+// workerLabel is "Worker N" for worker index i, followed by its name in parentheses when it
+// has one. Supervisors keep the numbers, which verdicts and worker IDs use.
+func workerLabel(i int, names []string) string {
+	label := fmt.Sprintf("Worker %d", i+1)
+	if i < len(names) {
+		label += fmt.Sprintf(" (%s)", names[i])
+	}
+	return label
+}
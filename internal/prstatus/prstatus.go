@@ -0,0 +1,274 @@
+package prstatus
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// Checks summarises the CI checks of a pull request.
+const (
+	ChecksNone    = "none"
+	ChecksPending = "pending"
+	ChecksPassing = "passing"
+	ChecksFailing = "failing"
+)
+
+// Status is the state of the pull request opened from one branch.
+type Status struct {
+	Branch string
+	Number int
+	URL    string
+	Title  string
+	// State is OPEN, CLOSED or MERGED as reported by gh.
+	State   string
+	Checks  string
+	Passed  int
+	Failed  int
+	Pending int
+}
+
+// Watcher polls gh for the pull request of a worktree's branch.
+type Watcher struct {
+	dir      string
+	branch   string
+	interval time.Duration
+}
+
+// NewWatcher builds a watcher for the pull request opened from branch in dir. An empty
+// branch follows whatever branch is checked out in dir.
+func NewWatcher(dir, branch string, interval time.Duration) *Watcher {
+	if interval <= 0 {
+		interval = time.Minute
+	}
+	return &Watcher{dir: dir, branch: branch, interval: interval}
+}
+
+// Start polls until ctx is canceled and calls emit whenever the pull request appears or its
+// state or checks change. Branches without a pull request are polled silently.
+func (w *Watcher) Start(ctx context.Context, emit func(Status)) {
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	var last Status
+	for {
+		if st, ok := w.poll(ctx); ok && st != last {
+			last = st
+			emit(st)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (w *Watcher) poll(ctx context.Context) (Status, bool) {
+	localCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	branch := w.branch
+	if branch == "" {
+		cmd := exec.CommandContext(localCtx, "git", "rev-parse", "--abbrev-ref", "HEAD")
+		cmd.Dir = w.dir
+		out, err := cmd.Output()
+		if err != nil {
+			return Status{}, false
+		}
+		branch = strings.TrimSpace(string(out))
+		if branch == "HEAD" {
+			return Status{}, false
+		}
+	}
+	st, err := Fetch(localCtx, w.dir, branch)
+	return st, err == nil
+}
+
+type prView struct {
+	Number            int           `json:"number"`
+	URL               string        `json:"url"`
+	Title             string        `json:"title"`
+	State             string        `json:"state"`
+	HeadRefName       string        `json:"headRefName"`
+	StatusCheckRollup []checkResult `json:"statusCheckRollup"`
+}
+
+// checkResult is either a check run (status/conclusion) or a commit status (state).
+type checkResult struct {
+	Status     string `json:"status"`
+	Conclusion string `json:"conclusion"`
+	State      string `json:"state"`
+}
+
+// Fetch asks gh for the pull request opened from branch, running in dir so gh picks the
+// right repository. It fails when the branch has no pull request.
+func Fetch(ctx context.Context, dir, branch string) (Status, error) {
+	cmd := exec.CommandContext(ctx, "gh", "pr", "view", branch, "--json", "number,url,title,state,statusCheckRollup")
+	cmd.Dir = dir
+	out, err := cmd.Output()
+	if err != nil {
+		return Status{}, fmt.Errorf("gh pr view %s: %w", branch, err)
+	}
+	var view prView
+	if err := json.Unmarshal(out, &view); err != nil {
+		return Status{}, fmt.Errorf("parse gh pr view output: %w", err)
+	}
+	view.HeadRefName = branch
+	return summarize(view), nil
+}
+
+func summarize(view prView) Status {
+	st := Status{Branch: view.HeadRefName, Number: view.Number, URL: view.URL, Title: view.Title, State: view.State}
+	for _, c := range view.StatusCheckRollup {
+		switch checkOutcome(c) {
+		case ChecksPassing:
+			st.Passed++
+		case ChecksFailing:
+			st.Failed++
+		default:
+			st.Pending++
+		}
+	}
+	switch {
+	case st.Failed > 0:
+		st.Checks = ChecksFailing
+	case st.Pending > 0:
+		st.Checks = ChecksPending
+	case st.Passed > 0:
+		st.Checks = ChecksPassing
+	default:
+		st.Checks = ChecksNone
+	}
+	return st
+}
+
+func checkOutcome(c checkResult) string {
+	if c.State != "" {
+		switch strings.ToUpper(c.State) {
+		case "SUCCESS":
+			return ChecksPassing
+		case "FAILURE", "ERROR":
+			return ChecksFailing
+		}
+		return ChecksPending
+	}
+	if !strings.EqualFold(c.Status, "COMPLETED") {
+		return ChecksPending
+	}
+	switch strings.ToUpper(c.Conclusion) {
+	case "SUCCESS", "NEUTRAL", "SKIPPED":
+		return ChecksPassing
+	}
+	return ChecksFailing
+}
+
+// Summary describes the checks in a few words, e.g. "checks failing (2 failed, 5 passed)".
+func (s Status) Summary() string {
+	if s.Checks == ChecksNone {
+		return "no checks"
+	}
+	var counts []string
+	if s.Failed > 0 {
+		counts = append(counts, fmt.Sprintf("%d failed", s.Failed))
+	}
+	if s.Pending > 0 {
+		counts = append(counts, fmt.Sprintf("%d pending", s.Pending))
+	}
+	if s.Passed > 0 {
+		counts = append(counts, fmt.Sprintf("%d passed", s.Passed))
+	}
+	return fmt.Sprintf("checks %s (%s)", s.Checks, strings.Join(counts, ", "))
+}
+
+// Candidate is an open pull request considered for auto-merge.
+type Candidate struct {
+	Status
+	// Mergeable is MERGEABLE, CONFLICTING or UNKNOWN as reported by gh.
+	Mergeable string
+	Files     []string
+}
+
+type candidateView struct {
+	prView
+	Mergeable string `json:"mergeable"`
+	Files     []struct {
+		Path string `json:"path"`
+	} `json:"files"`
+}
+
+// Inspect loads what auto-merge needs to know about the pull request ref (a number, URL or
+// branch).
+func Inspect(ctx context.Context, dir, ref string) (Candidate, error) {
+	cmd := exec.CommandContext(ctx, "gh", "pr", "view", ref, "--json", "number,url,title,state,headRefName,statusCheckRollup,mergeable,files")
+	cmd.Dir = dir
+	out, err := cmd.Output()
+	if err != nil {
+		return Candidate{}, fmt.Errorf("gh pr view %s: %w", ref, err)
+	}
+	var view candidateView
+	if err := json.Unmarshal(out, &view); err != nil {
+		return Candidate{}, fmt.Errorf("parse gh pr view output: %w", err)
+	}
+	c := Candidate{Status: summarize(view.prView), Mergeable: view.Mergeable}
+	for _, f := range view.Files {
+		c.Files = append(c.Files, f.Path)
+	}
+	return c, nil
+}
+
+// SelectForMerge splits candidates into those safe to auto-merge and the rest, with the
+// reason each was skipped. A pull request qualifies when it is open, its checks pass, it
+// has no conflicts and none of its files are touched by another open candidate.
+func SelectForMerge(candidates []Candidate) (merge []Candidate, skipped map[string]string) {
+	skipped = map[string]string{}
+	owners := map[string][]string{}
+	for _, c := range candidates {
+		if c.State != "OPEN" {
+			continue
+		}
+		for _, f := range c.Files {
+			owners[f] = append(owners[f], c.URL)
+		}
+	}
+	for _, c := range candidates {
+		switch {
+		case c.State != "OPEN":
+			skipped[c.URL] = "not open (" + strings.ToLower(c.State) + ")"
+		case c.Checks != ChecksPassing:
+			skipped[c.URL] = c.Summary()
+		case c.Mergeable == "CONFLICTING":
+			skipped[c.URL] = "has merge conflicts"
+		default:
+			if f := sharedFile(c, owners); f != "" {
+				skipped[c.URL] = "overlaps another pull request in " + f
+				continue
+			}
+			merge = append(merge, c)
+		}
+	}
+	return merge, skipped
+}
+
+func sharedFile(c Candidate, owners map[string][]string) string {
+	for _, f := range c.Files {
+		if len(owners[f]) > 1 {
+			return f
+		}
+	}
+	return ""
+}
+
+// EnableAutoMerge turns on squash auto-merge for the pull request ref, so GitHub merges it
+// once branch protection is satisfied.
+func EnableAutoMerge(ctx context.Context, dir, ref string) error {
+	cmd := exec.CommandContext(ctx, "gh", "pr", "merge", ref, "--auto", "--squash")
+	cmd.Dir = dir
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("gh pr merge %s: %v: %s", ref, err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
@@ -0,0 +1,85 @@
+package redact
+
+import (
+	"fmt"
+	"regexp"
+)
+
+// Placeholder replaces every redacted secret.
+const Placeholder = "[REDACTED]"
+
+// defaultPatterns catch the secrets agents most often print while inspecting a repo
+// (`cat .env`, `env`, curl commands). A named group "secret" limits the replacement to
+// that group so the surrounding key name stays readable.
+var defaultPatterns = []string{
+	// OpenAI / Anthropic style keys (sk-..., sk-ant-..., sk-proj-...).
+	`\bsk-[A-Za-z0-9_-]{20,}`,
+	// GitHub tokens.
+	`\bgh[pousr]_[A-Za-z0-9]{36,}`,
+	`\bgithub_pat_[A-Za-z0-9_]{22,}`,
+	// AWS access key IDs and secret keys.
+	`\b(?:AKIA|ASIA)[0-9A-Z]{16}\b`,
+	`(?i)aws_secret_access_key["']?\s*[=:]\s*["']?(?P<secret>[A-Za-z0-9/+=]{40})`,
+	// Google API keys.
+	`\bAIza[0-9A-Za-z_-]{35}`,
+	// Slack tokens.
+	`\bxox[abprs]-[A-Za-z0-9-]{10,}`,
+	// Bearer tokens in headers.
+	`(?i)\bbearer\s+(?P<secret>[A-Za-z0-9._~+/-]{8,}=*)`,
+	// Upper-case KEY=value assignments whose name looks secret (.env files, exports).
+	`\b[A-Z0-9_]*(?:API_KEY|APIKEY|SECRET|TOKEN|PASSWORD|PASSWD)[A-Z0-9_]*["']?\s*[=:]\s*["']?(?P<secret>[^\s"'\\]{4,})`,
+}
+
+// Redactor masks secrets in log lines.
+type Redactor struct {
+	patterns []*regexp.Regexp
+}
+
+// New builds a Redactor from the default patterns plus any extra regular expressions.
+func New(extra []string) (*Redactor, error) {
+	r := &Redactor{}
+	for _, expr := range append(append([]string(nil), defaultPatterns...), extra...) {
+		re, err := regexp.Compile(expr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid redaction pattern %q: %w", expr, err)
+		}
+		r.patterns = append(r.patterns, re)
+	}
+	return r, nil
+}
+
+// Line returns s with every match replaced by Placeholder. A nil Redactor returns s unchanged.
+func (r *Redactor) Line(s string) string {
+	if r == nil {
+		return s
+	}
+	for _, re := range r.patterns {
+		group := re.SubexpIndex("secret")
+		if group < 0 {
+			s = re.ReplaceAllLiteralString(s, Placeholder)
+			continue
+		}
+		s = replaceGroup(re, s, group)
+	}
+	return s
+}
+
+func replaceGroup(re *regexp.Regexp, s string, group int) string {
+	matches := re.FindAllStringSubmatchIndex(s, -1)
+	if matches == nil {
+		return s
+	}
+	out := make([]byte, 0, len(s))
+	last := 0
+	for _, m := range matches {
+		start, end := m[2*group], m[2*group+1]
+		if start < 0 {
+			continue
+		}
+		out = append(out, s[last:start]...)
+		out = append(out, Placeholder...)
+		last = end
+	}
+	out = append(out, s[last:]...)
+	return string(out)
+}
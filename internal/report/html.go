@@ -0,0 +1,239 @@
+package report
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"html/template"
+	"os"
+	"time"
+
+	"github.com/asynkron/Asynkron.SwarmGo/internal/events"
+	"github.com/asynkron/Asynkron.SwarmGo/internal/pricing"
+)
+
+// WriteHTML writes the round as a single HTML file with embedded styles, for attaching to
+// tickets or sharing with people who don't use the terminal. Each worker's log is in a
+// collapsible section.
+func WriteHTML(ctx context.Context, path string, round Round) error {
+	reports := collectAll(ctx, round)
+	page := htmlPage{Round: round, Duration: round.Finished.Sub(round.Started).Round(time.Second), Docs: readDocs(round.DocsPath)}
+	for _, r := range reports {
+		page.Commits += len(r.git.RecentCommits)
+		page.Added += r.added
+		page.Deleted += r.deleted
+		page.Files += r.files
+		page.PullRequests = append(page.PullRequests, r.pullRequests...)
+		if r.priced {
+			page.cost += r.cost
+			page.Cost = pricing.Format(page.cost)
+		}
+		w := htmlWorker{
+			Report:  r,
+			Commits: r.git.RecentCommits,
+			Added:   r.added,
+			Deleted: r.deleted,
+			Files:   r.files,
+			Branch:  r.git.Branch,
+			Pending: len(r.git.Staged) + len(r.git.Unstaged) + len(r.git.Untracked),
+			Tests:   r.tests,
+			Signal:  r.testSignal,
+			PRs:     r.pullRequests,
+			Review:  r.review,
+			Tokens:  r.usage.Tokens(),
+		}
+		if r.priced {
+			w.Cost = pricing.Format(r.cost)
+		}
+		for _, m := range r.transcript {
+			w.Log = append(w.Log, htmlLine{Class: kindClass(m.Kind), Text: m.Text})
+		}
+		page.Workers = append(page.Workers, w)
+	}
+
+	var b bytes.Buffer
+	if err := htmlTemplate.Execute(&b, page); err != nil {
+		return fmt.Errorf("render html report: %w", err)
+	}
+	if err := os.WriteFile(path, b.Bytes(), 0o644); err != nil {
+		return fmt.Errorf("write html report: %w", err)
+	}
+	return nil
+}
+
+type htmlPage struct {
+	Round        Round
+	Duration     time.Duration
+	Commits      int
+	Added        int
+	Deleted      int
+	Files        int
+	PullRequests []string
+	Cost         string
+	cost         float64
+	Workers      []htmlWorker
+	Docs         string
+}
+
+type htmlWorker struct {
+	Report  workerReport
+	Commits []string
+	Added   int
+	Deleted int
+	Files   int
+	Branch  string
+	Pending int
+	Tests   string
+	Signal  string
+	PRs     []string
+	Review  string
+	Tokens  int
+	Cost    string
+	Log     []htmlLine
+}
+
+type htmlLine struct {
+	Class string
+	Text  string
+}
+
+func kindClass(kind events.AgentMessageKind) string {
+	switch kind {
+	case events.MessageDo:
+		return "do"
+	case events.MessageSee:
+		return "see"
+	default:
+		return "say"
+	}
+}
+
+var htmlTemplate = template.Must(template.New("report").Funcs(template.FuncMap{
+	"time": func(t time.Time) string { return t.Local().Format("15:04:05") },
+	"date": func(t time.Time) string { return t.Local().Format(time.DateTime) },
+}).Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>Swarm report: session {{.Round.SessionID}}</title>
+<style>
+body { font-family: -apple-system, "Segoe UI", Helvetica, Arial, sans-serif; margin: 2rem auto; max-width: 70rem; padding: 0 1rem; color: #1f2328; }
+h1 { font-size: 1.6rem; }
+h2 { font-size: 1.25rem; border-bottom: 1px solid #d0d7de; padding-bottom: .3rem; margin-top: 2rem; }
+table { border-collapse: collapse; margin: .5rem 0; }
+th, td { text-align: left; padding: .25rem .75rem; border: 1px solid #d0d7de; vertical-align: top; }
+th { background: #f6f8fa; }
+code { font-family: ui-monospace, SFMono-Regular, Menlo, monospace; font-size: .9em; }
+.ok { color: #1a7f37; }
+.bad { color: #cf222e; }
+.added { color: #1a7f37; }
+.deleted { color: #cf222e; }
+details { margin: .5rem 0; }
+summary { cursor: pointer; font-weight: 600; }
+.log { background: #0d1117; color: #e6edf3; padding: .75rem; border-radius: 6px; max-height: 40rem; overflow: auto; font-family: ui-monospace, SFMono-Regular, Menlo, monospace; font-size: .8rem; }
+.log div { white-space: pre-wrap; word-break: break-word; padding: 1px 0; }
+.log .say { color: #e6edf3; }
+.log .do { color: #79c0ff; }
+.log .see { color: #8b949e; }
+.review { background: #f6f8fa; padding: .75rem; border-radius: 6px; white-space: pre-wrap; font-size: .85rem; }
+.timeline td:first-child { white-space: nowrap; color: #57606a; }
+</style>
+</head>
+<body>
+<h1>Swarm report: session {{.Round.SessionID}}</h1>
+<table>
+<tr><th>Repository</th><td><code>{{.Round.Repo}}</code></td></tr>
+<tr><th>Round</th><td>{{date .Round.Started}} to {{date .Round.Finished}} ({{.Duration}})</td></tr>
+{{- if .Round.TodoTotal}}
+<tr><th>Todo</th><td>{{.Round.TodoDone}}/{{.Round.TodoTotal}} tasks done</td></tr>
+{{- end}}
+<tr><th>Workers</th><td>{{len .Workers}}</td></tr>
+<tr><th>Diff</th><td>{{.Commits}} commits, <span class="added">+{{.Added}}</span>/<span class="deleted">-{{.Deleted}}</span> across {{.Files}} files</td></tr>
+{{- if .Cost}}
+<tr><th>Estimated cost</th><td>~{{.Cost}}</td></tr>
+{{- end}}
+<tr><th>Pull requests</th><td>{{range .PullRequests}}<a href="{{.}}">{{.}}</a><br>{{else}}none{{end}}</td></tr>
+</table>
+
+<h2>Workers</h2>
+<table>
+<tr><th>Worker</th><th>Status</th><th>Diff</th><th>Tests</th><th>Cost</th><th>Pull requests</th></tr>
+{{- range .Workers}}
+<tr>
+<td><a href="#worker-{{.Report.Number}}">{{.Report.Name}}</a> ({{.Report.Kind}}{{if .Report.Model}} {{.Report.Model}}{{end}})</td>
+<td>{{if .Report.Completed}}<span class="ok">finished cleanly</span>{{else}}did not finish{{end}}{{if .Report.Restarts}}, {{.Report.Restarts}} restarts{{end}}</td>
+<td>{{len .Commits}} commits, <span class="added">+{{.Added}}</span>/<span class="deleted">-{{.Deleted}}</span> in {{.Files}} files</td>
+<td class="{{if eq .Tests "passing"}}ok{{else if eq .Tests "failing"}}bad{{end}}">{{.Tests}}</td>
+<td>{{if .Cost}}~{{.Cost}}{{else}}-{{end}}</td>
+<td>{{range .PRs}}<a href="{{.}}">{{.}}</a><br>{{else}}-{{end}}</td>
+</tr>
+{{- end}}
+</table>
+
+{{- with .Round.Outcome}}
+<h2>Verdict</h2>
+<p>Winner: <strong>{{.WinnerName}}</strong></p>
+<p>{{.Rationale}}</p>
+{{- if .Scores}}
+<table>
+<tr><th>Worker</th><th>Score</th><th>Tests</th><th>Notes</th></tr>
+{{- range .Scores}}
+<tr><td><a href="#worker-{{.Worker}}">{{$.Round.Outcome.Name .Worker}}</a></td><td>{{.Score}}/10</td><td>{{.Tests}}</td><td>{{.Notes}}</td></tr>
+{{- end}}
+</table>
+{{- end}}
+{{- end}}
+
+{{- if .Round.Timeline}}
+<h2>Timeline</h2>
+<table class="timeline">
+{{- range .Round.Timeline}}
+<tr><td>{{time .Time}}</td><td>{{.Text}}</td></tr>
+{{- end}}
+</table>
+{{- end}}
+
+{{- range .Workers}}
+<h2 id="worker-{{.Report.Number}}">{{.Report.Name}} ({{.Report.Kind}}{{if .Report.Model}} {{.Report.Model}}{{end}})</h2>
+<table>
+{{- if .Report.Repo}}
+<tr><th>Repository</th><td><code>{{.Report.Repo}}</code></td></tr>
+{{- end}}
+<tr><th>Worktree</th><td><code>{{.Report.Worktree}}</code></td></tr>
+{{- if .Branch}}
+<tr><th>Branch</th><td><code>{{.Branch}}</code></td></tr>
+{{- end}}
+<tr><th>Diff</th><td>{{len .Commits}} commits, <span class="added">+{{.Added}}</span>/<span class="deleted">-{{.Deleted}}</span> across {{.Files}} files{{if .Pending}}; {{.Pending}} uncommitted files{{end}}</td></tr>
+<tr><th>Tests</th><td>{{.Tests}}{{if .Signal}} (last signal: <code>{{.Signal}}</code>){{end}}</td></tr>
+{{- if .Tokens}}
+<tr><th>Usage</th><td>{{.Tokens}} tokens{{if .Cost}} (~{{.Cost}}){{end}}</td></tr>
+{{- end}}
+<tr><th>Log</th><td><code>{{.Report.LogPath}}</code></td></tr>
+</table>
+{{- if .Commits}}
+<details open><summary>Commits ({{len .Commits}})</summary>
+<ul>{{range .Commits}}<li><code>{{.}}</code></li>{{end}}</ul>
+</details>
+{{- end}}
+{{- if .Review}}
+<details open><summary>Review</summary>
+<pre class="review">{{.Review}}</pre>
+</details>
+{{- end}}
+{{- if .Log}}
+<details><summary>Log ({{len .Log}} messages)</summary>
+<div class="log">
+{{- range .Log}}
+<div class="{{.Class}}">{{.Text}}</div>
+{{- end}}
+</div>
+</details>
+{{- end}}
+{{- end}}
+{{- if .Docs}}
+<h2>Docs and changelog draft</h2>
+<pre class="review">{{.Docs}}</pre>
+{{- end}}
+</body>
+</html>
+`))
@@ -0,0 +1,477 @@
+package report
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/asynkron/Asynkron.SwarmGo/internal/agents"
+	"github.com/asynkron/Asynkron.SwarmGo/internal/events"
+	"github.com/asynkron/Asynkron.SwarmGo/internal/pricing"
+	"github.com/asynkron/Asynkron.SwarmGo/internal/status"
+	"github.com/asynkron/Asynkron.SwarmGo/internal/termtext"
+	"github.com/asynkron/Asynkron.SwarmGo/internal/verdict"
+)
+
+// Round is everything the orchestrator knows about a finished round.
+type Round struct {
+	SessionID string
+	Repo      string
+	Started   time.Time
+	Finished  time.Time
+	TodoDone  int
+	TodoTotal int
+	// NoGit skips commits and diff stats for projects that are not under version control.
+	NoGit bool
+	// Prices turns the token usage in worker logs into cost estimates.
+	Prices  pricing.Table
+	Workers []Worker
+	// Timeline lists notable session events in order, for the HTML and timeline reports.
+	Timeline []Entry
+	// Verdict is the supervisor's final message, if it had one.
+	Verdict string
+	// Outcome is the structured verdict the supervisor wrote to verdict.json; nil without one.
+	Outcome *verdict.Verdict
+	// DocsPath is the docs agent's changelog and documentation draft; empty without --docs.
+	DocsPath string
+}
+
+// Entry is one line of the session timeline.
+type Entry struct {
+	Time time.Time
+	// Kind groups entries: phase, start, stop, pr, alert or status.
+	Kind string
+	Text string
+}
+
+// Worker describes one worker slot of the round.
+type Worker struct {
+	Number int
+	Name   string
+	Kind   string
+	Model  string
+	// ModelID is the model name passed to the CLI, used to look up its price.
+	ModelID   string
+	Repo      string
+	Worktree  string
+	LogPath   string
+	CLI       agents.CLI
+	Restarts  int
+	Completed bool
+	// Running is whether the worker process is still alive; only Progress uses it.
+	Running bool
+	// ReviewPath is the reviewer's review of the worker's branch; empty without --reviewer.
+	ReviewPath string
+}
+
+type workerReport struct {
+	Worker
+	git          status.Snapshot
+	added        int
+	deleted      int
+	files        int
+	tests        string
+	testSignal   string
+	pullRequests []string
+	failures     []string
+	messages     []string
+	usage        pricing.Usage
+	cost         float64
+	priced       bool
+	// transcript is the tail of the parsed log, for the HTML report.
+	transcript []logMessage
+	// review is the reviewer's review of the branch without its title line.
+	review string
+}
+
+type logMessage struct {
+	Kind events.AgentMessageKind
+	Text string
+}
+
+var (
+	// GitHub, Bitbucket and Azure DevOps pull requests and GitLab merge requests (gitlab.com
+	// or self-hosted).
+	prRegex    = regexp.MustCompile(`https://github\.com/[\w.-]+/[\w.-]+/pull/\d+|https://bitbucket\.org/[\w.-]+/[\w.-]+/pull-requests/\d+|https://[\w.-]+/[\w%./-]+/_git/[\w%.-]+/pullrequest/\d+|https://[\w.-]+/[\w./-]+?/-/merge_requests/\d+`)
+	issueRegex = regexp.MustCompile(`https://github\.com/[\w.-]+/[\w.-]+/issues/\d+|https://[\w.-]+/[\w./-]+?/-/issues/\d+`)
+	passRegex  = regexp.MustCompile(`(?i)\b(all tests passed|tests? passed|\d+ passed|passed|succeeded|success)\b`)
+	failRegex  = regexp.MustCompile(`(?i)\b(tests? failed|\d+ failed|fail(ed)?|panic|exception|traceback)\b`)
+)
+
+const (
+	maxFailures = 3
+	maxMessages = 5
+	maxLineLen  = 300
+	// maxTranscript and maxTranscriptLine bound the log kept for the HTML report.
+	maxTranscript     = 2000
+	maxTranscriptLine = 4000
+)
+
+// Write collects git and log details for every worker and writes the Markdown report to path.
+func Write(ctx context.Context, path string, round Round) error {
+	if err := os.WriteFile(path, []byte(render(round, collectAll(ctx, round))), 0o644); err != nil {
+		return fmt.Errorf("write report: %w", err)
+	}
+	return nil
+}
+
+// collectAll collects every worker of the round and prices its usage.
+func collectAll(ctx context.Context, round Round) []workerReport {
+	reports := make([]workerReport, 0, len(round.Workers))
+	for _, w := range round.Workers {
+		r := collect(ctx, w, round.Started, round.NoGit)
+		if r.usage.Tokens() > 0 || r.usage.CostUSD > 0 {
+			r.cost, r.priced = round.Prices.Estimate(r.usage, r.ModelID, strings.ToLower(r.Kind))
+		}
+		reports = append(reports, r)
+	}
+	return reports
+}
+
+// Progress renders a short Markdown update for a round that is still running, with one
+// table row per worker. remaining is the time left in the round.
+func Progress(ctx context.Context, round Round, remaining time.Duration) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "### Swarm progress: session %s\n\n", round.SessionID)
+	fmt.Fprintf(&b, "- Elapsed: %s, %s remaining\n", round.Finished.Sub(round.Started).Round(time.Minute), remaining.Round(time.Minute))
+	if round.TodoTotal > 0 {
+		fmt.Fprintf(&b, "- Todo: %d/%d tasks done\n", round.TodoDone, round.TodoTotal)
+	}
+	b.WriteString("\n| Worker | State | Commits | Tests | Pull requests |\n|---|---|---|---|---|\n")
+	for _, w := range round.Workers {
+		r := collect(ctx, w, round.Started, round.NoGit)
+		state := "stopped"
+		switch {
+		case r.Running:
+			state = "running"
+		case r.Completed:
+			state = "finished"
+		}
+		if r.Restarts > 0 {
+			state += fmt.Sprintf(" (%d restarts)", r.Restarts)
+		}
+		prs := "-"
+		if len(r.pullRequests) > 0 {
+			prs = strings.Join(r.pullRequests, "<br>")
+		}
+		fmt.Fprintf(&b, "| %s (%s) | %s | %d | %s | %s |\n", r.Name, r.Kind, state, len(r.git.RecentCommits), r.tests, prs)
+	}
+	return b.String()
+}
+
+func collect(ctx context.Context, w Worker, since time.Time, noGit bool) workerReport {
+	r := workerReport{Worker: w, tests: "unknown"}
+	if !noGit {
+		r.git = status.NewCollector(w.Worktree, "", nil, since, 0).Collect(ctx)
+		r.added, r.deleted, r.files = diffStats(ctx, w.Worktree, since)
+	}
+	scanLog(&r)
+	if w.ReviewPath != "" {
+		if data, err := os.ReadFile(w.ReviewPath); err == nil {
+			r.review = reviewBody(string(data))
+		}
+	}
+	return r
+}
+
+// readDocs returns the docs agent's draft without its title and with its headings moved
+// below the report's "## Docs and changelog draft", or "" when there is none.
+func readDocs(path string) string {
+	if path == "" {
+		return ""
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return ""
+	}
+	return nestHeadings(string(data), "#")
+}
+
+// reviewBody drops the title of a review file and moves its headings below the worker's
+// sections, so the review nests under "### Review".
+func reviewBody(content string) string {
+	return nestHeadings(content, "##")
+}
+
+// nestHeadings drops a leading "# " title line and prefixes the remaining headings.
+func nestHeadings(content, prefix string) string {
+	lines := strings.Split(strings.TrimSpace(content), "\n")
+	if len(lines) > 0 && strings.HasPrefix(lines[0], "# ") {
+		lines = lines[1:]
+	}
+	for i, l := range lines {
+		if strings.HasPrefix(l, "#") {
+			lines[i] = prefix + l
+		}
+	}
+	return strings.TrimSpace(strings.Join(lines, "\n"))
+}
+
+// diffStats sums the numstat of every commit made in the worktree since the round started.
+func diffStats(ctx context.Context, worktree string, since time.Time) (added, deleted, files int) {
+	cmd := exec.CommandContext(ctx, "git", "log", "--since="+since.Format(time.RFC3339), "--numstat", "--format=")
+	cmd.Dir = worktree
+	out, err := cmd.Output()
+	if err != nil {
+		return 0, 0, 0
+	}
+	seen := map[string]bool{}
+	for _, line := range strings.Split(string(out), "\n") {
+		parts := strings.Split(line, "\t")
+		if len(parts) < 3 {
+			continue
+		}
+		a, _ := strconv.Atoi(parts[0])
+		d, _ := strconv.Atoi(parts[1])
+		added += a
+		deleted += d
+		seen[parts[2]] = true
+	}
+	return added, deleted, len(seen)
+}
+
+// scanLog pulls the last test signal, PR links, recent failures, token usage and the
+// agent's final messages out of its log.
+func scanLog(r *workerReport) {
+	if r.LogPath == "" || r.CLI == nil {
+		return
+	}
+	f, err := os.Open(r.LogPath)
+	if err != nil {
+		return
+	}
+	defer f.Close()
+
+	seenPR := map[string]bool{}
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		for _, url := range prRegex.FindAllString(line, -1) {
+			if !seenPR[url] {
+				seenPR[url] = true
+				r.pullRequests = append(r.pullRequests, url)
+			}
+		}
+		if u, ok := agents.ParseUsage(r.CLI, line); ok {
+			r.usage = r.usage.Add(pricing.Usage(u))
+		}
+		for _, msg := range r.CLI.Parse(line) {
+			if full := strings.TrimSpace(msg.Text); full != "" {
+				full = termtext.Truncate(full, maxTranscriptLine, "...")
+				r.transcript = append(r.transcript, logMessage{Kind: msg.Kind, Text: full})
+				if len(r.transcript) > 2*maxTranscript {
+					r.transcript = append(r.transcript[:0], r.transcript[len(r.transcript)-maxTranscript:]...)
+				}
+			}
+			text := trim(msg.Text)
+			if text == "" {
+				continue
+			}
+			switch {
+			case failRegex.MatchString(text):
+				r.tests, r.testSignal = "failing", text
+				r.failures = keepLast(r.failures, text, maxFailures)
+			case passRegex.MatchString(text):
+				r.tests, r.testSignal = "passing", text
+			}
+			if msg.Kind == events.MessageSay {
+				r.messages = keepLast(r.messages, text, maxMessages)
+			}
+		}
+	}
+	if len(r.transcript) > maxTranscript {
+		r.transcript = r.transcript[len(r.transcript)-maxTranscript:]
+	}
+}
+
+// Links returns the distinct pull request and issue URLs mentioned in a log file, in order
+// of first appearance.
+func Links(logPath string) (pullRequests, issues []string) {
+	f, err := os.Open(logPath)
+	if err != nil {
+		return nil, nil
+	}
+	defer f.Close()
+
+	seen := map[string]bool{}
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		for _, url := range prRegex.FindAllString(line, -1) {
+			if !seen[url] {
+				seen[url] = true
+				pullRequests = append(pullRequests, url)
+			}
+		}
+		for _, url := range issueRegex.FindAllString(line, -1) {
+			if !seen[url] {
+				seen[url] = true
+				issues = append(issues, url)
+			}
+		}
+	}
+	return pullRequests, issues
+}
+
+func render(round Round, reports []workerReport) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# Swarm report: session %s\n\n", round.SessionID)
+
+	var commits, added, deleted, files, completed int
+	var prs []string
+	var cost float64
+	priced := false
+	for _, r := range reports {
+		cost += r.cost
+		priced = priced || r.priced
+		commits += len(r.git.RecentCommits)
+		added += r.added
+		deleted += r.deleted
+		files += r.files
+		prs = append(prs, r.pullRequests...)
+		if r.Completed {
+			completed++
+		}
+	}
+
+	fmt.Fprintf(&b, "- Repository: `%s`\n", round.Repo)
+	fmt.Fprintf(&b, "- Round: %s to %s (%s)\n", round.Started.Format(time.RFC3339), round.Finished.Format(time.RFC3339), round.Finished.Sub(round.Started).Round(time.Second))
+	if round.TodoTotal > 0 {
+		fmt.Fprintf(&b, "- Todo: %d/%d tasks done\n", round.TodoDone, round.TodoTotal)
+	}
+	fmt.Fprintf(&b, "- Workers: %d (%d finished cleanly)\n", len(reports), completed)
+	fmt.Fprintf(&b, "- Commits: %d, +%d/-%d across %d files\n", commits, added, deleted, files)
+	if priced {
+		fmt.Fprintf(&b, "- Estimated cost: ~%s (workers with usage in their logs)\n", pricing.Format(cost))
+	}
+	fmt.Fprintf(&b, "- Pull requests: %d\n", len(prs))
+	for _, pr := range prs {
+		fmt.Fprintf(&b, "  - %s\n", pr)
+	}
+
+	if round.Outcome != nil {
+		b.WriteString("\n## Verdict\n\n")
+		renderOutcome(&b, *round.Outcome)
+	}
+	for _, r := range reports {
+		b.WriteString("\n")
+		renderWorker(&b, r)
+	}
+	if docs := readDocs(round.DocsPath); docs != "" {
+		b.WriteString("\n## Docs and changelog draft\n\n")
+		b.WriteString(docs)
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+// renderOutcome writes the winner, the rationale and the score table of a verdict.
+func renderOutcome(b *strings.Builder, v verdict.Verdict) {
+	fmt.Fprintf(b, "Winner: **%s**\n\n%s\n", v.WinnerName(), v.Rationale)
+	if len(v.Scores) == 0 {
+		return
+	}
+	b.WriteString("\n| Worker | Score | Tests | Notes |\n|---|---|---|---|\n")
+	cell := strings.NewReplacer("|", "\\|", "\n", " ")
+	for _, s := range v.Scores {
+		fmt.Fprintf(b, "| %s | %g/10 | %s | %s |\n", v.Name(s.Worker), s.Score, cell.Replace(s.Tests), cell.Replace(s.Notes))
+	}
+}
+
+func renderWorker(b *strings.Builder, r workerReport) {
+	title := fmt.Sprintf("%s (%s", r.Name, r.Kind)
+	if r.Model != "" {
+		title += " " + r.Model
+	}
+	fmt.Fprintf(b, "## %s)\n\n", title)
+
+	state := "did not finish"
+	if r.Completed {
+		state = "finished cleanly"
+	}
+	if r.Repo != "" {
+		fmt.Fprintf(b, "- Repository: `%s`\n", r.Repo)
+	}
+	fmt.Fprintf(b, "- Worktree: `%s`\n", r.Worktree)
+	if r.git.Branch != "" {
+		fmt.Fprintf(b, "- Branch: `%s`\n", r.git.Branch)
+	}
+	fmt.Fprintf(b, "- Status: %s, %d restarts\n", state, r.Restarts)
+	fmt.Fprintf(b, "- Diff: %d commits, +%d/-%d across %d files", len(r.git.RecentCommits), r.added, r.deleted, r.files)
+	if pending := len(r.git.Staged) + len(r.git.Unstaged) + len(r.git.Untracked); pending > 0 {
+		fmt.Fprintf(b, "; %d uncommitted files", pending)
+	}
+	b.WriteString("\n")
+	if r.testSignal != "" {
+		fmt.Fprintf(b, "- Tests: %s (last signal: %q)\n", r.tests, r.testSignal)
+	} else {
+		fmt.Fprintf(b, "- Tests: %s\n", r.tests)
+	}
+	if u := r.usage; u.Tokens() > 0 {
+		fmt.Fprintf(b, "- Usage: %d input, %d output, %d cache read, %d cache write tokens", u.InputTokens, u.OutputTokens, u.CacheReadTokens, u.CacheWriteTokens)
+		if r.priced {
+			fmt.Fprintf(b, " (~%s)", pricing.Format(r.cost))
+		}
+		b.WriteString("\n")
+	}
+	fmt.Fprintf(b, "- Log: `%s`\n", r.LogPath)
+
+	if len(r.git.RecentCommits) > 0 {
+		b.WriteString("\n### Commits\n\n")
+		for _, c := range r.git.RecentCommits {
+			fmt.Fprintf(b, "- %s\n", c)
+		}
+	}
+	if len(r.pullRequests) > 0 {
+		b.WriteString("\n### Pull requests\n\n")
+		for _, pr := range r.pullRequests {
+			fmt.Fprintf(b, "- %s\n", pr)
+		}
+	}
+	if r.review != "" {
+		b.WriteString("\n### Review\n\n")
+		b.WriteString(r.review)
+		b.WriteString("\n")
+	}
+	if len(r.failures) > 0 {
+		b.WriteString("\n### Recent failures\n\n")
+		writeExcerpt(b, r.failures)
+	}
+	if len(r.messages) > 0 {
+		b.WriteString("\n### Last messages\n\n")
+		writeExcerpt(b, r.messages)
+	}
+}
+
+func writeExcerpt(b *strings.Builder, lines []string) {
+	b.WriteString("~~~text\n")
+	for _, l := range lines {
+		b.WriteString(strings.ReplaceAll(l, "~~~", "~ ~ ~"))
+		b.WriteString("\n")
+	}
+	b.WriteString("~~~\n")
+}
+
+func keepLast(list []string, item string, limit int) []string {
+	list = append(list, item)
+	if len(list) > limit {
+		list = list[len(list)-limit:]
+	}
+	return list
+}
+
+func trim(s string) string {
+	s = strings.TrimSpace(s)
+	return termtext.Truncate(s, maxLineLen, "...")
+}
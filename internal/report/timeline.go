@@ -0,0 +1,83 @@
+package report
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/asynkron/Asynkron.SwarmGo/internal/agents"
+	"github.com/asynkron/Asynkron.SwarmGo/internal/events"
+)
+
+// Timeline renders the round as a Markdown timeline: phases, agent starts and stops, alerts
+// and pull requests in the order they happened, followed by the outcome of the round.
+func Timeline(round Round) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# Swarm timeline: session %s\n\n", round.SessionID)
+	fmt.Fprintf(&b, "- Repository: `%s`\n", round.Repo)
+	fmt.Fprintf(&b, "- Round: %s to %s (%s)\n", round.Started.Format(time.RFC3339), round.Finished.Format(time.RFC3339), round.Finished.Sub(round.Started).Round(time.Second))
+
+	b.WriteString("\n## Timeline\n\n")
+	if len(round.Timeline) == 0 {
+		b.WriteString("No events were recorded.\n")
+	} else {
+		b.WriteString("| Time | Kind | Event |\n|---|---|---|\n")
+		for _, e := range round.Timeline {
+			text := strings.ReplaceAll(strings.ReplaceAll(e.Text, "|", "\\|"), "\n", " ")
+			fmt.Fprintf(&b, "| %s | %s | %s |\n", e.Time.Local().Format("15:04:05"), e.Kind, text)
+		}
+	}
+
+	b.WriteString("\n## Verdict\n\n")
+	if round.TodoTotal > 0 {
+		fmt.Fprintf(&b, "- Todo: %d/%d tasks done\n", round.TodoDone, round.TodoTotal)
+	}
+	completed := 0
+	var prs []string
+	for _, w := range round.Workers {
+		if w.Completed {
+			completed++
+		}
+		found, _ := Links(w.LogPath)
+		prs = append(prs, found...)
+	}
+	fmt.Fprintf(&b, "- Workers: %d (%d finished cleanly)\n", len(round.Workers), completed)
+	fmt.Fprintf(&b, "- Pull requests: %d\n", len(prs))
+	for _, pr := range prs {
+		fmt.Fprintf(&b, "  - %s\n", pr)
+	}
+	if round.Outcome != nil {
+		b.WriteString("\n")
+		renderOutcome(&b, *round.Outcome)
+	}
+	if round.Verdict != "" {
+		b.WriteString("\nSupervisor's last word:\n\n")
+		for _, line := range strings.Split(round.Verdict, "\n") {
+			fmt.Fprintf(&b, "> %s\n", line)
+		}
+	}
+	return b.String()
+}
+
+// LastMessage returns the last thing the agent said in its log, or "".
+func LastMessage(cli agents.CLI, logPath string) string {
+	f, err := os.Open(logPath)
+	if err != nil {
+		return ""
+	}
+	defer f.Close()
+
+	var last string
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		for _, msg := range cli.Parse(scanner.Text()) {
+			if text := strings.TrimSpace(msg.Text); msg.Kind == events.MessageSay && text != "" {
+				last = text
+			}
+		}
+	}
+	return last
+}
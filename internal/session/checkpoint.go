@@ -0,0 +1,84 @@
+package session
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// Checkpoint is the orchestrator state saved while a round runs, so --resume can
+// reconstruct the round after swarm itself crashed or the machine rebooted.
+type Checkpoint struct {
+	Saved time.Time `json:"saved"`
+	Phase string    `json:"phase"`
+	// Round is the arena round number; it is 1 outside --arena.
+	Round    int       `json:"round"`
+	Deadline time.Time `json:"deadline,omitempty"`
+	// BaseRef is the commit worker worktrees of the primary repository start from, set once
+	// the prep step is done. Empty means the crash happened before the worktrees existed.
+	BaseRef string            `json:"baseRef,omitempty"`
+	Agents  []AgentCheckpoint `json:"agents"`
+}
+
+// AgentCheckpoint is the state of one agent at checkpoint time.
+type AgentCheckpoint struct {
+	ID      string `json:"id"`
+	Running bool   `json:"running"`
+	// Stopped is set when the user (or the round's end) stopped the agent, as opposed to
+	// it exiting on its own.
+	Stopped  bool   `json:"stopped,omitempty"`
+	ExitCode int    `json:"exitCode,omitempty"`
+	Restarts int    `json:"restarts"`
+	Branch   string `json:"branch,omitempty"`
+}
+
+// Agent returns the checkpointed state of the agent with the given ID.
+func (c Checkpoint) Agent(id string) (AgentCheckpoint, bool) {
+	for _, a := range c.Agents {
+		if a.ID == id {
+			return a, true
+		}
+	}
+	return AgentCheckpoint{}, false
+}
+
+// CheckpointPath returns the file the orchestrator checkpoints its state to.
+func (s *Session) CheckpointPath() string {
+	return filepath.Join(s.Path, "checkpoint.json")
+}
+
+// SaveCheckpoint writes c through a temporary file and a rename, so a crash mid-write
+// leaves the previous checkpoint intact.
+func (s *Session) SaveCheckpoint(c Checkpoint) error {
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encode checkpoint: %w", err)
+	}
+	tmp := s.CheckpointPath() + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return fmt.Errorf("write checkpoint: %w", err)
+	}
+	if err := os.Rename(tmp, s.CheckpointPath()); err != nil {
+		return fmt.Errorf("write checkpoint: %w", err)
+	}
+	return nil
+}
+
+// LoadCheckpoint reads the session's last checkpoint. ok is false when the session has
+// none, e.g. because it was created by an older version.
+func (s *Session) LoadCheckpoint() (c Checkpoint, ok bool, err error) {
+	data, err := os.ReadFile(s.CheckpointPath())
+	if errors.Is(err, os.ErrNotExist) {
+		return Checkpoint{}, false, nil
+	}
+	if err != nil {
+		return Checkpoint{}, false, fmt.Errorf("read checkpoint: %w", err)
+	}
+	if err := json.Unmarshal(data, &c); err != nil {
+		return Checkpoint{}, false, fmt.Errorf("parse checkpoint: %w", err)
+	}
+	return c, true, nil
+}
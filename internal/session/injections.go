@@ -0,0 +1,65 @@
+package session
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// Injection is a message given to an agent mid-run: injected into its prompt on a restart,
+// or sent live to a CLI that accepts input.
+type Injection struct {
+	Time    time.Time `json:"time"`
+	Agent   string    `json:"agent"`
+	Message string    `json:"message"`
+	Live    bool      `json:"live,omitempty"`
+}
+
+// InjectionLogPath returns the file every injected message is appended to.
+func (s *Session) InjectionLogPath() string {
+	return filepath.Join(s.Path, "injections.jsonl")
+}
+
+// RecordInjection appends in to the session's injection log.
+func (s *Session) RecordInjection(in Injection) error {
+	data, err := json.Marshal(in)
+	if err != nil {
+		return err
+	}
+	f, err := os.OpenFile(s.InjectionLogPath(), os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return fmt.Errorf("open injection log: %w", err)
+	}
+	_, err = f.Write(append(data, '\n'))
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	return err
+}
+
+// Injections returns the recorded injections in order. Unreadable lines are skipped.
+func (s *Session) Injections() ([]Injection, error) {
+	f, err := os.Open(s.InjectionLogPath())
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("open injection log: %w", err)
+	}
+	defer f.Close()
+
+	var list []Injection
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 4*1024*1024)
+	for scanner.Scan() {
+		var in Injection
+		if json.Unmarshal(scanner.Bytes(), &in) == nil {
+			list = append(list, in)
+		}
+	}
+	return list, scanner.Err()
+}
@@ -4,6 +4,7 @@ import (
 	"crypto/rand"
 	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -16,6 +17,10 @@ import (
 
 const sessionBaseDir = "swarmgo"
 
+// Root is the directory sessions are stored in. It defaults to a folder under the system
+// temp directory; --session-dir overrides it.
+var Root = filepath.Join(os.TempDir(), sessionBaseDir)
+
 // Session represents a swarm run and contains derived paths.
 type Session struct {
 	ID       string         `json:"id"`
@@ -23,17 +28,41 @@ type Session struct {
 	Options  config.Options `json:"options"`
 	Created  time.Time      `json:"created"`
 	Complete []int          `json:"complete"`
-	mu       sync.Mutex     `json:"-"`
+	// Artifacts are the GitHub pull requests and issues the session's agents linked to.
+	Artifacts []Artifact `json:"artifacts,omitempty"`
+	mu        sync.Mutex `json:"-"`
+}
+
+// Artifact kinds.
+const (
+	ArtifactPullRequest = "pr"
+	ArtifactIssue       = "issue"
+	// ArtifactBundle is the uploaded --upload-artifacts tarball.
+	ArtifactBundle = "bundle"
+)
+
+// Artifact is a GitHub pull request or issue tied to a session.
+type Artifact struct {
+	Kind  string    `json:"kind"`
+	URL   string    `json:"url"`
+	Agent string    `json:"agent,omitempty"`
+	Found time.Time `json:"found"`
 }
 
-// New creates a fresh session stored under the system temp directory.
+// Tag is the marker agents put in the body of pull requests and issues they create, so
+// they can be traced back to the session on GitHub.
+func (s *Session) Tag() string {
+	return "swarm:" + s.ID
+}
+
+// New creates a fresh session stored under Root.
 func New(opts config.Options) (*Session, error) {
 	id, err := generateID()
 	if err != nil {
 		return nil, err
 	}
 
-	path := filepath.Join(os.TempDir(), sessionBaseDir, id)
+	path := filepath.Join(Root, id)
 	if err := os.MkdirAll(path, 0o755); err != nil {
 		return nil, fmt.Errorf("create session dir: %w", err)
 	}
@@ -53,7 +82,7 @@ func New(opts config.Options) (*Session, error) {
 
 // Load restores a session from disk using its ID.
 func Load(id string) (*Session, error) {
-	path := filepath.Join(os.TempDir(), sessionBaseDir, id)
+	path := filepath.Join(Root, id)
 	cfg := filepath.Join(path, "session.json")
 	data, err := os.ReadFile(cfg)
 	if err != nil {
@@ -70,6 +99,31 @@ func Load(id string) (*Session, error) {
 	return &sess, nil
 }
 
+// List loads every session under Root, newest first. Directories without a readable
+// session.json are skipped.
+func List() ([]*Session, error) {
+	entries, err := os.ReadDir(Root)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("list sessions: %w", err)
+	}
+	var out []*Session
+	for _, e := range entries {
+		if !e.IsDir() {
+			continue
+		}
+		sess, err := Load(e.Name())
+		if err != nil {
+			continue
+		}
+		out = append(out, sess)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Created.After(out[j].Created) })
+	return out, nil
+}
+
 // WorktreePath returns the path for a worker's git worktree.
 func (s *Session) WorktreePath(worker int) string {
 	return filepath.Join(s.Path, fmt.Sprintf("wt%d", worker))
@@ -100,16 +154,129 @@ func (s *Session) AppLogPath() string {
 	return filepath.Join(s.Path, "app.log")
 }
 
+// StatusLogPath returns the timestamped status history file shown in the UI.
+func (s *Session) StatusLogPath() string {
+	return filepath.Join(s.Path, "status.log")
+}
+
+// EventLogPath returns the JSONL file holding every event emitted to the UI.
+func (s *Session) EventLogPath() string {
+	return filepath.Join(s.Path, "events.jsonl")
+}
+
+// ReportPath returns the Markdown summary written at the end of a round.
+func (s *Session) ReportPath() string {
+	return filepath.Join(s.Path, "report.md")
+}
+
+// PRDraftPath returns where a worker writes the pull request it wants opened when pull
+// requests need approval (--approve-prs).
+func (s *Session) PRDraftPath(worker int) string {
+	return filepath.Join(s.Path, "pr-drafts", fmt.Sprintf("worker-%d.md", worker))
+}
+
+// ReviewerLogPath returns the log file path for the reviewer agent (--reviewer).
+func (s *Session) ReviewerLogPath() string {
+	return filepath.Join(s.Path, "reviewer.log")
+}
+
+// ReviewPath returns where the reviewer writes its review of a worker's branch.
+func (s *Session) ReviewPath(worker int) string {
+	return filepath.Join(s.Path, "reviews", fmt.Sprintf("worker-%d.md", worker))
+}
+
+// TesterWorktreePath returns the worktree the tester agent (--tester) checks out worker
+// commits in.
+func (s *Session) TesterWorktreePath() string {
+	return filepath.Join(s.Path, "tester")
+}
+
+// TesterLogPath returns the log file path for the tester agent.
+func (s *Session) TesterLogPath() string {
+	return filepath.Join(s.Path, "tester.log")
+}
+
+// PlanPath returns the plan the prep agent writes in planning mode (--plan).
+func (s *Session) PlanPath() string {
+	return filepath.Join(s.Path, "plan.json")
+}
+
+// DocsPath returns the changelog and documentation draft of the docs agent (--docs).
+func (s *Session) DocsPath() string {
+	return filepath.Join(s.Path, "docs.md")
+}
+
+// DocsLogPath returns the log file path for the docs agent.
+func (s *Session) DocsLogPath() string {
+	return filepath.Join(s.Path, "docs.log")
+}
+
+// DocsWorktreePath returns the worktree the docs agent commits to --docs-branch in.
+func (s *Session) DocsWorktreePath() string {
+	return filepath.Join(s.Path, "docs")
+}
+
+// VerdictPath returns where the supervisor writes its structured verdict of the round.
+func (s *Session) VerdictPath() string {
+	return filepath.Join(s.Path, "verdict.json")
+}
+
+// ScrollbackDir returns the directory the UI pages agent output that no longer fits in
+// memory to. It is scratch space, not part of the session record.
+func (s *Session) ScrollbackDir() string {
+	return filepath.Join(s.Path, "scrollback")
+}
+
+// ScratchpadPath returns the shared scratchpad every agent of the round may read and edit.
+func (s *Session) ScratchpadPath() string {
+	return filepath.Join(s.Path, "scratchpad.md")
+}
+
+// RoleLogPath returns the log file path for a role defined in the config file (--role).
+func (s *Session) RoleLogPath(name string) string {
+	return filepath.Join(s.Path, "role-"+name+".log")
+}
+
+// TimelinePath returns the Markdown timeline of the session written at the end of a round.
+func (s *Session) TimelinePath() string {
+	return filepath.Join(s.Path, "timeline.md")
+}
+
+// HTMLReportPath returns the self-contained HTML report written with --report html.
+func (s *Session) HTMLReportPath() string {
+	return filepath.Join(s.Path, "report.html")
+}
+
 // UserCommandLogPath returns the log file path for the user command agent.
 func (s *Session) UserCommandLogPath() string {
 	return filepath.Join(s.Path, "user-command.log")
 }
 
+// CommandLogPath returns the audit log of the shell commands agents ran.
+func (s *Session) CommandLogPath() string {
+	return filepath.Join(s.Path, "commands.log")
+}
+
+// IncidentLogPath returns the log of denied commands agents ran.
+func (s *Session) IncidentLogPath() string {
+	return filepath.Join(s.Path, "incidents.log")
+}
+
 // CodedSupervisorPath returns the aggregated supervisor JSON path.
 func (s *Session) CodedSupervisorPath() string {
 	return filepath.Join(s.Path, "coded-supervisor.json")
 }
 
+// BundlePath returns the tarball of logs, reports and diffs --upload-artifacts uploads.
+func (s *Session) BundlePath() string {
+	return filepath.Join(s.Path, "swarm-"+s.ID+".tar.gz")
+}
+
+// ExportPath returns a timestamped file path for exporting a UI panel.
+func (s *Session) ExportPath(panel string, at time.Time) string {
+	return filepath.Join(s.Path, "exports", fmt.Sprintf("%s-%s.txt", panel, at.Format("20060102-150405")))
+}
+
 // IsWorkerCompleted reports whether the worker finished successfully in this session.
 func (s *Session) IsWorkerCompleted(worker int) bool {
 	s.mu.Lock()
@@ -138,6 +305,52 @@ func (s *Session) MarkWorkerCompleted(worker int) error {
 	return s.save()
 }
 
+// ClearCompleted forgets the completed workers, as a new arena round starts them over, and
+// persists the session file.
+func (s *Session) ClearCompleted() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.Complete = []int{}
+	return s.save()
+}
+
+// RecordArtifacts adds artifacts whose URL is not yet known and persists the session file
+// if anything was new.
+func (s *Session) RecordArtifacts(list ...Artifact) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	known := make(map[string]bool, len(s.Artifacts))
+	for _, a := range s.Artifacts {
+		known[a.URL] = true
+	}
+	added := false
+	for _, a := range list {
+		if a.URL == "" || known[a.URL] {
+			continue
+		}
+		known[a.URL] = true
+		s.Artifacts = append(s.Artifacts, a)
+		added = true
+	}
+	if !added {
+		return nil
+	}
+	return s.save()
+}
+
+// ArtifactsOf returns a copy of the recorded artifacts of the given kind.
+func (s *Session) ArtifactsOf(kind string) []Artifact {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var out []Artifact
+	for _, a := range s.Artifacts {
+		if a.Kind == kind {
+			out = append(out, a)
+		}
+	}
+	return out
+}
+
 func (s *Session) save() error {
 	if err := os.MkdirAll(s.Path, 0o755); err != nil {
 		return fmt.Errorf("ensure session dir: %w", err)
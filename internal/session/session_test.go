@@ -0,0 +1,45 @@
+package session
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/asynkron/Asynkron.SwarmGo/internal/config"
+)
+
+func TestPaths(t *testing.T) {
+	old := Root
+	Root = t.TempDir()
+	t.Cleanup(func() { Root = old })
+
+	sess, err := New(config.Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	base := filepath.Join(Root, sess.ID)
+	tests := []struct {
+		name string
+		got  string
+		want string
+	}{
+		{"session", sess.Path, base},
+		{"worktree", sess.WorktreePath(2), filepath.Join(base, "wt2")},
+		{"prep worktree", sess.PrepWorktreePath(), filepath.Join(base, "prep")},
+		{"worker log", sess.WorkerLogPath(2), filepath.Join(base, "worker2.log")},
+		{"event log", sess.EventLogPath(), filepath.Join(base, "events.jsonl")},
+		{"pr draft", sess.PRDraftPath(2), filepath.Join(base, "pr-drafts", "worker-2.md")},
+	}
+	for _, tt := range tests {
+		if tt.got != tt.want {
+			t.Errorf("%s path = %q, want %q", tt.name, tt.got, tt.want)
+		}
+	}
+
+	loaded, err := Load(sess.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if loaded.Path != base || loaded.WorktreePath(1) != filepath.Join(base, "wt1") {
+		t.Errorf("loaded session paths = %q, %q", loaded.Path, loaded.WorktreePath(1))
+	}
+}
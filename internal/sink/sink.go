@@ -0,0 +1,267 @@
+package sink
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// queueSize is how many lines a sink buffers before it starts dropping them, so a slow
+	// destination never holds up the event stream.
+	queueSize = 4096
+	// batchSize and batchDelay bound how many lines an HTTP sink sends per request and how
+	// long it waits to fill a batch.
+	batchSize  = 200
+	batchDelay = time.Second
+	// closeTimeout is how long Close waits for queued lines to be delivered.
+	closeTimeout = 5 * time.Second
+	// retryDelay is how long a unix socket sink waits before reconnecting.
+	retryDelay = 5 * time.Second
+)
+
+// Spec is a parsed --log-sink destination.
+type Spec struct {
+	// Kind is "file", "unix" or "http".
+	Kind string
+	// Target is the file path, socket path or URL.
+	Target string
+}
+
+func (s Spec) String() string {
+	if s.Kind == "http" {
+		return s.Target
+	}
+	return s.Kind + ":" + s.Target
+}
+
+// Parse reads a sink destination: "file:PATH", "unix:PATH", or an http:// or https:// URL.
+func Parse(spec string) (Spec, error) {
+	spec = strings.TrimSpace(spec)
+	if strings.HasPrefix(spec, "http://") || strings.HasPrefix(spec, "https://") {
+		u, err := url.Parse(spec)
+		if err != nil || u.Host == "" {
+			return Spec{}, fmt.Errorf("invalid log sink URL %q", spec)
+		}
+		return Spec{Kind: "http", Target: spec}, nil
+	}
+	kind, target, ok := strings.Cut(spec, ":")
+	if !ok || target == "" || (kind != "file" && kind != "unix") {
+		return Spec{}, fmt.Errorf("invalid log sink %q (expected file:PATH, unix:PATH or an http(s) URL)", spec)
+	}
+	return Spec{Kind: kind, Target: target}, nil
+}
+
+// Sink streams event records, one JSON object per line, to a destination in the
+// background. Lines are tagged with the session ID so several runs can share one
+// destination. Send never blocks; lines that don't fit the queue are dropped and counted.
+type Sink struct {
+	spec    Spec
+	session string
+	queue   chan []byte
+	done    chan struct{}
+	mu      sync.Mutex
+	dropped int
+	err     error
+	closed  bool
+}
+
+// Open starts a sink for spec. File sinks are opened here so a bad path fails the run
+// up front; sockets and HTTP endpoints are only contacted once lines arrive.
+func Open(spec Spec, session string) (*Sink, error) {
+	s := &Sink{
+		spec:    spec,
+		session: session,
+		queue:   make(chan []byte, queueSize),
+		done:    make(chan struct{}),
+	}
+	switch spec.Kind {
+	case "file":
+		if err := os.MkdirAll(filepath.Dir(spec.Target), 0o755); err != nil {
+			return nil, fmt.Errorf("log sink %s: %w", spec, err)
+		}
+		f, err := os.OpenFile(spec.Target, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+		if err != nil {
+			return nil, fmt.Errorf("log sink %s: %w", spec, err)
+		}
+		go s.run(func(lines [][]byte) error {
+			_, err := f.Write(bytes.Join(lines, nil))
+			return err
+		}, func() { _ = f.Close() })
+	case "unix":
+		var conn net.Conn
+		var failedAt time.Time
+		go s.run(func(lines [][]byte) error {
+			if conn == nil {
+				if time.Since(failedAt) < retryDelay {
+					return fmt.Errorf("waiting to reconnect to %s", spec.Target)
+				}
+				c, err := net.DialTimeout("unix", spec.Target, 2*time.Second)
+				if err != nil {
+					failedAt = time.Now()
+					return err
+				}
+				conn = c
+			}
+			_ = conn.SetWriteDeadline(time.Now().Add(5 * time.Second))
+			if _, err := conn.Write(bytes.Join(lines, nil)); err != nil {
+				_ = conn.Close()
+				conn, failedAt = nil, time.Now()
+				return err
+			}
+			return nil
+		}, func() {
+			if conn != nil {
+				_ = conn.Close()
+			}
+		})
+	case "http":
+		client := &http.Client{Timeout: 10 * time.Second}
+		go s.run(func(lines [][]byte) error {
+			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+			defer cancel()
+			req, err := http.NewRequestWithContext(ctx, http.MethodPost, spec.Target, bytes.NewReader(bytes.Join(lines, nil)))
+			if err != nil {
+				return err
+			}
+			req.Header.Set("Content-Type", "application/x-ndjson")
+			resp, err := client.Do(req)
+			if err != nil {
+				return err
+			}
+			_ = resp.Body.Close()
+			if resp.StatusCode >= 300 {
+				return fmt.Errorf("%s: %s", spec.Target, resp.Status)
+			}
+			return nil
+		}, func() {})
+	default:
+		return nil, fmt.Errorf("unknown log sink kind %q", spec.Kind)
+	}
+	return s, nil
+}
+
+// Send queues one JSON record line for delivery.
+func (s *Sink) Send(line []byte) {
+	tagged := s.tag(line)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return
+	}
+	select {
+	case s.queue <- tagged:
+	default:
+		s.dropped++
+	}
+}
+
+// tag adds the session ID as the first field of a JSON object line and ends it with a newline.
+func (s *Sink) tag(line []byte) []byte {
+	line = bytes.TrimRight(line, "\n")
+	if s.session == "" || len(line) < 2 || line[0] != '{' {
+		return append(append([]byte(nil), line...), '\n')
+	}
+	id, _ := json.Marshal(s.session)
+	out := make([]byte, 0, len(line)+len(id)+13)
+	out = append(out, `{"session":`...)
+	out = append(out, id...)
+	if len(line) > 2 {
+		out = append(out, ',')
+	}
+	out = append(out, line[1:]...)
+	return append(out, '\n')
+}
+
+// run delivers queued lines in batches until the queue is closed. A failed batch is
+// dropped and its error kept for Close to report.
+func (s *Sink) run(write func([][]byte) error, release func()) {
+	defer close(s.done)
+	defer release()
+	for line := range s.queue {
+		batch := [][]byte{line}
+		if s.spec.Kind == "http" {
+			batch = s.fill(batch)
+		} else {
+			batch = s.drain(batch)
+		}
+		if err := write(batch); err != nil {
+			s.mu.Lock()
+			s.dropped += len(batch)
+			s.err = err
+			s.mu.Unlock()
+		}
+	}
+}
+
+// drain adds the lines already queued to batch, up to batchSize.
+func (s *Sink) drain(batch [][]byte) [][]byte {
+	for len(batch) < batchSize {
+		select {
+		case line, ok := <-s.queue:
+			if !ok {
+				return batch
+			}
+			batch = append(batch, line)
+		default:
+			return batch
+		}
+	}
+	return batch
+}
+
+// fill waits up to batchDelay for batch to reach batchSize, so HTTP sinks make one
+// request per second or so rather than one per event.
+func (s *Sink) fill(batch [][]byte) [][]byte {
+	timer := time.NewTimer(batchDelay)
+	defer timer.Stop()
+	for len(batch) < batchSize {
+		select {
+		case line, ok := <-s.queue:
+			if !ok {
+				return batch
+			}
+			batch = append(batch, line)
+		case <-timer.C:
+			return batch
+		}
+	}
+	return batch
+}
+
+// Close stops accepting lines and waits a few seconds for the queued ones to be
+// delivered. It reports how many lines were lost and the last delivery error, if any.
+func (s *Sink) Close() error {
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return nil
+	}
+	s.closed = true
+	close(s.queue)
+	s.mu.Unlock()
+
+	select {
+	case <-s.done:
+	case <-time.After(closeTimeout):
+		return fmt.Errorf("log sink %s: gave up delivering queued lines after %s", s.spec, closeTimeout)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	switch {
+	case s.dropped > 0 && s.err != nil:
+		return fmt.Errorf("log sink %s: %d lines dropped, last error: %w", s.spec, s.dropped, s.err)
+	case s.dropped > 0:
+		return fmt.Errorf("log sink %s: %d lines dropped because it could not keep up", s.spec, s.dropped)
+	}
+	return nil
+}
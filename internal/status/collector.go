@@ -14,6 +14,7 @@ import (
 	"time"
 
 	"github.com/asynkron/Asynkron.SwarmGo/internal/agents"
+	"github.com/asynkron/Asynkron.SwarmGo/internal/termtext"
 )
 
 // Snapshot represents a lightweight git/log view for a single agent.
@@ -43,7 +44,8 @@ type LogEvent struct {
 	Message   string    `json:"message"`
 }
 
-// Collector polls git state (and optionally the agent log) for a single worktree.
+// Collector polls git state (and optionally the agent log) for a single worktree. An empty
+// worktree skips git, for projects that are not under version control.
 type Collector struct {
 	worktree  string
 	logPath   string
@@ -94,8 +96,18 @@ func (c *Collector) Start(ctx context.Context, emit func(Snapshot)) {
 	}
 }
 
+// Collect takes a single snapshot without starting the poll loop.
+func (c *Collector) Collect(ctx context.Context) Snapshot {
+	var snap Snapshot
+	c.pollOnce(ctx, func(s Snapshot) { snap = s })
+	return snap
+}
+
 func (c *Collector) pollOnce(ctx context.Context, emit func(Snapshot)) {
-	gitSnap := c.collectGit(ctx)
+	var gitSnap gitSnapshot
+	if c.worktree != "" {
+		gitSnap = c.collectGit(ctx)
+	}
 	logSnap := c.collectLogs()
 
 	c.mu.Lock()
@@ -275,10 +287,7 @@ func splitLines(input string) []string {
 	return lines
 }
 
+// trimLine cuts a log message to 500 columns without splitting a character.
 func trimLine(line string) string {
-	const max = 500
-	if len(line) <= max {
-		return line
-	}
-	return line[:max]
+	return termtext.Truncate(line, 500, "")
 }
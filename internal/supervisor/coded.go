@@ -16,12 +16,30 @@ import (
 	"time"
 
 	"github.com/asynkron/Asynkron.SwarmGo/internal/agents"
+	"github.com/asynkron/Asynkron.SwarmGo/internal/bench"
 	"github.com/asynkron/Asynkron.SwarmGo/internal/config"
+	"github.com/asynkron/Asynkron.SwarmGo/internal/termtext"
 )
 
 // CodedSupervisor collects lightweight signals from worker worktrees and logs, writing them
 // to a JSON file the supervisor agent can read.
 type CodedSupervisor struct {
+	// SkipGit disables git snapshots for projects that are not under version control.
+	// Set it before Start.
+	SkipGit bool
+	// BaseRef is the commit the workers started from; files committed since count as
+	// touched. A worker whose worktree lacks it is compared with its HEAD at the first poll.
+	BaseRef string
+	// Repos holds each worker's repository by worker index; workers of different
+	// repositories never conflict. Set it and BaseRef before Start.
+	Repos []string
+	// Conflicts receives each pair of workers that starts modifying the same files, and
+	// again whenever their shared files grow. Sends never block.
+	Conflicts chan<- Conflict
+	// SkipExistingLogs starts reading worker logs at their current end, so an arena round
+	// only sees its own output. Set it before Start.
+	SkipExistingLogs bool
+
 	outputPath string
 	workers    []workerInfo
 	interval   time.Duration
@@ -30,6 +48,10 @@ type CodedSupervisor struct {
 	mu     sync.Mutex
 	state  map[int]*workerState
 	offset map[int]int64
+	// conflicts is the last poll's conflicts; reported holds the files already sent to
+	// Conflicts per worker pair.
+	conflicts []Conflict
+	reported  map[[2]int]map[string]bool
 
 	ctx    context.Context
 	cancel context.CancelFunc
@@ -47,6 +69,16 @@ type workerState struct {
 	Git         gitSnapshot
 	Logs        []logEvent
 	LastUpdated time.Time
+	// Activity holds the lines changed per poll, oldest first; lines is the diff size seen
+	// by the previous poll, or -1 before the first one.
+	Activity []int
+	lines    int
+	// Tests is the tester agent's last report on the worker's latest commit.
+	Tests *testReport
+	// TestRun is the last test runner summary in the worker's log.
+	TestRun *TestRun
+	// base is the commit committed files are counted from, resolved at the first poll.
+	base string
 }
 
 type gitSnapshot struct {
@@ -55,8 +87,10 @@ type gitSnapshot struct {
 	Unstaged      []fileChange `json:"unstaged"`
 	Untracked     []string     `json:"untracked"`
 	RecentCommits []string     `json:"recentCommits"`
-	Error         string       `json:"error,omitempty"`
-	UpdatedAt     time.Time    `json:"updatedAt"`
+	// Committed lists the files changed by commits since the worker started.
+	Committed []string  `json:"committed,omitempty"`
+	Error     string    `json:"error,omitempty"`
+	UpdatedAt time.Time `json:"updatedAt"`
 }
 
 type fileChange struct {
@@ -71,6 +105,21 @@ type logEvent struct {
 	Message   string    `json:"message"`
 }
 
+// testReport is what the tester agent (--tester) last reported for a worker's commit.
+type testReport struct {
+	Status     string    `json:"status"`
+	Detail     string    `json:"detail,omitempty"`
+	ReportedAt time.Time `json:"reportedAt"`
+}
+
+// TestRun is the last test runner result found in a worker's log, counted from summary lines
+// such as "12 passed, 3 failed" or from go test -v output.
+type TestRun struct {
+	Passed int       `json:"passed"`
+	Failed int       `json:"failed"`
+	At     time.Time `json:"at"`
+}
+
 type logSummary struct {
 	LastPass *logEvent  `json:"lastPass,omitempty"`
 	LastFail *logEvent  `json:"lastFail,omitempty"`
@@ -81,12 +130,20 @@ type workerSnapshot struct {
 	WorkerNumber int         `json:"workerNumber"`
 	Git          gitSnapshot `json:"git"`
 	Logs         logSummary  `json:"logs"`
-	LastUpdated  time.Time   `json:"lastUpdated"`
+	// Activity is the number of lines changed in the worktree per poll, oldest first.
+	Activity []int `json:"activity"`
+	// Tests is the tester agent's last report, when one runs.
+	Tests *testReport `json:"tests,omitempty"`
+	// TestRun is the last test run the worker's log showed.
+	TestRun     *TestRun  `json:"testRun,omitempty"`
+	LastUpdated time.Time `json:"lastUpdated"`
 }
 
 type snapshot struct {
 	UpdatedAt time.Time        `json:"updatedAt"`
 	Workers   []workerSnapshot `json:"workers"`
+	// Conflicts lists the worker pairs modifying the same files.
+	Conflicts []Conflict `json:"conflicts,omitempty"`
 }
 
 var (
@@ -97,6 +154,8 @@ var (
 const (
 	defaultInterval = 5 * time.Second
 	maxLogEvents    = 50
+	// maxActivity is how many polls of activity each worker keeps.
+	maxActivity = 40
 )
 
 // NewCodedSupervisor returns a background collector. Call Start to begin polling, and Close to stop.
@@ -129,7 +188,7 @@ func NewCodedSupervisor(outputPath string, worktrees []string, workerLogs []stri
 	state := make(map[int]*workerState)
 	offsets := make(map[int]int64)
 	for _, w := range workers {
-		state[w.Number] = &workerState{LastUpdated: time.Now()}
+		state[w.Number] = &workerState{LastUpdated: time.Now(), lines: -1}
 		offsets[w.Number] = 0
 	}
 
@@ -139,6 +198,7 @@ func NewCodedSupervisor(outputPath string, worktrees []string, workerLogs []stri
 		interval:   interval,
 		state:      state,
 		offset:     offsets,
+		reported:   make(map[[2]int]map[string]bool),
 		ctx:        ctx,
 		cancel:     cancel,
 		startTime:  startTime,
@@ -147,14 +207,25 @@ func NewCodedSupervisor(outputPath string, worktrees []string, workerLogs []stri
 
 // Start begins the background polling loop.
 func (c *CodedSupervisor) Start() {
+	if c.SkipExistingLogs {
+		for _, w := range c.workers {
+			if info, err := os.Stat(w.LogPath); err == nil {
+				c.offset[w.Number] = info.Size()
+			}
+		}
+	}
 	c.wg.Add(1)
 	go c.loop()
 }
 
-// Close stops the polling loop and writes a final snapshot.
+// Close stops the polling loop and writes a final snapshot, including what the workers
+// logged since the last poll.
 func (c *CodedSupervisor) Close() {
 	c.cancel()
 	c.wg.Wait()
+	for _, w := range c.workers {
+		c.collectLogs(w)
+	}
 	_ = c.writeSnapshot()
 }
 
@@ -176,9 +247,14 @@ func (c *CodedSupervisor) loop() {
 
 func (c *CodedSupervisor) pollOnce() {
 	for _, w := range c.workers {
-		c.collectGit(w)
+		if !c.SkipGit {
+			c.collectGit(w)
+		}
 		c.collectLogs(w)
 	}
+	if !c.SkipGit {
+		c.updateConflicts()
+	}
 	_ = c.writeSnapshot()
 }
 
@@ -215,19 +291,96 @@ func (c *CodedSupervisor) collectGit(w workerInfo) {
 	if commits, err := runGit(ctx, w.Worktree, logCmd); err == nil {
 		snap.RecentCommits = splitLines(commits)
 	}
+	if base := c.workerBase(ctx, w); base != "" {
+		if files, err := runGit(ctx, w.Worktree, "diff --name-only "+base+" HEAD"); err == nil {
+			snap.Committed = splitLines(files)
+		}
+	}
 
 	c.storeGit(w.Number, snap)
 }
 
+// workerBase returns the commit a worker's committed files are counted from, resolving it
+// on first use: BaseRef when the worktree has it, otherwise the worktree's HEAD.
+func (c *CodedSupervisor) workerBase(ctx context.Context, w workerInfo) string {
+	c.mu.Lock()
+	state, ok := c.state[w.Number]
+	base := ""
+	if ok {
+		base = state.base
+	}
+	c.mu.Unlock()
+	if !ok || base != "" {
+		return base
+	}
+	if c.BaseRef != "" {
+		if _, err := runGit(ctx, w.Worktree, "cat-file -e "+c.BaseRef+"^{commit}"); err == nil {
+			base = c.BaseRef
+		}
+	}
+	if base == "" {
+		out, err := runGit(ctx, w.Worktree, "rev-parse HEAD")
+		if err != nil {
+			return ""
+		}
+		base = strings.TrimSpace(out)
+	}
+	c.mu.Lock()
+	state.base = base
+	c.mu.Unlock()
+	return base
+}
+
 func (c *CodedSupervisor) storeGit(worker int, snap gitSnapshot) {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 	if s, ok := c.state[worker]; ok {
 		s.Git = snap
 		s.LastUpdated = time.Now()
+		if snap.Error == "" {
+			s.recordActivity(diffLines(snap))
+		}
 	}
 }
 
+// RecordTests stores the tester agent's report on a worker's latest commit, so the next
+// snapshot carries it.
+func (c *CodedSupervisor) RecordTests(worker int, status, detail string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if s, ok := c.state[worker]; ok {
+		s.Tests = &testReport{Status: status, Detail: detail, ReportedAt: time.Now()}
+	}
+}
+
+// recordActivity appends how far the diff size moved since the previous poll. Committing
+// shrinks the diff, so a commit counts as activity too.
+func (s *workerState) recordActivity(lines int) {
+	if s.lines >= 0 {
+		delta := lines - s.lines
+		if delta < 0 {
+			delta = -delta
+		}
+		s.Activity = append(s.Activity, delta)
+		if len(s.Activity) > maxActivity {
+			s.Activity = s.Activity[len(s.Activity)-maxActivity:]
+		}
+	}
+	s.lines = lines
+}
+
+// diffLines is the number of added and deleted lines in the staged and unstaged changes.
+func diffLines(snap gitSnapshot) int {
+	total := 0
+	for _, fc := range snap.Staged {
+		total += fc.Added + fc.Deleted
+	}
+	for _, fc := range snap.Unstaged {
+		total += fc.Added + fc.Deleted
+	}
+	return total
+}
+
 func (c *CodedSupervisor) collectLogs(w workerInfo) {
 	data, newOffset, err := c.readNewLogData(w)
 	if err != nil || len(data) == 0 {
@@ -253,6 +406,9 @@ func (c *CodedSupervisor) collectLogs(w workerInfo) {
 			continue
 		}
 		for _, msg := range msgs {
+			if score := bench.ParseScore(msg.Text); score.Passed > 0 || score.Failed > 0 {
+				state.TestRun = &TestRun{Passed: score.Passed, Failed: score.Failed, At: now}
+			}
 			text := trimLine(msg.Text)
 			switch {
 			case passRegex.MatchString(text):
@@ -342,6 +498,9 @@ func (c *CodedSupervisor) buildSnapshot() *snapshot {
 				LastFail: lastFail,
 				Recent:   append([]logEvent(nil), state.Logs...),
 			},
+			Activity:    append([]int(nil), state.Activity...),
+			Tests:       state.Tests,
+			TestRun:     state.TestRun,
 			LastUpdated: state.LastUpdated,
 		})
 	}
@@ -349,9 +508,44 @@ func (c *CodedSupervisor) buildSnapshot() *snapshot {
 	return &snapshot{
 		UpdatedAt: time.Now(),
 		Workers:   workers,
+		Conflicts: append([]Conflict(nil), c.conflicts...),
 	}
 }
 
+// RoundResult is what a round's snapshot says about the workers' tests.
+type RoundResult struct {
+	// Passed is the most tests a worker's last test run passed.
+	Passed int
+	// Passing is set when a worker's last test run had no failures or the tester last
+	// reported PASS for it.
+	Passing bool
+}
+
+// ReadRoundResult reads the snapshot at path, written when the round's collector closed.
+func ReadRoundResult(path string) (RoundResult, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return RoundResult{}, err
+	}
+	var snap snapshot
+	if err := json.Unmarshal(data, &snap); err != nil {
+		return RoundResult{}, fmt.Errorf("parse %s: %w", path, err)
+	}
+	var r RoundResult
+	for _, w := range snap.Workers {
+		if run := w.TestRun; run != nil {
+			r.Passed = max(r.Passed, run.Passed)
+			if run.Passed > 0 && run.Failed == 0 {
+				r.Passing = true
+			}
+		}
+		if w.Tests != nil && w.Tests.Status == "PASS" {
+			r.Passing = true
+		}
+	}
+	return r, nil
+}
+
 func lastEvent(events []logEvent, kind string) *logEvent {
 	for i := len(events) - 1; i >= 0; i-- {
 		if events[i].Kind == kind {
@@ -409,12 +603,9 @@ func splitLines(input string) []string {
 	return lines
 }
 
+// trimLine cuts a log message to 500 columns without splitting a character.
 func trimLine(line string) string {
-	const max = 500
-	if len(line) <= max {
-		return line
-	}
-	return line[:max]
+	return termtext.Truncate(line, 500, "")
 }
 
 func summarizeLogLine(line string) string {
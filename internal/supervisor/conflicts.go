@@ -0,0 +1,126 @@
+package supervisor
+
+import (
+	"sort"
+	"strings"
+)
+
+// Conflict is a pair of workers whose worktrees modify the same files: staged, unstaged,
+// untracked or committed since they started.
+type Conflict struct {
+	Workers [2]int   `json:"workers"`
+	Files   []string `json:"files"`
+}
+
+// touchedFiles is every file a worker's snapshot shows as modified.
+func touchedFiles(snap gitSnapshot) []string {
+	seen := map[string]bool{}
+	var files []string
+	add := func(f string) {
+		f = strings.TrimSpace(f)
+		if f != "" && !seen[f] {
+			seen[f] = true
+			files = append(files, f)
+		}
+	}
+	for _, fc := range snap.Staged {
+		add(fc.File)
+	}
+	for _, fc := range snap.Unstaged {
+		add(fc.File)
+	}
+	for _, f := range snap.Untracked {
+		add(f)
+	}
+	for _, f := range snap.Committed {
+		add(f)
+	}
+	return files
+}
+
+// findConflicts lists the worker pairs touching the same files, ordered by worker. Workers
+// of different repositories never conflict; repos maps worker numbers to their repository.
+func findConflicts(touched map[int][]string, repos map[int]string) []Conflict {
+	owners := map[string][]int{}
+	for worker, files := range touched {
+		for _, f := range files {
+			key := repos[worker] + "\x00" + f
+			owners[key] = append(owners[key], worker)
+		}
+	}
+	shared := map[[2]int][]string{}
+	for key, workers := range owners {
+		if len(workers) < 2 {
+			continue
+		}
+		sort.Ints(workers)
+		file := key[strings.IndexByte(key, 0)+1:]
+		for i := 0; i < len(workers); i++ {
+			for j := i + 1; j < len(workers); j++ {
+				pair := [2]int{workers[i], workers[j]}
+				shared[pair] = append(shared[pair], file)
+			}
+		}
+	}
+	conflicts := make([]Conflict, 0, len(shared))
+	for pair, files := range shared {
+		sort.Strings(files)
+		conflicts = append(conflicts, Conflict{Workers: pair, Files: files})
+	}
+	sort.Slice(conflicts, func(i, j int) bool {
+		a, b := conflicts[i].Workers, conflicts[j].Workers
+		return a[0] < b[0] || (a[0] == b[0] && a[1] < b[1])
+	})
+	return conflicts
+}
+
+// updateConflicts recomputes the conflicts after a poll and sends the ones that gained
+// files since they were last sent to Conflicts, without blocking.
+func (c *CodedSupervisor) updateConflicts() {
+	c.mu.Lock()
+	touched := make(map[int][]string, len(c.state))
+	repos := make(map[int]string, len(c.workers))
+	for _, w := range c.workers {
+		if s, ok := c.state[w.Number]; ok && s.Git.Error == "" {
+			touched[w.Number] = touchedFiles(s.Git)
+		}
+		if w.Number-1 < len(c.Repos) {
+			repos[w.Number] = c.Repos[w.Number-1]
+		}
+	}
+	conflicts := findConflicts(touched, repos)
+	c.conflicts = conflicts
+	var fresh []Conflict
+	for _, cf := range conflicts {
+		known := c.reported[cf.Workers]
+		grew := false
+		for _, f := range cf.Files {
+			if !known[f] {
+				grew = true
+				break
+			}
+		}
+		if !grew {
+			continue
+		}
+		if known == nil {
+			known = map[string]bool{}
+			c.reported[cf.Workers] = known
+		}
+		for _, f := range cf.Files {
+			known[f] = true
+		}
+		fresh = append(fresh, cf)
+	}
+	c.mu.Unlock()
+
+	if c.Conflicts == nil {
+		return
+	}
+	for _, cf := range fresh {
+		select {
+		case c.Conflicts <- cf:
+		default:
+		}
+	}
+}
@@ -0,0 +1,87 @@
+// Package termtext measures, truncates and cleans agent output by grapheme cluster and
+// terminal column rather than by byte, so CJK text, emoji and combining characters keep
+// their shape and the layout around them.
+package termtext
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+
+	"github.com/rivo/uniseg"
+)
+
+// Width returns how many terminal columns s takes.
+func Width(s string) int {
+	return uniseg.StringWidth(s)
+}
+
+// Truncate cuts s to at most width columns without splitting a grapheme cluster. When
+// anything is cut, tail is appended and counted in the width.
+func Truncate(s string, width int, tail string) string {
+	if Width(s) <= width {
+		return s
+	}
+	width -= Width(tail)
+	var b strings.Builder
+	used, state := 0, -1
+	rest := s
+	for rest != "" {
+		var cluster string
+		var w int
+		cluster, rest, w, state = uniseg.FirstGraphemeClusterInString(rest, state)
+		if used+w > width {
+			break
+		}
+		b.WriteString(cluster)
+		used += w
+	}
+	return b.String() + tail
+}
+
+// TrimLast removes the last grapheme cluster of s, e.g. for backspace in a text field.
+func TrimLast(s string) string {
+	last := 0
+	state := -1
+	for rest := s; rest != ""; {
+		var cluster string
+		cluster, rest, _, state = uniseg.FirstGraphemeClusterInString(rest, state)
+		if rest == "" {
+			break
+		}
+		last += len(cluster)
+	}
+	return s[:last]
+}
+
+// escapes matches terminal escape sequences: CSI (colours, cursor movement) and OSC
+// (window titles, hyperlinks), the latter ended by BEL or ST.
+var escapes = regexp.MustCompile(`\x1b\[[0-9;?]*[ -/]*[@-~]|\x1b\][^\x07\x1b]*(?:\x07|\x1b\\)`)
+
+// StripANSI removes terminal escape sequences from s.
+func StripANSI(s string) string {
+	if strings.IndexByte(s, '\x1b') == -1 {
+		return s
+	}
+	return escapes.ReplaceAllString(s, "")
+}
+
+// Clean prepares a line of agent output for display: escape sequences and control
+// characters (C0, DEL and C1) are removed and tabs become four spaces. Invalid UTF-8 is
+// dropped. Format characters such as zero-width joiners, and combining marks, are kept so
+// emoji sequences and accented text stay intact.
+func Clean(s string) string {
+	s = StripANSI(s)
+	var b strings.Builder
+	b.Grow(len(s))
+	for _, r := range strings.ToValidUTF8(s, "") {
+		switch {
+		case r == '\t':
+			b.WriteString("    ")
+		case unicode.IsControl(r):
+		default:
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,76 @@
+package todo
+
+import (
+	"regexp"
+	"strings"
+)
+
+// matchThreshold is the Similarity above which a commit message or claim counts as being
+// about a task.
+const matchThreshold = 0.6
+
+var wordRegex = regexp.MustCompile(`[a-z0-9]+`)
+
+// stopWords are left out when comparing texts, along with words shorter than three letters.
+var stopWords = map[string]bool{
+	"the": true, "and": true, "for": true, "with": true, "from": true, "into": true,
+	"that": true, "this": true, "when": true, "add": true, "fix": true, "use": true,
+	"make": true, "update": true, "task": true, "todo": true,
+}
+
+// Match returns the task a claim or commit message is about: the task Find returns for it
+// or, failing that, the open task whose title is most similar to it, if similar enough.
+func Match(tasks []Task, text string) (Task, bool) {
+	if t, ok := Find(tasks, text); ok {
+		return t, true
+	}
+	var best Task
+	bestScore := 0.0
+	for _, t := range tasks {
+		if t.Done {
+			continue
+		}
+		if s := Similarity(t.Title, text); s > bestScore {
+			best, bestScore = t, s
+		}
+	}
+	return best, bestScore >= matchThreshold
+}
+
+// Similar reports whether two texts, such as the commit messages of two workers, share
+// enough words to be about the same thing.
+func Similar(a, b string) bool {
+	return Similarity(a, b) >= matchThreshold
+}
+
+// Similarity is the Dice coefficient of the significant words of a and b: 1 when they
+// use the same words, 0 when they share none. A time box on either side is ignored.
+func Similarity(a, b string) float64 {
+	wa, wb := words(stripBudget(a)), words(stripBudget(b))
+	if len(wa) == 0 || len(wb) == 0 {
+		return 0
+	}
+	shared := 0
+	for w := range wa {
+		if wb[w] {
+			shared++
+		}
+	}
+	return 2 * float64(shared) / float64(len(wa)+len(wb))
+}
+
+// words returns the significant words of s, lower-cased, with a trailing plural "s"
+// dropped so "tests" and "test" compare equal.
+func words(s string) map[string]bool {
+	set := make(map[string]bool)
+	for _, w := range wordRegex.FindAllString(strings.ToLower(s), -1) {
+		if len(w) > 3 {
+			w = strings.TrimSuffix(w, "s")
+		}
+		if len(w) < 3 || stopWords[w] {
+			continue
+		}
+		set[w] = true
+	}
+	return set
+}
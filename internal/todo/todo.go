@@ -0,0 +1,145 @@
+package todo
+
+import (
+	"os"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// Task is a single actionable item parsed from a markdown todo file.
+type Task struct {
+	Title string
+	Done  bool
+	Line  int
+	// Budget is the task's time box, written at the end of its line as "(timebox 30m)" or
+	// "[1h30m]"; zero means none.
+	Budget time.Duration
+}
+
+// Progress summarizes how many baseline tasks have been completed.
+type Progress struct {
+	Done  int
+	Total int
+}
+
+var (
+	checkboxRegex = regexp.MustCompile(`^\s*(?:[-*+]|\d+[.)])\s+\[([ xX])\]\s+(.+)$`)
+	bulletRegex   = regexp.MustCompile(`^(?:[-*+]|\d+[.)])\s+(.+)$`)
+	budgetRegex   = regexp.MustCompile(`(?i)[(\[](?:time\s?box:?\s*)?((?:\d+h)?(?:\d+m)?)[)\]]\s*$`)
+)
+
+// budget reads the time box at the end of a task title.
+func budget(title string) time.Duration {
+	m := budgetRegex.FindStringSubmatch(title)
+	if m == nil || m[1] == "" {
+		return 0
+	}
+	d, err := time.ParseDuration(m[1])
+	if err != nil {
+		return 0
+	}
+	return d
+}
+
+// Parse extracts tasks from markdown. Checkbox items ("- [ ] foo") are preferred; when the file
+// has none, top-level list items are treated as open tasks since workers remove finished ones.
+func Parse(content string) []Task {
+	lines := strings.Split(content, "\n")
+	var tasks []Task
+	for i, line := range lines {
+		if m := checkboxRegex.FindStringSubmatch(line); m != nil {
+			title := strings.TrimSpace(m[2])
+			tasks = append(tasks, Task{Title: title, Done: m[1] != " ", Line: i + 1, Budget: budget(title)})
+		}
+	}
+	if len(tasks) > 0 {
+		return tasks
+	}
+	inFence := false
+	for i, line := range lines {
+		if strings.HasPrefix(strings.TrimSpace(line), "```") {
+			inFence = !inFence
+			continue
+		}
+		if inFence {
+			continue
+		}
+		if m := bulletRegex.FindStringSubmatch(line); m != nil {
+			title := strings.TrimSpace(m[1])
+			tasks = append(tasks, Task{Title: title, Line: i + 1, Budget: budget(title)})
+		}
+	}
+	return tasks
+}
+
+// Load reads and parses a todo file.
+func Load(path string) ([]Task, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return Parse(string(data)), nil
+}
+
+// Compute reports progress of the baseline task list against one or more later copies of the
+// todo file (e.g., one per worker worktree). A baseline task counts as done when it is checked
+// off or removed in any copy.
+func Compute(baseline []Task, copies [][]Task) Progress {
+	progress := Progress{Total: len(baseline)}
+	for _, task := range baseline {
+		key := normalize(task.Title)
+		done := task.Done
+		for _, tasks := range copies {
+			if done {
+				break
+			}
+			found := false
+			for _, t := range tasks {
+				if normalize(t.Title) != key {
+					continue
+				}
+				found = true
+				if t.Done {
+					done = true
+				}
+				break
+			}
+			if !found {
+				done = true
+			}
+		}
+		if done {
+			progress.Done++
+		}
+	}
+	return progress
+}
+
+// Find returns the task whose title matches title, ignoring case, spacing and a time box
+// on either side, or a task whose title starts with it.
+func Find(tasks []Task, title string) (Task, bool) {
+	key := normalize(stripBudget(title))
+	if key == "" {
+		return Task{}, false
+	}
+	for _, t := range tasks {
+		if normalize(stripBudget(t.Title)) == key {
+			return t, true
+		}
+	}
+	for _, t := range tasks {
+		if strings.HasPrefix(normalize(stripBudget(t.Title)), key) {
+			return t, true
+		}
+	}
+	return Task{}, false
+}
+
+func stripBudget(title string) string {
+	return budgetRegex.ReplaceAllString(title, "")
+}
+
+func normalize(title string) string {
+	return strings.ToLower(strings.Join(strings.Fields(title), " "))
+}
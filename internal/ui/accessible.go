@@ -0,0 +1,343 @@
+package ui
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/asynkron/Asynkron.SwarmGo/internal/control"
+	"github.com/asynkron/Asynkron.SwarmGo/internal/events"
+	"github.com/asynkron/Asynkron.SwarmGo/internal/session"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// accessibleKeys is the key help printed at start and on ?.
+const accessibleKeys = "Keys: a approve pull request, x reject it, + and - change the time limit by 5 minutes, w take back time lost to sleep, ? repeat this help, q quit."
+
+// Accessible is the front end for screen readers (--accessible). Instead of panels that
+// redraw in place it prints one labeled line per update, without colours, spinners or box
+// drawing, and only takes the keys that answer what a run asks of the user. Agents' spoken
+// output and the commands they run are printed; command output is left to the logs.
+type Accessible struct {
+	session *session.Session
+	events  <-chan events.Event
+	control chan<- control.Command
+
+	names   map[string]string
+	running map[string]bool
+	// pending lists the agents whose pull request waits for approval, oldest first.
+	pending []string
+	// pendingStates and prStates are the last announced states of each agent's pull
+	// request, before and after it was opened.
+	pendingStates map[string]string
+	prStates      map[string]string
+
+	minutesLeft  int
+	todoDone     int
+	sleptOffer   time.Duration
+	confirmQuit  bool
+	shuttingDown bool
+	eventsClosed bool
+}
+
+// NewAccessible creates the screen-reader front end for a run.
+func NewAccessible(sess *session.Session, eventCh <-chan events.Event, control chan<- control.Command) Accessible {
+	return Accessible{
+		session:       sess,
+		events:        eventCh,
+		control:       control,
+		names:         map[string]string{},
+		running:       map[string]bool{},
+		pendingStates: map[string]string{},
+		prStates:      map[string]string{},
+		minutesLeft:   -1,
+		todoDone:      -1,
+	}
+}
+
+func (a Accessible) Init() tea.Cmd {
+	return tea.Sequence(
+		tea.Println("Swarm session "+a.session.ID+" started. "+accessibleKeys),
+		waitForEvent(a.events),
+	)
+}
+
+func (a Accessible) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		return a.handleKey(msg.String())
+	case events.Event:
+		lines := a.describe(msg)
+		if len(lines) == 0 {
+			return a, waitForEvent(a.events)
+		}
+		// Print before taking the next event so lines come out in event order.
+		return a, tea.Sequence(tea.Println(strings.Join(lines, "\n")), waitForEvent(a.events))
+	case eventsClosedMsg:
+		a.eventsClosed = true
+		if a.shuttingDown {
+			return a, tea.Sequence(tea.Println("All agents stopped."), tea.Quit)
+		}
+		return a, tea.Println("The run has ended. Press q to quit.")
+	}
+	return a, nil
+}
+
+// View is empty: everything is printed above it, line by line.
+func (a Accessible) View() string {
+	return ""
+}
+
+func (a Accessible) handleKey(key string) (tea.Model, tea.Cmd) {
+	if a.shuttingDown {
+		if key == "ctrl+c" {
+			return a, tea.Quit
+		}
+		return a, nil
+	}
+	if a.confirmQuit {
+		switch key {
+		case "y", "Y", "enter", "q", "ctrl+c":
+			a.confirmQuit = false
+			if a.control == nil || a.eventsClosed {
+				return a, tea.Quit
+			}
+			a.shuttingDown = true
+			ctrl := a.control
+			go func() { ctrl <- control.Shutdown{} }()
+			return a, tea.Println("Stopping all agents. Press ctrl+c to quit without waiting.")
+		case "n", "N", "esc":
+			a.confirmQuit = false
+			return a, tea.Println("Quit cancelled.")
+		}
+		return a, nil
+	}
+	switch key {
+	case "q", "ctrl+c":
+		running := a.runningAgents()
+		if a.eventsClosed || running == 0 {
+			return a, tea.Quit
+		}
+		a.confirmQuit = true
+		return a, tea.Println(fmt.Sprintf("%d agent(s) are still running and will be stopped. Quit? Press y to quit or n to cancel. You can resume later with: swarm --resume %s", running, a.session.ID))
+	case "?":
+		return a, tea.Println(accessibleKeys)
+	case "a", "x":
+		return a, a.decidePR(key == "a")
+	case "+", "=":
+		return a, a.adjustTime(timeStep)
+	case "-":
+		return a, a.adjustTime(-timeStep)
+	case "w":
+		if a.sleptOffer <= 0 {
+			return a, tea.Println("No time to take back.")
+		}
+		offer := a.sleptOffer
+		a.sleptOffer = 0
+		return a, a.adjustTime(offer)
+	}
+	return a, nil
+}
+
+// decidePR approves or rejects the oldest pull request waiting for approval.
+func (a *Accessible) decidePR(approve bool) tea.Cmd {
+	if a.control == nil || len(a.pending) == 0 {
+		return tea.Println("No pull request is waiting for approval.")
+	}
+	id := a.pending[0]
+	a.pending = a.pending[1:]
+	ctrl := a.control
+	if approve {
+		go func() { ctrl <- control.ApprovePR{AgentID: id} }()
+		return tea.Println("Approved the pull request of " + a.name(id) + ".")
+	}
+	go func() { ctrl <- control.RejectPR{AgentID: id} }()
+	return tea.Println("Rejected the pull request of " + a.name(id) + ".")
+}
+
+func (a *Accessible) adjustTime(delta time.Duration) tea.Cmd {
+	if a.control == nil {
+		return nil
+	}
+	ctrl := a.control
+	go func() { ctrl <- control.AdjustTime{Delta: delta} }()
+	if delta > 0 {
+		return tea.Println(fmt.Sprintf("Extending the round by %s.", delta))
+	}
+	return tea.Println(fmt.Sprintf("Shortening the round by %s.", -delta))
+}
+
+func (a *Accessible) runningAgents() int {
+	count := 0
+	for id, running := range a.running {
+		if id != "app" && running {
+			count++
+		}
+	}
+	return count
+}
+
+// name is how an agent is announced: the name it was added with, else its ID.
+func (a *Accessible) name(id string) string {
+	if name := a.names[id]; name != "" {
+		return name
+	}
+	return id
+}
+
+// describe turns an event into the lines to print; most events need none.
+func (a *Accessible) describe(ev events.Event) []string {
+	switch e := ev.(type) {
+	case events.AgentAdded:
+		a.names[e.ID] = e.Name
+		a.running[e.ID] = e.Running
+		if e.ID == "app" {
+			return nil
+		}
+		if e.Running {
+			return []string{fmt.Sprintf("Started %s (%s).", e.Name, e.Kind)}
+		}
+		return []string{fmt.Sprintf("%s (%s) is ready.", e.Name, e.Kind)}
+	case events.AgentRemoved:
+		line := fmt.Sprintf("Removed %s.", a.name(e.ID))
+		delete(a.names, e.ID)
+		delete(a.running, e.ID)
+		return []string{line}
+	case events.AgentStopped:
+		a.running[e.ID] = false
+		return []string{fmt.Sprintf("%s exited with code %d.", a.name(e.ID), e.ExitCode)}
+	case events.AgentLine:
+		return a.describeLine(e)
+	case events.AgentLines:
+		var lines []string
+		for _, line := range e.Lines {
+			lines = append(lines, a.describeLine(line)...)
+		}
+		return lines
+	case events.StatusMessage:
+		return []string{"Status: " + e.Message}
+	case events.PhaseChanged:
+		return []string{"Phase: " + e.Phase}
+	case events.RoundChanged:
+		return []string{fmt.Sprintf("Round %d of %d.", e.Current, e.Total)}
+	case events.RemainingTime:
+		return a.describeRemaining(e.Duration)
+	case events.Slept:
+		a.sleptOffer = 0
+		if !e.Extended {
+			a.sleptOffer = e.Offer
+		}
+	case events.TodoProgress:
+		if e.Done == a.todoDone {
+			return nil
+		}
+		a.todoDone = e.Done
+		return []string{fmt.Sprintf("Todo: %d of %d tasks done.", e.Done, e.Total)}
+	case events.AgentStartFailed:
+		return []string{fmt.Sprintf("%s failed to start, attempt %d of %d: %s", a.name(e.ID), e.Attempt, e.Attempts, e.Error)}
+	case events.AgentStalled:
+		return []string{fmt.Sprintf("%s stalled: no output for %s.", a.name(e.ID), e.Idle)}
+	case events.RateLimited:
+		if e.Message == "" {
+			return nil
+		}
+		return []string{fmt.Sprintf("%s is rate limited: %s", a.name(e.ID), e.Message)}
+	case events.QuotaWarning:
+		return []string{fmt.Sprintf("%s usage warning: %s", a.name(e.ID), e.Message)}
+	case events.PendingPR:
+		return a.describePending(e)
+	case events.PRStatus:
+		state := strings.ToLower(e.State) + ", " + e.Summary
+		if a.prStates[e.ID] == state {
+			return nil
+		}
+		a.prStates[e.ID] = state
+		return []string{fmt.Sprintf("Pull request #%d of %s is %s.", e.Number, a.name(e.ID), state)}
+	case events.Verdict:
+		return a.describeVerdict(e)
+	}
+	return nil
+}
+
+func (a *Accessible) describeLine(line events.AgentLine) []string {
+	text := strings.TrimSpace(line.Line)
+	if text == "" {
+		return nil
+	}
+	switch line.Kind {
+	case events.MessageSay:
+		return []string{a.name(line.ID) + " says: " + text}
+	case events.MessageDo:
+		return []string{a.name(line.ID) + " runs: " + text}
+	}
+	return nil
+}
+
+// describeRemaining announces the time left every five minutes, and once more in the
+// last minute.
+func (a *Accessible) describeRemaining(left time.Duration) []string {
+	minutes := int(math.Ceil(left.Minutes()))
+	if minutes == a.minutesLeft || minutes <= 0 {
+		return nil
+	}
+	a.minutesLeft = minutes
+	switch {
+	case minutes == 1:
+		return []string{"One minute left in the round."}
+	case minutes%5 == 0:
+		return []string{fmt.Sprintf("%d minutes left in the round.", minutes)}
+	}
+	return nil
+}
+
+func (a *Accessible) describePending(e events.PendingPR) []string {
+	if a.pendingStates[e.ID] == e.State {
+		return nil
+	}
+	a.pendingStates[e.ID] = e.State
+	a.pending = slices.DeleteFunc(a.pending, func(id string) bool { return id == e.ID })
+	if e.State == "pending" || e.State == "failed" {
+		a.pending = append(a.pending, e.ID)
+	}
+	switch e.State {
+	case "pending":
+		return []string{fmt.Sprintf("%s has a pull request waiting for approval: %s. Press a to approve or x to reject it.", a.name(e.ID), e.Title)}
+	case "failed":
+		return []string{fmt.Sprintf("Opening the pull request of %s failed: %s. Press a to try again or x to reject it.", a.name(e.ID), e.Error)}
+	case "opened":
+		return []string{fmt.Sprintf("Opened the pull request of %s: %s", a.name(e.ID), e.URL)}
+	case "rejected":
+		return []string{fmt.Sprintf("The pull request of %s was rejected.", a.name(e.ID))}
+	}
+	return nil
+}
+
+func (a *Accessible) describeVerdict(v events.Verdict) []string {
+	label := func(n int) string {
+		label := fmt.Sprintf("Worker %d", n)
+		if name := a.names[fmt.Sprintf("worker-%d", n)]; name != "" && name != label {
+			label += fmt.Sprintf(" (%s)", name)
+		}
+		return label
+	}
+	lines := []string{"Verdict: no winner."}
+	if v.Winner != 0 {
+		lines[0] = "Verdict: " + label(v.Winner) + " wins."
+	}
+	if v.Rationale != "" {
+		lines = append(lines, v.Rationale)
+	}
+	for _, s := range v.Scores {
+		line := fmt.Sprintf("%s scored %.1f.", label(s.Worker), s.Score)
+		if s.Tests != "" {
+			line += " Tests: " + s.Tests + "."
+		}
+		if s.Notes != "" {
+			line += " " + s.Notes
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
@@ -0,0 +1,78 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// listActivity is how many polls of activity the agent list shows.
+const listActivity = 12
+
+var sparkLevels = []rune("▁▂▃▄▅▆▇█")
+
+// activityFor returns the lines changed per poll for a worker, oldest first, as recorded
+// by the coded supervisor. Other agents have no activity series.
+func (m *Model) activityFor(id string) []int {
+	var workerNum int
+	if _, err := fmt.Sscanf(id, "worker-%d", &workerNum); err != nil {
+		return nil
+	}
+	snap := m.loadCodedSnapshot()
+	if snap == nil {
+		return nil
+	}
+	for _, w := range snap.Workers {
+		if w.WorkerNumber == workerNum {
+			return w.Activity
+		}
+	}
+	return nil
+}
+
+// sparkline draws the last width samples scaled to their maximum. Idle polls sit on the
+// lowest block so any change stands out.
+func sparkline(samples []int, width int) string {
+	if width > 0 && len(samples) > width {
+		samples = samples[len(samples)-width:]
+	}
+	peak := 0
+	for _, v := range samples {
+		peak = max(peak, v)
+	}
+	var b strings.Builder
+	for _, v := range samples {
+		level := 0
+		if v > 0 {
+			level = (v*(len(sparkLevels)-1) + peak - 1) / peak
+		}
+		b.WriteRune(sparkLevels[level])
+	}
+	return b.String()
+}
+
+// renderActivity is the styled sparkline of an agent's last width polls, highlighted when it
+// changed anything in them, or "" before there is history.
+func (m *Model) renderActivity(id string, width int) string {
+	samples := m.activityFor(id)
+	if len(samples) == 0 {
+		return ""
+	}
+	if width > 0 && len(samples) > width {
+		samples = samples[len(samples)-width:]
+	}
+	color := m.styles.dim
+	if sumSamples(samples) > 0 {
+		color = m.styles.running
+	}
+	return lipgloss.NewStyle().Foreground(color).Render(sparkline(samples, width))
+}
+
+func sumSamples(samples []int) int {
+	total := 0
+	for _, v := range samples {
+		total += v
+	}
+	return total
+}
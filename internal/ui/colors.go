@@ -0,0 +1,58 @@
+package ui
+
+import (
+	"fmt"
+	"hash/fnv"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// agentColor returns the accent of an agent. Workers take the palette in order, so the
+// first ones never share a color; other agents get one from their ID. Either way it stays
+// the same across restarts and replays.
+func (m Model) agentColor(id string) lipgloss.Color {
+	palette := m.styles.agents
+	var n int
+	if _, err := fmt.Sscanf(id, "worker-%d", &n); err == nil && n > 0 {
+		return palette[(n-1)%len(palette)]
+	}
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(id))
+	return palette[h.Sum32()%uint32(len(palette))]
+}
+
+// agentName renders an agent's name, or its ID when it is unknown, bold in its accent.
+func (m Model) agentName(id string) string {
+	name := id
+	if ag, ok := m.agents[id]; ok && ag.Name != "" {
+		name = ag.Name
+	}
+	return lipgloss.NewStyle().Bold(true).Foreground(m.agentColor(id)).Render(name)
+}
+
+// agentMentions returns a replacer that colors the IDs and names of agents where they
+// appear in shared text such as the status history, longest first so "worker-12" wins
+// over "worker-1".
+func (m Model) agentMentions() *strings.Replacer {
+	type mention struct{ text, id string }
+	var mentions []mention
+	for id, ag := range m.agents {
+		mentions = append(mentions, mention{id, id})
+		if ag.Name != "" && ag.Name != id {
+			mentions = append(mentions, mention{ag.Name, id})
+		}
+	}
+	sort.Slice(mentions, func(i, j int) bool {
+		if len(mentions[i].text) != len(mentions[j].text) {
+			return len(mentions[i].text) > len(mentions[j].text)
+		}
+		return mentions[i].text < mentions[j].text
+	})
+	pairs := make([]string, 0, 2*len(mentions))
+	for _, mn := range mentions {
+		pairs = append(pairs, mn.text, lipgloss.NewStyle().Foreground(m.agentColor(mn.id)).Render(mn.text))
+	}
+	return strings.NewReplacer(pairs...)
+}
@@ -0,0 +1,71 @@
+package ui
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// exportSelected writes the selected panel as plain text into the session directory.
+func (m *Model) exportSelected() {
+	id := m.selectedID()
+	if id == "" || m.session == nil {
+		return
+	}
+	path := m.session.ExportPath(id, time.Now())
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		m.addStatus(fmt.Sprintf("export failed: %v", err))
+		return
+	}
+	if err := os.WriteFile(path, []byte(m.exportContent(id)+"\n"), 0o644); err != nil {
+		m.addStatus(fmt.Sprintf("export failed: %v", err))
+		return
+	}
+	m.addStatus(fmt.Sprintf("Exported %s to %s", id, path))
+}
+
+func (m *Model) exportContent(id string) string {
+	switch id {
+	case "session":
+		return m.renderSessionInfo()
+	case "todo":
+		content, err := os.ReadFile(m.todoFilePath())
+		if err != nil {
+			return m.todo
+		}
+		return string(content)
+	case "scratchpad":
+		if m.scratchpad == nil {
+			return ""
+		}
+		return m.scratchpad.Content
+	case "coded":
+		return m.renderMetrics()
+	case "verdict":
+		return m.exportVerdict()
+	case "pending":
+		return m.renderPending()
+	case "prs":
+		var lines []string
+		for _, pr := range m.sortedPRs() {
+			lines = append(lines, fmt.Sprintf("%s  #%d %s  %s  %s  %s", pr.ID, pr.Number, strings.ToLower(pr.State), pr.Summary, pr.Branch, pr.URL))
+		}
+		return strings.Join(lines, "\n")
+	case "quota":
+		return m.exportQuota()
+	case "status":
+		lines := make([]string, 0, len(m.history))
+		for _, e := range m.history {
+			lines = append(lines, e.Time.Format(historyTimeLayout)+"  "+e.Message)
+		}
+		return strings.Join(lines, "\n")
+	default:
+		buf, ok := m.logs[id]
+		if !ok {
+			return ""
+		}
+		return buf.content()
+	}
+}
@@ -0,0 +1,95 @@
+package ui
+
+import (
+	"cmp"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// execDoneMsg reports that a program the TUI was suspended for (the editor or a shell)
+// has exited.
+type execDoneMsg struct {
+	name string
+	err  error
+}
+
+// editorCommand builds the command that opens worktree: configured (with "{worktree}"
+// replaced, or the path appended) or else $VISUAL, $EDITOR or VS Code.
+func editorCommand(configured, worktree string) *exec.Cmd {
+	command := configured
+	for _, candidate := range []string{os.Getenv("VISUAL"), os.Getenv("EDITOR"), "code"} {
+		if command != "" {
+			break
+		}
+		command = candidate
+	}
+	fields := strings.Fields(command)
+	replaced := false
+	for i, f := range fields {
+		if strings.Contains(f, "{worktree}") {
+			fields[i] = strings.ReplaceAll(f, "{worktree}", worktree)
+			replaced = true
+		}
+	}
+	if !replaced {
+		fields = append(fields, worktree)
+	}
+	cmd := exec.Command(fields[0], fields[1:]...)
+	cmd.Dir = worktree
+	return cmd
+}
+
+// selectedWorktree returns the selected agent and its worktree, reporting why not when the
+// worktree can't be used.
+func (m *Model) selectedWorktree() (*agentView, bool) {
+	if m.selected >= len(m.itemOrder) {
+		return nil, false
+	}
+	ag, ok := m.agents[m.itemOrder[m.selected]]
+	if !ok || ag.Worktree == "" {
+		return nil, false
+	}
+	if _, err := os.Stat(ag.Worktree); err != nil {
+		m.addStatus(fmt.Sprintf("Cannot use the worktree of %s: %v", ag.ID, err))
+		return nil, false
+	}
+	return ag, true
+}
+
+// openWorktree opens the selected agent's worktree in the editor. The TUI is suspended
+// while it runs, so terminal editors work as well as ones that open a window.
+func (m *Model) openWorktree() tea.Cmd {
+	ag, ok := m.selectedWorktree()
+	if !ok {
+		return nil
+	}
+	cmd := editorCommand(m.opts.OpenCommand, ag.Worktree)
+	m.addStatus(fmt.Sprintf("Opening %s in %s", ag.Worktree, cmd.Path))
+	return tea.ExecProcess(cmd, func(err error) tea.Msg { return execDoneMsg{name: "Editor", err: err} })
+}
+
+// openShell starts $SHELL in the selected agent's worktree with SWARM_AGENT set to its ID,
+// for quick manual test runs. The TUI comes back when the shell exits.
+func (m *Model) openShell() tea.Cmd {
+	ag, ok := m.selectedWorktree()
+	if !ok {
+		return nil
+	}
+	shell := os.Getenv("SHELL")
+	if shell == "" {
+		shell = "sh"
+		if runtime.GOOS == "windows" {
+			shell = cmp.Or(os.Getenv("COMSPEC"), "cmd")
+		}
+	}
+	cmd := exec.Command(shell)
+	cmd.Dir = ag.Worktree
+	cmd.Env = append(os.Environ(), "SWARM_AGENT="+ag.ID)
+	m.addStatus(fmt.Sprintf("Shell in %s (exit to return)", ag.Worktree))
+	return tea.ExecProcess(cmd, func(err error) tea.Msg { return execDoneMsg{name: "Shell", err: err} })
+}
@@ -0,0 +1,99 @@
+package ui
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/alecthomas/chroma/v2"
+	"github.com/alecthomas/chroma/v2/formatters"
+	"github.com/alecthomas/chroma/v2/lexers"
+	"github.com/alecthomas/chroma/v2/styles"
+)
+
+var (
+	fenceRegex     = regexp.MustCompile("^\\s*(```|~~~)\\s*([\\w+#.-]*)")
+	diffStartRegex = regexp.MustCompile(`^(diff --git |--- a/|\+\+\+ b/|@@ -\d+(,\d+)? \+\d+(,\d+)? @@)`)
+)
+
+// highlightCode renders source with chroma, guessing the lexer when no language hint is given.
+func highlightCode(source, language string) string {
+	var lexer chroma.Lexer
+	if language != "" {
+		lexer = lexers.Get(language)
+	}
+	if lexer == nil {
+		lexer = lexers.Analyse(source)
+	}
+	if lexer == nil {
+		return source
+	}
+	iterator, err := chroma.Coalesce(lexer).Tokenise(nil, source)
+	if err != nil {
+		return source
+	}
+	style := styles.Get("monokai")
+	var b strings.Builder
+	if err := formatters.TTY256.Format(&b, style, iterator); err != nil {
+		return source
+	}
+	return strings.TrimRight(b.String(), "\n")
+}
+
+// isDiffStart reports whether a line opens a unified diff (git diff output, patch headers, hunks).
+func isDiffStart(line string) bool {
+	return diffStartRegex.MatchString(strings.TrimSpace(line))
+}
+
+// looksLikeDiff reports whether a multi-line message is a bare unified diff.
+func looksLikeDiff(text string) bool {
+	hunk := false
+	for _, line := range strings.Split(text, "\n") {
+		if isDiffStart(line) {
+			hunk = true
+			break
+		}
+	}
+	return hunk && strings.Contains(text, "\n")
+}
+
+type textSegment struct {
+	code     bool
+	language string
+	body     string
+}
+
+// splitFences separates fenced code blocks from surrounding prose. An unterminated fence runs
+// to the end of the text, matching how agents stream partial answers.
+func splitFences(text string) []textSegment {
+	var segments []textSegment
+	var current []string
+	inCode := false
+	marker, language := "", ""
+	flush := func(code bool) {
+		if len(current) > 0 {
+			segments = append(segments, textSegment{code: code, language: language, body: strings.Join(current, "\n")})
+		}
+		current = nil
+	}
+	for _, line := range strings.Split(text, "\n") {
+		if m := fenceRegex.FindStringSubmatch(line); m != nil {
+			if !inCode {
+				flush(false)
+				inCode, marker, language = true, m[1], m[2]
+				continue
+			}
+			if m[1] == marker && strings.TrimSpace(line) == marker {
+				flush(true)
+				inCode, marker, language = false, "", ""
+				continue
+			}
+		}
+		current = append(current, line)
+	}
+	flush(inCode)
+	return segments
+}
+
+func hasFence(text string) bool {
+	return strings.Contains(text, "```") || strings.Contains(text, "~~~")
+}
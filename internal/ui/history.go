@@ -0,0 +1,131 @@
+package ui
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/asynkron/Asynkron.SwarmGo/internal/termtext"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+const (
+	historyLimit      = 2000
+	historyTimeLayout = "2006-01-02 15:04:05"
+)
+
+type statusEntry struct {
+	Time    time.Time
+	Message string
+}
+
+// addStatus records a message in the status strip and the persisted status history.
+func (m *Model) addStatus(message string) {
+	m.status = append(m.status, message)
+	m.trimStatus()
+
+	entry := statusEntry{Time: time.Now(), Message: message}
+	m.history = append(m.history, entry)
+	if len(m.history) > historyLimit {
+		m.history = m.history[len(m.history)-historyLimit:]
+	}
+	m.persistStatus(entry)
+}
+
+func (m *Model) persistStatus(entry statusEntry) {
+	if m.session == nil || m.replay {
+		return
+	}
+	f, err := os.OpenFile(m.session.StatusLogPath(), os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return
+	}
+	defer f.Close()
+	message := strings.ReplaceAll(entry.Message, "\n", " ")
+	_, _ = fmt.Fprintf(f, "%s %s\n", entry.Time.Format(historyTimeLayout), message)
+}
+
+// loadHistory restores status history written by earlier runs of the same session.
+func (m *Model) loadHistory() {
+	if m.session == nil {
+		return
+	}
+	f, err := os.Open(m.session.StatusLogPath())
+	if err != nil {
+		return
+	}
+	defer f.Close()
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if len(line) <= len(historyTimeLayout) {
+			continue
+		}
+		ts, err := time.ParseInLocation(historyTimeLayout, line[:len(historyTimeLayout)], time.Local)
+		if err != nil {
+			continue
+		}
+		m.history = append(m.history, statusEntry{Time: ts, Message: strings.TrimSpace(line[len(historyTimeLayout):])})
+	}
+	if len(m.history) > historyLimit {
+		m.history = m.history[len(m.history)-historyLimit:]
+	}
+}
+
+func (m *Model) renderHistory() string {
+	query := strings.ToLower(m.historyQuery)
+	var lines []string
+	if m.searching || m.historyQuery != "" {
+		prompt := fmt.Sprintf("Search: %s", m.historyQuery)
+		if m.searching {
+			prompt += "█"
+		} else {
+			prompt += "  (/ to edit, Esc to clear)"
+		}
+		lines = append(lines, lipgloss.NewStyle().Foreground(m.styles.accent).Render(prompt), "")
+	}
+	mentions := m.agentMentions()
+	matches := 0
+	for _, e := range m.history {
+		if query != "" && !strings.Contains(strings.ToLower(e.Message), query) {
+			continue
+		}
+		matches++
+		ts := lipgloss.NewStyle().Foreground(m.styles.dim).Render(e.Time.Format(historyTimeLayout))
+		lines = append(lines, ts+"  "+mentions.Replace(e.Message))
+	}
+	if matches == 0 {
+		if query != "" {
+			lines = append(lines, "no matching status messages")
+		} else {
+			lines = append(lines, "no status messages yet")
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// updateSearch edits the status history query while search input is active.
+func (m *Model) updateSearch(msg tea.KeyMsg) {
+	switch msg.Type {
+	case tea.KeyEnter:
+		m.searching = false
+	case tea.KeyEsc:
+		m.searching = false
+		m.historyQuery = ""
+	case tea.KeyBackspace:
+		m.historyQuery = termtext.TrimLast(m.historyQuery)
+	case tea.KeyRunes, tea.KeySpace:
+		m.historyQuery += string(msg.Runes)
+	}
+	m.updateViewport()
+}
+
+func (m *Model) selectedID() string {
+	if m.selected < 0 || m.selected >= len(m.itemOrder) {
+		return ""
+	}
+	return m.itemOrder[m.selected]
+}
@@ -0,0 +1,51 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/asynkron/Asynkron.SwarmGo/internal/events"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// loadInjections restores the messages agents received earlier in a resumed session.
+func (m *Model) loadInjections() {
+	if m.session == nil {
+		return
+	}
+	list, err := m.session.Injections()
+	if err != nil {
+		return
+	}
+	for _, in := range list {
+		m.injections[in.Agent] = append(m.injections[in.Agent], events.PromptInjected{ID: in.Agent, Time: in.Time, Message: in.Message, Live: in.Live})
+	}
+}
+
+// renderInjections lists the messages an agent has received, across restarts, as the
+// section above its log. It is empty for agents that received none.
+func (m *Model) renderInjections(id string) string {
+	list := m.injections[id]
+	if len(list) == 0 {
+		return ""
+	}
+	header := lipgloss.NewStyle().Bold(true).Foreground(m.styles.accent)
+	dim := lipgloss.NewStyle().Foreground(m.styles.dim)
+	var b strings.Builder
+	b.WriteString(header.Render(fmt.Sprintf("Instructions received (%d)", len(list))))
+	b.WriteString("\n")
+	for _, in := range list {
+		how := "on restart"
+		if in.Live {
+			how = "live"
+		}
+		b.WriteString(dim.Render(fmt.Sprintf("%s %s", in.Time.Local().Format("15:04:05"), how)))
+		b.WriteString("\n")
+		for _, line := range strings.Split(strings.TrimSpace(in.Message), "\n") {
+			b.WriteString("  " + line + "\n")
+		}
+	}
+	b.WriteString(dim.Render(strings.Repeat("─", max(m.view.Width, 1))))
+	b.WriteString("\n")
+	return b.String()
+}
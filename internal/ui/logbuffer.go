@@ -0,0 +1,188 @@
+package ui
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	// logBufferBudget is how many bytes of an agent's output are kept in memory. Older
+	// entries move to a scrollback file and are paged back in when scrolled to.
+	logBufferBudget = 2 << 20
+	// logPageBytes is how much older output one page-in loads.
+	logPageBytes = 256 << 10
+)
+
+// logBuffer holds an agent's most recent log entries within logBufferBudget. Entries
+// pushed out are appended to the spill file, in order, and offsets records where each
+// starts; first is the index in the whole log of lines[0]. Entries paged back in stay in
+// memory on top of the budget until release.
+type logBuffer struct {
+	lines    []logEntry
+	budget   int
+	size     int
+	doMode   bool
+	diffMode bool
+
+	spillPath string
+	spill     *os.File
+	offsets   []int64
+	first     int
+	// paged is the size of the entries paged back in from the spill file.
+	paged int
+}
+
+func newLogBuffer(spillPath string) *logBuffer {
+	return &logBuffer{budget: logBufferBudget, spillPath: spillPath}
+}
+
+// append adds an entry and pushes the oldest ones out of memory once over budget. It
+// reports whether entries were pushed out.
+func (b *logBuffer) append(entry logEntry) bool {
+	b.lines = append(b.lines, entry)
+	b.size += len(entry.Text)
+	limit := b.budget
+	if b.paged > 0 {
+		// Someone is reading paged-in output; rather than pull it away, let new output grow
+		// past the budget, up to another budget's worth.
+		limit *= 2
+	}
+	trimmed := false
+	for b.size-b.paged > limit && len(b.lines) > 1 {
+		b.evict()
+		trimmed = true
+	}
+	return trimmed
+}
+
+// evict drops the oldest entry in memory, writing it to the spill file unless it was
+// paged in from there. Without a spill file it is lost.
+func (b *logBuffer) evict() {
+	l := b.lines[0]
+	if b.first < b.onDisk() {
+		b.paged -= len(l.Text)
+	} else {
+		b.write(l)
+	}
+	b.lines[0] = logEntry{}
+	b.lines = b.lines[1:]
+	b.size -= len(l.Text)
+	b.first++
+}
+
+// onDisk is how many entries the spill file holds.
+func (b *logBuffer) onDisk() int {
+	return max(len(b.offsets)-1, 0)
+}
+
+func (b *logBuffer) write(l logEntry) {
+	if b.spill == nil {
+		if b.spillPath == "" {
+			return
+		}
+		if err := os.MkdirAll(filepath.Dir(b.spillPath), 0o755); err != nil {
+			b.spillPath = ""
+			return
+		}
+		f, err := os.OpenFile(b.spillPath, os.O_CREATE|os.O_RDWR|os.O_TRUNC, 0o644)
+		if err != nil {
+			b.spillPath = ""
+			return
+		}
+		b.spill, b.offsets = f, []int64{0}
+	}
+	data, err := json.Marshal(l)
+	end := b.offsets[len(b.offsets)-1]
+	if err == nil {
+		_, err = b.spill.WriteAt(append(data, '\n'), end)
+	}
+	if err != nil {
+		// A gap would misnumber the entries on disk; stop spilling and lose them instead.
+		b.close()
+		b.spillPath, b.offsets = "", nil
+		return
+	}
+	b.offsets = append(b.offsets, end+int64(len(data))+1)
+}
+
+// older reports how many earlier entries can still be paged in.
+func (b *logBuffer) older() int {
+	if b.spill == nil {
+		return 0
+	}
+	return min(b.first, b.onDisk())
+}
+
+// pageIn loads up to maxBytes of the entries before the first one in memory, at least
+// one, and returns how many it added to the front of lines.
+func (b *logBuffer) pageIn(maxBytes int64) int {
+	end := b.older()
+	if end == 0 {
+		return 0
+	}
+	start := end - 1
+	for start > 0 && b.offsets[end]-b.offsets[start-1] <= maxBytes {
+		start--
+	}
+	r := io.NewSectionReader(b.spill, b.offsets[start], b.offsets[end]-b.offsets[start])
+	loaded := make([]logEntry, 0, end-start)
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		var l logEntry
+		if err := json.Unmarshal(scanner.Bytes(), &l); err != nil {
+			return 0
+		}
+		loaded = append(loaded, l)
+	}
+	if scanner.Err() != nil || len(loaded) != end-start {
+		return 0
+	}
+	for _, l := range loaded {
+		b.size += len(l.Text)
+		b.paged += len(l.Text)
+	}
+	b.lines = append(loaded, b.lines...)
+	b.first = start
+	return len(loaded)
+}
+
+// release drops the entries paged in from the spill file again.
+func (b *logBuffer) release() {
+	for b.first < b.onDisk() && len(b.lines) > 1 {
+		b.evict()
+	}
+	b.paged = 0
+}
+
+// content returns the whole log as plain text, older entries from the spill file included.
+func (b *logBuffer) content() string {
+	var out []string
+	if n := b.older(); n > 0 {
+		scanner := bufio.NewScanner(io.NewSectionReader(b.spill, 0, b.offsets[n]))
+		scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+		for scanner.Scan() {
+			var l logEntry
+			if json.Unmarshal(scanner.Bytes(), &l) == nil {
+				out = append(out, l.Text)
+			}
+		}
+	}
+	for _, l := range b.lines {
+		out = append(out, l.Text)
+	}
+	return strings.Join(out, "\n")
+}
+
+// close removes the spill file.
+func (b *logBuffer) close() {
+	if b.spill != nil {
+		_ = b.spill.Close()
+		_ = os.Remove(b.spillPath)
+		b.spill = nil
+	}
+}
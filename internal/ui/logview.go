@@ -0,0 +1,132 @@
+package ui
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/asynkron/Asynkron.SwarmGo/internal/events"
+)
+
+// logWindowMargin is how many screens of an agent log are kept in the viewport above and
+// below the visible part; scrolling closer than a screen to either edge moves the window.
+const logWindowMargin = 2
+
+// logLines returns an agent's log as display lines wrapped to the viewport: the
+// instructions it received, then its entries. Each entry is rendered and wrapped once per
+// width and kept, so only new entries cost anything.
+func (m *Model) logLines(id string) []string {
+	var lines []string
+	if inj := m.renderInjections(id); inj != "" {
+		wrap := lipgloss.NewStyle().Width(m.view.Width)
+		lines = append(lines, strings.Split(wrap.Render(strings.TrimSuffix(inj, "\n")), "\n")...)
+	}
+	buf, ok := m.logs[id]
+	if !ok || len(buf.lines) == 0 {
+		return append(lines, "waiting for output...")
+	}
+	if buf.older() > 0 {
+		lines = append(lines, lipgloss.NewStyle().Foreground(m.styles.dim).Render(olderOutputNote))
+	}
+	for i := range buf.lines {
+		lines = append(lines, m.entryLines(&buf.lines[i])...)
+	}
+	if buf.lines[len(buf.lines)-1].Kind == events.MessageDo {
+		lines = append(lines, lipgloss.NewStyle().Foreground(m.styles.do).Render(m.spinner.View()+" running..."))
+	}
+	return lines
+}
+
+// olderOutputNote heads a log whose earlier output was paged out to disk.
+const olderOutputNote = "↑ earlier output is on disk; scroll up to load it"
+
+// entryLines returns a log entry rendered and wrapped to the viewport, caching both.
+func (m *Model) entryLines(l *logEntry) []string {
+	// Render markdown for all agents once (no reflow) to improve readability.
+	if l.rendered == "" {
+		var ready bool
+		l.rendered, ready = m.renderLogEntry(*l)
+		l.mdPending = !ready
+		l.wrapped = nil
+	}
+	if width := m.view.Width; l.wrapped == nil || l.wrapWidth != width {
+		l.wrapped = strings.Split(lipgloss.NewStyle().Width(width).Render(l.rendered), "\n")
+		l.wrapWidth = width
+	}
+	return l.wrapped
+}
+
+// showLog puts the part of an agent log around the current position in the viewport,
+// rather than the whole log, so long logs and wide terminals stay cheap to draw. It jumps
+// to the tail when following or switching to the log.
+func (m *Model) showLog(id string) {
+	if m.follow || id != m.viewID {
+		// Output paged in for reading goes back to disk once the tail is shown again.
+		if buf, ok := m.logs[id]; ok {
+			buf.release()
+		}
+		if buf, ok := m.logs[m.viewID]; ok && id != m.viewID {
+			buf.release()
+		}
+	}
+	lines := m.logLines(id)
+	height := max(m.view.Height, 1)
+	last := max(len(lines)-height, 0)
+	top := m.logTop + m.view.YOffset
+	if m.follow || id != m.viewID {
+		top = last
+	}
+	top = min(max(top, 0), last)
+	margin := height * logWindowMargin
+	start := max(top-margin, 0)
+	end := min(top+height+margin, len(lines))
+	m.view.SetContent(strings.Join(lines[start:end], "\n"))
+	m.view.SetYOffset(top - start)
+	m.logTop, m.logTotal = start, len(lines)
+	m.viewID = id
+}
+
+// logWindowed reports whether the viewport holds a window of an agent log.
+func (m Model) logWindowed() bool {
+	return m.viewID != "" && !isPanelID(m.viewID)
+}
+
+// refillLogWindow moves the log window once scrolling has brought the viewport within a
+// screen of the window's edge, so scrolling continues through the whole log. At the top of
+// what is in memory it pages in earlier output from disk.
+func (m *Model) refillLogWindow() {
+	if !m.logWindowed() || m.selectedID() != m.viewID {
+		return
+	}
+	height, count := m.view.Height, m.view.TotalLineCount()
+	if buf, ok := m.logs[m.viewID]; ok && m.logTop == 0 && m.view.YOffset < height && buf.older() > 0 {
+		if added := buf.pageIn(logPageBytes); added > 0 {
+			// Keep the same output in view above the newly loaded entries.
+			for i := range added {
+				m.logTop += len(m.entryLines(&buf.lines[i]))
+			}
+			if buf.older() == 0 {
+				m.logTop-- // the note about earlier output is gone
+			}
+			m.showLog(m.viewID)
+			return
+		}
+	}
+	nearTop := m.logTop > 0 && m.view.YOffset < height
+	nearEnd := m.logTop+count < m.logTotal && m.view.YOffset+2*height > count
+	if nearTop || nearEnd {
+		m.showLog(m.viewID)
+	}
+}
+
+// scrollPercent is how far the viewport is scrolled through the whole log or panel.
+func (m Model) scrollPercent() float64 {
+	if !m.logWindowed() {
+		return m.view.ScrollPercent()
+	}
+	last := m.logTotal - m.view.Height
+	if last <= 0 {
+		return 1
+	}
+	return min(max(float64(m.logTop+m.view.YOffset)/float64(last), 0), 1)
+}
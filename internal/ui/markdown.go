@@ -0,0 +1,105 @@
+package ui
+
+import (
+	"runtime"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/glamour"
+	"github.com/charmbracelet/lipgloss"
+)
+
+const (
+	// markdownMaxLen is the longest text rendered as markdown; longer text is shown as is.
+	markdownMaxLen = 8000
+	// markdownCacheLimit bounds the rendered texts kept; the cache is emptied past it.
+	markdownCacheLimit = 2000
+	// markdownQueue is how many texts may wait for a renderer.
+	markdownQueue = 256
+)
+
+// markdownPool renders markdown on background goroutines so glamour never runs in
+// Update or View. Lookups of texts not rendered yet queue them and return false; each
+// finished render arrives as a markdownRenderedMsg, which stores it and refreshes the
+// view. The cache and pending set are only touched from the UI goroutine.
+type markdownPool struct {
+	jobs    chan string
+	results chan markdownRenderedMsg
+	cache   map[string]string
+	pending map[string]bool
+}
+
+// markdownRenderedMsg delivers the rendering of text.
+type markdownRenderedMsg struct {
+	text     string
+	rendered string
+}
+
+func newMarkdownPool() *markdownPool {
+	p := &markdownPool{
+		jobs:    make(chan string, markdownQueue),
+		results: make(chan markdownRenderedMsg, markdownQueue),
+		cache:   make(map[string]string),
+		pending: make(map[string]bool),
+	}
+	// Resolve the style once, before the UI owns the terminal, rather than letting every
+	// renderer query the background colour.
+	style := glamour.LightStyle
+	if lipgloss.HasDarkBackground() {
+		style = glamour.DarkStyle
+	}
+	for range max(runtime.NumCPU()/2, 1) {
+		go p.work(style)
+	}
+	return p
+}
+
+// work renders queued texts with its own renderer, as one isn't safe for concurrent use.
+func (p *markdownPool) work(style string) {
+	r, err := glamour.NewTermRenderer(
+		glamour.WithStandardStyle(style),
+		glamour.WithWordWrap(0), // no reflow; render once per entry
+	)
+	for text := range p.jobs {
+		rendered := text
+		if err == nil {
+			if out, err := r.Render(text); err == nil {
+				rendered = strings.TrimRight(out, "\n")
+			}
+		}
+		p.results <- markdownRenderedMsg{text: text, rendered: rendered}
+	}
+}
+
+// lookup returns the rendering of text and true once it is done. Until then it queues
+// text, unless it already is, and returns the text itself. A full queue leaves text to be
+// queued by a later lookup.
+func (p *markdownPool) lookup(text string) (string, bool) {
+	if out, ok := p.cache[text]; ok {
+		return out, true
+	}
+	if !p.pending[text] {
+		select {
+		case p.jobs <- text:
+			p.pending[text] = true
+		default:
+		}
+	}
+	return text, false
+}
+
+// store records a finished rendering.
+func (p *markdownPool) store(msg markdownRenderedMsg) {
+	delete(p.pending, msg.text)
+	if len(p.cache) >= markdownCacheLimit {
+		clear(p.cache)
+	}
+	p.cache[msg.text] = msg.rendered
+}
+
+// wait returns a command that delivers the next finished rendering.
+func (p *markdownPool) wait() tea.Cmd {
+	return func() tea.Msg {
+		return <-p.results
+	}
+}
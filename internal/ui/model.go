@@ -6,21 +6,23 @@ import (
 	"math"
 	"os"
 	"path/filepath"
+	"slices"
 	"sort"
 	"strings"
-	"sync"
 	"time"
 	"unicode"
 
 	"github.com/asynkron/Asynkron.SwarmGo/internal/config"
 	"github.com/asynkron/Asynkron.SwarmGo/internal/control"
+	"github.com/asynkron/Asynkron.SwarmGo/internal/disk"
 	"github.com/asynkron/Asynkron.SwarmGo/internal/events"
+	"github.com/asynkron/Asynkron.SwarmGo/internal/pricing"
+	"github.com/asynkron/Asynkron.SwarmGo/internal/prstatus"
 	"github.com/asynkron/Asynkron.SwarmGo/internal/session"
 	"github.com/charmbracelet/bubbles/spinner"
 	"github.com/charmbracelet/bubbles/textarea"
 	"github.com/charmbracelet/bubbles/viewport"
 	tea "github.com/charmbracelet/bubbletea"
-	"github.com/charmbracelet/glamour"
 	"github.com/charmbracelet/lipgloss"
 )
 
@@ -34,13 +36,31 @@ type Model struct {
 	width        int
 	height       int
 	phase        string
+	round        events.RoundChanged
 	remaining    time.Duration
+	deadline     time.Time
+	sleptOffer   time.Duration
+	todoDone     int
+	todoTotal    int
+	disk         events.DiskUsage
+	prs          map[string]events.PRStatus
+	injections   map[string][]events.PromptInjected
+	pending      map[string]events.PendingPR
+	scratchpad   *scratchpadView
+	verdict      *events.Verdict
+	quota        map[string]*quotaEntry
 	status       []string
+	history      []statusEntry
+	historyQuery string
+	searching    bool
 	selected     int
 	itemOrder    []string // "session", "todo", agent IDs
 	agents       map[string]*agentView
 	logs         map[string]*logBuffer
 	statuses     map[string]events.StatusSnapshot
+	prices       pricing.Table
+	// removedCost keeps the spend of agents removed from the list in the session total.
+	removedCost  float64
 	todoPath     string
 	todo         string
 	view         viewport.Model
@@ -48,40 +68,60 @@ type Model struct {
 	ready        bool
 	styles       theme
 	listWidth    int
-	mouseOverLog bool
 	mouseEnabled bool
 	hasCoded     bool
 	eventsClosed bool
 	pendingView  bool
-	followTail   bool
-	mdRenderer   *glamour.TermRenderer
-	mdMu         sync.Mutex
+	follow       bool
+	viewID       string
+	// logTop is the log line the viewport's content starts at and logTotal the log's line
+	// count, when it holds a window of an agent log.
+	logTop       int
+	logTotal     int
+	markdown     *markdownPool
 	todoCache    todoCache
 	codedCache   codedCache
 	listView     viewport.Model
 	inputActive  bool
 	inputField   textarea.Model
 	inputTarget  string
+	confirmQuit  bool
+	shuttingDown bool
+	replay       bool
 }
 
 type agentView struct {
-	ID       string
-	Name     string
-	Kind     string
-	Model    string
-	LogPath  string
-	Running  bool
-	ExitCode int
-	Spinner  int
-}
-
-type logBuffer struct {
-	lines []logEntry
-	limit int
-	// cached rendered content to avoid re-rendering large logs on every selection
-	rendered string
-	dirty    bool
-	doMode   bool
+	ID        string
+	Name      string
+	Kind      string
+	Model     string
+	LogPath   string
+	Worktree  string
+	Running   bool
+	Stopping  bool
+	ExitCode  int
+	Spinner   int
+	Restarts  int
+	StartedAt time.Time
+	StoppedAt time.Time
+	TokensIn  int
+	TokensOut int
+	// CostUSD is the estimated spend so far, from the CLI's own figures or the pricing table.
+	CostUSD float64
+	// Stalled is set when the orchestrator reports no output for a while and cleared by the next line.
+	Stalled bool
+	// LastActive is when the agent last wrote to its log, from the last heartbeat.
+	LastActive time.Time
+	// RateLimited is set when the agent hit a provider rate limit, until its next start.
+	// RateLimitedUntil is when the cooldown ends, if known.
+	RateLimited      bool
+	RateLimitedUntil time.Time
+	// Denied counts the commands of the agent that matched the denylist.
+	Denied int
+	// Overlaps lists the other workers modifying the same files as this one.
+	Overlaps []string
+	// Duplicates lists the other workers that appear to work on the same task as this one.
+	Duplicates []string
 }
 
 type todoCache struct {
@@ -98,18 +138,18 @@ type codedCache struct {
 
 var spinnerFrames = []string{"⠋", "⠙", "⠹", "⠸", "⠼", "⠴", "⠦", "⠧", "⠇", "⠏"}
 
-const (
-	logBufferLimit        = 300
-	markdownRenderTimeout = 75 * time.Millisecond
-	markdownRenderBudget  = 150 * time.Millisecond
-)
-
 type logEntry struct {
 	Kind events.AgentMessageKind
 	Text string
+	// Diff marks tool output that belongs to a unified diff.
+	Diff bool
 	// cached render to avoid re-rendering on scroll
-	rendered       string
-	renderMarkdown bool
+	rendered string
+	// mdPending marks a render that shows plain text while its markdown is being rendered.
+	mdPending bool
+	// wrapped is rendered wrapped to wrapWidth and split into display lines.
+	wrapped   []string
+	wrapWidth int
 }
 
 // New returns a ready-to-run UI model.
@@ -124,7 +164,7 @@ func New(sess *session.Session, opts config.Options, eventCh <-chan events.Event
 
 	ti := textarea.New()
 	ti.Prompt = ""
-	ti.Placeholder = "Enter note (Enter=send, Alt+Enter=newline, Esc=cancel)"
+	ti.Placeholder = notePlaceholder
 	ti.SetWidth(view.Width)
 	ti.SetHeight(5)
 	ti.ShowLineNumbers = false
@@ -134,10 +174,15 @@ func New(sess *session.Session, opts config.Options, eventCh <-chan events.Event
 		session:      sess,
 		opts:         opts,
 		events:       eventCh,
-		itemOrder:    []string{"session", "todo", "coded"},
+		itemOrder:    []string{"session", "todo", "coded", "status"},
 		agents:       make(map[string]*agentView),
 		logs:         make(map[string]*logBuffer),
 		statuses:     make(map[string]events.StatusSnapshot),
+		prices:       pricing.Default.With(opts.Pricing),
+		prs:          make(map[string]events.PRStatus),
+		injections:   make(map[string][]events.PromptInjected),
+		pending:      make(map[string]events.PendingPR),
+		quota:        make(map[string]*quotaEntry),
 		view:         view,
 		styles:       theme,
 		mouseEnabled: true,
@@ -146,6 +191,8 @@ func New(sess *session.Session, opts config.Options, eventCh <-chan events.Event
 		control:      control,
 		inputField:   ti,
 		listView:     listView,
+		markdown:     newMarkdownPool(),
+		follow:       true,
 	}
 	// Default to showing the todo panel first so something useful is visible.
 	if len(m.itemOrder) > 1 {
@@ -153,6 +200,8 @@ func New(sess *session.Session, opts config.Options, eventCh <-chan events.Event
 	} else {
 		m.selected = 0
 	}
+	m.loadHistory()
+	m.loadInjections()
 	m.width = 80
 	m.height = 24
 	m.resize()
@@ -160,8 +209,19 @@ func New(sess *session.Session, opts config.Options, eventCh <-chan events.Event
 	return m
 }
 
+// NewReplay returns a read-only UI model that renders a recorded event stream.
+// Agent controls are disabled and nothing is written back to the session.
+func NewReplay(sess *session.Session, opts config.Options, eventCh <-chan events.Event) Model {
+	m := New(sess, opts, eventCh, nil)
+	m.replay = true
+	m.history = nil
+	// The recorded stream carries the injections again.
+	m.injections = make(map[string][]events.PromptInjected)
+	return m
+}
+
 func (m Model) Init() tea.Cmd {
-	return tea.Batch(waitForEvent(m.events), tea.EnableMouseCellMotion, spinner.Tick)
+	return tea.Batch(waitForEvent(m.events), m.markdown.wait(), tea.EnableMouseCellMotion, spinner.Tick)
 }
 
 func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
@@ -177,7 +237,6 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		if !m.mouseEnabled {
 			break
 		}
-		m.mouseOverLog = msg.X > m.listWidth
 		switch msg.Type {
 		case tea.MouseWheelUp:
 			if msg.X <= m.listWidth {
@@ -188,9 +247,9 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				skipViewport = true
 			} else {
 				m.view.LineUp(3)
+				m.pauseFollow()
 				skipViewport = true
 			}
-			m.followTail = m.view.AtBottom()
 		case tea.MouseWheelDown:
 			if msg.X <= m.listWidth {
 				if m.selected < len(m.itemOrder)-1 {
@@ -202,12 +261,30 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				m.view.LineDown(3)
 				skipViewport = true
 			}
-			m.followTail = m.view.AtBottom()
 		case tea.MouseMotion:
 			// update hover only
 			skipViewport = true
 		}
 	case tea.KeyMsg:
+		if m.shuttingDown {
+			if m.eventsClosed || msg.String() == "ctrl+c" {
+				return m, tea.Quit
+			}
+			return m, nil
+		}
+		if m.confirmQuit {
+			switch msg.String() {
+			case "y", "Y", "enter", "q", "ctrl+c":
+				return m, m.beginShutdown()
+			case "n", "N", "esc":
+				m.confirmQuit = false
+			}
+			return m, nil
+		}
+		if m.searching {
+			m.updateSearch(msg)
+			return m, nil
+		}
 		if m.inputActive {
 			switch {
 			case msg.Type == tea.KeyEsc:
@@ -218,6 +295,34 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			case msg.Type == tea.KeyEnter && msg.Alt:
 				m.inputField.SetValue(m.inputField.Value() + "\n")
 				return m, nil
+			case m.inputTarget == broadcastTarget && (msg.Type == tea.KeyCtrlS || msg.Type == tea.KeyEnter):
+				value := m.inputField.Value()
+				m.inputActive = false
+				m.inputTarget = ""
+				m.inputField.Reset()
+				if m.control != nil && strings.TrimSpace(value) != "" {
+					go func() { m.control <- control.Broadcast{Message: value} }()
+					m.addStatus("Broadcast queued for all running workers")
+				}
+				return m, nil
+			case m.inputTarget == supervisorTarget && msg.Type == tea.KeyEnter:
+				value := m.inputField.Value()
+				m.inputActive = false
+				m.inputTarget = ""
+				m.inputField.Reset()
+				m.swapSupervisor(value)
+				return m, nil
+			case msg.Type == tea.KeyCtrlS:
+				target := m.inputTarget
+				value := m.inputField.Value()
+				m.inputActive = false
+				m.inputTarget = ""
+				m.inputField.Reset()
+				if target != "" && m.control != nil && strings.TrimSpace(value) != "" {
+					go func() { m.control <- control.SendMessage{AgentID: target, Text: value} }()
+					m.addStatus(fmt.Sprintf("Message queued for %s", target))
+				}
+				return m, nil
 			case msg.Type == tea.KeyEnter:
 				target := m.inputTarget
 				value := m.inputField.Value()
@@ -226,8 +331,7 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				m.inputField.Reset()
 				if target != "" && m.control != nil {
 					go func() { m.control <- control.RestartAgent{AgentID: target, Message: value} }()
-					m.status = append(m.status, fmt.Sprintf("Restart requested for %s", target))
-					m.trimStatus()
+					m.addStatus(fmt.Sprintf("Restart requested for %s", target))
 				}
 				return m, nil
 			}
@@ -237,29 +341,75 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 		switch msg.String() {
 		case "q", "ctrl+c":
-			return m, tea.Quit
+			if m.replay || m.runningAgents() == 0 {
+				return m, tea.Quit
+			}
+			m.confirmQuit = true
+			return m, nil
 		case "up", "k":
 			if m.selected > 0 {
 				m.selected--
-				m.followTail = m.isLogSelection()
 				skipViewport = true
 			}
 		case "down", "j":
 			if m.selected < len(m.itemOrder)-1 {
 				m.selected++
-				m.followTail = m.isLogSelection()
 				skipViewport = true
 			}
 		case "pgup":
 			m.view.LineUp(10)
-			m.followTail = m.view.AtBottom()
+			m.pauseFollow()
 		case "pgdown":
 			m.view.LineDown(10)
-			m.followTail = m.view.AtBottom()
+		case "f":
+			// The viewport binds "f" to page down; handle it here only.
+			m.follow = !m.follow
+			if m.follow {
+				m.view.GotoBottom()
+			}
+			skipViewport = true
+		case "/":
+			if m.selectedID() == "status" {
+				m.searching = true
+				skipViewport = true
+			}
+		case "esc":
+			if m.selectedID() == "status" && m.historyQuery != "" {
+				m.historyQuery = ""
+				m.updateViewport()
+			}
+		case "e":
+			m.exportSelected()
+			skipViewport = true
 		case " ":
 			m.toggleAgent()
+		case "t":
+			m.takeOverAgent()
+		case "o":
+			cmds = append(cmds, m.openWorktree())
+			skipViewport = true
+		case "!":
+			cmds = append(cmds, m.openShell())
+			skipViewport = true
+		case "a":
+			m.decidePR(true)
+		case "x":
+			m.decidePR(false)
 		case "enter":
 			m.startInjectPrompt()
+		case "b":
+			m.startBroadcastPrompt()
+		case "s":
+			m.startSwapSupervisorPrompt()
+		case "+", "=":
+			m.adjustTime(timeStep)
+		case "-":
+			m.adjustTime(-timeStep)
+		case "w":
+			if m.sleptOffer > 0 {
+				m.adjustTime(m.sleptOffer)
+				m.sleptOffer = 0
+			}
 		case "m":
 			m.mouseEnabled = !m.mouseEnabled
 			if m.mouseEnabled {
@@ -277,17 +427,26 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 	case eventsClosedMsg:
 		m.eventsClosed = true
+	case takeoverDoneMsg:
+		m.handBack(msg)
+	case execDoneMsg:
+		if msg.err != nil {
+			m.addStatus(fmt.Sprintf("%s exited: %v", msg.name, msg.err))
+		}
 	case spinner.TickMsg:
 		var cmd tea.Cmd
 		m.spinner, cmd = m.spinner.Update(msg)
 		if m.anyPendingDo() {
-			m.markDoBuffersDirty()
 			m.updateViewport()
 		}
 		return m, cmd
 	case viewportUpdateMsg:
 		m.pendingView = false
 		m.updateViewport()
+	case markdownRenderedMsg:
+		m.markdown.store(msg)
+		m.markMarkdownStale()
+		return m, tea.Batch(m.markdown.wait(), m.requestViewportUpdate())
 	}
 
 	if !m.eventsClosed {
@@ -295,17 +454,19 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	}
 	var cmd tea.Cmd
 	if !skipViewport {
+		offset := m.view.YOffset
 		m.view, cmd = m.view.Update(msg)
 		cmds = append(cmds, cmd)
-	}
-	if !skipViewport && m.isLogSelection() {
-		m.followTail = m.view.AtBottom()
+		if m.view.YOffset < offset {
+			m.pauseFollow()
+		}
 	}
 	if skipViewport {
 		if c := m.requestViewportUpdate(); c != nil {
 			cmds = append(cmds, c)
 		}
 	}
+	m.refillLogWindow()
 	return m, tea.Batch(cmds...)
 }
 
@@ -315,6 +476,10 @@ func (m Model) View() string {
 		return lipgloss.NewStyle().Width(m.width).Height(m.height).Align(lipgloss.Center).Render(msg)
 	}
 
+	if m.shuttingDown {
+		return m.renderShutdown()
+	}
+
 	header := m.renderHeader()
 	list := m.renderList()
 	log := m.renderLog()
@@ -324,39 +489,72 @@ func (m Model) View() string {
 	if m.inputActive {
 		body = lipgloss.JoinHorizontal(lipgloss.Top, list, m.renderInputOverlay())
 	}
+	if m.confirmQuit {
+		body = lipgloss.JoinHorizontal(lipgloss.Top, list, m.renderConfirmQuit())
+	}
 	return lipgloss.JoinVertical(lipgloss.Left, header, body, status)
 }
 
 func (m *Model) handleEvent(ev events.Event) (Model, tea.Cmd) {
 	switch e := ev.(type) {
+	case events.AgentTakeover:
+		return *m, m.attach(e)
+	case events.PromptInjected:
+		m.injections[e.ID] = append(m.injections[e.ID], e)
+		if m.selectedID() == e.ID {
+			m.updateViewport()
+		}
 	case events.AgentAdded:
 		running := e.Running
 		if ag, exists := m.agents[e.ID]; exists {
+			if running && !ag.Running {
+				// Fresh start after a stop or restart; reset the elapsed clock.
+				ag.StartedAt = time.Now()
+				ag.StoppedAt = time.Time{}
+				ag.RateLimited = false
+				ag.RateLimitedUntil = time.Time{}
+			}
 			ag.Name = e.Name
 			ag.Kind = e.Kind
 			ag.Model = e.Model
 			ag.LogPath = e.LogPath
+			ag.Worktree = e.Worktree
 			ag.Running = running
+			ag.Restarts = e.Restarts
+			ag.Stalled = false
+			ag.LastActive = time.Time{}
 		} else {
-			m.agents[e.ID] = &agentView{
-				ID:      e.ID,
-				Name:    e.Name,
-				Kind:    e.Kind,
-				Model:   e.Model,
-				LogPath: e.LogPath,
-				Running: running,
+			ag := &agentView{
+				ID:       e.ID,
+				Name:     e.Name,
+				Kind:     e.Kind,
+				Model:    e.Model,
+				LogPath:  e.LogPath,
+				Worktree: e.Worktree,
+				Running:  running,
+				Restarts: e.Restarts,
 			}
+			if running {
+				ag.StartedAt = time.Now()
+			}
+			m.agents[e.ID] = ag
 			m.itemOrder = append(m.itemOrder, e.ID)
 		}
 		status := "Started"
 		if !running {
 			status = "Ready"
 		}
-		m.status = append(m.status, fmt.Sprintf("%s %s (%s)", status, e.Name, e.Kind))
+		m.addStatus(fmt.Sprintf("%s %s (%s)", status, e.Name, e.Kind))
 		m.ensureLog(e.ID)
 		m.updateViewport()
 	case events.AgentRemoved:
+		if ag, ok := m.agents[e.ID]; ok {
+			m.removedCost += ag.CostUSD
+		}
 		delete(m.agents, e.ID)
+		if buf, ok := m.logs[e.ID]; ok {
+			buf.close()
+		}
 		delete(m.logs, e.ID)
 		delete(m.statuses, e.ID)
 		m.rebuildOrder()
@@ -364,50 +562,159 @@ func (m *Model) handleEvent(ev events.Event) (Model, tea.Cmd) {
 	case events.AgentStopped:
 		if ag, ok := m.agents[e.ID]; ok {
 			ag.Running = false
+			ag.Stopping = false
+			ag.Stalled = false
 			ag.ExitCode = e.ExitCode
+			ag.StoppedAt = time.Now()
 		}
-		m.status = append(m.status, fmt.Sprintf("%s exited (%d)", e.ID, e.ExitCode))
+		m.addStatus(fmt.Sprintf("%s exited (%d)", e.ID, e.ExitCode))
 	case events.AgentStatus:
 		m.statuses[e.ID] = e.Snapshot
 		m.updateViewport()
 	case events.AgentLine:
-		if ag, ok := m.agents[e.ID]; ok {
-			ag.Spinner = (ag.Spinner + 1) % len(spinnerFrames)
-		}
-		buf := m.ensureLog(e.ID)
-		kind := e.Kind
-		selected := m.selected < len(m.itemOrder) && m.itemOrder[m.selected] == e.ID
-		if selected && !m.view.AtBottom() {
-			m.followTail = false
-		}
-		if ag, ok := m.agents[e.ID]; ok && ag.Kind == "Codex" {
-			switch strings.TrimSpace(e.Line) {
-			case "[exec]":
-				buf.doMode = true
-			case "[thinking]":
-				buf.doMode = false
-			}
-			if buf.doMode {
-				kind = events.MessageDo
-			}
-		}
-		trimmed := buf.append(logEntry{Kind: kind, Text: e.Line})
-		if trimmed && m.selected < len(m.itemOrder) && m.itemOrder[m.selected] == e.ID {
-			m.clampViewport()
+		m.appendLine(e)
+		if c := m.requestViewportUpdate(); c != nil {
+			return *m, c
 		}
-		if selected && !m.mouseOverLog && m.followTail {
-			m.view.GotoBottom()
+	case events.AgentLines:
+		for _, line := range e.Lines {
+			m.appendLine(line)
 		}
 		if c := m.requestViewportUpdate(); c != nil {
 			return *m, c
 		}
 	case events.StatusMessage:
-		m.status = append(m.status, e.Message)
+		m.addStatus(e.Message)
 	case events.PhaseChanged:
 		m.phase = e.Phase
-		m.status = append(m.status, e.Phase)
+		m.addStatus(e.Phase)
+	case events.RoundChanged:
+		m.round = e
+		m.addStatus(fmt.Sprintf("Round %d of %d", e.Current, e.Total))
 	case events.RemainingTime:
-		m.remaining = e.Duration
+		m.remaining, m.deadline = e.Duration, e.Deadline
+	case events.Slept:
+		m.sleptOffer = 0
+		if !e.Extended {
+			m.sleptOffer = e.Offer
+		}
+	case events.AgentUsage:
+		if ag, ok := m.agents[e.ID]; ok {
+			ag.TokensIn += e.InputTokens + e.CacheReadTokens + e.CacheWriteTokens
+			ag.TokensOut += e.OutputTokens
+			usage := pricing.Usage{
+				InputTokens:      e.InputTokens,
+				OutputTokens:     e.OutputTokens,
+				CacheReadTokens:  e.CacheReadTokens,
+				CacheWriteTokens: e.CacheWriteTokens,
+				CostUSD:          e.CostUSD,
+			}
+			if cost, ok := m.prices.Estimate(usage, e.Model, strings.ToLower(ag.Kind)); ok {
+				ag.CostUSD += cost
+			}
+		}
+	case events.AgentStartFailed:
+		path := e.Path
+		if path == "" {
+			path = "not on PATH"
+		}
+		m.addStatus(fmt.Sprintf("%s failed to start (attempt %d/%d): %s [%s: %s, workdir %s]", e.ID, e.Attempt, e.Attempts, e.Error, e.Command, path, e.Workdir))
+	case events.AgentHeartbeat:
+		if ag, ok := m.agents[e.ID]; ok && ag.Running {
+			ag.LastActive = e.LastActive
+		}
+	case events.AgentStalled:
+		if ag, ok := m.agents[e.ID]; ok {
+			ag.Stalled = true
+		}
+		m.addStatus(fmt.Sprintf("%s stalled: no output for %s", e.ID, e.Idle))
+	case events.RateLimited:
+		if ag, ok := m.agents[e.ID]; ok {
+			ag.RateLimited = true
+			if e.Until.After(ag.RateLimitedUntil) {
+				ag.RateLimitedUntil = e.Until
+			}
+		}
+		// The orchestrator re-announces the limit with its restart time and no message.
+		if e.Message != "" {
+			m.addStatus(fmt.Sprintf("%s rate limited: %s", e.ID, e.Message))
+			m.recordQuota(e.ID, e.Message, true)
+		}
+	case events.CommandDenied:
+		// The orchestrator's status message carries the alert; the list keeps the count.
+		if ag, ok := m.agents[e.ID]; ok {
+			ag.Denied++
+		}
+	case events.FileConflict:
+		// The orchestrator's status message carries the warning; the rows name the overlap.
+		for i, id := range e.IDs {
+			ag, ok := m.agents[id]
+			if !ok {
+				continue
+			}
+			for j, other := range e.IDs {
+				if j != i && !slices.Contains(ag.Overlaps, other) {
+					ag.Overlaps = append(ag.Overlaps, other)
+				}
+			}
+		}
+	case events.DuplicateTask:
+		// As with file conflicts, the status message carries the warning.
+		for i, id := range e.IDs {
+			ag, ok := m.agents[id]
+			if !ok {
+				continue
+			}
+			for j, other := range e.IDs {
+				if j != i && !slices.Contains(ag.Duplicates, other) {
+					ag.Duplicates = append(ag.Duplicates, other)
+				}
+			}
+		}
+	case events.QuotaWarning:
+		m.recordQuota(e.ID, e.Message, false)
+		m.addStatus(fmt.Sprintf("%s usage warning: %s", e.ID, e.Message))
+	case events.DiskUsage:
+		m.disk = e
+	case events.PendingPR:
+		_, known := m.pending[e.ID]
+		m.pending[e.ID] = e
+		if !known {
+			m.rebuildOrder()
+		}
+		if c := m.requestViewportUpdate(); c != nil {
+			return *m, c
+		}
+	case events.PRStatus:
+		_, known := m.prs[e.ID]
+		m.prs[e.ID] = e
+		if !known {
+			m.rebuildOrder()
+		}
+		if c := m.requestViewportUpdate(); c != nil {
+			return *m, c
+		}
+	case events.TodoProgress:
+		m.todoDone = e.Done
+		m.todoTotal = e.Total
+	case events.ScratchpadChanged:
+		known := m.scratchpad != nil
+		m.scratchpad = &scratchpadView{ScratchpadChanged: e}
+		if !known {
+			m.rebuildOrder()
+		}
+		if c := m.requestViewportUpdate(); c != nil {
+			return *m, c
+		}
+	case events.Verdict:
+		known := m.verdict != nil
+		m.verdict = &e
+		if !known {
+			m.rebuildOrder()
+		}
+		if c := m.requestViewportUpdate(); c != nil {
+			return *m, c
+		}
 	case events.TodoLoaded:
 		m.todo = e.Content
 		m.todoPath = e.Path
@@ -417,25 +724,77 @@ func (m *Model) handleEvent(ev events.Event) (Model, tea.Cmd) {
 		}
 	}
 
-	m.trimStatus()
 	m.ready = true
 	return *m, nil
 }
 
+// appendLine adds an agent's output line to its log buffer, keeping the viewport at the
+// bottom when it follows that agent.
+func (m *Model) appendLine(e events.AgentLine) {
+	if ag, ok := m.agents[e.ID]; ok {
+		ag.Spinner = (ag.Spinner + 1) % len(spinnerFrames)
+		ag.Stalled = false
+	}
+	buf := m.ensureLog(e.ID)
+	kind := e.Kind
+	selected := m.selected < len(m.itemOrder) && m.itemOrder[m.selected] == e.ID
+	if ag, ok := m.agents[e.ID]; ok && ag.Kind == "Codex" {
+		switch strings.TrimSpace(e.Line) {
+		case "[exec]":
+			buf.doMode = true
+		case "[thinking]":
+			buf.doMode = false
+		}
+		if buf.doMode {
+			kind = events.MessageDo
+		}
+	}
+	if kind == events.MessageSee {
+		if isDiffStart(e.Line) {
+			buf.diffMode = true
+		}
+	} else {
+		buf.diffMode = false
+	}
+	trimmed := buf.append(logEntry{Kind: kind, Text: e.Line, Diff: kind == events.MessageSee && buf.diffMode})
+	if trimmed && m.selected < len(m.itemOrder) && m.itemOrder[m.selected] == e.ID {
+		m.clampViewport()
+	}
+	if selected && m.follow {
+		m.view.GotoBottom()
+	}
+}
+
 func (m *Model) ensureLog(id string) *logBuffer {
 	if buf, ok := m.logs[id]; ok {
 		return buf
 	}
-	buf := &logBuffer{limit: logBufferLimit}
+	buf := newLogBuffer(filepath.Join(m.session.ScrollbackDir(), id+".jsonl"))
 	m.logs[id] = buf
 	return buf
 }
 
 func (m *Model) rebuildOrder() {
 	order := []string{"session", "todo"}
+	if m.scratchpad != nil {
+		order = append(order, "scratchpad")
+	}
 	if m.hasCoded {
 		order = append(order, "coded")
 	}
+	if m.verdict != nil {
+		order = append(order, "verdict")
+	}
+	if len(m.pending) > 0 {
+		order = append(order, "pending")
+	}
+	if len(m.prs) > 0 {
+		order = append(order, "prs")
+	}
+	if len(m.quota) > 0 {
+		order = append(order, "quota")
+	}
+	order = append(order, "status")
 	for id := range m.agents {
 		order = append(order, id)
 	}
@@ -478,20 +837,36 @@ func (m *Model) updateViewport() {
 		m.view.SetContent(style.Render(content))
 	case "todo":
 		m.view.SetContent(style.Render(m.renderTodo()))
+	case "scratchpad":
+		m.view.SetContent(style.Render(m.renderScratchpad()))
 	case "coded":
 		m.view.SetContent(style.Render(m.renderMetrics()))
+	case "verdict":
+		m.view.SetContent(style.Render(m.renderVerdict()))
+	case "pending":
+		m.view.SetContent(style.Render(m.renderPending()))
+	case "prs":
+		m.view.SetContent(style.Render(m.renderPRs()))
+	case "quota":
+		m.view.SetContent(style.Render(m.renderQuota()))
+	case "status":
+		m.view.SetContent(style.Render(m.renderHistory()))
 	default:
-		if buf, ok := m.logs[id]; ok {
-			m.view.SetContent(style.Render(m.renderAgentLog(id, buf)))
-		} else {
-			m.view.SetContent(style.Render("waiting for output..."))
-		}
+		m.showLog(id)
+		return
 	}
 	m.clampViewport()
-	// Always jump to bottom when switching selection to match log tail expectation.
-	if id != "session" && id != "todo" && id != "coded" {
+	// Jump to the tail when switching to a log, and keep it pinned while following.
+	if (id == "status" || !isPanelID(id)) && (m.follow || id != m.viewID) {
 		m.view.GotoBottom()
-		m.followTail = true
+	}
+	m.viewID = id
+}
+
+// pauseFollow turns off follow mode once the user scrolls away from the tail.
+func (m *Model) pauseFollow() {
+	if m.follow && !m.view.AtBottom() {
+		m.follow = false
 	}
 }
 
@@ -499,8 +874,14 @@ func (m Model) renderHeader() string {
 	session := lipgloss.NewStyle().Bold(true).Foreground(m.styles.header).Render("SWARM")
 	id := lipgloss.NewStyle().Foreground(m.styles.dim).Render(m.session.ID)
 	mode := ""
-	if m.opts.Arena {
-		mode = lipgloss.NewStyle().Foreground(m.styles.accent).Render("Arena")
+	if m.replay {
+		mode = lipgloss.NewStyle().Foreground(m.styles.accent).Render("Replay")
+	} else if m.opts.Arena {
+		label := "Arena"
+		if m.round.Total > 0 {
+			label = fmt.Sprintf("Arena %d/%d", m.round.Current, m.round.Total)
+		}
+		mode = lipgloss.NewStyle().Foreground(m.styles.accent).Render(label)
 	} else if m.opts.Autopilot {
 		mode = lipgloss.NewStyle().Foreground(m.styles.accent).Render("Autopilot")
 	}
@@ -508,6 +889,10 @@ func (m Model) renderHeader() string {
 	if m.remaining > 0 {
 		timeText = lipgloss.NewStyle().Foreground(m.styles.accent).Render(m.remaining.Round(time.Second).String())
 	}
+	progress := ""
+	if m.todoTotal > 0 {
+		progress = m.renderTodoProgress()
+	}
 	phase := ""
 	if m.phase != "" {
 		phase = lipgloss.NewStyle().Foreground(m.styles.dim).Render(m.phase)
@@ -519,10 +904,51 @@ func (m Model) renderHeader() string {
 	if timeText != "" {
 		parts = append(parts, timeText)
 	}
+	if progress != "" {
+		parts = append(parts, progress)
+	}
+	if m.disk.Bytes > 0 {
+		parts = append(parts, m.renderDiskUsage())
+	}
+	if cost := m.totalCost(); cost > 0 {
+		parts = append(parts, lipgloss.NewStyle().Foreground(m.styles.dim).Render("~"+pricing.Format(cost)))
+	}
 	if phase != "" {
 		parts = append(parts, phase)
 	}
-	return strings.Join(parts, "  ")
+	// Clip rather than wrap: the layout below counts the header as one line.
+	return lipgloss.NewStyle().MaxWidth(m.width).Render(strings.Join(parts, "  "))
+}
+
+// totalCost is the estimated spend of every agent in the session so far.
+func (m Model) totalCost() float64 {
+	total := m.removedCost
+	for _, ag := range m.agents {
+		total += ag.CostUSD
+	}
+	return total
+}
+
+// renderDiskUsage shows the session directory size, highlighted past --disk-warn and in the
+// error colour past --disk-limit.
+func (m Model) renderDiskUsage() string {
+	color := m.styles.dim
+	switch {
+	case m.disk.Limit > 0 && m.disk.Bytes >= m.disk.Limit:
+		color = m.styles.error
+	case m.disk.Warn > 0 && m.disk.Bytes >= m.disk.Warn:
+		color = m.styles.accent
+	}
+	return lipgloss.NewStyle().Foreground(color).Render("disk " + disk.FormatSize(m.disk.Bytes))
+}
+
+func (m Model) renderTodoProgress() string {
+	const barWidth = 10
+	filled := m.todoDone * barWidth / m.todoTotal
+	bar := lipgloss.NewStyle().Foreground(m.styles.running).Render(strings.Repeat("█", filled)) +
+		lipgloss.NewStyle().Foreground(m.styles.dim).Render(strings.Repeat("░", barWidth-filled))
+	count := lipgloss.NewStyle().Foreground(m.styles.dim).Render(fmt.Sprintf("%d/%d", m.todoDone, m.todoTotal))
+	return bar + " " + count
 }
 
 func (m Model) renderList() string {
@@ -538,12 +964,36 @@ func (m Model) renderList() string {
 			rows = append(rows, m.renderRow("Session", m.session.ID, selected, ""))
 		case "todo":
 			rows = append(rows, m.renderRow("Todo", m.opts.Todo, selected, ""))
+		case "scratchpad":
+			rows = append(rows, m.renderRow("Scratchpad", m.scratchpadSummary(), selected, ""))
 		case "coded":
 			rows = append(rows, m.renderRow("Metrics", filepath.Base(m.session.CodedSupervisorPath()), selected, ""))
+		case "verdict":
+			rows = append(rows, m.renderRow("Verdict", m.verdictWinner(*m.verdict), selected, ""))
+		case "pending":
+			rows = append(rows, m.renderRow("Pending PRs", m.pendingSummary(), selected, ""))
+		case "prs":
+			rows = append(rows, m.renderRow("PRs", m.prSummary(), selected, ""))
+		case "quota":
+			rows = append(rows, m.renderRow("Quota", m.quotaSummary(), selected, ""))
+		case "status":
+			rows = append(rows, m.renderRow("Status", fmt.Sprintf("%d entries", len(m.history)), selected, ""))
 		default:
 			ag := m.agents[id]
 			var state string
-			if ag.Running {
+			if rateLimitText(ag) != "" {
+				if selected {
+					state = "⏸"
+				} else {
+					state = lipgloss.NewStyle().Foreground(m.styles.accent).Render("⏸")
+				}
+			} else if ag.Running && ag.Stalled {
+				if selected {
+					state = "!"
+				} else {
+					state = lipgloss.NewStyle().Foreground(m.styles.accent).Render("!")
+				}
+			} else if ag.Running {
 				frame := spinnerFrames[ag.Spinner%len(spinnerFrames)]
 				if selected {
 					state = frame
@@ -558,7 +1008,31 @@ func (m Model) renderList() string {
 				}
 			}
 			meta := fmt.Sprintf("%s %s", ag.Kind, ag.Model)
-			rows = append(rows, m.renderRow(ag.Name, meta, selected, state))
+			if limit := rateLimitText(ag); limit != "" {
+				meta += " · " + limit
+			}
+			if ag.Denied > 0 {
+				meta += fmt.Sprintf(" · ⚠ %d denied", ag.Denied)
+			}
+			if len(ag.Overlaps) > 0 {
+				meta += " · ⚠ overlaps " + strings.Join(ag.Overlaps, ", ")
+			}
+			if activity := lastActiveText(ag); activity != "" {
+				meta += " · " + activity
+			}
+			if len(ag.Duplicates) > 0 {
+				meta += " · ⚠ same task as " + strings.Join(ag.Duplicates, ", ")
+			}
+			if selected {
+				if runtime := agentRuntime(ag); runtime != "" {
+					meta += " · " + runtime
+				}
+			}
+			name := ag.Name
+			if !selected {
+				name = lipgloss.NewStyle().Foreground(m.agentColor(id)).Render(name)
+			}
+			rows = append(rows, m.renderRow(name, meta, selected, state))
 			if info := m.renderWorkerSummary(id); info != "" {
 				rows = append(rows, info)
 			}
@@ -606,6 +1080,80 @@ func (m Model) renderRow(name, meta string, selected bool, prefix string) string
 	return row
 }
 
+// rateLimitText describes an agent's rate limit while it lasts, e.g. "rate limited until
+// 15:04", or returns "".
+func rateLimitText(ag *agentView) string {
+	if !ag.RateLimited {
+		return ""
+	}
+	if ag.RateLimitedUntil.IsZero() {
+		return "rate limited"
+	}
+	if time.Now().After(ag.RateLimitedUntil) {
+		return ""
+	}
+	return "rate limited until " + ag.RateLimitedUntil.Format("15:04")
+}
+
+// agentRuntime formats elapsed time since the agent's last start and its restart count.
+func agentRuntime(ag *agentView) string {
+	if ag.StartedAt.IsZero() {
+		return ""
+	}
+	end := time.Now()
+	if !ag.Running && !ag.StoppedAt.IsZero() {
+		end = ag.StoppedAt
+	}
+	elapsed := end.Sub(ag.StartedAt).Round(time.Second)
+	text := fmt.Sprintf("%s · restarts: %d", elapsed, ag.Restarts)
+	if ag.Stalled {
+		text += " · stalled"
+	}
+	if ag.TokensIn > 0 || ag.TokensOut > 0 {
+		text += fmt.Sprintf(" · tokens: %s in / %s out", formatTokens(ag.TokensIn), formatTokens(ag.TokensOut))
+	}
+	if ag.CostUSD > 0 {
+		text += " · ~" + pricing.Format(ag.CostUSD)
+	}
+	return text
+}
+
+// lastActiveText describes a running agent's last heartbeat: empty while it is writing
+// output, "last active 3m ago" once it has been quiet for a minute.
+func lastActiveText(ag *agentView) string {
+	if !ag.Running || ag.LastActive.IsZero() {
+		return ""
+	}
+	if idle := time.Since(ag.LastActive); idle >= time.Minute {
+		return "last active " + formatAgo(idle)
+	}
+	return ""
+}
+
+// formatAgo renders how long ago something happened in its largest unit: "45s ago",
+// "3m ago", "2h ago".
+func formatAgo(d time.Duration) string {
+	switch {
+	case d >= time.Hour:
+		return fmt.Sprintf("%dh ago", int(d/time.Hour))
+	case d >= time.Minute:
+		return fmt.Sprintf("%dm ago", int(d/time.Minute))
+	}
+	return fmt.Sprintf("%ds ago", int(d/time.Second))
+}
+
+// formatTokens abbreviates token counts (950, 12.3k, 1.2M).
+func formatTokens(n int) string {
+	switch {
+	case n >= 1_000_000:
+		return fmt.Sprintf("%.1fM", float64(n)/1_000_000)
+	case n >= 1_000:
+		return fmt.Sprintf("%.1fk", float64(n)/1_000)
+	default:
+		return fmt.Sprintf("%d", n)
+	}
+}
+
 func (m *Model) renderWorkerSummary(id string) string {
 	snap, ok := m.snapshotFor(id)
 	if !ok {
@@ -613,16 +1161,21 @@ func (m *Model) renderWorkerSummary(id string) string {
 	}
 	git := snapshotToCoded(*snap)
 	parts := make([]string, 0, 2)
+	spark := m.renderActivity(id, listActivity)
 	if git.Branch != "" {
 		parts = append(parts, fmt.Sprintf("Branch: %s", git.Branch))
 	}
 	if counts := summarizeCounts(git); counts != "" {
 		parts = append(parts, counts)
 	}
-	if len(parts) == 0 {
+	if len(parts) == 0 && spark == "" {
 		return ""
 	}
-	return lipgloss.NewStyle().Foreground(m.styles.dim).Render("  " + strings.Join(parts, "  "))
+	summary := lipgloss.NewStyle().Foreground(m.styles.dim).Render(strings.Join(parts, "  "))
+	if spark != "" {
+		summary = spark + " " + summary
+	}
+	return "  " + summary
 }
 
 func (m *Model) renderWorkerFiles(id string) string {
@@ -671,12 +1224,22 @@ func (m Model) renderLog() string {
 
 	selectedID := m.itemOrder[m.selected]
 	header := title(selectedID)
+	if !isPanelID(selectedID) {
+		header = m.agentName(selectedID)
+	}
+	if m.isLogSelection() {
+		if m.follow {
+			header += "  " + lipgloss.NewStyle().Foreground(m.styles.running).Render("● follow")
+		} else {
+			header += "  " + lipgloss.NewStyle().Foreground(m.styles.dim).Render("○ paused (f to follow)")
+		}
+	}
 	contentWidth := m.view.Width - 1
 	if contentWidth < 1 {
 		contentWidth = m.view.Width
 	}
 	content := lipgloss.NewStyle().Width(contentWidth).Height(m.view.Height).Render(m.view.View())
-	scroll := m.renderScrollbar(m.view.Height, m.view.ScrollPercent())
+	scroll := m.renderScrollbar(m.view.Height, m.scrollPercent())
 	if contentWidth >= m.view.Width {
 		// If we don't have room for scrollbar, skip it.
 		scroll = ""
@@ -697,13 +1260,22 @@ func (m Model) renderSessionInfo() string {
 	lines := []string{
 		fmt.Sprintf("Session ID: %s", m.session.ID),
 		fmt.Sprintf("Path: %s", m.session.Path),
-		fmt.Sprintf("Repository: %s", m.opts.Repo),
+		fmt.Sprintf("Repository: %s", strings.Join(m.opts.Repositories(), ", ")),
 		fmt.Sprintf("Todo: %s", m.opts.Todo),
 		fmt.Sprintf("Created: %s", m.session.Created.Format("2006-01-02 15:04:05")),
-		fmt.Sprintf("Workers: Claude %d, Codex %d, Copilot %d, Gemini %d",
-			m.opts.ClaudeWorkers, m.opts.CodexWorkers, m.opts.CopilotWorkers, m.opts.GeminiWorkers),
+		fmt.Sprintf("Workers: %s", m.opts.WorkerSummary()),
 		fmt.Sprintf("Supervisor: %s", title(string(m.opts.Supervisor))),
 	}
+	if m.opts.Observing() {
+		lines[5] = fmt.Sprintf("Workers: %d observed (%s logs)", len(m.opts.Observe), title(string(m.opts.ObserveAgent)))
+		for _, spec := range m.opts.Observe {
+			lines = append(lines, "  "+spec)
+		}
+	}
+	lines = append(lines, fmt.Sprintf("Time limit: %s", m.opts.Duration()))
+	if !m.deadline.IsZero() {
+		lines = append(lines, fmt.Sprintf("Deadline: %s", m.deadline.Format("2006-01-02 15:04:05")))
+	}
 	return strings.Join(lines, "\n")
 }
 
@@ -717,6 +1289,7 @@ func (m Model) renderStatus() string {
 	}
 	return lipgloss.NewStyle().
 		Foreground(m.styles.dim).
+		MaxWidth(m.width).
 		Render(strings.Join(lines, "   "))
 }
 
@@ -727,25 +1300,6 @@ func (m *Model) trimStatus() {
 	}
 }
 
-func (b *logBuffer) append(entry logEntry) bool {
-	trimmed := false
-	b.lines = append(b.lines, entry)
-	if len(b.lines) > b.limit && b.limit > 0 {
-		b.lines = b.lines[len(b.lines)-b.limit:]
-		trimmed = true
-	}
-	b.dirty = true
-	return trimmed
-}
-
-func (b *logBuffer) content() string {
-	out := make([]string, 0, len(b.lines))
-	for _, l := range b.lines {
-		out = append(out, l.Text)
-	}
-	return strings.Join(out, "\n")
-}
-
 func (m *Model) todoFilePath() string {
 	path := m.todoPath
 	if path == "" {
@@ -781,7 +1335,11 @@ func (m *Model) renderTodo() string {
 		return fmt.Sprintf("todo not found: %s (%v)", path, err)
 	}
 
-	rendered := m.renderMarkdown(string(content))
+	rendered, ok := m.renderMarkdown(string(content))
+	if !ok {
+		// Not cached: the markdown rendering's arrival redraws the todo.
+		return rendered
+	}
 	m.todoCache = todoCache{
 		path:     path,
 		modTime:  info.ModTime(),
@@ -792,6 +1350,56 @@ func (m *Model) renderTodo() string {
 	return rendered
 }
 
+// sortedPRs returns the tracked pull requests ordered by agent ID.
+func (m *Model) sortedPRs() []events.PRStatus {
+	ids := make([]string, 0, len(m.prs))
+	for id := range m.prs {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	out := make([]events.PRStatus, 0, len(ids))
+	for _, id := range ids {
+		out = append(out, m.prs[id])
+	}
+	return out
+}
+
+// prSummary counts pull requests by check state for the list row, e.g. "2 passing, 1 failing".
+func (m *Model) prSummary() string {
+	counts := map[string]int{}
+	for _, pr := range m.prs {
+		counts[pr.Checks]++
+	}
+	var parts []string
+	for _, checks := range []string{prstatus.ChecksFailing, prstatus.ChecksPending, prstatus.ChecksPassing, prstatus.ChecksNone} {
+		if counts[checks] > 0 {
+			parts = append(parts, fmt.Sprintf("%d %s", counts[checks], checks))
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+// renderPRs lists the autopilot pull requests with their CI state.
+func (m *Model) renderPRs() string {
+	var b strings.Builder
+	for _, pr := range m.sortedPRs() {
+		color := m.styles.dim
+		switch pr.Checks {
+		case prstatus.ChecksPassing:
+			color = m.styles.running
+		case prstatus.ChecksFailing:
+			color = m.styles.error
+		case prstatus.ChecksPending:
+			color = m.styles.accent
+		}
+		fmt.Fprintf(&b, "%s  #%d %s\n", m.agentName(pr.ID), pr.Number, pr.Title)
+		fmt.Fprintf(&b, "  %s  %s\n", strings.ToLower(pr.State), lipgloss.NewStyle().Foreground(color).Render(pr.Summary))
+		fmt.Fprintf(&b, "  %s\n", lipgloss.NewStyle().Foreground(m.styles.dim).Render(pr.Branch+"  "+pr.URL))
+		b.WriteString("\n")
+	}
+	return strings.TrimRight(b.String(), "\n")
+}
+
 func (m *Model) loadCodedSupervisor() string {
 	path := m.session.CodedSupervisorPath()
 	content, err := os.ReadFile(path)
@@ -842,12 +1450,13 @@ func (m *Model) renderMetrics() string {
 	var b strings.Builder
 	fmt.Fprintf(&b, "Updated: %s\n\n", snap.UpdatedAt.Format("15:04:05"))
 	for _, w := range snap.Workers {
-		fmt.Fprintf(&b, "Worker %d\n", w.WorkerNumber)
+		fmt.Fprintf(&b, "%s\n", m.workerLabel(w.WorkerNumber))
 		if w.Git.Error != "" {
 			fmt.Fprintf(&b, "  Git error: %s\n\n", w.Git.Error)
 			continue
 		}
 		fmt.Fprintf(&b, "  Branch: %s\n", w.Git.Branch)
+		writeActivity(&b, w.Activity)
 		writeChanges := func(title string, list []fileChange) {
 			if len(list) == 0 {
 				return
@@ -925,6 +1534,7 @@ func (m *Model) renderMetricsFromStatuses() string {
 		if git.Branch != "" {
 			fmt.Fprintf(&b, "  Branch: %s\n", git.Branch)
 		}
+		writeActivity(&b, m.activityFor(e.id))
 		writeChanges := func(title string, list []fileChange) {
 			if len(list) == 0 {
 				return
@@ -962,6 +1572,14 @@ func (m *Model) renderMetricsFromStatuses() string {
 	return strings.TrimRight(b.String(), "\n")
 }
 
+// writeActivity adds the sparkline of lines changed per poll to a metrics entry.
+func writeActivity(b *strings.Builder, samples []int) {
+	if len(samples) == 0 {
+		return
+	}
+	fmt.Fprintf(b, "  Activity: %s  %d lines over %d polls\n", sparkline(samples, 0), sumSamples(samples), len(samples))
+}
+
 type codedSnapshot struct {
 	UpdatedAt time.Time     `json:"updatedAt"`
 	Workers   []codedWorker `json:"workers"`
@@ -971,6 +1589,7 @@ type codedWorker struct {
 	WorkerNumber int           `json:"workerNumber"`
 	Git          codedGit      `json:"git"`
 	Logs         codedLogState `json:"logs"`
+	Activity     []int         `json:"activity"`
 	LastUpdated  time.Time     `json:"lastUpdated"`
 }
 
@@ -1107,54 +1726,44 @@ func summarizeFileLines(staged, unstaged []fileChange, untracked []string, limit
 	return lines
 }
 
-func (m *Model) renderAgentLog(id string, buf *logBuffer) string {
-	tailDo := len(buf.lines) > 0 && buf.lines[len(buf.lines)-1].Kind == events.MessageDo
-	if tailDo {
-		buf.dirty = true
-	}
-	if !buf.dirty && buf.rendered != "" {
-		return buf.rendered
-	}
-
-	renderDeadline := time.Now().Add(markdownRenderBudget)
-	// Render markdown for all agents once (no reflow) to improve readability.
-	markdownAllowed := true
-	lines := make([]string, 0, len(buf.lines))
-	for i := range buf.lines {
-		l := &buf.lines[i]
-		useMarkdown := markdownAllowed && time.Now().Before(renderDeadline)
-		if l.rendered == "" || l.renderMarkdown != useMarkdown {
-			l.rendered = m.renderLogEntry(*l, useMarkdown)
-			l.renderMarkdown = useMarkdown
-		}
-		lines = append(lines, l.rendered)
-	}
-	if tailDo {
-		lines = append(lines, lipgloss.NewStyle().Foreground(m.styles.do).Render(m.spinner.View()+" running..."))
-	}
-	if len(lines) == 0 {
-		buf.rendered = "waiting for output..."
-	} else {
-		buf.rendered = strings.Join(lines, "\n")
-	}
-	buf.dirty = false
-	return buf.rendered
-}
-
-func (m *Model) renderLogEntry(l logEntry, markdown bool) string {
+// renderLogEntry renders one log entry. It reports false when markdown in it is still
+// being rendered and plain text stands in for it.
+func (m *Model) renderLogEntry(l logEntry) (string, bool) {
 	switch l.Kind {
 	case events.MessageDo:
-		return lipgloss.NewStyle().Foreground(m.styles.do).Render("→ " + l.Text)
+		return lipgloss.NewStyle().Foreground(m.styles.do).Render("→ " + l.Text), true
 	case events.MessageSee:
-		return lipgloss.NewStyle().Foreground(m.styles.see).Render(l.Text)
+		if l.Diff {
+			return highlightCode(l.Text, "diff"), true
+		}
+		return lipgloss.NewStyle().Foreground(m.styles.see).Render(l.Text), true
 	default:
-		if markdown {
-			return m.renderMarkdown(l.Text)
+		if hasFence(l.Text) {
+			return m.renderWithCodeBlocks(l.Text)
+		}
+		if looksLikeDiff(l.Text) {
+			return highlightCode(l.Text, "diff"), true
 		}
-		return l.Text
+		return m.renderMarkdown(l.Text)
 	}
 }
 
+// renderWithCodeBlocks highlights fenced code directly and renders the prose around it as markdown.
+func (m *Model) renderWithCodeBlocks(text string) (string, bool) {
+	var parts []string
+	ready := true
+	for _, seg := range splitFences(text) {
+		if seg.code {
+			parts = append(parts, highlightCode(seg.body, seg.language))
+			continue
+		}
+		out, ok := m.renderMarkdown(seg.body)
+		parts = append(parts, out)
+		ready = ready && ok
+	}
+	return strings.Join(parts, "\n"), ready
+}
+
 func waitForEvent(ch <-chan events.Event) tea.Cmd {
 	return func() tea.Msg {
 		if ch == nil {
@@ -1170,42 +1779,25 @@ func waitForEvent(ch <-chan events.Event) tea.Cmd {
 
 type eventsClosedMsg struct{}
 
-func (m *Model) renderMarkdown(text string) string {
-	// Guard against pathological cases: very large content or slow renderer.
-	if len(text) > 8000 {
-		return text
+// renderMarkdown returns text rendered as markdown and true, or text itself and false
+// while the rendering is under way.
+func (m *Model) renderMarkdown(text string) (string, bool) {
+	// Guard against pathological cases: very large content.
+	if len(text) > markdownMaxLen {
+		return text, true
 	}
+	return m.markdown.lookup(text)
+}
 
-	if m.mdRenderer == nil {
-		r, err := glamour.NewTermRenderer(
-			glamour.WithAutoStyle(),
-			glamour.WithWordWrap(0), // no reflow; render once per entry
-		)
-		if err == nil {
-			m.mdRenderer = r
-		}
-	}
-	if m.mdRenderer == nil {
-		return text
-	}
-	done := make(chan string, 1)
-	go func() {
-		m.mdMu.Lock()
-		defer m.mdMu.Unlock()
-		out, err := m.mdRenderer.Render(text)
-		if err != nil {
-			done <- text
-			return
+// markMarkdownStale drops the renders that stand in plain text for markdown, so the next
+// view picks up the renderings that have arrived since.
+func (m *Model) markMarkdownStale() {
+	for _, buf := range m.logs {
+		for i := range buf.lines {
+			if l := &buf.lines[i]; l.mdPending {
+				l.rendered, l.mdPending = "", false
+			}
 		}
-		done <- strings.TrimRight(out, "\n")
-	}()
-
-	select {
-	case out := <-done:
-		return out
-	case <-time.After(markdownRenderTimeout):
-		// Fall back to plain text if rendering is slow to avoid UI stalls.
-		return text
 	}
 }
 
@@ -1232,24 +1824,25 @@ func (m *Model) isLogSelection() bool {
 		return false
 	}
 	id := m.itemOrder[m.selected]
-	return id != "session" && id != "todo" && id != "coded"
+	return !isPanelID(id)
 }
 
-func (m *Model) anyPendingDo() bool {
-	for _, buf := range m.logs {
-		if len(buf.lines) > 0 && buf.lines[len(buf.lines)-1].Kind == events.MessageDo {
-			return true
-		}
+// isPanelID reports whether an item is a built-in panel rather than an agent.
+func isPanelID(id string) bool {
+	switch id {
+	case "session", "todo", "scratchpad", "coded", "verdict", "pending", "prs", "quota", "status":
+		return true
 	}
 	return false
 }
 
-func (m *Model) markDoBuffersDirty() {
+func (m *Model) anyPendingDo() bool {
 	for _, buf := range m.logs {
 		if len(buf.lines) > 0 && buf.lines[len(buf.lines)-1].Kind == events.MessageDo {
-			buf.dirty = true
+			return true
 		}
 	}
+	return false
 }
 
 func (m *Model) clampViewport() {
@@ -1289,12 +1882,87 @@ func (m *Model) startInjectPrompt() {
 		return
 	}
 	id := m.itemOrder[m.selected]
-	if id == "session" || id == "todo" || id == "coded" {
+	if isPanelID(id) {
 		return
 	}
 	m.inputField.SetWidth(m.view.Width)
 	m.inputActive = true
 	m.inputTarget = id
+	m.inputField.Placeholder = notePlaceholder
+	m.inputField.Reset()
+	m.inputField.Focus()
+}
+
+// timeStep is how far + and - move the round deadline.
+const timeStep = 5 * time.Minute
+
+func (m *Model) adjustTime(delta time.Duration) {
+	if m.replay || m.control == nil {
+		return
+	}
+	go func() { m.control <- control.AdjustTime{Delta: delta} }()
+	if delta > 0 {
+		m.addStatus(fmt.Sprintf("Extending round by %s", delta))
+	} else {
+		m.addStatus(fmt.Sprintf("Shortening round by %s", -delta))
+	}
+}
+
+const (
+	// broadcastTarget is the input target for a message to every running worker.
+	broadcastTarget = "*workers"
+	// supervisorTarget is the input target for the supervisor's new agent type and model.
+	supervisorTarget = "*supervisor"
+)
+
+const (
+	notePlaceholder       = "Enter note (Enter=restart with note, Ctrl+S=send without restart, Alt+Enter=newline, Esc=cancel)"
+	broadcastPlaceholder  = "Enter message for all workers (Enter=send, Alt+Enter=newline, Esc=cancel)"
+	supervisorPlaceholder = "Agent type and optional model, e.g. codex gpt-5-codex-mini (Enter=switch, Esc=cancel)"
+)
+
+func (m *Model) startSwapSupervisorPrompt() {
+	if m.inputActive || m.replay {
+		return
+	}
+	if _, ok := m.agents["supervisor"]; !ok {
+		return
+	}
+	m.inputField.SetWidth(m.view.Width)
+	m.inputActive = true
+	m.inputTarget = supervisorTarget
+	m.inputField.Placeholder = supervisorPlaceholder
+	m.inputField.Reset()
+	m.inputField.Focus()
+}
+
+// swapSupervisor parses "<agent> [model]" and asks the orchestrator to switch the supervisor.
+func (m *Model) swapSupervisor(value string) {
+	fields := strings.Fields(value)
+	if len(fields) == 0 || m.control == nil {
+		return
+	}
+	t, err := config.ParseAgentType(fields[0])
+	if err != nil {
+		m.addStatus(fmt.Sprintf("Supervisor not switched: %v", err))
+		return
+	}
+	model := ""
+	if len(fields) > 1 {
+		model = fields[1]
+	}
+	go func() { m.control <- control.SwapSupervisor{AgentType: t, Model: model} }()
+	m.addStatus(fmt.Sprintf("Supervisor switch to %s requested", strings.TrimSpace(string(t)+" "+model)))
+}
+
+func (m *Model) startBroadcastPrompt() {
+	if m.inputActive || m.replay {
+		return
+	}
+	m.inputField.SetWidth(m.view.Width)
+	m.inputActive = true
+	m.inputTarget = broadcastTarget
+	m.inputField.Placeholder = broadcastPlaceholder
 	m.inputField.Reset()
 	m.inputField.Focus()
 }
@@ -1304,7 +1972,7 @@ func (m *Model) toggleAgent() {
 		return
 	}
 	id := m.itemOrder[m.selected]
-	if id == "session" || id == "todo" || id == "coded" || id == "app" {
+	if isPanelID(id) || id == "app" {
 		return
 	}
 	ag, ok := m.agents[id]
@@ -1315,19 +1983,26 @@ func (m *Model) toggleAgent() {
 		if m.control != nil {
 			go func() { m.control <- control.StopAgent{AgentID: id} }()
 		}
-		m.status = append(m.status, fmt.Sprintf("Stop requested for %s", id))
+		m.addStatus(fmt.Sprintf("Stop requested for %s", id))
 	} else {
 		if m.control != nil {
 			go func() { m.control <- control.StartAgent{AgentID: id} }()
 		}
-		m.status = append(m.status, fmt.Sprintf("Start requested for %s", id))
+		m.addStatus(fmt.Sprintf("Start requested for %s", id))
 	}
-	m.trimStatus()
 }
 
 func (m Model) renderInputOverlay() string {
 	label := fmt.Sprintf("Inject & restart %s", title(m.inputTarget))
-	warn := "Note: agent restarts fresh; context comes from its log."
+	warn := "Note: agent restarts fresh; context comes from its log.\nCtrl+S sends the note to the running agent instead (Claude only)."
+	switch m.inputTarget {
+	case broadcastTarget:
+		label = "Broadcast to all running workers"
+		warn = "Sent live to agents that accept input (Claude);\nthe others restart with the message injected."
+	case supervisorTarget:
+		label = "Switch supervisor agent"
+		warn = "The supervisor restarts on the chosen CLI and picks up from its log.\nWorkers keep running."
+	}
 	body := fmt.Sprintf("%s\n%s\n\n%s", label, warn, m.inputField.View())
 	box := lipgloss.NewStyle().
 		Border(lipgloss.RoundedBorder()).
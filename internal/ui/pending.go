@@ -0,0 +1,99 @@
+package ui
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/asynkron/Asynkron.SwarmGo/internal/control"
+	"github.com/asynkron/Asynkron.SwarmGo/internal/events"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// sortedPending returns the pull requests waiting for (or past) approval by worker ID.
+func (m *Model) sortedPending() []events.PendingPR {
+	out := make([]events.PendingPR, 0, len(m.pending))
+	for _, p := range m.pending {
+		out = append(out, p)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].ID < out[j].ID })
+	return out
+}
+
+// pendingSummary counts the pull requests still waiting for approval, for the list row.
+func (m *Model) pendingSummary() string {
+	waiting := 0
+	for _, p := range m.pending {
+		if p.State == "pending" || p.State == "failed" {
+			waiting++
+		}
+	}
+	return fmt.Sprintf("%d waiting", waiting)
+}
+
+// renderPending lists the pull requests workers asked for, with their commits and diff
+// stats, and how to approve them.
+func (m *Model) renderPending() string {
+	dim := lipgloss.NewStyle().Foreground(m.styles.dim)
+	var b strings.Builder
+	b.WriteString(dim.Render("a approves and x rejects the first waiting pull request here, or the selected worker's."))
+	b.WriteString("\n\n")
+	for _, p := range m.sortedPending() {
+		color := m.styles.dim
+		switch p.State {
+		case "pending":
+			color = m.styles.accent
+		case "opened":
+			color = m.styles.running
+		case "failed":
+			color = m.styles.error
+		}
+		fmt.Fprintf(&b, "%s  %s  %s\n", m.agentName(p.ID), lipgloss.NewStyle().Foreground(color).Render(p.State), p.Title)
+		fmt.Fprintf(&b, "  %s\n", dim.Render(strings.TrimSpace(p.Branch+"  "+p.Stat)))
+		for _, c := range p.Commits {
+			fmt.Fprintf(&b, "  • %s\n", c)
+		}
+		if p.URL != "" {
+			fmt.Fprintf(&b, "  %s\n", p.URL)
+		}
+		if p.Error != "" {
+			fmt.Fprintf(&b, "  %s\n", lipgloss.NewStyle().Foreground(m.styles.error).Render(p.Error))
+		}
+		if body := strings.TrimSpace(p.Body); body != "" {
+			for _, line := range strings.Split(body, "\n") {
+				fmt.Fprintf(&b, "  %s\n", dim.Render("│ "+line))
+			}
+		}
+		b.WriteString("\n")
+	}
+	return strings.TrimRight(b.String(), "\n")
+}
+
+// decidePR approves or rejects a pending pull request: the selected worker's, or on the
+// Pending PRs panel the first one waiting.
+func (m *Model) decidePR(approve bool) {
+	if m.replay || m.control == nil || m.selected >= len(m.itemOrder) {
+		return
+	}
+	id := m.itemOrder[m.selected]
+	if id == "pending" {
+		id = ""
+		for _, p := range m.sortedPending() {
+			if p.State == "pending" || p.State == "failed" {
+				id = p.ID
+				break
+			}
+		}
+	}
+	p, ok := m.pending[id]
+	if !ok || (p.State != "pending" && p.State != "failed") {
+		return
+	}
+	if approve {
+		go func() { m.control <- control.ApprovePR{AgentID: id} }()
+		m.addStatus(fmt.Sprintf("Approved the pull request of %s: %s", id, p.Title))
+	} else {
+		go func() { m.control <- control.RejectPR{AgentID: id} }()
+		m.addStatus(fmt.Sprintf("Rejected the pull request of %s: %s", id, p.Title))
+	}
+}
@@ -0,0 +1,123 @@
+package ui
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// maxQuotaNotices is how many recent notices the Quota panel keeps per agent type.
+const maxQuotaNotices = 5
+
+// quotaEntry aggregates the usage notices of every agent of one type (e.g. "Claude").
+type quotaEntry struct {
+	warnings   int
+	rateLimits int
+	notices    []quotaNotice
+}
+
+type quotaNotice struct {
+	at      time.Time
+	agentID string
+	text    string
+	limited bool
+}
+
+// recordQuota files a usage warning or rate limit of agent id under its agent type.
+func (m *Model) recordQuota(id, text string, limited bool) {
+	kind := id
+	if ag, ok := m.agents[id]; ok && ag.Kind != "" {
+		kind = ag.Kind
+	}
+	e, ok := m.quota[kind]
+	if !ok {
+		e = &quotaEntry{}
+		m.quota[kind] = e
+		m.rebuildOrder()
+	}
+	if limited {
+		e.rateLimits++
+	} else {
+		e.warnings++
+	}
+	e.notices = append(e.notices, quotaNotice{at: time.Now(), agentID: id, text: text, limited: limited})
+	if len(e.notices) > maxQuotaNotices {
+		e.notices = e.notices[len(e.notices)-maxQuotaNotices:]
+	}
+}
+
+func (m *Model) quotaKinds() []string {
+	kinds := make([]string, 0, len(m.quota))
+	for kind := range m.quota {
+		kinds = append(kinds, kind)
+	}
+	sort.Strings(kinds)
+	return kinds
+}
+
+// quotaSummary is the list row detail, e.g. "Claude 3, Codex 1".
+func (m *Model) quotaSummary() string {
+	var parts []string
+	for _, kind := range m.quotaKinds() {
+		e := m.quota[kind]
+		parts = append(parts, fmt.Sprintf("%s %d", kind, e.warnings+e.rateLimits))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// quotaAgents counts the agents of a type and how many of them are rate limited now.
+func (m *Model) quotaAgents(kind string) (total, limited int) {
+	for _, ag := range m.agents {
+		if ag.Kind != kind {
+			continue
+		}
+		total++
+		if rateLimitText(ag) != "" {
+			limited++
+		}
+	}
+	return total, limited
+}
+
+// renderQuota lists the usage warnings and rate limits per agent type, to help decide
+// whether to move work to another CLI.
+func (m *Model) renderQuota() string {
+	var b strings.Builder
+	for _, kind := range m.quotaKinds() {
+		e := m.quota[kind]
+		total, limited := m.quotaAgents(kind)
+		header := lipgloss.NewStyle().Bold(true).Render(kind)
+		fmt.Fprintf(&b, "%s  %d agents", header, total)
+		if limited > 0 {
+			b.WriteString(lipgloss.NewStyle().Foreground(m.styles.accent).Render(fmt.Sprintf(", %d rate limited now", limited)))
+		}
+		b.WriteString("\n")
+		fmt.Fprintf(&b, "  %d usage warnings, %d rate limits\n", e.warnings, e.rateLimits)
+		for i := len(e.notices) - 1; i >= 0; i-- {
+			n := e.notices[i]
+			color := m.styles.dim
+			if n.limited {
+				color = m.styles.error
+			}
+			id := lipgloss.NewStyle().Foreground(m.agentColor(n.agentID)).Render(n.agentID)
+			fmt.Fprintf(&b, "  %s %s  %s\n", n.at.Format("15:04"), id, lipgloss.NewStyle().Foreground(color).Render(n.text))
+		}
+		b.WriteString("\n")
+	}
+	return strings.TrimRight(b.String(), "\n")
+}
+
+func (m *Model) exportQuota() string {
+	var lines []string
+	for _, kind := range m.quotaKinds() {
+		e := m.quota[kind]
+		lines = append(lines, fmt.Sprintf("%s  %d usage warnings, %d rate limits", kind, e.warnings, e.rateLimits))
+		for _, n := range e.notices {
+			lines = append(lines, fmt.Sprintf("  %s %s  %s", n.at.Format(historyTimeLayout), n.agentID, n.text))
+		}
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,49 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/asynkron/Asynkron.SwarmGo/internal/events"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// scratchpadView is the shared scratchpad as last announced, with its Markdown rendering
+// cached for the viewport width it was rendered at.
+type scratchpadView struct {
+	events.ScratchpadChanged
+	width    int
+	rendered string
+}
+
+// scratchpadSummary describes the scratchpad for the list row.
+func (m *Model) scratchpadSummary() string {
+	if m.scratchpad == nil {
+		return ""
+	}
+	lines := strings.Count(strings.TrimRight(m.scratchpad.Content, "\n"), "\n") + 1
+	return fmt.Sprintf("%d lines, %s", lines, m.scratchpad.Time.Format("15:04:05"))
+}
+
+// renderScratchpad shows the scratchpad's Markdown with its path and last change above it.
+func (m *Model) renderScratchpad() string {
+	s := m.scratchpad
+	if s == nil {
+		return ""
+	}
+	width := m.view.Width
+	if width <= 0 {
+		width = 80
+	}
+	body := s.rendered
+	if body == "" || s.width != width {
+		var ready bool
+		body, ready = m.renderMarkdown(s.Content)
+		if ready {
+			s.rendered, s.width = body, width
+		}
+	}
+	dim := lipgloss.NewStyle().Foreground(m.styles.dim)
+	header := dim.Render(fmt.Sprintf("%s · last changed %s · every agent may edit it; the last write wins", s.Path, s.Time.Format("15:04:05")))
+	return header + "\n\n" + body
+}
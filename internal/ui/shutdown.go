@@ -0,0 +1,123 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/asynkron/Asynkron.SwarmGo/internal/control"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// runningAgents counts agent processes that are still alive (the app log is not a process).
+func (m *Model) runningAgents() int {
+	count := 0
+	for id, ag := range m.agents {
+		if id != "app" && ag.Running {
+			count++
+		}
+	}
+	return count
+}
+
+// beginShutdown switches to the shutdown view and asks the orchestrator to stop every agent.
+func (m *Model) beginShutdown() tea.Cmd {
+	m.confirmQuit = false
+	if m.control == nil || m.eventsClosed {
+		return tea.Quit
+	}
+	m.shuttingDown = true
+	for id, ag := range m.agents {
+		if id != "app" && ag.Running {
+			ag.Stopping = true
+		}
+	}
+	ctrl := m.control
+	go func() { ctrl <- control.Shutdown{} }()
+	return nil
+}
+
+func (m Model) renderConfirmQuit() string {
+	label := lipgloss.NewStyle().Bold(true).Foreground(m.styles.accent).Render("Quit swarm?")
+	body := fmt.Sprintf("%s\n\n%d agent(s) are still running and will be stopped.\nYou can resume later with: swarm --resume %s\n\n[y] stop agents and quit   [n] cancel",
+		label, m.runningAgents(), m.session.ID)
+	box := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(m.styles.border).
+		Padding(1, 2).
+		Width(m.view.Width + 2).
+		Height(m.view.Height + 2)
+	return box.Render(body)
+}
+
+func (m Model) renderShutdown() string {
+	heading := "Shutting down swarm " + m.spinner.View()
+	if m.eventsClosed {
+		heading = "Shutdown complete"
+	}
+	lines := []string{
+		lipgloss.NewStyle().Bold(true).Foreground(m.styles.header).Render(heading),
+		"",
+	}
+
+	nameWidth := 0
+	for _, id := range m.itemOrder {
+		if ag, ok := m.agents[id]; ok && id != "app" && len(ag.Name) > nameWidth {
+			nameWidth = len(ag.Name)
+		}
+	}
+	for _, id := range m.itemOrder {
+		ag, ok := m.agents[id]
+		if !ok || id == "app" {
+			continue
+		}
+		var state string
+		switch {
+		case ag.Stopping && !m.eventsClosed:
+			state = lipgloss.NewStyle().Foreground(m.styles.accent).Render("stopping...")
+		case ag.Running && !m.eventsClosed:
+			state = lipgloss.NewStyle().Foreground(m.styles.running).Render("running")
+		default:
+			state = lipgloss.NewStyle().Foreground(m.styles.dim).Render(fmt.Sprintf("stopped (exit %d)", ag.ExitCode))
+		}
+		row := fmt.Sprintf("  %-*s  %s", nameWidth, ag.Name, state)
+		if wt := m.worktreeState(id); wt != "" {
+			row += "  " + lipgloss.NewStyle().Foreground(m.styles.dim).Render(wt)
+		}
+		lines = append(lines, row)
+	}
+
+	lines = append(lines, "", fmt.Sprintf("Session data: %s", m.session.Path))
+	lines = append(lines, fmt.Sprintf("Resume with:  swarm --resume %s", m.session.ID))
+	if m.eventsClosed {
+		lines = append(lines, "", lipgloss.NewStyle().Foreground(m.styles.dim).Render("Press any key to exit."))
+	} else {
+		lines = append(lines, "", lipgloss.NewStyle().Foreground(m.styles.dim).Render("Press ctrl+c to force quit."))
+	}
+	return lipgloss.NewStyle().Padding(1, 2).Render(strings.Join(lines, "\n"))
+}
+
+// worktreeState summarizes the latest git snapshot for an agent's worktree.
+func (m *Model) worktreeState(id string) string {
+	snap, ok := m.snapshotFor(id)
+	if !ok {
+		return ""
+	}
+	git := snapshotToCoded(*snap)
+	if git.Error != "" {
+		return "git error"
+	}
+	parts := []string{}
+	if git.Branch != "" {
+		parts = append(parts, git.Branch)
+	}
+	if counts := summarizeCounts(git); counts != "" {
+		parts = append(parts, counts)
+	} else {
+		parts = append(parts, "clean")
+	}
+	if n := len(git.RecentCommits); n > 0 {
+		parts = append(parts, fmt.Sprintf("%d commit(s)", n))
+	}
+	return strings.Join(parts, "  ")
+}
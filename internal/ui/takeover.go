@@ -0,0 +1,60 @@
+package ui
+
+import (
+	"fmt"
+	"os/exec"
+	"strings"
+
+	"github.com/asynkron/Asynkron.SwarmGo/internal/control"
+	"github.com/asynkron/Asynkron.SwarmGo/internal/events"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// handBackNote is injected when a worker is restarted after a human took it over.
+const handBackNote = "A human took over your session interactively and has handed control back. Review what changed in your worktree (git status, git log) and your log, then continue with your task."
+
+// takeoverDoneMsg reports that the interactive session of a taken-over agent has ended.
+type takeoverDoneMsg struct {
+	id  string
+	err error
+}
+
+// takeOverAgent asks the orchestrator to stop the selected worker so its conversation can
+// be continued interactively in the terminal.
+func (m *Model) takeOverAgent() {
+	if m.replay || m.selected >= len(m.itemOrder) {
+		return
+	}
+	id := m.itemOrder[m.selected]
+	if _, ok := m.agents[id]; !ok || isPanelID(id) || id == "app" {
+		return
+	}
+	if m.control != nil {
+		go func() { m.control <- control.TakeOver{AgentID: id} }()
+	}
+	m.addStatus(fmt.Sprintf("Take over requested for %s; the dashboard pauses once it has stopped", id))
+}
+
+// attach suspends the TUI and runs the agent's CLI interactively in its worktree.
+func (m *Model) attach(e events.AgentTakeover) tea.Cmd {
+	if m.replay {
+		m.addStatus(fmt.Sprintf("%s was taken over", e.ID))
+		return nil
+	}
+	m.addStatus(fmt.Sprintf("Attached to %s: %s %s", e.ID, e.Command, strings.Join(e.Args, " ")))
+	cmd := exec.Command(e.Command, e.Args...)
+	cmd.Dir = e.Dir
+	id := e.ID
+	return tea.ExecProcess(cmd, func(err error) tea.Msg { return takeoverDoneMsg{id: id, err: err} })
+}
+
+// handBack restarts the agent once the human has left its interactive session.
+func (m *Model) handBack(msg takeoverDoneMsg) {
+	if msg.err != nil {
+		m.addStatus(fmt.Sprintf("Interactive session of %s ended: %v", msg.id, msg.err))
+	}
+	if m.control != nil {
+		go func() { m.control <- control.RestartAgent{AgentID: msg.id, Message: handBackNote} }()
+	}
+	m.addStatus(fmt.Sprintf("Handed %s back to swarm", msg.id))
+}
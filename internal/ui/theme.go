@@ -13,6 +13,8 @@ type theme struct {
 	say     lipgloss.Color
 	do      lipgloss.Color
 	see     lipgloss.Color
+	// agents are the accents agents are told apart by across panels.
+	agents []lipgloss.Color
 }
 
 func defaultTheme() theme {
@@ -27,5 +29,9 @@ func defaultTheme() theme {
 		say:     lipgloss.Color("#abb2bf"),
 		do:      lipgloss.Color("#5c6370"),
 		see:     lipgloss.Color("#7f848e"),
+		agents: []lipgloss.Color{
+			"#61afef", "#c678dd", "#98c379", "#e5c07b", "#56b6c2",
+			"#d19a66", "#e06c75", "#ff79c6", "#8be9fd", "#b5bd68",
+		},
 	}
 }
@@ -0,0 +1,76 @@
+package ui
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/asynkron/Asynkron.SwarmGo/internal/events"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// verdictWinner names the winner of a verdict for the list row and the panel.
+func (m *Model) verdictWinner(v events.Verdict) string {
+	if v.Winner == 0 {
+		return "no winner"
+	}
+	return m.workerLabel(v.Winner) + " wins"
+}
+
+// workerLabel is "Worker N" for worker number n, followed by the name it was given in
+// parentheses, if any.
+func (m *Model) workerLabel(n int) string {
+	label := fmt.Sprintf("Worker %d", n)
+	if ag, ok := m.agents[fmt.Sprintf("worker-%d", n)]; ok && ag.Name != label && ag.Name != "" {
+		label += fmt.Sprintf(" (%s)", ag.Name)
+	}
+	return label
+}
+
+// renderVerdict shows the supervisor's verdict: the winner, its rationale and every
+// worktree's score, best first.
+func (m *Model) renderVerdict() string {
+	v := m.verdict
+	if v == nil {
+		return ""
+	}
+	dim := lipgloss.NewStyle().Foreground(m.styles.dim)
+	var b strings.Builder
+	b.WriteString(lipgloss.NewStyle().Bold(true).Foreground(m.styles.running).Render(m.verdictWinner(*v)))
+	b.WriteString("\n\n")
+	b.WriteString(v.Rationale)
+	b.WriteString("\n")
+	scores := append([]events.VerdictScore(nil), v.Scores...)
+	sort.SliceStable(scores, func(i, j int) bool { return scores[i].Score > scores[j].Score })
+	if len(scores) > 0 {
+		b.WriteString("\n")
+	}
+	for _, s := range scores {
+		filled := int(s.Score + 0.5)
+		bar := strings.Repeat("█", filled) + strings.Repeat("░", 10-filled)
+		style := lipgloss.NewStyle().Foreground(m.agentColor(fmt.Sprintf("worker-%d", s.Worker)))
+		name := style.Bold(s.Worker == v.Winner).Render(m.workerLabel(s.Worker))
+		fmt.Fprintf(&b, "%s  %s %4.1f", name, lipgloss.NewStyle().Foreground(m.styles.accent).Render(bar), s.Score)
+		if s.Tests != "" {
+			fmt.Fprintf(&b, "  %s", s.Tests)
+		}
+		b.WriteString("\n")
+		if s.Notes != "" {
+			fmt.Fprintf(&b, "  %s\n", dim.Render(s.Notes))
+		}
+	}
+	return strings.TrimRight(b.String(), "\n")
+}
+
+// exportVerdict is the verdict as plain text for the clipboard.
+func (m *Model) exportVerdict() string {
+	v := m.verdict
+	if v == nil {
+		return ""
+	}
+	lines := []string{m.verdictWinner(*v), v.Rationale}
+	for _, s := range v.Scores {
+		lines = append(lines, strings.TrimRight(fmt.Sprintf("%s  %g/10  %s  %s", m.workerLabel(s.Worker), s.Score, s.Tests, s.Notes), " "))
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,95 @@
+package verdict
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+)
+
+// Verdict is the supervisor's structured judgement of a round: which worker won, why, and
+// how every worktree scored.
+type Verdict struct {
+	// Winner is the number of the winning worker, starting at 1; 0 means no worker won.
+	Winner    int     `json:"winner"`
+	Rationale string  `json:"rationale"`
+	Scores    []Score `json:"scores"`
+	// Names holds the --worker-name of the first workers; it is not part of the file.
+	Names []string `json:"-"`
+}
+
+// Score is how one worker's worktree did.
+type Score struct {
+	Worker int `json:"worker"`
+	// Score runs from 0 (nothing usable) to 10 (everything done, all tests pass).
+	Score float64 `json:"score"`
+	// Tests is the test result in the supervisor's words, e.g. "41/43 passing".
+	Tests string `json:"tests,omitempty"`
+	Notes string `json:"notes,omitempty"`
+}
+
+// Load reads a verdict and checks it against the number of workers; see Normalize.
+func Load(path string, workers int) (Verdict, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Verdict{}, fmt.Errorf("read verdict: %w", err)
+	}
+	var v Verdict
+	if err := json.Unmarshal(data, &v); err != nil {
+		return Verdict{}, fmt.Errorf("parse verdict %s: %w", path, err)
+	}
+	if err := v.Normalize(workers); err != nil {
+		return Verdict{}, fmt.Errorf("verdict %s: %w", path, err)
+	}
+	return v, nil
+}
+
+// Normalize trims the text fields and orders the scores by worker. It fails on a winner or
+// scored worker outside 1..workers, a worker scored twice and scores outside 0..10.
+func (v *Verdict) Normalize(workers int) error {
+	if v.Winner < 0 || v.Winner > workers {
+		return fmt.Errorf("winner %d is not a worker of this round (1-%d)", v.Winner, workers)
+	}
+	v.Rationale = strings.TrimSpace(v.Rationale)
+	if v.Rationale == "" {
+		return errors.New("the verdict has no rationale")
+	}
+	seen := make(map[int]bool, len(v.Scores))
+	for i := range v.Scores {
+		s := &v.Scores[i]
+		if s.Worker < 1 || s.Worker > workers {
+			return fmt.Errorf("score for worker %d, which is not a worker of this round (1-%d)", s.Worker, workers)
+		}
+		if seen[s.Worker] {
+			return fmt.Errorf("worker %d is scored twice", s.Worker)
+		}
+		seen[s.Worker] = true
+		if s.Score < 0 || s.Score > 10 {
+			return fmt.Errorf("score %g of worker %d is outside 0-10", s.Score, s.Worker)
+		}
+		s.Tests = strings.TrimSpace(s.Tests)
+		s.Notes = strings.TrimSpace(s.Notes)
+	}
+	sort.Slice(v.Scores, func(i, j int) bool { return v.Scores[i].Worker < v.Scores[j].Worker })
+	return nil
+}
+
+// WinnerName is the name of the winner, or "no winner".
+func (v Verdict) WinnerName() string {
+	if v.Winner == 0 {
+		return "no winner"
+	}
+	return v.Name(v.Winner)
+}
+
+// Name is the name of worker n (starting at 1): "Worker N", followed by its --worker-name
+// in parentheses when it has one.
+func (v Verdict) Name(n int) string {
+	name := fmt.Sprintf("Worker %d", n)
+	if n >= 1 && n <= len(v.Names) {
+		name += fmt.Sprintf(" (%s)", v.Names[n-1])
+	}
+	return name
+}
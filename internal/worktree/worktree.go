@@ -7,6 +7,8 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"strings"
+	"sync"
 )
 
 // Create prepares git worktrees rooted at repoPath pointing to HEAD.
@@ -16,21 +18,86 @@ func Create(ctx context.Context, repoPath string, worktreePaths []string) error
 
 // CreateFromRef prepares git worktrees rooted at repoPath pointing to the given ref.
 func CreateFromRef(ctx context.Context, repoPath string, worktreePaths []string, ref string) error {
+	return CreateSparse(ctx, repoPath, worktreePaths, ref, nil)
+}
+
+// CreateSparse is CreateFromRef for large repositories: when dirs is not empty each worktree
+// only checks out those directories (cone-mode sparse checkout) plus the files at the top
+// level. The sparse settings are per worktree and leave the main checkout alone.
+func CreateSparse(ctx context.Context, repoPath string, worktreePaths []string, ref string, dirs []string) error {
 	repoPath, _ = filepath.Abs(repoPath)
 
-	if err := runGit(ctx, repoPath, "worktree", "prune"); err != nil {
+	if err := Prepare(ctx, repoPath, len(dirs) > 0); err != nil {
 		return err
 	}
-
 	for _, wt := range worktreePaths {
-		if _, err := os.Stat(wt); err == nil {
-			_ = Remove(ctx, repoPath, wt)
+		if err := Add(ctx, repoPath, wt, ref, dirs); err != nil {
+			return err
 		}
-		if err := runGit(ctx, repoPath, "worktree", "add", "--detach", wt, ref); err != nil {
-			return fmt.Errorf("create worktree %s: %w", wt, err)
+	}
+	return nil
+}
+
+// Prepare readies repoPath for Add: it prunes stale worktree records and, for sparse
+// worktrees, enables per-worktree config up front, so that several Add calls can run at
+// once without racing on the shared config file.
+func Prepare(ctx context.Context, repoPath string, sparse bool) error {
+	if err := runGit(ctx, repoPath, "worktree", "prune"); err != nil {
+		return err
+	}
+	if sparse {
+		if err := runGit(ctx, repoPath, "config", "extensions.worktreeConfig", "true"); err != nil {
+			return fmt.Errorf("enable per-worktree config: %w", err)
 		}
 	}
+	return nil
+}
 
+// addMu serializes registering worktrees: concurrent `git worktree add` calls read each
+// other's half-written records under .git/worktrees and fail.
+var addMu sync.Mutex
+
+// Add creates one worktree of repoPath at ref, replacing whatever is at wt, with the sparse
+// checkout CreateSparse describes when dirs is not empty. Call Prepare first; Add calls for
+// different paths may then run concurrently. Only registering the worktree is serialized;
+// the checkout itself, the slow part, runs in parallel.
+func Add(ctx context.Context, repoPath, wt, ref string, dirs []string) error {
+	if err := register(ctx, repoPath, wt, ref, dirs); err != nil {
+		return err
+	}
+	// Nothing is checked out yet; populate the index and the working tree.
+	if err := runGit(ctx, wt, "reset", "--hard", "--quiet"); err != nil {
+		return fmt.Errorf("checkout worktree %s: %w", wt, err)
+	}
+	return nil
+}
+
+func register(ctx context.Context, repoPath, wt, ref string, dirs []string) error {
+	addMu.Lock()
+	defer addMu.Unlock()
+	if _, err := os.Stat(wt); err == nil {
+		_ = Remove(ctx, repoPath, wt)
+	}
+	if err := runGit(ctx, repoPath, "worktree", "add", "--detach", "--no-checkout", wt, ref); err != nil {
+		return fmt.Errorf("create worktree %s: %w", wt, err)
+	}
+	if len(dirs) > 0 {
+		if err := runGit(ctx, wt, append([]string{"sparse-checkout", "set", "--cone"}, dirs...)...); err != nil {
+			return fmt.Errorf("sparse checkout %s: %w", wt, err)
+		}
+	}
+	return nil
+}
+
+// InitSubmodules checks out the submodules of a worktree, recursively. Worktrees without a
+// .gitmodules file are left alone.
+func InitSubmodules(ctx context.Context, worktreePath string) error {
+	if _, err := os.Stat(filepath.Join(worktreePath, ".gitmodules")); err != nil {
+		return nil
+	}
+	if err := runGit(ctx, worktreePath, "submodule", "update", "--init", "--recursive"); err != nil {
+		return fmt.Errorf("init submodules in %s: %w", worktreePath, err)
+	}
 	return nil
 }
 
@@ -43,6 +110,125 @@ func Remove(ctx context.Context, repoPath, worktreePath string) error {
 	return nil
 }
 
+// Prune drops git's records of worktrees whose directories are gone.
+func Prune(ctx context.Context, repoPath string) error {
+	return runGit(ctx, repoPath, "worktree", "prune")
+}
+
+// CheckoutBranch adds a worktree at worktreePath with branch checked out, creating the
+// branch from base when it doesn't exist.
+func CheckoutBranch(ctx context.Context, repoPath, worktreePath, branch, base string) error {
+	var err error
+	if runGit(ctx, repoPath, "rev-parse", "--verify", "--quiet", "refs/heads/"+branch) == nil {
+		err = runGit(ctx, repoPath, "worktree", "add", worktreePath, branch)
+	} else {
+		err = runGit(ctx, repoPath, "worktree", "add", "-b", branch, worktreePath, base)
+	}
+	if err != nil {
+		return fmt.Errorf("check out %s: %w", branch, err)
+	}
+	return nil
+}
+
+// CommitFile commits data as file (a slash-separated path) on branch, creating the branch
+// from HEAD when it doesn't exist. It works in a temporary worktree, so the repository's
+// own checkout is left alone.
+func CommitFile(ctx context.Context, repoPath, branch, file string, data []byte, message string) error {
+	tmp, err := os.MkdirTemp("", "swarm-commit-")
+	if err != nil {
+		return err
+	}
+	defer func() { _ = Remove(context.Background(), repoPath, tmp) }()
+
+	if err := CheckoutBranch(ctx, repoPath, tmp, branch, "HEAD"); err != nil {
+		return err
+	}
+	path := filepath.Join(tmp, filepath.FromSlash(file))
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		return err
+	}
+	if err := runGit(ctx, tmp, "add", "--", file); err != nil {
+		return err
+	}
+	if err := runGit(ctx, tmp, "commit", "--quiet", "-m", message, "--", file); err != nil {
+		return fmt.Errorf("commit %s on %s: %w", file, branch, err)
+	}
+	return nil
+}
+
+// BranchSummary lists the subjects of the commits on branch that are not on base or, when
+// base is empty, on any remote branch except branch's own, newest first. stat is git's
+// shortstat of those commits, e.g. "3 files changed, 40 insertions(+), 2 deletions(-)".
+func BranchSummary(ctx context.Context, worktreePath, branch, base string) (commits []string, stat string, err error) {
+	args := []string{"log", "--format=%H %s", branch, "--not"}
+	if base != "" {
+		args = append(args, base)
+	} else {
+		args = append(args, "--exclude=refs/remotes/origin/"+branch, "--remotes")
+	}
+	out, err := output(ctx, worktreePath, args...)
+	if err != nil {
+		return nil, "", err
+	}
+	var oldest string
+	for _, line := range strings.Split(strings.TrimSpace(out), "\n") {
+		hash, subject, ok := strings.Cut(line, " ")
+		if !ok {
+			continue
+		}
+		commits = append(commits, subject)
+		oldest = hash
+	}
+	if oldest == "" {
+		return nil, "", nil
+	}
+	// The root commit has no parent to diff against; leave the stat empty then.
+	if out, err := output(ctx, worktreePath, "diff", "--shortstat", oldest+"^", branch); err == nil {
+		stat = strings.TrimSpace(out)
+	}
+	return commits, stat, nil
+}
+
+// Unsaved reports what would be lost by removing a worktree: uncommitted changes or commits
+// that no branch, remote-tracking branch or the base commit reaches. It returns "" when the
+// worktree is safe to remove. base may be empty.
+func Unsaved(ctx context.Context, worktreePath string, base string) (string, error) {
+	status, err := output(ctx, worktreePath, "status", "--porcelain")
+	if err != nil {
+		return "", err
+	}
+	if strings.TrimSpace(status) != "" {
+		return "uncommitted changes", nil
+	}
+	args := []string{"rev-list", "--count", "HEAD", "--not", "--branches", "--remotes"}
+	if base != "" {
+		args = append(args, base)
+	}
+	count, err := output(ctx, worktreePath, args...)
+	if err != nil {
+		return "", err
+	}
+	if n := strings.TrimSpace(count); n != "0" {
+		return fmt.Sprintf("%s commits not on any branch", n), nil
+	}
+	return "", nil
+}
+
+func output(ctx context.Context, dir string, args ...string) (string, error) {
+	cmd := exec.CommandContext(ctx, "git", args...)
+	cmd.Dir = dir
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("%v: %s", err, stderr.String())
+	}
+	return string(out), nil
+}
+
 func runGit(ctx context.Context, dir string, args ...string) error {
 	cmd := exec.CommandContext(ctx, "git", args...)
 	cmd.Dir = dir